@@ -1,14 +1,14 @@
 import { e2e } from '@grafana/e2e';
 
 e2e.scenario({
-  describeName: 'Create a public dashboard with template variables is disabled',
-  itName: 'Create a public dashboard with template variables is disabled',
+  describeName: 'Create a public dashboard with unsupported template variables is disabled',
+  itName: 'Create a public dashboard with unsupported template variables is disabled',
   addScenarioDataSource: false,
   addScenarioDashBoard: false,
   skipScenario: false,
   scenario: () => {
-    // Opening a dashboard with template variables
-    e2e.flows.openDashboard({ uid: 'HYaGDGIMk' });
+    // Opening a dashboard with a textbox template variable
+    e2e.flows.openDashboard({ uid: 'spVR9LSMk' });
 
     // Open sharing modal
     e2e.pages.ShareDashboardModal.shareButton().click();
@@ -16,7 +16,7 @@ e2e.scenario({
     // Select public dashboards tab
     e2e.pages.ShareDashboardModal.PublicDashboard.Tab().click();
 
-    // Warning Alert dashboard cannot be made public because it has template variables
+    // Warning Alert dashboard cannot be made public because it has unsupported template variables
     e2e.pages.ShareDashboardModal.PublicDashboard.TemplateVariablesWarningAlert().should('be.visible');
 
     // Configuration elements for public dashboards should not exist
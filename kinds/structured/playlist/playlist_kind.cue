@@ -27,7 +27,7 @@ lineage: seqs: [
 
 				#PlaylistItem: {
 					// Type of the item.
-					type: "dashboard_by_uid" | "dashboard_by_id" | "dashboard_by_tag"
+					type: "dashboard_by_uid" | "dashboard_by_id" | "dashboard_by_tag" | "dashboard_by_search"
 					// Value depends on type and describes the playlist item.
 					//
 					//  - dashboard_by_id: The value is an internal numerical identifier set by Grafana. This
@@ -36,10 +36,32 @@ lineage: seqs: [
 					//  - dashboard_by_tag: The value is a tag which is set on any number of dashboards. All
 					//  dashboards behind the tag will be added to the playlist.
 					//  - dashboard_by_uid: The value is the dashboard UID
+					//  - dashboard_by_search: The value is a JSON encoded search query, e.g. {"location": "<folder uid>"}
+					//  or {"tags": ["prod"], "sort": "name_sort"}. All dashboards matching the query when the playlist
+					//  is played will be added to the playlist, in the order of the search results.
 					value: string
 
 					// Title is an unused property -- it will be removed in the future
     				title?: string
+
+					// Interval overrides the interval of the playlist for the dashboards of this item.
+					interval?: string
+
+					// TimeFrom overrides the start of the time range of the dashboards of this item, e.g. now-6h.
+					timeFrom?: string
+
+					// TimeTo overrides the end of the time range of the dashboards of this item, e.g. now.
+					timeTo?: string
+
+					// Variables sets the values of template variables of the dashboards of this item.
+					variables?: [...#PlaylistItemVariable]
+				} @cuetsy(kind="interface")
+
+				#PlaylistItemVariable: {
+					// Name of the template variable.
+					name: string
+					// Values of the template variable, more than one for multi-value variables.
+					values: [...string]
 				} @cuetsy(kind="interface")
 			}
 		]
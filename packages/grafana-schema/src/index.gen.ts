@@ -89,11 +89,16 @@ export {
 // Raw generated types from Playlist kind.
 export type {
   Playlist,
-  PlaylistItem
+  PlaylistItem,
+  PlaylistItemVariable
 } from './raw/playlist/x/playlist_types.gen';
 
 // Raw generated enums and default consts from playlist kind.
-export { defaultPlaylist } from './raw/playlist/x/playlist_types.gen';
+export {
+  defaultPlaylist,
+  defaultPlaylistItem,
+  defaultPlaylistItemVariable
+} from './raw/playlist/x/playlist_types.gen';
 
 // Raw generated types from Team kind.
 export type { Team } from './raw/team/x/team_types.gen';
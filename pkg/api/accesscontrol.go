@@ -316,6 +316,32 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		Grants: []string{string(org.RoleEditor)},
 	}
 
+	liveSubscriberRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:live:subscriber",
+			DisplayName: "Live stream subscriber",
+			Description: "Subscribe to all Live stream channels.",
+			Group:       "Live",
+			Permissions: []ac.Permission{
+				{Action: ac.ActionLiveSubscribe, Scope: ac.ScopeLiveStreamsAll},
+			},
+		},
+		Grants: []string{string(org.RoleViewer)},
+	}
+
+	livePublisherRole := ac.RoleRegistration{
+		Role: ac.RoleDTO{
+			Name:        "fixed:live:publisher",
+			DisplayName: "Live stream publisher",
+			Description: "Push data to all Live stream channels.",
+			Group:       "Live",
+			Permissions: []ac.Permission{
+				{Action: ac.ActionLivePublish, Scope: ac.ScopeLiveStreamsAll},
+			},
+		},
+		Grants: []string{string(org.RoleEditor)},
+	}
+
 	dashboardsCreatorRole := ac.RoleRegistration{
 		Role: ac.RoleDTO{
 			Name:        "fixed:dashboards:creator",
@@ -427,6 +453,7 @@ func (hs *HTTPServer) declareFixedRoles() error {
 		datasourcesIdReaderRole, orgReaderRole, orgWriterRole,
 		orgMaintainerRole, teamsCreatorRole, teamsWriterRole, datasourcesExplorerRole,
 		annotationsReaderRole, dashboardAnnotationsWriterRole, annotationsWriterRole,
+		liveSubscriberRole, livePublisherRole,
 		dashboardsCreatorRole, dashboardsReaderRole, dashboardsWriterRole,
 		foldersCreatorRole, foldersReaderRole, foldersWriterRole, apikeyReaderRole, apikeyWriterRole,
 		publicDashboardsWriterRole,
@@ -37,6 +37,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	dashboardsstore "github.com/grafana/grafana/pkg/services/dashboards/database"
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusagetest"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
@@ -255,17 +256,18 @@ func setupAccessControlScenarioContext(t *testing.T, cfg *setting.Cfg, url strin
 	store := sqlstore.InitTestDB(t)
 	statsService := statsimpl.ProvideService(store)
 	hs := &HTTPServer{
-		Cfg:                  cfg,
-		Live:                 newTestLive(t, store),
-		License:              &licensing.OSSLicensingService{},
-		Features:             featuremgmt.WithFeatures(),
-		QuotaService:         quotatest.New(false, nil),
-		RouteRegister:        routing.NewRouteRegister(),
-		AccessControl:        accesscontrolmock.New().WithPermissions(permissions),
-		searchUsersService:   searchusers.ProvideUsersService(filters.ProvideOSSSearchUserFilter(), usertest.NewUserServiceFake()),
-		ldapGroups:           ldap.ProvideGroupsService(),
-		accesscontrolService: actest.FakeService{},
-		statsService:         statsService,
+		Cfg:                   cfg,
+		Live:                  newTestLive(t, store),
+		License:               &licensing.OSSLicensingService{},
+		Features:              featuremgmt.WithFeatures(),
+		QuotaService:          quotatest.New(false, nil),
+		RouteRegister:         routing.NewRouteRegister(),
+		AccessControl:         accesscontrolmock.New().WithPermissions(permissions),
+		searchUsersService:    searchusers.ProvideUsersService(filters.ProvideOSSSearchUserFilter(), usertest.NewUserServiceFake()),
+		ldapGroups:            ldap.ProvideGroupsService(),
+		accesscontrolService:  actest.FakeService{},
+		statsService:          statsService,
+		dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 	}
 
 	sc := setupScenarioContext(t, url)
@@ -447,6 +449,7 @@ func setupHTTPServerWithCfgDb(
 		authInfoService: &logintest.AuthInfoServiceFake{
 			ExpectedLabels: map[int64]string{int64(1): login.GetAuthProviderLabel(login.LDAPAuthModule)},
 		},
+		dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 	}
 
 	for _, o := range options {
@@ -514,11 +517,12 @@ func SetupAPITestServer(t *testing.T, opts ...APITestServerOption) *webtest.Serv
 	t.Helper()
 
 	hs := &HTTPServer{
-		RouteRegister:      routing.NewRouteRegister(),
-		License:            &licensing.OSSLicensingService{},
-		Features:           featuremgmt.WithFeatures(),
-		QuotaService:       quotatest.New(false, nil),
-		searchUsersService: &searchusers.OSSService{},
+		RouteRegister:         routing.NewRouteRegister(),
+		License:               &licensing.OSSLicensingService{},
+		Features:              featuremgmt.WithFeatures(),
+		QuotaService:          quotatest.New(false, nil),
+		searchUsersService:    &searchusers.OSSService{},
+		dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 	}
 
 	for _, opt := range opts {
@@ -243,6 +243,8 @@ func (hs *HTTPServer) GetDashboard(c *models.ReqContext) response.Response {
 		Meta:      meta,
 	}
 
+	hs.dashboardUsageService.RecordView(c.Req.Context(), c.OrgID, dash.Uid, c.UserID)
+
 	c.TimeRequest(metrics.MApiDashboardGet)
 	return response.JSON(http.StatusOK, dto)
 }
@@ -930,7 +932,7 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *models.ReqContext) response.Resp
 		return response.Error(500, "Unable to compute diff", err)
 	}
 
-	if options.DiffType == dashdiffs.DiffDelta {
+	if options.DiffType == dashdiffs.DiffDelta || options.DiffType == dashdiffs.DiffJSONPatch {
 		return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "application/json")
 	}
 
@@ -993,7 +995,7 @@ func (hs *HTTPServer) RestoreDashboardVersion(c *models.ReqContext) response.Res
 		return dashboardGuardianResponse(err)
 	}
 
-	versionQuery := dashver.GetDashboardVersionQuery{DashboardID: dashID, Version: apiCmd.Version, OrgID: c.OrgID}
+	versionQuery := dashver.GetDashboardVersionQuery{DashboardID: dash.Id, Version: apiCmd.Version, OrgID: c.OrgID}
 	version, err := hs.dashboardVersionService.Get(c.Req.Context(), &versionQuery)
 	if err != nil {
 		return response.Error(404, "Dashboard version not found", nil)
@@ -1008,10 +1010,45 @@ func (hs *HTTPServer) RestoreDashboardVersion(c *models.ReqContext) response.Res
 	saveCmd.Dashboard.Set("uid", dash.Uid)
 	saveCmd.Message = fmt.Sprintf("Restored from version %d", version.Version)
 	saveCmd.FolderId = dash.FolderId
+	if apiCmd.FolderUID != "" {
+		folderID, rsp := hs.getRestoreFolderID(c, dash, apiCmd.FolderUID)
+		if rsp != nil {
+			return rsp
+		}
+		saveCmd.FolderId = folderID
+	}
 
 	return hs.postDashboard(c, saveCmd)
 }
 
+// getRestoreFolderID returns the id of the folder a dashboard version is restored to,
+// moving the dashboard to another folder requires the permission to write in that folder
+func (hs *HTTPServer) getRestoreFolderID(c *models.ReqContext, dash *models.Dashboard, folderUID string) (int64, response.Response) {
+	f, err := hs.folderService.Get(c.Req.Context(), &folder.GetFolderQuery{
+		OrgID:        c.OrgID,
+		UID:          &folderUID,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrFolderNotFound) {
+			return 0, response.Error(http.StatusBadRequest, "Folder not found", err)
+		}
+		return 0, response.Error(http.StatusInternalServerError, "Error while checking folder ID", err)
+	}
+	if f.ID == dash.FolderId {
+		return f.ID, nil
+	}
+
+	g, err := guardian.New(c.Req.Context(), f.ID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return 0, response.Err(err)
+	}
+	if canSave, err := g.CanSave(); err != nil || !canSave {
+		return 0, dashboardGuardianResponse(err)
+	}
+	return f.ID, nil
+}
+
 // swagger:route GET /dashboards/tags dashboards getDashboardTags
 //
 // Get all dashboards tags of an organisation.
@@ -1154,7 +1191,8 @@ type CalcDashboardDiffParams struct {
 		// Description:
 		// * `basic`
 		// * `json`
-		// Enum: basic,json
+		// * `jsonpatch`
+		// Enum: basic,json,jsonpatch
 		DiffType string `json:"diffType" binding:"Required"`
 	}
 }
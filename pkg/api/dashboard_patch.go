@@ -0,0 +1,191 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/uid/{uid}/patch dashboard_versions getDashboardVersionsPatchByUID
+//
+// Get the JSON Patch between two versions of a dashboard.
+//
+// Returns the RFC 6902 JSON Patch which turns the base version of the dashboard into the new version.
+//
+// Produces:
+// - application/json-patch+json
+//
+// Responses:
+// 200: dashboardPatchResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionsPatch(c *models.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	baseVersion := c.QueryInt("base")
+	if baseVersion <= 0 {
+		return response.Error(http.StatusBadRequest, "base version is required", nil)
+	}
+	newVersion := c.QueryInt("new")
+	if newVersion == 0 {
+		newVersion = dash.Version
+	}
+
+	baseVersionRes, err := hs.dashboardVersionService.Get(c.Req.Context(), &dashver.GetDashboardVersionQuery{
+		DashboardID: dash.Id,
+		Version:     baseVersion,
+		OrgID:       c.OrgID,
+	})
+	if err != nil {
+		return dashboardVersionErrorResponse(err, baseVersion)
+	}
+
+	newVersionRes, err := hs.dashboardVersionService.Get(c.Req.Context(), &dashver.GetDashboardVersionQuery{
+		DashboardID: dash.Id,
+		Version:     newVersion,
+		OrgID:       c.OrgID,
+	})
+	if err != nil {
+		return dashboardVersionErrorResponse(err, newVersion)
+	}
+
+	patch, err := dashdiffs.CreateJSONPatch(baseVersionRes.Data, newVersionRes.Data)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute patch", err)
+	}
+
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute patch", err)
+	}
+
+	return response.Respond(http.StatusOK, body).SetHeader("Content-Type", "application/json-patch+json")
+}
+
+// swagger:route PATCH /dashboards/uid/{uid} dashboards patchDashboardByUID
+//
+// Apply a JSON Patch to a dashboard.
+//
+// Applies the RFC 6902 JSON Patch to the latest version of the dashboard and saves the result as a new version.
+// The patch is rejected when the dashboard has been saved since the given version.
+// Changes to the id, uid and version of the dashboard are ignored.
+//
+// Responses:
+// 200: postDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 409: conflictError
+// 412: preconditionFailedError
+// 422: unprocessableEntityError
+// 500: internalServerError
+func (hs *HTTPServer) PatchDashboard(c *models.ReqContext) response.Response {
+	apiCmd := dtos.PatchDashboardCommand{}
+	if err := web.Bind(c.Req, &apiCmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	if apiCmd.Version != dash.Version {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, dashboards.ErrDashboardVersionMismatch)
+	}
+
+	data, err := dashdiffs.ApplyJSONPatch(dash.Data, apiCmd.Patch)
+	if err != nil {
+		if errors.Is(err, dashdiffs.ErrJSONPatchTestFailed) {
+			return response.Error(http.StatusConflict, "JSON Patch test operation failed", err)
+		}
+		return response.Error(http.StatusBadRequest, fmt.Sprintf("Unable to apply JSON Patch: %s", err), err)
+	}
+	if _, err := data.Map(); err != nil {
+		return response.Error(http.StatusBadRequest, "The patched dashboard must be an object", err)
+	}
+
+	saveCmd := models.SaveDashboardCommand{}
+	saveCmd.OrgId = c.OrgID
+	saveCmd.UserId = c.UserID
+	saveCmd.Dashboard = data
+	saveCmd.Dashboard.Set("id", dash.Id)
+	saveCmd.Dashboard.Set("uid", dash.Uid)
+	saveCmd.Dashboard.Set("version", dash.Version)
+	saveCmd.Message = apiCmd.Message
+	saveCmd.FolderId = dash.FolderId
+
+	return hs.postDashboard(c, saveCmd)
+}
+
+func dashboardVersionErrorResponse(err error, version int) response.Response {
+	if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+		return response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+	}
+	return response.Error(http.StatusInternalServerError, "Unable to compute patch", err)
+}
+
+// swagger:parameters getDashboardVersionsPatchByUID
+type GetDashboardVersionsPatchByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Version the patch starts from
+	// in:query
+	// required:true
+	Base int `json:"base"`
+	// Version the patch leads to, defaults to the latest version
+	// in:query
+	// required:false
+	New int `json:"new"`
+}
+
+// swagger:parameters patchDashboardByUID
+type PatchDashboardByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dtos.PatchDashboardCommand
+}
+
+// swagger:response dashboardPatchResponse
+type DashboardPatchResponse struct {
+	// in: body
+	Body dashdiffs.JSONPatch `json:"body"`
+}
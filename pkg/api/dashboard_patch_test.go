@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
+	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestDashboardPatchAPIEndpoint(t *testing.T) {
+	origNewGuardian := guardian.New
+	origNewGuardianByUID := guardian.NewByUID
+	origNewGuardianByDashboard := guardian.NewByDashboard
+	t.Cleanup(func() {
+		guardian.New = origNewGuardian
+		guardian.NewByUID = origNewGuardianByUID
+		guardian.NewByDashboard = origNewGuardianByDashboard
+	})
+	guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+	newDashboard := func() *models.Dashboard {
+		return &models.Dashboard{
+			Id:      1,
+			Uid:     "dash",
+			OrgId:   1,
+			Version: 3,
+			Data: simplejson.MustJson([]byte(`{
+				"id": 1,
+				"uid": "dash",
+				"title": "Production",
+				"version": 3,
+				"panels": [{"id": 1, "datasource": {"uid": "old"}}]
+			}`)),
+		}
+	}
+
+	setupServer := func(t *testing.T, dashSvc *dashboards.FakeDashboardService, versionSvc dashver.Service) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.DashboardService = dashSvc
+			hs.dashboardVersionService = versionSvc
+			hs.dashboardProvisioningService = mockDashboardProvisioningService{}
+			hs.ProvisioningService = provisioning.NewProvisioningServiceMock(context.Background())
+			hs.LibraryPanelService = &mockLibraryPanelService{}
+			hs.pluginStore = &plugins.FakePluginStore{}
+			hs.accesscontrolService = actest.FakeService{}
+		})
+	}
+
+	t.Run("Should return the JSON Patch between two versions", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+
+		versionSvc := dashvertest.NewDashboardVersionServiceFake()
+		versionSvc.ExpectedDashboardVersions = []*dashver.DashboardVersion{
+			{DashboardID: 1, Version: 1, Data: simplejson.MustJson([]byte(`{"title": "Staging", "version": 1}`))},
+			{DashboardID: 1, Version: 3, Data: simplejson.MustJson([]byte(`{"title": "Production", "version": 3}`))},
+		}
+
+		server := setupServer(t, dashSvc, versionSvc)
+		req := server.NewGetRequest("/api/dashboards/uid/dash/patch?base=1")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/json-patch+json", res.Header.Get("Content-Type"))
+
+		var body []map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, []map[string]interface{}{
+			{"op": "replace", "path": "/title", "value": "Production"},
+			{"op": "replace", "path": "/version", "value": float64(3)},
+		}, body)
+	})
+
+	t.Run("Should require the base version", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+
+		server := setupServer(t, dashSvc, dashvertest.NewDashboardVersionServiceFake())
+		req := server.NewGetRequest("/api/dashboards/uid/dash/patch")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Should apply a JSON Patch to the latest version", func(t *testing.T) {
+		var saved *models.Dashboard
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+		dashSvc.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard
+		}).Return(&models.Dashboard{Id: 1, Uid: "dash", Title: "Production", Slug: "production", Version: 4}, nil)
+
+		server := setupServer(t, dashSvc, dashvertest.NewDashboardVersionServiceFake())
+		req := server.NewRequest(http.MethodPatch, "/api/dashboards/uid/dash", strings.NewReader(`{
+			"version": 3,
+			"message": "swap data source",
+			"patch": [
+				{"op": "test", "path": "/panels/0/datasource/uid", "value": "old"},
+				{"op": "replace", "path": "/panels/0/datasource/uid", "value": "new"},
+				{"op": "replace", "path": "/uid", "value": "ignored"}
+			]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		require.NotNil(t, saved)
+		assert.Equal(t, "dash", saved.Uid)
+		assert.Equal(t, 3, saved.Version)
+		assert.Equal(t, "new", saved.Data.GetPath("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+	})
+
+	t.Run("Should reject a patch written against an older version", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+
+		server := setupServer(t, dashSvc, dashvertest.NewDashboardVersionServiceFake())
+		req := server.NewRequest(http.MethodPatch, "/api/dashboards/uid/dash", strings.NewReader(`{
+			"version": 2,
+			"patch": [{"op": "replace", "path": "/title", "value": "New title"}]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "version-mismatch", body["status"])
+	})
+
+	t.Run("Should return 409 when a test operation fails", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+
+		server := setupServer(t, dashSvc, dashvertest.NewDashboardVersionServiceFake())
+		req := server.NewRequest(http.MethodPatch, "/api/dashboards/uid/dash", strings.NewReader(`{
+			"version": 3,
+			"patch": [{"op": "test", "path": "/title", "value": "Staging"}]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("Should return 400 for a patch which can not be applied", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = newDashboard()
+		}).Return(nil)
+
+		server := setupServer(t, dashSvc, dashvertest.NewDashboardVersionServiceFake())
+		req := server.NewRequest(http.MethodPatch, "/api/dashboards/uid/dash", strings.NewReader(`{
+			"version": 3,
+			"patch": [{"op": "remove", "path": "/panels/5"}]
+		}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
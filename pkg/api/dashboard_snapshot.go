@@ -16,6 +16,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/web"
@@ -93,6 +95,20 @@ func createOriginalDashboardURL(appURL string, cmd *dashboardsnapshots.CreateDas
 	return fmt.Sprintf("/d/%v", dashUID), nil
 }
 
+// validateSnapshotTeams checks that the teams a snapshot is shared with belong to the organization of the user
+func (hs *HTTPServer) validateSnapshotTeams(c *models.ReqContext, teamIDs []int64) response.Response {
+	for _, teamID := range teamIDs {
+		query := models.GetTeamByIdQuery{OrgId: c.OrgID, Id: teamID, SignedInUser: c.SignedInUser}
+		if err := hs.teamService.GetTeamById(c.Req.Context(), &query); err != nil {
+			if errors.Is(err, models.ErrTeamNotFound) {
+				return response.Error(http.StatusBadRequest, fmt.Sprintf("Team %d not found", teamID), err)
+			}
+			return response.Error(http.StatusInternalServerError, "Failed to get team", err)
+		}
+	}
+	return nil
+}
+
 // swagger:route POST /snapshots snapshots createDashboardSnapshot
 //
 // When creating a snapshot using the API, you have to provide the full dashboard payload including the snapshot data. This endpoint is designed for the Grafana UI.
@@ -113,6 +129,29 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 		cmd.Name = "Unnamed snapshot"
 	}
 
+	switch cmd.Access {
+	case "":
+		cmd.Access = dashboardsnapshots.AccessPublic
+	case dashboardsnapshots.AccessPublic:
+	case dashboardsnapshots.AccessOrg, dashboardsnapshots.AccessTeams:
+		if cmd.External {
+			return response.Error(http.StatusBadRequest, "External snapshots can only be public", nil)
+		}
+		if !c.IsSignedIn {
+			return response.Error(http.StatusBadRequest, "Only signed in users can restrict access to a snapshot", nil)
+		}
+		if cmd.Access == dashboardsnapshots.AccessTeams && len(cmd.TeamIDs) == 0 {
+			return response.Error(http.StatusBadRequest, "Snapshots with teams access require at least one team", nil)
+		}
+	default:
+		return response.Error(http.StatusBadRequest, "Invalid snapshot access, must be one of public, org or teams", nil)
+	}
+	if cmd.Access != dashboardsnapshots.AccessTeams {
+		cmd.TeamIDs = nil
+	} else if rsp := hs.validateSnapshotTeams(c, cmd.TeamIDs); rsp != nil {
+		return rsp
+	}
+
 	var snapshotUrl string
 	cmd.ExternalUrl = ""
 	cmd.OrgId = c.OrgID
@@ -188,9 +227,13 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *models.ReqContext) response.Res
 //
 // Get Snapshot by Key.
 //
+// Snapshots with `org` or `teams` access require authentication.
+//
 // Responses:
 // 200: getDashboardSnapshotResponse
 // 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
 // 404: notFoundError
 // 500: internalServerError
 func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Response {
@@ -213,6 +256,15 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Respon
 		return response.Error(404, "Dashboard snapshot not found", err)
 	}
 
+	if snapshot.Access == dashboardsnapshots.AccessOrg || snapshot.Access == dashboardsnapshots.AccessTeams {
+		if !c.IsSignedIn {
+			return response.Error(http.StatusUnauthorized, "Unauthorized", nil)
+		}
+		if !canViewDashboardSnapshot(c.SignedInUser, snapshot) {
+			return response.Error(http.StatusForbidden, "Access denied to this snapshot", nil)
+		}
+	}
+
 	dto := dtos.DashboardFullWithMeta{
 		Dashboard: snapshot.Dashboard,
 		Meta: dtos.DashboardMeta{
@@ -225,9 +277,38 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *models.ReqContext) response.Respon
 
 	metrics.MApiDashboardSnapshotGet.Inc()
 
+	if snapshot.Access == dashboardsnapshots.AccessOrg || snapshot.Access == dashboardsnapshots.AccessTeams {
+		return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "private, no-cache")
+	}
+
 	return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
+// canViewDashboardSnapshot checks the access of a snapshot which is not public.
+func canViewDashboardSnapshot(signedInUser *user.SignedInUser, snapshot *dashboardsnapshots.DashboardSnapshot) bool {
+	if signedInUser.OrgID != snapshot.OrgId {
+		return false
+	}
+
+	if snapshot.Access == dashboardsnapshots.AccessOrg {
+		return true
+	}
+
+	if signedInUser.OrgRole == org.RoleAdmin || signedInUser.UserID == snapshot.UserId {
+		return true
+	}
+
+	for _, teamID := range snapshot.TeamIDs {
+		for _, userTeamID := range signedInUser.Teams {
+			if teamID == userTeamID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func deleteExternalDashboardSnapshot(externalUrl string) error {
 	response, err := client.Get(externalUrl)
 	if err != nil {
@@ -407,6 +488,7 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *models.ReqContext) response.Re
 			UserId:      snapshot.UserId,
 			External:    snapshot.External,
 			ExternalUrl: snapshot.ExternalUrl,
+			Access:      snapshot.Access,
 			Expires:     snapshot.Expires,
 			Created:     snapshot.Created,
 			Updated:     snapshot.Updated,
@@ -20,7 +20,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/sqlstore/mockstore"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
@@ -354,3 +357,178 @@ func TestGetDashboardSnapshotFailure(t *testing.T) {
 			assert.Equal(t, http.StatusInternalServerError, sc.resp.Code)
 		}, sqlmock)
 }
+
+func TestGetDashboardSnapshotAccess(t *testing.T) {
+	setupServer := func(t *testing.T, snapshot *dashboardsnapshots.DashboardSnapshot) *webtest.Server {
+		dashSnapSvc := dashboardsnapshots.NewMockService(t)
+		dashSnapSvc.On("GetDashboardSnapshot", mock.Anything, mock.AnythingOfType("*dashboardsnapshots.GetDashboardSnapshotQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*dashboardsnapshots.GetDashboardSnapshotQuery).Result = snapshot
+		}).Return(nil)
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.dashboardsnapshotsService = dashSnapSvc
+		})
+	}
+
+	newSnapshot := func(access string, teamIDs ...int64) *dashboardsnapshots.DashboardSnapshot {
+		return &dashboardsnapshots.DashboardSnapshot{
+			Id:        1,
+			Key:       "12345",
+			OrgId:     1,
+			UserId:    2,
+			Access:    access,
+			TeamIDs:   teamIDs,
+			Dashboard: simplejson.New(),
+			Expires:   time.Now().Add(time.Hour),
+		}
+	}
+
+	anonymous := func(req *http.Request) *http.Request {
+		return webtest.RequestWithWebContext(req, &models.ReqContext{SignedInUser: &user.SignedInUser{}})
+	}
+
+	signedIn := func(u *user.SignedInUser) func(req *http.Request) *http.Request {
+		return func(req *http.Request) *http.Request {
+			return webtest.RequestWithSignedInUser(req, u)
+		}
+	}
+
+	testCases := []struct {
+		desc         string
+		snapshot     *dashboardsnapshots.DashboardSnapshot
+		withUser     func(req *http.Request) *http.Request
+		expectedCode int
+		cacheControl string
+	}{
+		{
+			desc:         "public snapshots can be viewed by anyone with the key",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessPublic),
+			withUser:     anonymous,
+			expectedCode: http.StatusOK,
+			cacheControl: "public, max-age=3600",
+		},
+		{
+			desc:         "org snapshots require authentication",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessOrg),
+			withUser:     anonymous,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			desc:         "org snapshots can be viewed by the members of the organization",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessOrg),
+			withUser:     signedIn(&user.SignedInUser{UserID: 3, OrgID: 1, OrgRole: org.RoleViewer}),
+			expectedCode: http.StatusOK,
+			cacheControl: "private, no-cache",
+		},
+		{
+			desc:         "org snapshots can not be viewed from another organization",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessOrg),
+			withUser:     signedIn(&user.SignedInUser{UserID: 3, OrgID: 2, OrgRole: org.RoleAdmin}),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			desc:         "teams snapshots can be viewed by the members of the teams",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessTeams, 4, 5),
+			withUser:     signedIn(&user.SignedInUser{UserID: 3, OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{5}}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "teams snapshots can be viewed by their creator",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessTeams, 4),
+			withUser:     signedIn(&user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleViewer}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "teams snapshots can be viewed by org admins",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessTeams, 4),
+			withUser:     signedIn(&user.SignedInUser{UserID: 3, OrgID: 1, OrgRole: org.RoleAdmin}),
+			expectedCode: http.StatusOK,
+		},
+		{
+			desc:         "teams snapshots can not be viewed by other members of the organization",
+			snapshot:     newSnapshot(dashboardsnapshots.AccessTeams, 4),
+			withUser:     signedIn(&user.SignedInUser{UserID: 3, OrgID: 1, OrgRole: org.RoleEditor, Teams: []int64{5}}),
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			server := setupServer(t, tc.snapshot)
+			res, err := server.Send(tc.withUser(server.NewGetRequest("/api/snapshots/12345")))
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			assert.Equal(t, tc.expectedCode, res.StatusCode)
+			if tc.cacheControl != "" {
+				assert.Equal(t, tc.cacheControl, res.Header.Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestCreateDashboardSnapshotAccess(t *testing.T) {
+	setupServerWithTeams := func(t *testing.T, dashSnapSvc *dashboardsnapshots.MockService, teamSvc team.Service) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.dashboardsnapshotsService = dashSnapSvc
+			hs.teamService = teamSvc
+		})
+	}
+	setupServer := func(t *testing.T, dashSnapSvc *dashboardsnapshots.MockService) *webtest.Server {
+		return setupServerWithTeams(t, dashSnapSvc, &teamtest.FakeService{ExpectedTeamDTO: &models.TeamDTO{Id: 4, OrgId: 1}})
+	}
+
+	createSnapshot := func(t *testing.T, server *webtest.Server, body string) *http.Response {
+		t.Helper()
+		req := server.NewPostRequest("/api/snapshots", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		res, err := server.Send(webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 2, OrgID: 1, OrgRole: org.RoleEditor}))
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res
+	}
+
+	t.Run("Should create a snapshot for teams", func(t *testing.T) {
+		var cmd *dashboardsnapshots.CreateDashboardSnapshotCommand
+		dashSnapSvc := dashboardsnapshots.NewMockService(t)
+		dashSnapSvc.On("CreateDashboardSnapshot", mock.Anything, mock.AnythingOfType("*dashboardsnapshots.CreateDashboardSnapshotCommand")).Run(func(args mock.Arguments) {
+			cmd = args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+			cmd.Result = &dashboardsnapshots.DashboardSnapshot{Id: 1}
+		}).Return(nil)
+
+		res := createSnapshot(t, setupServer(t, dashSnapSvc), `{"dashboard": {"uid": "dash"}, "access": "teams", "teamIds": [4]}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, dashboardsnapshots.AccessTeams, cmd.Access)
+		assert.Equal(t, []int64{4}, cmd.TeamIDs)
+	})
+
+	t.Run("Should create a public snapshot by default", func(t *testing.T) {
+		var cmd *dashboardsnapshots.CreateDashboardSnapshotCommand
+		dashSnapSvc := dashboardsnapshots.NewMockService(t)
+		dashSnapSvc.On("CreateDashboardSnapshot", mock.Anything, mock.AnythingOfType("*dashboardsnapshots.CreateDashboardSnapshotCommand")).Run(func(args mock.Arguments) {
+			cmd = args.Get(1).(*dashboardsnapshots.CreateDashboardSnapshotCommand)
+			cmd.Result = &dashboardsnapshots.DashboardSnapshot{Id: 1}
+		}).Return(nil)
+
+		res := createSnapshot(t, setupServer(t, dashSnapSvc), `{"dashboard": {"uid": "dash"}, "teamIds": [4]}`)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, dashboardsnapshots.AccessPublic, cmd.Access)
+		assert.Empty(t, cmd.TeamIDs)
+	})
+
+	t.Run("Should not create a snapshot for a team of another organization", func(t *testing.T) {
+		server := setupServerWithTeams(t, dashboardsnapshots.NewMockService(t), &teamtest.FakeService{ExpectedError: models.ErrTeamNotFound})
+		res := createSnapshot(t, server, `{"dashboard": {"uid": "dash"}, "access": "teams", "teamIds": [4]}`)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	for desc, body := range map[string]string{
+		"Should not create a snapshot with an invalid access":   `{"dashboard": {"uid": "dash"}, "access": "everyone"}`,
+		"Should not create a snapshot for teams without a team": `{"dashboard": {"uid": "dash"}, "access": "teams"}`,
+		"Should not create an external snapshot for the org":    `{"dashboard": {"uid": "dash"}, "access": "org", "external": true}`,
+	} {
+		t.Run(desc, func(t *testing.T) {
+			res := createSnapshot(t, setupServer(t, dashboardsnapshots.NewMockService(t)), body)
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,6 +30,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/database"
 	"github.com/grafana/grafana/pkg/services/dashboards/service"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusagetest"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/dashboardversion/dashvertest"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
@@ -48,6 +50,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
+	"github.com/grafana/grafana/pkg/web/webtest"
 )
 
 func TestGetHomeDashboard(t *testing.T) {
@@ -67,6 +70,7 @@ func TestGetHomeDashboard(t *testing.T) {
 		preferenceService:       prefService,
 		dashboardVersionService: dashboardVersionService,
 		Kinds:                   corekind.NewBase(nil),
+		dashboardUsageService:   dashboardusagetest.NewDashboardUsageServiceFake(),
 	}
 
 	tests := []struct {
@@ -115,7 +119,7 @@ func newTestLive(t *testing.T, store db.DB) *live.GrafanaLive {
 		nil,
 		&usagestats.UsageStatsMock{T: t},
 		nil,
-		features, accesscontrolmock.New(), &dashboards.FakeDashboardService{}, annotationstest.NewFakeAnnotationsRepo(), nil)
+		features, accesscontrolmock.New(), &dashboards.FakeDashboardService{}, annotationstest.NewFakeAnnotationsRepo(), nil, nil, nil)
 	require.NoError(t, err)
 	return gLive
 }
@@ -152,6 +156,7 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 			dashboardVersionService: fakeDashboardVersionService,
 			Kinds:                   corekind.NewBase(nil),
 			QuotaService:            quotatest.New(false, nil),
+			dashboardUsageService:   dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		setUp := func() {
@@ -274,6 +279,7 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 			dashboardVersionService: fakeDashboardVersionService,
 			Features:                featuremgmt.WithFeatures(),
 			Kinds:                   corekind.NewBase(nil),
+			dashboardUsageService:   dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		setUp := func() {
@@ -978,6 +984,7 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 				DashboardService:             dashboardService,
 				Features:                     featuremgmt.WithFeatures(),
 				Kinds:                        corekind.NewBase(nil),
+				dashboardUsageService:        dashboardusagetest.NewDashboardUsageServiceFake(),
 			}
 			hs.callGetDashboard(sc)
 
@@ -992,6 +999,85 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestRestoreDashboardVersionToFolder(t *testing.T) {
+	origNewGuardian := guardian.New
+	origNewGuardianByUID := guardian.NewByUID
+	origNewGuardianByDashboard := guardian.NewByDashboard
+	t.Cleanup(func() {
+		guardian.New = origNewGuardian
+		guardian.NewByUID = origNewGuardianByUID
+		guardian.NewByDashboard = origNewGuardianByDashboard
+	})
+
+	setup := func(t *testing.T, canSaveFolder bool) (*webtest.Server, *dashboards.FakeDashboardService) {
+		// the dashboard is saved through its own guardian, the target folder through guardian.New
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+		folderGuardian := &guardian.FakeDashboardGuardian{CanSaveValue: canSaveFolder}
+		guardian.New = func(_ context.Context, dashID int64, orgID int64, user *user.SignedInUser) (guardian.DashboardGuardian, error) {
+			folderGuardian.DashID = dashID
+			return folderGuardian, nil
+		}
+
+		fakeDash := models.NewDashboard("Child dash")
+		fakeDash.Id = 2
+		fakeDash.Uid = "dash"
+		fakeDash.FolderId = 1
+		fakeDash.Data.Set("id", fakeDash.Id)
+
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.GetDashboardQuery).Result = fakeDash
+		}).Return(nil)
+
+		versionSvc := dashvertest.NewDashboardVersionServiceFake()
+		versionSvc.ExpectedDashboardVersions = []*dashver.DashboardVersion{{DashboardID: 2, Version: 1, Data: fakeDash.Data}}
+
+		folderSvc := foldertest.NewFakeService()
+		folderSvc.ExpectedFolder = &folder.Folder{ID: 3, UID: "target"}
+
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.DashboardService = dashSvc
+			hs.dashboardVersionService = versionSvc
+			hs.folderService = folderSvc
+			hs.dashboardProvisioningService = mockDashboardProvisioningService{}
+			hs.ProvisioningService = provisioning.NewProvisioningServiceMock(context.Background())
+			hs.LibraryPanelService = &mockLibraryPanelService{}
+			hs.LibraryElementService = &mockLibraryElementService{}
+			hs.Live = newTestLive(t, db.InitTestDB(t))
+			hs.pluginStore = &plugins.FakePluginStore{}
+			hs.accesscontrolService = actest.FakeService{}
+		})
+		return server, dashSvc
+	}
+
+	restore := func(t *testing.T, server *webtest.Server) int {
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/uid/dash/restore", strings.NewReader(`{"version":1,"folderUid":"target"}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		return res.StatusCode
+	}
+
+	t.Run("Should restore the dashboard into the folder", func(t *testing.T) {
+		server, dashSvc := setup(t, true)
+		dashSvc.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), mock.AnythingOfType("bool")).Run(func(args mock.Arguments) {
+			assert.Equal(t, int64(3), args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.FolderId)
+		}).Return(&models.Dashboard{Id: 2, Uid: "dash", Title: "Child dash", Slug: "child-dash", Version: 2}, nil)
+
+		assert.Equal(t, http.StatusOK, restore(t, server))
+		dashSvc.AssertNumberOfCalls(t, "SaveDashboard", 1)
+	})
+
+	t.Run("Should not restore the dashboard into a folder the user cannot write to", func(t *testing.T) {
+		server, dashSvc := setup(t, false)
+
+		assert.Equal(t, http.StatusForbidden, restore(t, server))
+		dashSvc.AssertNotCalled(t, "SaveDashboard", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func getDashboardShouldReturn200WithConfig(t *testing.T, sc *scenarioContext, provisioningService provisioning.ProvisioningService, dashboardStore dashboards.Store, dashboardService dashboards.DashboardService) dtos.DashboardFullWithMeta {
 	t.Helper()
 
@@ -1033,9 +1119,10 @@ func getDashboardShouldReturn200WithConfig(t *testing.T, sc *scenarioContext, pr
 			cfg, dashboardStore, nil, features,
 			folderPermissions, dashboardPermissions, ac,
 		),
-		DashboardService: dashboardService,
-		Features:         featuremgmt.WithFeatures(),
-		Kinds:            corekind.NewBase(nil),
+		DashboardService:      dashboardService,
+		Features:              featuremgmt.WithFeatures(),
+		Kinds:                 corekind.NewBase(nil),
+		dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 	}
 
 	hs.callGetDashboard(sc)
@@ -1101,6 +1188,7 @@ func postDashboardScenario(t *testing.T, desc string, url string, routePattern s
 			Features:              featuremgmt.WithFeatures(),
 			Kinds:                 corekind.NewBase(nil),
 			accesscontrolService:  actest.FakeService{},
+			dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -1133,6 +1221,7 @@ func postValidateScenario(t *testing.T, desc string, url string, routePattern st
 			SQLStore:              sqlmock,
 			Features:              featuremgmt.WithFeatures(),
 			Kinds:                 corekind.NewBase(nil),
+			dashboardUsageService: dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -1173,6 +1262,7 @@ func postDiffScenario(t *testing.T, desc string, url string, routePattern string
 			Features:                featuremgmt.WithFeatures(),
 			Kinds:                   corekind.NewBase(nil),
 			DashboardService:        dashSvc,
+			dashboardUsageService:   dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -1213,6 +1303,7 @@ func restoreDashboardVersionScenario(t *testing.T, desc string, url string, rout
 			dashboardVersionService: fakeDashboardVersionService,
 			Kinds:                   corekind.NewBase(nil),
 			accesscontrolService:    actest.FakeService{},
+			dashboardUsageService:   dashboardusagetest.NewDashboardUsageServiceFake(),
 		}
 
 		sc := setupScenarioContext(t, url)
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/apierrors"
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /dashboards/trash dashboards getTrashedDashboards
+//
+// Get deleted dashboards.
+//
+// Returns the dashboards and folders of the current organization which have been deleted and can still be restored.
+//
+// Responses:
+// 200: getTrashedDashboardsResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetTrashedDashboards(c *models.ReqContext) response.Response {
+	trashed, err := hs.DashboardService.GetTrashedDashboards(c.Req.Context(), &dashboards.GetTrashedDashboardsQuery{OrgID: c.OrgID})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get deleted dashboards", err)
+	}
+
+	result := make([]dtos.TrashedDashboard, 0, len(trashed))
+	for _, t := range trashed {
+		result = append(result, dtos.TrashedDashboard{
+			UID:       t.UID,
+			Title:     t.Title,
+			IsFolder:  t.IsFolder,
+			FolderUID: t.FolderUID,
+			Deleted:   t.Deleted,
+			DeletedBy: hs.getUserLogin(c.Req.Context(), t.DeletedBy),
+			Expires:   t.Deleted.Add(hs.Cfg.DashboardTrashRetention),
+		})
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
+
+// swagger:route POST /dashboards/trash/{uid}/restore dashboards restoreTrashedDashboard
+//
+// Restore a deleted dashboard.
+//
+// Restores the dashboard with its versions and permissions. A folder is restored together with its dashboards,
+// the alert rules of the folder are deleted with it and are not restored.
+// The dashboard is restored into its original folder unless another folder is given, and into the General folder
+// when the original folder does not exist anymore.
+//
+// Responses:
+// 200: restoreTrashedDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 412: preconditionFailedError
+// 500: internalServerError
+func (hs *HTTPServer) RestoreTrashedDashboard(c *models.ReqContext) response.Response {
+	cmd := dtos.RestoreTrashedDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, err := hs.DashboardService.RestoreTrashedDashboard(c.Req.Context(), &dashboards.RestoreTrashedDashboardCommand{
+		OrgID:     c.OrgID,
+		UID:       web.Params(c.Req)[":uid"],
+		FolderUID: cmd.FolderUID,
+	})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, err)
+	}
+
+	return response.JSON(http.StatusOK, util.DynMap{
+		"id":      dash.Id,
+		"uid":     dash.Uid,
+		"title":   dash.Title,
+		"url":     models.GetDashboardFolderUrl(dash.IsFolder, dash.Uid, dash.Slug),
+		"message": "Dashboard restored",
+	})
+}
+
+// swagger:route DELETE /dashboards/trash/{uid} dashboards purgeTrashedDashboard
+//
+// Permanently delete a deleted dashboard.
+//
+// Removes the dashboard from the trash, it can not be restored afterwards.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) PurgeTrashedDashboard(c *models.ReqContext) response.Response {
+	err := hs.DashboardService.PurgeTrashedDashboard(c.Req.Context(), &dashboards.PurgeTrashedDashboardCommand{
+		OrgID: c.OrgID,
+		UID:   web.Params(c.Req)[":uid"],
+	})
+	if err != nil {
+		return apierrors.ToDashboardErrorResponse(c.Req.Context(), hs.pluginStore, err)
+	}
+
+	return response.Success("Dashboard permanently deleted")
+}
+
+// swagger:parameters restoreTrashedDashboard
+type RestoreTrashedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dtos.RestoreTrashedDashboardCommand
+}
+
+// swagger:parameters purgeTrashedDashboard
+type PurgeTrashedDashboardParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
+// swagger:response getTrashedDashboardsResponse
+type GetTrashedDashboardsResponse struct {
+	// in: body
+	Body []dtos.TrashedDashboard `json:"body"`
+}
+
+// swagger:response restoreTrashedDashboardResponse
+type RestoreTrashedDashboardResponse struct {
+	// in: body
+	Body struct {
+		// ID Identifier of the restored dashboard.
+		// required: true
+		// example: 65
+		ID int64 `json:"id"`
+
+		// UID Identifier of the restored dashboard.
+		// required: true
+		// example: jHBvx3Gnk
+		UID string `json:"uid"`
+
+		// Title Title of the restored dashboard.
+		// required: true
+		// example: My Dashboard
+		Title string `json:"title"`
+
+		// URL URL of the restored dashboard.
+		// required: true
+		// example: /d/jHBvx3Gnk/my-dashboard
+		URL string `json:"url"`
+
+		// Message Message of the restored dashboard.
+		// required: true
+		// example: Dashboard restored
+		Message string `json:"message"`
+	} `json:"body"`
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestDashboardTrashAPIEndpoint(t *testing.T) {
+	setupServer := func(t *testing.T, dashSvc *dashboards.FakeDashboardService) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.Cfg = setting.NewCfg()
+			hs.Cfg.RBACEnabled = false
+			hs.Cfg.DashboardTrashRetention = 24 * time.Hour
+			hs.DashboardService = dashSvc
+			hs.userService = &usertest.FakeUserService{ExpectedUser: &user.User{Login: "editor"}}
+			hs.pluginStore = &plugins.FakePluginStore{}
+		})
+	}
+
+	t.Run("Should list the trashed dashboards of the organization", func(t *testing.T) {
+		deleted := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("GetTrashedDashboards", mock.Anything, &dashboards.GetTrashedDashboardsQuery{OrgID: 1}).Return([]*dashboards.TrashedDashboard{
+			{UID: "dash", Title: "Production", FolderUID: "folder", Deleted: deleted, DeletedBy: 2},
+		}, nil)
+
+		server := setupServer(t, dashSvc)
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/trash"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body []dtos.TrashedDashboard
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		require.Len(t, body, 1)
+		assert.Equal(t, "dash", body[0].UID)
+		assert.Equal(t, "folder", body[0].FolderUID)
+		assert.Equal(t, "editor", body[0].DeletedBy)
+		assert.Equal(t, deleted.Add(24*time.Hour), body[0].Expires)
+	})
+
+	t.Run("Should not allow editors to access the trash", func(t *testing.T) {
+		server := setupServer(t, dashboards.NewFakeDashboardService(t))
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/trash"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("Should restore a trashed dashboard into the given folder", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("RestoreTrashedDashboard", mock.Anything, &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: "dash", FolderUID: "other"}).
+			Return(&models.Dashboard{Id: 5, Uid: "dash", Title: "Production", Slug: "production"}, nil)
+
+		server := setupServer(t, dashSvc)
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/trash/dash/restore", strings.NewReader(`{"folderUid": "other"}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "/d/dash/production", body["url"])
+	})
+
+	t.Run("Should return 404 when restoring a dashboard which is not in the trash", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("RestoreTrashedDashboard", mock.Anything, mock.AnythingOfType("*dashboards.RestoreTrashedDashboardCommand")).
+			Return(nil, dashboards.ErrTrashedDashboardNotFound)
+
+		server := setupServer(t, dashSvc)
+		req := server.NewRequest(http.MethodPost, "/api/dashboards/trash/dash/restore", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Should purge a trashed dashboard", func(t *testing.T) {
+		dashSvc := dashboards.NewFakeDashboardService(t)
+		dashSvc.On("PurgeTrashedDashboard", mock.Anything, &dashboards.PurgeTrashedDashboardCommand{OrgID: 1, UID: "dash"}).Return(nil)
+
+		server := setupServer(t, dashSvc)
+		req := webtest.RequestWithSignedInUser(server.NewRequest(http.MethodDelete, "/api/dashboards/trash/dash", nil), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+}
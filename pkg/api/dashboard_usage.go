@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+const defaultDashboardUsageDays = 30
+
+// swagger:route GET /dashboards/uid/{uid}/usage dashboards getDashboardUsageByUID
+//
+// Get the usage of a dashboard.
+//
+// Returns the views and panel queries of the dashboard per day and per user.
+// Usage is written to the database every minute.
+//
+// Responses:
+// 200: dashboardUsageResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardUsage(c *models.ReqContext) response.Response {
+	days := c.QueryInt("days")
+	if days == 0 {
+		days = defaultDashboardUsageDays
+	}
+	if days < 0 {
+		return response.Error(http.StatusBadRequest, "days must be a positive number", nil)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	to := time.Now()
+	usage, err := hs.dashboardUsageService.GetDashboardUsage(c.Req.Context(), &dashboardusage.GetDashboardUsageQuery{
+		OrgID:        c.OrgID,
+		DashboardUID: dash.Uid,
+		From:         to.AddDate(0, 0, -days),
+		To:           to,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get dashboard usage", err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
+
+// swagger:parameters getDashboardUsageByUID
+type GetDashboardUsageByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// Number of days of usage to return
+	// in:query
+	// required:false
+	// default:30
+	Days int `json:"days"`
+}
+
+// swagger:response dashboardUsageResponse
+type DashboardUsageResponse struct {
+	// in: body
+	Body *dashboardusage.DashboardUsage `json:"body"`
+}
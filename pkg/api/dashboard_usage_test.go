@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusagetest"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+func TestGetDashboardUsage(t *testing.T) {
+	origNewGuardian := guardian.New
+	origNewGuardianByUID := guardian.NewByUID
+	origNewGuardianByDashboard := guardian.NewByDashboard
+	t.Cleanup(func() {
+		guardian.New = origNewGuardian
+		guardian.NewByUID = origNewGuardianByUID
+		guardian.NewByDashboard = origNewGuardianByDashboard
+	})
+
+	dashSvc := dashboards.NewFakeDashboardService(t)
+	dashSvc.On("GetDashboard", mock.Anything, mock.AnythingOfType("*models.GetDashboardQuery")).Run(func(args mock.Arguments) {
+		q := args.Get(1).(*models.GetDashboardQuery)
+		q.Result = &models.Dashboard{Id: 1, Uid: q.Uid, OrgId: q.OrgId}
+	}).Return(nil).Maybe()
+
+	usageSvc := dashboardusagetest.NewDashboardUsageServiceFake()
+	usageSvc.ExpectedDashboardUsage = &dashboardusage.DashboardUsage{
+		DashboardUID: "dash",
+		Views:        10,
+		Queries:      42,
+		Viewers:      2,
+	}
+
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.DashboardService = dashSvc
+		hs.dashboardUsageService = usageSvc
+	})
+
+	t.Run("Should return the usage of a dashboard the user can save", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+		req := server.NewGetRequest("/api/dashboards/uid/dash/usage?days=7")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		body := dashboardusage.DashboardUsage{}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		assert.Equal(t, "dash", body.DashboardUID)
+		assert.Equal(t, int64(10), body.Views)
+		assert.Equal(t, int64(42), body.Queries)
+		assert.Equal(t, int64(2), body.Viewers)
+	})
+
+	t.Run("Should return 403 when the user can not save the dashboard", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: false})
+
+		req := server.NewGetRequest("/api/dashboards/uid/dash/usage")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("Should return 400 for a negative number of days", func(t *testing.T) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanSaveValue: true})
+
+		req := server.NewGetRequest("/api/dashboards/uid/dash/usage?days=-1")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
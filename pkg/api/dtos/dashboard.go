@@ -3,6 +3,7 @@ package dtos
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -77,4 +78,30 @@ type CalculateDiffTarget struct {
 
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
+	// FolderUID of the folder to restore the dashboard into, it stays in its current folder when empty.
+	FolderUID string `json:"folderUid"`
+}
+
+type PatchDashboardCommand struct {
+	// Version of the dashboard the patch was written against, the patch is
+	// rejected when the dashboard has been saved since.
+	Version int                 `json:"version" binding:"Required"`
+	Patch   dashdiffs.JSONPatch `json:"patch" binding:"Required"`
+	Message string              `json:"message"`
+}
+
+type TrashedDashboard struct {
+	UID       string    `json:"uid"`
+	Title     string    `json:"title"`
+	IsFolder  bool      `json:"isFolder"`
+	FolderUID string    `json:"folderUid"`
+	Deleted   time.Time `json:"deleted"`
+	DeletedBy string    `json:"deletedBy"`
+	// Expires is when the dashboard is permanently deleted.
+	Expires time.Time `json:"expires"`
+}
+
+type RestoreTrashedDashboardCommand struct {
+	// FolderUID of the folder to restore the dashboard into, it is restored into its original folder when empty.
+	FolderUID string `json:"folderUid"`
 }
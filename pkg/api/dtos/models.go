@@ -68,6 +68,10 @@ type MetricRequest struct {
 	// required: true
 	// example: [ { "refId": "A", "intervalMs": 86400000, "maxDataPoints": 1092, "datasource":{ "uid":"PD8C576611E62080A" }, "rawSql": "SELECT 1 as valueOne, 2 as valueTwo", "format": "table" } ]
 	Queries []*simplejson.Json `json:"queries"`
+	// transformations – Specifies dashboard panel transformations to apply to the frames of all queries. Supports joinByField, groupBy, organize and filterByValue.
+	// required: false
+	// example: [ { "id": "organize", "options": { "excludeByName": { "Time": true } } } ]
+	Transformations []*simplejson.Json `json:"transformations"`
 	// required: false
 	Debug bool `json:"debug"`
 
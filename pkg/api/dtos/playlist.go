@@ -1,5 +1,7 @@
 package dtos
 
+import "github.com/grafana/grafana/pkg/services/playlist"
+
 type PlaylistDashboard struct {
 	Id    int64  `json:"id"`
 	Slug  string `json:"slug"`
@@ -22,3 +24,25 @@ func (slice PlaylistDashboardsSlice) Less(i, j int) bool {
 func (slice PlaylistDashboardsSlice) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
+
+// ResolvedPlaylist is a playlist resolved into the dashboards it currently shows
+type ResolvedPlaylist struct {
+	Uid      string                 `json:"uid"`
+	Name     string                 `json:"name"`
+	Interval string                 `json:"interval"`
+	Items    []ResolvedPlaylistItem `json:"items"`
+}
+
+// ResolvedPlaylistItem is a single dashboard of a resolved playlist, with the
+// settings of the playlist item it was resolved from
+type ResolvedPlaylistItem struct {
+	DashboardUid string                          `json:"dashboardUid"`
+	Title        string                          `json:"title"`
+	Url          string                          `json:"url"`
+	ItemIndex    int                             `json:"itemIndex"`
+	ItemType     string                          `json:"itemType"`
+	Interval     string                          `json:"interval"`
+	TimeFrom     string                          `json:"timeFrom,omitempty"`
+	TimeTo       string                          `json:"timeTo,omitempty"`
+	Variables    []playlist.PlaylistItemVariable `json:"variables,omitempty"`
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -56,9 +58,30 @@ func (hs *HTTPServer) QueryMetricsV2(c *models.ReqContext) response.Response {
 	if err != nil {
 		return hs.handleQueryMetricsError(err)
 	}
+
+	hs.recordDashboardQueries(c, int64(len(reqDTO.Queries)))
+
 	return hs.toJsonStreamingResponse(resp)
 }
 
+// recordDashboardQueries counts the queries in the usage of the dashboard sent by the panels,
+// the header is set by the client so it is only trusted for a dashboard the user can view
+func (hs *HTTPServer) recordDashboardQueries(c *models.ReqContext, count int64) {
+	dashboardUID := c.Req.Header.Get(query.HeaderDashboardUID)
+	if dashboardUID == "" || !hs.Cfg.DashboardUsageEnabled {
+		return
+	}
+
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return
+	}
+	if canView, err := g.CanView(); err != nil || !canView {
+		return
+	}
+	hs.dashboardUsageService.RecordQueries(c.Req.Context(), c.OrgID, dashboardUID, c.UserID, count)
+}
+
 func (hs *HTTPServer) toJsonStreamingResponse(qdr *backend.QueryDataResponse) response.Response {
 	statusWhenError := http.StatusBadRequest
 	if hs.Features.IsEnabled(featuremgmt.FlagDatasourceQueryMultiStatus) {
@@ -19,8 +19,10 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/config"
 	pluginClient "github.com/grafana/grafana/pkg/plugins/manager/client"
 	"github.com/grafana/grafana/pkg/plugins/manager/registry"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusagetest"
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/guardian"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
@@ -61,6 +63,8 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 				return &backend.QueryDataResponse{Responses: resp}, nil
 			},
 		},
+		nil,
+		nil,
 	)
 	serverFeatureEnabled := SetupAPITestServer(t, func(hs *HTTPServer) {
 		hs.queryDataService = qds
@@ -92,6 +96,58 @@ func TestAPIEndpoint_Metrics_QueryMetricsV2(t *testing.T) {
 	})
 }
 
+func TestAPIEndpoint_Metrics_QueryMetricsV2_DashboardUsage(t *testing.T) {
+	qds := query.ProvideService(
+		setting.NewCfg(),
+		nil,
+		nil,
+		&fakePluginRequestValidator{},
+		&fakeDatasources.FakeDataSourceService{},
+		&fakePluginClient{
+			QueryDataHandlerFunc: func(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+				return &backend.QueryDataResponse{Responses: backend.Responses{"A": backend.DataResponse{}, "B": backend.DataResponse{}}}, nil
+			},
+		},
+		nil,
+		nil,
+	)
+	usageService := dashboardusagetest.NewDashboardUsageServiceFake()
+	server := SetupAPITestServer(t, func(hs *HTTPServer) {
+		hs.Cfg = setting.NewCfg()
+		hs.Cfg.RBACEnabled = false
+		hs.Cfg.DashboardUsageEnabled = true
+		hs.queryDataService = qds
+		hs.dashboardUsageService = usageService
+		hs.QuotaService = quotatest.New(false, nil)
+	})
+
+	origNewByUID := guardian.NewByUID
+	t.Cleanup(func() {
+		guardian.NewByUID = origNewByUID
+	})
+
+	sendQueries := func(t *testing.T, canView bool) {
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: canView})
+		req := server.NewPostRequest("/api/ds/query", strings.NewReader(reqTwoQueries))
+		req.Header.Set(query.HeaderDashboardUID, "dash")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+		resp, err := server.SendJSON(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	t.Run("Counts every query of a dashboard the user can view", func(t *testing.T) {
+		sendQueries(t, true)
+		require.Equal(t, int64(2), usageService.Queries["dash"])
+	})
+
+	t.Run("Does not count the queries of a dashboard the user cannot view", func(t *testing.T) {
+		sendQueries(t, false)
+		require.Equal(t, int64(2), usageService.Queries["dash"])
+	})
+}
+
 func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
 	qds := query.ProvideService(
 		setting.NewCfg(),
@@ -109,6 +165,8 @@ func TestAPIEndpoint_Metrics_PluginDecryptionFailure(t *testing.T) {
 				return &backend.QueryDataResponse{Responses: resp}, nil
 			},
 		},
+		nil,
+		nil,
 	)
 	httpServer := SetupAPITestServer(t, func(hs *HTTPServer) {
 		hs.queryDataService = qds
@@ -148,6 +206,29 @@ var reqValid = `{
 	]
 }`
 
+var reqTwoQueries = `{
+	"from": "",
+	"to": "",
+	"queries": [
+		{
+			"datasource": {
+				"type": "datasource",
+				"uid": "grafana"
+			},
+			"queryType": "randomWalk",
+			"refId": "A"
+		},
+		{
+			"datasource": {
+				"type": "datasource",
+				"uid": "grafana"
+			},
+			"queryType": "randomWalk",
+			"refId": "B"
+		}
+	]
+}`
+
 var reqNoQueries = `{
 	"from": "",
 	"to": "",
@@ -262,6 +343,8 @@ func TestDataSourceQueryError(t *testing.T) {
 					&fakePluginRequestValidator{},
 					&fakeDatasources.FakeDataSourceService{},
 					pluginClient.ProvideService(r, &config.Cfg{}),
+					nil,
+					nil,
 				)
 				hs.QuotaService = quotatest.New(false, nil)
 			})
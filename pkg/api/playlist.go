@@ -125,6 +125,30 @@ func (hs *HTTPServer) GetPlaylistDashboards(c *models.ReqContext) response.Respo
 	return response.JSON(http.StatusOK, playlists)
 }
 
+// swagger:route GET /playlists/{uid}/resolve playlists getResolvedPlaylist
+//
+// Resolve playlist.
+//
+// Returns the dashboards currently shown by the playlist, in order. Every playlist item is resolved
+// to the dashboards it matches, with the item's interval, time range and variables applied.
+//
+// Responses:
+// 200: getResolvedPlaylistResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetResolvedPlaylist(c *models.ReqContext) response.Response {
+	playlistUID := web.Params(c.Req)[":uid"]
+
+	resolved, err := hs.ResolvePlaylist(c.Req.Context(), c.OrgID, c.SignedInUser, playlistUID)
+	if err != nil {
+		return response.Error(500, "Could not resolve playlist", err)
+	}
+
+	return response.JSON(http.StatusOK, resolved)
+}
+
 // swagger:route DELETE /playlists/{uid} playlists deletePlaylist
 //
 // Delete playlist.
@@ -161,6 +185,9 @@ func (hs *HTTPServer) CreatePlaylist(c *models.ReqContext) response.Response {
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
+	if err := validatePlaylistItems(cmd.Items); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
 	cmd.OrgId = c.OrgID
 
 	p, err := hs.playlistService.Create(c.Req.Context(), &cmd)
@@ -186,6 +213,9 @@ func (hs *HTTPServer) UpdatePlaylist(c *models.ReqContext) response.Response {
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
+	if err := validatePlaylistItems(cmd.Items); err != nil {
+		return response.Error(http.StatusBadRequest, err.Error(), err)
+	}
 	cmd.OrgId = c.OrgID
 	cmd.UID = web.Params(c.Req)[":uid"]
 
@@ -235,6 +265,13 @@ type GetPlaylistDashboardsParams struct {
 	UID string `json:"uid"`
 }
 
+// swagger:parameters getResolvedPlaylist
+type GetResolvedPlaylistParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+}
+
 // swagger:parameters deletePlaylist
 type DeletePlaylistParams struct {
 	// in:path
@@ -287,6 +324,13 @@ type GetPlaylistDashboardsResponse struct {
 	Body dtos.PlaylistDashboardsSlice `json:"body"`
 }
 
+// swagger:response getResolvedPlaylistResponse
+type GetResolvedPlaylistResponse struct {
+	// The response message
+	// in: body
+	Body *dtos.ResolvedPlaylist `json:"body"`
+}
+
 // swagger:response updatePlaylistResponse
 type UpdatePlaylistResponse struct {
 	// The response message
@@ -2,15 +2,21 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"sort"
 	"strconv"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	_ "github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins/adapters"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -106,3 +112,203 @@ func (hs *HTTPServer) LoadPlaylistDashboards(ctx context.Context, orgID int64, s
 	sort.Sort(result)
 	return result, nil
 }
+
+// playlistSearchLimit is the maximum number of dashboards a single playlist item resolves to
+const playlistSearchLimit = 1000
+
+// ResolvePlaylist resolves the items of a playlist into the dashboards they currently
+// match, in the order they are shown, with the per item overrides applied.
+func (hs *HTTPServer) ResolvePlaylist(ctx context.Context, orgID int64, signedInUser *user.SignedInUser, playlistUID string) (*dtos.ResolvedPlaylist, error) {
+	dto, err := hs.playlistService.Get(ctx,
+		&playlist.GetPlaylistByUidQuery{UID: playlistUID, OrgId: orgID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.ResolvedPlaylist{
+		Uid:      dto.Uid,
+		Name:     dto.Name,
+		Interval: dto.Interval,
+		Items:    make([]dtos.ResolvedPlaylistItem, 0),
+	}
+	if dto.Items == nil {
+		return result, nil
+	}
+
+	for i, item := range *dto.Items {
+		resolved, err := hs.resolvePlaylistItem(ctx, orgID, signedInUser, item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve playlist item %d: %w", i, err)
+		}
+
+		for _, dash := range resolved {
+			dash.ItemIndex = i
+			dash.ItemType = string(item.Type)
+			dash.Interval = dto.Interval
+			if item.Interval != nil && *item.Interval != "" {
+				dash.Interval = *item.Interval
+			}
+			if item.TimeFrom != nil {
+				dash.TimeFrom = *item.TimeFrom
+			}
+			if item.TimeTo != nil {
+				dash.TimeTo = *item.TimeTo
+			}
+			if item.Variables != nil {
+				dash.Variables = *item.Variables
+			}
+			result.Items = append(result.Items, dash)
+		}
+	}
+
+	return result, nil
+}
+
+func (hs *HTTPServer) resolvePlaylistItem(ctx context.Context, orgID int64, signedInUser *user.SignedInUser, item playlist.PlaylistItemDTO) ([]dtos.ResolvedPlaylistItem, error) {
+	query := &search.Query{
+		OrgId:        orgID,
+		SignedInUser: signedInUser,
+	}
+
+	switch item.Type {
+	case playlist.PlaylistItemTypeDashboardById:
+		dashboardID, err := strconv.ParseInt(item.Value, 10, 64)
+		if err != nil {
+			// matches nothing, same as the deprecated dashboards API
+			return nil, nil
+		}
+		query.DashboardIds = []int64{dashboardID}
+	case playlist.PlaylistItemTypeDashboardByUid:
+		query.DashboardUIDs = []string{item.Value}
+	case playlist.PlaylistItemTypeDashboardByTag:
+		query.Tags = []string{item.Value}
+	case playlist.PlaylistItemTypeDashboardBySearch:
+		return hs.resolvePlaylistSearchItem(ctx, orgID, signedInUser, item.Value)
+	default:
+		return nil, nil
+	}
+
+	return hs.searchPlaylistDashboards(ctx, query)
+}
+
+// resolvePlaylistSearchItem runs the saved search query of a dashboard_by_search item. The
+// legacy search is used when the search service is disabled.
+func (hs *HTTPServer) resolvePlaylistSearchItem(ctx context.Context, orgID int64, signedInUser *user.SignedInUser, value string) ([]dtos.ResolvedPlaylistItem, error) {
+	q, err := parsePlaylistSearchQuery(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if hs.searchV2Service.IsDisabled() {
+		query := &search.Query{
+			Tags:          q.Tags,
+			Sort:          q.Sort,
+			DashboardUIDs: q.UIDs,
+			Limit:         int64(q.Limit),
+			OrgId:         orgID,
+			SignedInUser:  signedInUser,
+		}
+		if q.Query != "*" {
+			query.Title = q.Query
+		}
+
+		switch q.Location {
+		case "":
+		case folder.GeneralFolderUID:
+			query.FolderIds = []int64{0}
+		default:
+			f, err := hs.folderService.Get(ctx, &folder.GetFolderQuery{OrgID: orgID, UID: &q.Location, SignedInUser: signedInUser})
+			if err != nil {
+				if errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, dashboards.ErrFolderAccessDenied) {
+					return nil, nil
+				}
+				return nil, err
+			}
+			query.FolderIds = []int64{f.ID}
+		}
+
+		return hs.searchPlaylistDashboards(ctx, query)
+	}
+
+	rsp := hs.searchV2Service.DoDashboardQuery(ctx, adapters.BackendUserFromSignedInUser(signedInUser), orgID, q)
+	if rsp.Error != nil {
+		return nil, rsp.Error
+	}
+
+	result := make([]dtos.ResolvedPlaylistItem, 0)
+	if len(rsp.Frames) == 0 {
+		return result, nil
+	}
+
+	frame := rsp.Frames[0]
+	uidField, uidIdx := frame.FieldByName("uid")
+	nameField, nameIdx := frame.FieldByName("name")
+	urlField, urlIdx := frame.FieldByName("url")
+	if uidIdx == -1 || nameIdx == -1 || urlIdx == -1 {
+		return nil, errors.New("unexpected search response")
+	}
+
+	for i := 0; i < uidField.Len(); i++ {
+		uid, _ := uidField.At(i).(string)
+		name, _ := nameField.At(i).(string)
+		url, _ := urlField.At(i).(string)
+		result = append(result, dtos.ResolvedPlaylistItem{
+			DashboardUid: uid,
+			Title:        name,
+			Url:          url,
+		})
+	}
+
+	return result, nil
+}
+
+func (hs *HTTPServer) searchPlaylistDashboards(ctx context.Context, query *search.Query) ([]dtos.ResolvedPlaylistItem, error) {
+	query.Type = string(models.DashHitDB)
+	if query.Limit <= 0 || query.Limit > playlistSearchLimit {
+		query.Limit = playlistSearchLimit
+	}
+
+	if err := hs.SearchService.SearchHandler(ctx, query); err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.ResolvedPlaylistItem, 0, len(query.Result))
+	for _, hit := range query.Result {
+		result = append(result, dtos.ResolvedPlaylistItem{
+			DashboardUid: hit.UID,
+			Title:        hit.Title,
+			Url:          hit.URL,
+		})
+	}
+
+	return result, nil
+}
+
+// validatePlaylistItems checks that the saved search queries of the playlist items can be read
+func validatePlaylistItems(items []playlist.PlaylistItem) error {
+	for i, item := range items {
+		if item.Type != string(playlist.PlaylistItemTypeDashboardBySearch) {
+			continue
+		}
+		if _, err := parsePlaylistSearchQuery(item.Value); err != nil {
+			return fmt.Errorf("playlist item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// parsePlaylistSearchQuery reads the saved search query of a dashboard_by_search item.
+// Only dashboards are returned, whatever kind the query asks for.
+func parsePlaylistSearchQuery(value string) (searchV2.DashboardQuery, error) {
+	q := searchV2.DashboardQuery{}
+	if err := json.Unmarshal([]byte(value), &q); err != nil {
+		return q, fmt.Errorf("invalid search query: %w", err)
+	}
+
+	q.Kind = []string{models.StandardKindDashboard}
+	if q.Limit <= 0 || q.Limit > playlistSearchLimit {
+		q.Limit = playlistSearchLimit
+	}
+
+	return q, nil
+}
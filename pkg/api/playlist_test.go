@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/playlist"
+	"github.com/grafana/grafana/pkg/services/playlist/playlistimpl"
+	"github.com/grafana/grafana/pkg/services/search"
+	"github.com/grafana/grafana/pkg/services/searchV2"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web/webtest"
+)
+
+type fakePlaylistSearchService struct {
+	queries []search.Query
+	results map[string]models.HitList
+}
+
+// SearchHandler returns the hits registered for the tag, dashboard uid or title of the query
+func (f *fakePlaylistSearchService) SearchHandler(_ context.Context, q *search.Query) error {
+	f.queries = append(f.queries, *q)
+	for _, key := range append(append(q.Tags, q.DashboardUIDs...), q.Title) {
+		q.Result = append(q.Result, f.results[key]...)
+	}
+	return nil
+}
+
+func (f *fakePlaylistSearchService) SortOptions() []models.SortOption { return nil }
+
+func TestPlaylistAPIEndpoint(t *testing.T) {
+	interval := "30s"
+	timeFrom := "now-1h"
+	variables := []playlist.PlaylistItemVariable{{Name: "env", Values: []string{"prod"}}}
+
+	setup := func(t *testing.T, searchV2Service searchV2.SearchService) (*webtest.Server, *fakePlaylistSearchService, *playlist.Playlist) {
+		playlistService := playlistimpl.ProvideService(db.InitTestDB(t), featuremgmt.WithFeatures(), nil)
+		p, err := playlistService.Create(context.Background(), &playlist.CreatePlaylistCommand{
+			Name:     "TV wall",
+			Interval: "5m",
+			OrgId:    1,
+			Items: []playlist.PlaylistItem{
+				{Type: "dashboard_by_uid", Value: "home"},
+				{Type: "dashboard_by_search", Value: `{"location":"general","tags":["prod"]}`,
+					Interval: interval, TimeFrom: timeFrom, Variables: variables},
+				{Type: "dashboard_by_tag", Value: "ops"},
+			},
+		})
+		require.NoError(t, err)
+
+		searchService := &fakePlaylistSearchService{results: map[string]models.HitList{
+			"home": {{UID: "home", Title: "Home", URL: "/d/home/home"}},
+			"prod": {{UID: "a", Title: "A", URL: "/d/a/a"}, {UID: "b", Title: "B", URL: "/d/b/b"}},
+			"ops":  {{UID: "ops", Title: "Ops", URL: "/d/ops/ops"}},
+		}}
+
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.playlistService = playlistService
+			hs.SearchService = searchService
+			hs.searchV2Service = searchV2Service
+		})
+		return server, searchService, p
+	}
+
+	resolve := func(t *testing.T, server *webtest.Server, uid string) dtos.ResolvedPlaylist {
+		req := webtest.RequestWithSignedInUser(server.NewGetRequest("/api/playlists/"+uid+"/resolve"), &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleViewer})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, res.Body.Close()) }()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+
+		var body dtos.ResolvedPlaylist
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+		return body
+	}
+
+	t.Run("Should resolve search items with the legacy search when search is disabled", func(t *testing.T) {
+		server, searchService, p := setup(t, searchV2.NewStubSearchService())
+
+		body := resolve(t, server, p.UID)
+		assert.Equal(t, "5m", body.Interval)
+		require.Len(t, body.Items, 4)
+
+		uids := make([]string, 0, len(body.Items))
+		for _, item := range body.Items {
+			uids = append(uids, item.DashboardUid)
+		}
+		assert.Equal(t, []string{"home", "a", "b", "ops"}, uids)
+
+		assert.Equal(t, "5m", body.Items[0].Interval)
+		assert.Empty(t, body.Items[0].Variables)
+		assert.Equal(t, dtos.ResolvedPlaylistItem{
+			DashboardUid: "b",
+			Title:        "B",
+			Url:          "/d/b/b",
+			ItemIndex:    1,
+			ItemType:     "dashboard_by_search",
+			Interval:     interval,
+			TimeFrom:     timeFrom,
+			Variables:    variables,
+		}, body.Items[2])
+		assert.Equal(t, 2, body.Items[3].ItemIndex)
+
+		require.Len(t, searchService.queries, 3)
+		assert.Equal(t, []int64{0}, searchService.queries[1].FolderIds)
+		assert.Equal(t, string(models.DashHitDB), searchService.queries[1].Type)
+	})
+
+	t.Run("Should resolve search items with the search service", func(t *testing.T) {
+		searchV2Service := &searchV2.MockSearchService{}
+		searchV2Service.On("IsDisabled").Return(false)
+		searchV2Service.On("DoDashboardQuery", mock.Anything, mock.Anything, int64(1), searchV2.DashboardQuery{
+			Location: "general",
+			Tags:     []string{"prod"},
+			Kind:     []string{"dashboard"},
+			Limit:    1000,
+		}).Return(&backend.DataResponse{Frames: data.Frames{data.NewFrame("Query results",
+			data.NewField("uid", nil, []string{"c"}),
+			data.NewField("name", nil, []string{"C"}),
+			data.NewField("url", nil, []string{"/d/c/c"}),
+		)}})
+
+		server, _, p := setup(t, searchV2Service)
+
+		body := resolve(t, server, p.UID)
+		require.Len(t, body.Items, 3)
+		assert.Equal(t, "c", body.Items[1].DashboardUid)
+		assert.Equal(t, "C", body.Items[1].Title)
+		assert.Equal(t, "/d/c/c", body.Items[1].Url)
+		assert.Equal(t, interval, body.Items[1].Interval)
+		searchV2Service.AssertExpectations(t)
+	})
+
+	t.Run("Should not save search items with an invalid query", func(t *testing.T) {
+		server, _, _ := setup(t, searchV2.NewStubSearchService())
+
+		req := server.NewRequest(http.MethodPost, "/api/playlists", strings.NewReader(`{"name":"broken","interval":"5m","items":[{"type":"dashboard_by_search","value":"{tags"}]}`))
+		req.Header.Set("Content-Type", "application/json")
+		webtest.RequestWithSignedInUser(req, &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleEditor})
+		res, err := server.Send(req)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
@@ -53,6 +53,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashsnapstore "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
 	dashsnapsvc "github.com/grafana/grafana/pkg/services/dashboardsnapshots/service"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusageimpl"
 	"github.com/grafana/grafana/pkg/services/dashboardversion/dashverimpl"
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -66,6 +68,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/librarypanels"
 	"github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
 	"github.com/grafana/grafana/pkg/services/live/pushhttp"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/login/authinfoservice"
@@ -163,6 +166,8 @@ var wireSet = wire.NewSet(
 	wire.Bind(new(alerting.UsageStatsQuerier), new(*alerting.AlertEngine)),
 	api.ProvideHTTPServer,
 	query.ProvideService,
+	query.ProvideDataSourceQuerier,
+	wire.Bind(new(tempo.DataSourceQuerier), new(*query.DataSourceQuerier)),
 	thumbs.ProvideService,
 	rendering.ProvideService,
 	wire.Bind(new(rendering.Service), new(*rendering.RenderingService)),
@@ -190,6 +195,7 @@ var wireSet = wire.NewSet(
 	wire.Bind(new(queryhistory.Service), new(*queryhistory.QueryHistoryService)),
 	quotaimpl.ProvideService,
 	remotecache.ProvideService,
+	wire.Bind(new(remotecache.CacheStorage), new(*remotecache.RemoteCache)),
 	loginservice.ProvideService,
 	wire.Bind(new(login.Service), new(*loginservice.Implementation)),
 	authinfoservice.ProvideAuthInfoService,
@@ -205,6 +211,8 @@ var wireSet = wire.NewSet(
 	store.ProvideService,
 	export.ProvideService,
 	live.ProvideService,
+	managedstream.ProvideRedisClient,
+	managedstream.ProvideFrameHistory,
 	pushhttp.ProvideService,
 	contexthandler.ProvideService,
 	jwt.ProvideService,
@@ -283,6 +291,8 @@ var wireSet = wire.NewSet(
 	starimpl.ProvideService,
 	playlistimpl.ProvideService,
 	dashverimpl.ProvideService,
+	dashboardusageimpl.ProvideService,
+	wire.Bind(new(dashboardusage.Service), new(*dashboardusageimpl.Service)),
 	publicdashboardsService.ProvideService,
 	wire.Bind(new(publicdashboards.Service), new(*publicdashboardsService.PublicDashboardServiceImpl)),
 	publicdashboardsStore.ProvideStore,
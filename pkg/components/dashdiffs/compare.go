@@ -23,6 +23,7 @@ const (
 	DiffJSON DiffType = iota
 	DiffBasic
 	DiffDelta
+	DiffJSONPatch
 )
 
 type Options struct {
@@ -50,6 +51,8 @@ func ParseDiffType(diff string) DiffType {
 		return DiffBasic
 	case "delta":
 		return DiffDelta
+	case "jsonpatch":
+		return DiffJSONPatch
 	}
 	return DiffBasic
 }
@@ -57,6 +60,18 @@ func ParseDiffType(diff string) DiffType {
 // CompareDashboardVersionsCommand computes the JSON diff of two versions,
 // assigning the delta of the diff to the `Delta` field.
 func CalculateDiff(ctx context.Context, options *Options, baseData, newData *simplejson.Json) (*Result, error) {
+	if options.DiffType == DiffJSONPatch {
+		patch, err := CreateJSONPatch(baseData, newData)
+		if err != nil {
+			return nil, err
+		}
+		patchOutput, err := json.Marshal(patch)
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Delta: patchOutput}, nil
+	}
+
 	left, jsonDiff, err := getDiff(baseData, newData)
 	if err != nil {
 		return nil, err
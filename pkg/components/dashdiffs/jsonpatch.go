@@ -0,0 +1,377 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+var (
+	// ErrInvalidJSONPatch occurs when a JSON Patch operation is malformed or
+	// can not be applied to the document.
+	ErrInvalidJSONPatch = errors.New("dashdiff: invalid json patch")
+	// ErrJSONPatchTestFailed occurs when a `test` operation does not match
+	// the document.
+	ErrJSONPatchTestFailed = errors.New("dashdiff: json patch test failed")
+)
+
+// JSON Patch operations, see https://www.rfc-editor.org/rfc/rfc6902
+const (
+	JSONPatchOpAdd     = "add"
+	JSONPatchOpRemove  = "remove"
+	JSONPatchOpReplace = "replace"
+	JSONPatchOpMove    = "move"
+	JSONPatchOpCopy    = "copy"
+	JSONPatchOpTest    = "test"
+)
+
+// JSONPatch is an RFC 6902 JSON Patch document.
+type JSONPatch []JSONPatchOperation
+
+type JSONPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON only writes the members used by the operation, so that
+// `null` values of add, replace and test operations are kept.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{
+		"op":   o.Op,
+		"path": o.Path,
+	}
+	switch o.Op {
+	case JSONPatchOpAdd, JSONPatchOpReplace, JSONPatchOpTest:
+		out["value"] = o.Value
+	case JSONPatchOpMove, JSONPatchOpCopy:
+		out["from"] = o.From
+	}
+	return json.Marshal(out)
+}
+
+// CreateJSONPatch computes the JSON Patch which turns baseData into newData.
+// Arrays are compared index by index, an empty patch is returned when the
+// documents are the same.
+func CreateJSONPatch(baseData, newData *simplejson.Json) (JSONPatch, error) {
+	left, err := normalizeJSON(baseData)
+	if err != nil {
+		return nil, err
+	}
+	right, err := normalizeJSON(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := JSONPatch{}
+	return appendJSONPatch(patch, "", left, right), nil
+}
+
+func appendJSONPatch(patch JSONPatch, path string, left, right interface{}) JSONPatch {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(l) {
+			p := path + "/" + escapeJSONPointer(key)
+			if rv, ok := r[key]; ok {
+				patch = appendJSONPatch(patch, p, l[key], rv)
+			} else {
+				patch = append(patch, JSONPatchOperation{Op: JSONPatchOpRemove, Path: p})
+			}
+		}
+		for _, key := range sortedKeys(r) {
+			if _, ok := l[key]; !ok {
+				patch = append(patch, JSONPatchOperation{Op: JSONPatchOpAdd, Path: path + "/" + escapeJSONPointer(key), Value: r[key]})
+			}
+		}
+		return patch
+	case []interface{}:
+		r, ok := right.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(l) && i < len(r); i++ {
+			patch = appendJSONPatch(patch, path+"/"+strconv.Itoa(i), l[i], r[i])
+		}
+		for i := len(l); i < len(r); i++ {
+			patch = append(patch, JSONPatchOperation{Op: JSONPatchOpAdd, Path: path + "/" + strconv.Itoa(i), Value: r[i]})
+		}
+		// remove from the end, so the indexes of the remaining items do not change
+		for i := len(l) - 1; i >= len(r); i-- {
+			patch = append(patch, JSONPatchOperation{Op: JSONPatchOpRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+		return patch
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		patch = append(patch, JSONPatchOperation{Op: JSONPatchOpReplace, Path: path, Value: right})
+	}
+	return patch
+}
+
+// ApplyJSONPatch applies the operations of the patch in order and returns the
+// patched copy of data. Nothing is returned if any of the operations fails.
+func ApplyJSONPatch(data *simplejson.Json, patch JSONPatch) (*simplejson.Json, error) {
+	doc, err := normalizeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range patch {
+		doc, err = applyJSONPatchOperation(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+
+	return simplejson.NewFromAny(doc), nil
+}
+
+func applyJSONPatchOperation(doc interface{}, op JSONPatchOperation) (interface{}, error) {
+	path, err := parseJSONPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case JSONPatchOpAdd:
+		return addJSONValue(doc, path, op.Value)
+	case JSONPatchOpRemove:
+		doc, _, err = removeJSONValue(doc, path)
+		return doc, err
+	case JSONPatchOpReplace:
+		if _, err := getJSONValue(doc, path); err != nil {
+			return nil, err
+		}
+		return setJSONValue(doc, path, op.Value)
+	case JSONPatchOpMove:
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("%w: can not move %q into one of its children", ErrInvalidJSONPatch, op.From)
+		}
+		doc, value, err := removeJSONValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return addJSONValue(doc, path, value)
+	case JSONPatchOpCopy:
+		from, err := parseJSONPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := getJSONValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		value, err = copyJSONValue(value)
+		if err != nil {
+			return nil, err
+		}
+		return addJSONValue(doc, path, value)
+	case JSONPatchOpTest:
+		value, err := getJSONValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := copyJSONValue(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, expected) {
+			return nil, ErrJSONPatchTestFailed
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported operation %q", ErrInvalidJSONPatch, op.Op)
+}
+
+func getJSONValue(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q not found", ErrInvalidJSONPatch, token)
+			}
+			doc = value
+		case []interface{}:
+			i, err := parseJSONArrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%w: %q is not an object or an array", ErrInvalidJSONPatch, token)
+		}
+	}
+	return doc, nil
+}
+
+// updateJSONValue calls update with the parent of the last token of the path,
+// and stores the returned parent back into the document.
+func updateJSONValue(doc interface{}, path []string, update func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+
+	child, err := getJSONValue(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = updateJSONValue(child, path[1:], update)
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := doc.(type) {
+	case map[string]interface{}:
+		node[path[0]] = child
+	case []interface{}:
+		i, _ := parseJSONArrayIndex(path[0], len(node)-1)
+		node[i] = child
+	}
+	return doc, nil
+}
+
+func addJSONValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateJSONValue(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			if token == "-" {
+				return append(node, value), nil
+			}
+			i, err := parseJSONArrayIndex(token, len(node))
+			if err != nil {
+				return nil, err
+			}
+			node = append(node, nil)
+			copy(node[i+1:], node[i:])
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: can not add %q to a value which is not an object or an array", ErrInvalidJSONPatch, token)
+	})
+}
+
+func setJSONValue(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return updateJSONValue(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			node[token] = value
+			return node, nil
+		case []interface{}:
+			i, err := parseJSONArrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = value
+			return node, nil
+		}
+		return nil, fmt.Errorf("%w: %q is not an object or an array", ErrInvalidJSONPatch, token)
+	})
+}
+
+func removeJSONValue(doc interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: can not remove the whole document", ErrInvalidJSONPatch)
+	}
+	var removed interface{}
+	doc, err := updateJSONValue(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: member %q not found", ErrInvalidJSONPatch, token)
+			}
+			removed = value
+			delete(node, token)
+			return node, nil
+		case []interface{}:
+			i, err := parseJSONArrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = node[i]
+			return append(node[:i], node[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: %q is not an object or an array", ErrInvalidJSONPatch, token)
+	})
+	return doc, removed, err
+}
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidJSONPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func parseJSONArrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidJSONPatch, token)
+	}
+	return i, nil
+}
+
+// normalizeJSON returns a copy of the document made of the types decoded by
+// encoding/json, so that values can be compared with the ones of a patch.
+func normalizeJSON(data *simplejson.Json) (interface{}, error) {
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func copyJSONValue(value interface{}) (interface{}, error) {
+	return normalizeJSON(simplejson.NewFromAny(value))
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
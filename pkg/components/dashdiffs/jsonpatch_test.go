@@ -0,0 +1,159 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestCreateJSONPatch(t *testing.T) {
+	base := simplejson.MustJson([]byte(`{
+		"title": "Production",
+		"version": 3,
+		"tags": ["a", "b", "c"],
+		"panels": [
+			{"id": 1, "datasource": {"uid": "old"}},
+			{"id": 2, "thresholds": [80]}
+		],
+		"a/b": "escaped",
+		"removed": true
+	}`))
+	updated := simplejson.MustJson([]byte(`{
+		"title": "Production",
+		"version": 4,
+		"tags": ["a"],
+		"panels": [
+			{"id": 1, "datasource": {"uid": "new"}},
+			{"id": 2, "thresholds": [80, 90]}
+		],
+		"a/b": "changed",
+		"added": null
+	}`))
+
+	patch, err := CreateJSONPatch(base, updated)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(patch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/a~1b", "value": "changed"},
+		{"op": "replace", "path": "/panels/0/datasource/uid", "value": "new"},
+		{"op": "add", "path": "/panels/1/thresholds/1", "value": 90},
+		{"op": "remove", "path": "/removed"},
+		{"op": "remove", "path": "/tags/2"},
+		{"op": "remove", "path": "/tags/1"},
+		{"op": "replace", "path": "/version", "value": 4},
+		{"op": "add", "path": "/added", "value": null}
+	]`, string(out))
+
+	t.Run("applying the patch to the base returns the new document", func(t *testing.T) {
+		patched, err := ApplyJSONPatch(base, patch)
+		require.NoError(t, err)
+
+		expected, err := updated.Encode()
+		require.NoError(t, err)
+		actual, err := patched.Encode()
+		require.NoError(t, err)
+		assert.JSONEq(t, string(expected), string(actual))
+	})
+
+	t.Run("the patch of identical documents is empty", func(t *testing.T) {
+		patch, err := CreateJSONPatch(base, base)
+		require.NoError(t, err)
+		assert.Empty(t, patch)
+	})
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := `{"foo": "bar", "list": [1, 2, 3], "obj": {"a": {"b": 1}}}`
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected string
+		err      error
+	}{
+		{
+			name:     "add member",
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			expected: `{"foo": "bar", "baz": "qux", "list": [1, 2, 3], "obj": {"a": {"b": 1}}}`,
+		},
+		{
+			name:     "add array item",
+			patch:    `[{"op": "add", "path": "/list/1", "value": 9}, {"op": "add", "path": "/list/-", "value": 10}]`,
+			expected: `{"foo": "bar", "list": [1, 9, 2, 3, 10], "obj": {"a": {"b": 1}}}`,
+		},
+		{
+			name:     "remove array item",
+			patch:    `[{"op": "remove", "path": "/list/0"}]`,
+			expected: `{"foo": "bar", "list": [2, 3], "obj": {"a": {"b": 1}}}`,
+		},
+		{
+			name:     "replace nested member",
+			patch:    `[{"op": "replace", "path": "/obj/a/b", "value": {"c": true}}]`,
+			expected: `{"foo": "bar", "list": [1, 2, 3], "obj": {"a": {"b": {"c": true}}}}`,
+		},
+		{
+			name:     "move and copy",
+			patch:    `[{"op": "move", "from": "/foo", "path": "/obj/foo"}, {"op": "copy", "from": "/list", "path": "/copy"}]`,
+			expected: `{"list": [1, 2, 3], "copy": [1, 2, 3], "obj": {"a": {"b": 1}, "foo": "bar"}}`,
+		},
+		{
+			name:     "passing test",
+			patch:    `[{"op": "test", "path": "/list", "value": [1, 2, 3]}, {"op": "replace", "path": "/foo", "value": "baz"}]`,
+			expected: `{"foo": "baz", "list": [1, 2, 3], "obj": {"a": {"b": 1}}}`,
+		},
+		{
+			name:  "failing test",
+			patch: `[{"op": "replace", "path": "/foo", "value": "baz"}, {"op": "test", "path": "/foo", "value": "bar"}]`,
+			err:   ErrJSONPatchTestFailed,
+		},
+		{
+			name:  "replace missing member",
+			patch: `[{"op": "replace", "path": "/missing", "value": 1}]`,
+			err:   ErrInvalidJSONPatch,
+		},
+		{
+			name:  "array index out of range",
+			patch: `[{"op": "remove", "path": "/list/3"}]`,
+			err:   ErrInvalidJSONPatch,
+		},
+		{
+			name:  "move into a child",
+			patch: `[{"op": "move", "from": "/obj", "path": "/obj/a/c"}]`,
+			err:   ErrInvalidJSONPatch,
+		},
+		{
+			name:  "unsupported operation",
+			patch: `[{"op": "merge", "path": "/foo", "value": 1}]`,
+			err:   ErrInvalidJSONPatch,
+		},
+		{
+			name:  "path without leading slash",
+			patch: `[{"op": "add", "path": "foo", "value": 1}]`,
+			err:   ErrInvalidJSONPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch JSONPatch
+			require.NoError(t, json.Unmarshal([]byte(tt.patch), &patch))
+
+			patched, err := ApplyJSONPatch(simplejson.MustJson([]byte(doc)), patch)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			actual, err := patched.Encode()
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(actual))
+		})
+	}
+}
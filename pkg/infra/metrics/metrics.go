@@ -108,6 +108,10 @@ var (
 
 	// MPublicDashboardDatasourceQuerySuccess is a metric counter for successful queries labelled by datasource
 	MPublicDashboardDatasourceQuerySuccess *prometheus.CounterVec
+
+	// MPublicDashboardAccessCount is a metric counter for requests to public dashboards labelled by public dashboard,
+	// kind of request and status code
+	MPublicDashboardAccessCount *prometheus.CounterVec
 )
 
 // Timers
@@ -126,6 +130,10 @@ var (
 
 	// MAccessEvaluationsSummary is a metric summary for loading permissions request duration when evaluating access
 	MAccessEvaluationsSummary prometheus.Histogram
+
+	// MPublicDashboardQueryDuration is a metric histogram for the duration of public dashboard queries labelled by
+	// public dashboard
+	MPublicDashboardQueryDuration *prometheus.HistogramVec
 )
 
 // StatTotals
@@ -426,6 +434,19 @@ func init() {
 		Namespace: ExporterName,
 	}, []string{"datasource", "status"}, map[string][]string{"status": pubdash.QueryResultStatuses})
 
+	MPublicDashboardAccessCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:      "public_dashboard_access_count",
+		Help:      "counter for requests to public dashboards labelled by public dashboard uid, kind of request and status code",
+		Namespace: ExporterName,
+	}, []string{"public_dashboard_uid", "kind", "status"})
+
+	MPublicDashboardQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:      "public_dashboard_query_duration_seconds",
+		Help:      "histogram for the duration of public dashboard queries labelled by public dashboard uid",
+		Namespace: ExporterName,
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"public_dashboard_uid"})
+
 	MStatTotalDashboards = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name:      "stat_totals_dashboard",
 		Help:      "total amount of dashboards",
@@ -680,5 +701,7 @@ func initMetricVars() {
 		MStatTotalPublicDashboards,
 		MPublicDashboardRequestCount,
 		MPublicDashboardDatasourceQuerySuccess,
+		MPublicDashboardAccessCount,
+		MPublicDashboardQueryDuration,
 	)
 }
@@ -124,6 +124,13 @@ func createClient(opts *setting.RemoteCacheOptions, sqlstore db.DB, codec codec)
 	return cache, nil
 }
 
+// NewUnencryptedStorage creates a storage for the configured remote cache which doesn't encrypt items, even if
+// encryption is enabled. It's meant for services caching data that isn't sensitive, which can't depend on the
+// secrets service.
+func NewUnencryptedStorage(cfg *setting.Cfg, sqlStore db.DB) (CacheStorage, error) {
+	return createClient(cfg.RemoteCacheOptions, sqlStore, &gobCodec{})
+}
+
 // Register records a type, identified by a value for that type, under its
 // internal type name. That name will identify the concrete type of a value
 // sent or received as an interface variable. Only types that will be
@@ -14,6 +14,8 @@ package playlist
 const (
 	PlaylistItemTypeDashboardById PlaylistItemType = "dashboard_by_id"
 
+	PlaylistItemTypeDashboardBySearch PlaylistItemType = "dashboard_by_search"
+
 	PlaylistItemTypeDashboardByTag PlaylistItemType = "dashboard_by_tag"
 
 	PlaylistItemTypeDashboardByUid PlaylistItemType = "dashboard_by_uid"
@@ -39,6 +41,15 @@ type Playlist struct {
 
 // PlaylistItem defines model for playlist.Item.
 type PlaylistItem struct {
+	// Interval overrides the interval of the playlist for the dashboards of this item.
+	Interval *string `json:"interval,omitempty"`
+
+	// TimeFrom overrides the start of the time range of the dashboards of this item, e.g. now-6h.
+	TimeFrom *string `json:"timeFrom,omitempty"`
+
+	// TimeTo overrides the end of the time range of the dashboards of this item, e.g. now.
+	TimeTo *string `json:"timeTo,omitempty"`
+
 	// Title is an unused property -- it will be removed in the future
 	Title *string `json:"title,omitempty"`
 
@@ -53,8 +64,23 @@ type PlaylistItem struct {
 	//  - dashboard_by_tag: The value is a tag which is set on any number of dashboards. All
 	//  dashboards behind the tag will be added to the playlist.
 	//  - dashboard_by_uid: The value is the dashboard UID
+	//  - dashboard_by_search: The value is a JSON encoded search query, e.g. {"location": "<folder uid>"}
+	//  or {"tags": ["prod"], "sort": "name_sort"}. All dashboards matching the query when the playlist
+	//  is played will be added to the playlist, in the order of the search results.
 	Value string `json:"value"`
+
+	// Variables sets the values of template variables of the dashboards of this item.
+	Variables *[]PlaylistItemVariable `json:"variables,omitempty"`
 }
 
 // Type of the item.
 type PlaylistItemType string
+
+// PlaylistItemVariable defines model for playlist.ItemVariable.
+type PlaylistItemVariable struct {
+	// Name of the template variable.
+	Name string `json:"name"`
+
+	// Values of the template variable, more than one for multi-value variables.
+	Values []string `json:"values"`
+}
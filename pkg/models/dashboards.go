@@ -220,6 +220,9 @@ type DeleteDashboardCommand struct {
 	Id                     int64
 	OrgId                  int64
 	ForceDeleteFolderRules bool
+	// MoveToTrash keeps a copy of the dashboard which can be restored, when the trash is enabled
+	MoveToTrash bool
+	DeletedBy   int64
 }
 
 type DeleteOrphanedProvisionedDashboardsCommand struct {
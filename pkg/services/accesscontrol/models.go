@@ -404,6 +404,10 @@ const (
 	// Alerting provisioning actions
 	ActionAlertingProvisioningRead  = "alert.provisioning:read"
 	ActionAlertingProvisioningWrite = "alert.provisioning:write"
+
+	// Live actions
+	ActionLivePublish   = "live:publish"
+	ActionLiveSubscribe = "live:subscribe"
 )
 
 var (
@@ -417,8 +421,18 @@ var (
 	ScopeAnnotationsID               = Scope(ScopeAnnotationsRoot, "id", Parameter(":annotationId"))
 	ScopeAnnotationsTypeDashboard    = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Dashboard.String())
 	ScopeAnnotationsTypeOrganization = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Organization.String())
+
+	// Live scopes
+	ScopeLiveRoot       = "live"
+	ScopeLiveStreamsAll = Scope(ScopeLiveRoot, "stream/*")
 )
 
+// ScopeLiveChannel returns a scope for a Live channel, i.e. live:stream/factory/line1.
+// Channel wildcard scopes like live:stream/factory/* match all channels with a prefix.
+func ScopeLiveChannel(channel string) string {
+	return Scope(ScopeLiveRoot, channel)
+}
+
 func BuiltInRolesWithParents(builtInRoles []string) map[string]struct{} {
 	res := map[string]struct{}{}
 
@@ -18,9 +18,12 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -30,7 +33,9 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	publicDashboardService publicdashboards.Service, dashboardUsageService dashboardusage.Service,
+	dashboardService dashboards.DashboardService) *CleanUpService {
 	s := &CleanUpService{
 		Cfg:                       cfg,
 		ServerLockService:         serverLockService,
@@ -44,6 +49,9 @@ func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockSe
 		tempUserService:           tempUserService,
 		tracer:                    tracer,
 		annotationCleaner:         annotationCleaner,
+		publicDashboardService:    publicDashboardService,
+		dashboardUsageService:     dashboardUsageService,
+		dashboardService:          dashboardService,
 	}
 	return s
 }
@@ -61,6 +69,9 @@ type CleanUpService struct {
 	deleteExpiredImageService *image.DeleteExpiredService
 	tempUserService           tempuser.Service
 	annotationCleaner         annotations.Cleaner
+	publicDashboardService    publicdashboards.Service
+	dashboardUsageService     dashboardusage.Service
+	dashboardService          dashboards.DashboardService
 }
 
 type cleanUpJob struct {
@@ -103,6 +114,10 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
 		{"delete stale query history", srv.deleteStaleQueryHistory},
+		{"delete expired public dashboard access logs", srv.deleteExpiredPublicDashboardAccessLogs},
+		{"delete expired public dashboard viewer events", srv.deleteExpiredPublicDashboardViewerEvents},
+		{"delete expired dashboard usage", srv.deleteExpiredDashboardUsage},
+		{"delete expired trashed dashboards", srv.deleteExpiredTrashedDashboards},
 	}
 
 	logger := srv.log.FromContext(ctx)
@@ -280,3 +295,66 @@ func (srv *CleanUpService) deleteStaleQueryHistory(ctx context.Context) {
 		logger.Debug("Enforced row limit for query_history_star", "rows affected", rowsCount)
 	}
 }
+
+func (srv *CleanUpService) deleteExpiredPublicDashboardAccessLogs(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.PublicDashboardsAccessLogRetention <= 0 {
+		logger.Debug("Public dashboard access log retention is disabled")
+		return
+	}
+
+	olderThan := time.Now().Add(-srv.Cfg.PublicDashboardsAccessLogRetention)
+	rowsCount, err := srv.publicDashboardService.DeleteAccessLogsOlderThan(ctx, olderThan)
+	if err != nil {
+		logger.Error("Problem deleting expired public dashboard access logs", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired public dashboard access logs", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredPublicDashboardViewerEvents(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.PublicDashboardsViewerEventRetention <= 0 {
+		logger.Debug("Public dashboard viewer event retention is disabled")
+		return
+	}
+
+	olderThan := time.Now().Add(-srv.Cfg.PublicDashboardsViewerEventRetention)
+	rowsCount, err := srv.publicDashboardService.DeleteViewerEventsOlderThan(ctx, olderThan)
+	if err != nil {
+		logger.Error("Problem deleting expired public dashboard viewer events", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired public dashboard viewer events", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredDashboardUsage(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.DashboardUsageRetention <= 0 {
+		logger.Debug("Dashboard usage retention is disabled")
+		return
+	}
+
+	olderThan := time.Now().Add(-srv.Cfg.DashboardUsageRetention)
+	rowsCount, err := srv.dashboardUsageService.DeleteOlderThan(ctx, olderThan)
+	if err != nil {
+		logger.Error("Problem deleting expired dashboard usage", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired dashboard usage", "rows affected", rowsCount)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredTrashedDashboards(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if srv.Cfg.DashboardTrashRetention <= 0 {
+		logger.Debug("Dashboard trash is disabled")
+		return
+	}
+
+	cmd := dashboards.DeleteExpiredTrashedDashboardsCommand{OlderThan: time.Now().Add(-srv.Cfg.DashboardTrashRetention)}
+	if err := srv.dashboardService.DeleteExpiredTrashedDashboards(ctx, &cmd); err != nil {
+		logger.Error("Problem deleting expired trashed dashboards", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired trashed dashboards", "rows affected", cmd.DeletedRows)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -48,7 +49,7 @@ func (s CorrelationsService) createCorrelation(ctx context.Context, cmd CreateCo
 			return err
 		}
 
-		return nil
+		return s.insertEntityEvent(session, cmd.OrgId, correlation.UID, store.EntityEventTypeCreate)
 	})
 
 	if err != nil {
@@ -76,7 +77,10 @@ func (s CorrelationsService) deleteCorrelation(ctx context.Context, cmd DeleteCo
 		if deletedCount == 0 {
 			return ErrCorrelationNotFound
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return s.insertEntityEvent(session, cmd.OrgId, cmd.UID, store.EntityEventTypeDelete)
 	})
 }
 
@@ -132,7 +136,10 @@ func (s CorrelationsService) updateCorrelation(ctx context.Context, cmd UpdateCo
 		if updateCount == 0 {
 			return ErrCorrelationNotFound
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		return s.insertEntityEvent(session, cmd.OrgId, correlation.UID, store.EntityEventTypeUpdate)
 	})
 
 	if err != nil {
@@ -208,6 +215,9 @@ func (s CorrelationsService) getCorrelations(ctx context.Context, cmd GetCorrela
 
 func (s CorrelationsService) deleteCorrelationsBySourceUID(ctx context.Context, cmd DeleteCorrelationsBySourceUIDCommand) error {
 	return s.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		if err := s.insertDeleteEntityEvents(session, cmd.OrgId, &Correlation{SourceUID: cmd.SourceUID}); err != nil {
+			return err
+		}
 		_, err := session.Delete(&Correlation{SourceUID: cmd.SourceUID})
 		return err
 	})
@@ -215,7 +225,36 @@ func (s CorrelationsService) deleteCorrelationsBySourceUID(ctx context.Context,
 
 func (s CorrelationsService) deleteCorrelationsByTargetUID(ctx context.Context, cmd DeleteCorrelationsByTargetUIDCommand) error {
 	return s.SQLStore.WithDbSession(ctx, func(session *db.Session) error {
+		if err := s.insertDeleteEntityEvents(session, cmd.OrgId, &Correlation{TargetUID: &cmd.TargetUID}); err != nil {
+			return err
+		}
 		_, err := session.Delete(&Correlation{TargetUID: &cmd.TargetUID})
 		return err
 	})
 }
+
+// insertEntityEvent records the change of a correlation so that the search index can follow it.
+func (s CorrelationsService) insertEntityEvent(session *db.Session, orgID int64, uid string, eventType store.EntityEventType) error {
+	if !store.EntityEventsEnabled(s.features) {
+		return nil
+	}
+	_, err := session.Insert(store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeCorrelation, eventType))
+	return err
+}
+
+// insertDeleteEntityEvents records the deletion of the correlations matching the condition.
+func (s CorrelationsService) insertDeleteEntityEvents(session *db.Session, orgID int64, condition *Correlation) error {
+	if !store.EntityEventsEnabled(s.features) {
+		return nil
+	}
+	var correlations []Correlation
+	if err := session.Find(&correlations, condition); err != nil {
+		return err
+	}
+	for _, correlation := range correlations {
+		if err := s.insertEntityEvent(session, orgID, correlation.UID, store.EntityEventTypeDelete); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -29,6 +29,10 @@ type DashboardService interface {
 	UpdateDashboardACL(ctx context.Context, uid int64, items []*models.DashboardACL) error
 	DeleteACLByUser(ctx context.Context, userID int64) error
 	CountDashboardsInFolder(ctx context.Context, query *CountDashboardsInFolderQuery) (int64, error)
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	RestoreTrashedDashboard(ctx context.Context, cmd *RestoreTrashedDashboardCommand) (*models.Dashboard, error)
+	PurgeTrashedDashboard(ctx context.Context, cmd *PurgeTrashedDashboardCommand) error
+	DeleteExpiredTrashedDashboards(ctx context.Context, cmd *DeleteExpiredTrashedDashboardsCommand) error
 }
 
 // PluginService is a service for operating on plugin dashboards.
@@ -84,6 +88,14 @@ type Store interface {
 	// the given parent folder ID.
 	CountDashboardsInFolder(ctx context.Context, request *CountDashboardsInFolderRequest) (int64, error)
 
+	// GetTrashedDashboards returns the deleted dashboards and folders of an organization.
+	GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error)
+	// RestoreTrashedDashboard recreates a deleted dashboard, or a deleted folder with its
+	// dashboards, together with their versions and permissions.
+	RestoreTrashedDashboard(ctx context.Context, cmd *RestoreTrashedDashboardCommand) (*models.Dashboard, error)
+	PurgeTrashedDashboard(ctx context.Context, cmd *PurgeTrashedDashboardCommand) error
+	DeleteExpiredTrashedDashboards(ctx context.Context, cmd *DeleteExpiredTrashedDashboardsCommand) error
+
 	FolderStore
 }
 
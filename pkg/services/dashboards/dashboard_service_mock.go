@@ -86,6 +86,20 @@ func (_m *FakeDashboardService) DeleteDashboard(ctx context.Context, dashboardId
 	return r0
 }
 
+// DeleteExpiredTrashedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) DeleteExpiredTrashedDashboards(ctx context.Context, cmd *DeleteExpiredTrashedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredTrashedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // FindDashboards provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) FindDashboards(ctx context.Context, query *models.FindPersistedDashboardsQuery) ([]DashboardSearchProjection, error) {
 	ret := _m.Called(ctx, query)
@@ -179,6 +193,29 @@ func (_m *FakeDashboardService) GetDashboards(ctx context.Context, query *models
 	return r0
 }
 
+// GetTrashedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardService) GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*TrashedDashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) []*TrashedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*TrashedDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *GetTrashedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasAdminPermissionInDashboardsOrFolders provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardService) HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *models.HasAdminPermissionInDashboardsOrFoldersQuery) error {
 	ret := _m.Called(ctx, query)
@@ -244,6 +281,43 @@ func (_m *FakeDashboardService) MakeUserAdmin(ctx context.Context, orgID int64,
 	return r0
 }
 
+// PurgeTrashedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) PurgeTrashedDashboard(ctx context.Context, cmd *PurgeTrashedDashboardCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreTrashedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardService) RestoreTrashedDashboard(ctx context.Context, cmd *RestoreTrashedDashboardCommand) (*models.Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *models.Dashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreTrashedDashboardCommand) *models.Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Dashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreTrashedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveDashboard provides a mock function with given fields: ctx, dto, allowUiUpdate
 func (_m *FakeDashboardService) SaveDashboard(ctx context.Context, dto *SaveDashboardDTO, allowUiUpdate bool) (*models.Dashboard, error) {
 	ret := _m.Called(ctx, dto, allowUiUpdate)
@@ -65,7 +65,7 @@ func ProvideDashboardStore(sqlStore db.DB, cfg *setting.Cfg, features featuremgm
 }
 
 func (d *DashboardStore) emitEntityEvent() bool {
-	return d.features != nil && d.features.IsEnabled(featuremgmt.FlagPanelTitleSearch)
+	return store.EntityEventsEnabled(d.features)
 }
 
 func (d *DashboardStore) ValidateDashboardBeforeSave(ctx context.Context, dashboard *models.Dashboard, overwrite bool) (bool, error) {
@@ -774,17 +774,26 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 		return dashboards.ErrDashboardNotFound
 	}
 
+	if cmd.MoveToTrash {
+		if err := moveToTrash(sess, d.store.GetDialect(), &dashboard, cmd.DeletedBy); err != nil {
+			return err
+		}
+	}
+
 	deletes := []string{
 		"DELETE FROM dashboard_tag WHERE dashboard_id = ? ",
 		"DELETE FROM star WHERE dashboard_id = ? ",
 		"DELETE FROM dashboard_public WHERE dashboard_uid = (SELECT uid FROM dashboard WHERE id = ?)",
 		"DELETE FROM dashboard WHERE id = ?",
 		"DELETE FROM playlist_item WHERE type = 'dashboard_by_id' AND value = ?",
-		"DELETE FROM dashboard_version WHERE dashboard_id = ?",
 		"DELETE FROM annotation WHERE dashboard_id = ?",
 		"DELETE FROM dashboard_provisioning WHERE dashboard_id = ?",
 		"DELETE FROM dashboard_acl WHERE dashboard_id = ?",
 	}
+	// the versions of a dashboard in the trash are kept until it is removed from the trash
+	if !cmd.MoveToTrash {
+		deletes = append(deletes, "DELETE FROM dashboard_version WHERE dashboard_id = ?")
+	}
 
 	if dashboard.IsFolder {
 		deletes = append(deletes, "DELETE FROM dashboard WHERE folder_id = ?")
@@ -822,12 +831,14 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 			childrenDeletes := []string{
 				"DELETE FROM dashboard_tag WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 				"DELETE FROM star WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
-				"DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 				"DELETE FROM annotation WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 				"DELETE FROM dashboard_provisioning WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 				"DELETE FROM dashboard_acl WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 				"DELETE FROM dashboard_public WHERE dashboard_uid IN (SELECT uid FROM dashboard WHERE org_id = ? AND folder_id = ?)",
 			}
+			if !cmd.MoveToTrash {
+				childrenDeletes = append(childrenDeletes, "DELETE FROM dashboard_version WHERE dashboard_id IN (SELECT id FROM dashboard WHERE org_id = ? AND folder_id = ?)")
+			}
 			for _, sql := range childrenDeletes {
 				_, err := sess.Exec(sql, dashboard.OrgId, dashboard.Id)
 				if err != nil {
@@ -846,7 +857,7 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 				return fmt.Errorf("folder cannot be deleted: %w", dashboards.ErrFolderContainsAlertRules)
 			}
 
-			// Delete all rules under this folder.
+			// Delete all rules under this folder. They are not kept in the trash, restoring the folder does not bring them back.
 			deleteNGAlertsByFolder := []string{
 				"DELETE FROM alert_rule WHERE namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
 				"DELETE FROM alert_rule_version WHERE rule_namespace_uid = (SELECT uid FROM dashboard WHERE id = ?)",
@@ -887,21 +898,10 @@ func (d *DashboardStore) deleteDashboard(cmd *models.DeleteDashboardCommand, ses
 }
 
 func createEntityEvent(dashboard *models.Dashboard, eventType store.EntityEventType) *store.EntityEvent {
-	var entityEvent *store.EntityEvent
 	if dashboard.IsFolder {
-		entityEvent = &store.EntityEvent{
-			EventType: eventType,
-			EntityId:  store.CreateDatabaseEntityId(dashboard.Uid, dashboard.OrgId, store.EntityTypeFolder),
-			Created:   time.Now().Unix(),
-		}
-	} else {
-		entityEvent = &store.EntityEvent{
-			EventType: eventType,
-			EntityId:  store.CreateDatabaseEntityId(dashboard.Uid, dashboard.OrgId, store.EntityTypeDashboard),
-			Created:   time.Now().Unix(),
-		}
+		return store.NewDatabaseEntityEvent(dashboard.Uid, dashboard.OrgId, store.EntityTypeFolder, eventType)
 	}
-	return entityEvent
+	return store.NewDatabaseEntityEvent(dashboard.Uid, dashboard.OrgId, store.EntityTypeDashboard, eventType)
 }
 
 func (d *DashboardStore) deleteAlertDefinition(dashboardId int64, sess *db.Session) error {
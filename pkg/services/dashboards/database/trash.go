@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/store"
+)
+
+// trashedDashboardRow is a row of the dashboard_trash table. Every deleted dashboard has its own
+// row, the dashboards of a deleted folder reference the row of the folder with ParentID.
+// The versions of the dashboard are kept in the dashboard_version table, marked as deleted.
+type trashedDashboardRow struct {
+	ID          int64     `xorm:"pk autoincr 'id'"`
+	OrgID       int64     `xorm:"org_id"`
+	UID         string    `xorm:"uid"`
+	Title       string    `xorm:"title"`
+	IsFolder    bool      `xorm:"is_folder"`
+	FolderUID   string    `xorm:"folder_uid"`
+	ParentID    int64     `xorm:"parent_id"`
+	DashboardID int64     `xorm:"dashboard_id"`
+	Data        string    `xorm:"data"`
+	ACL         string    `xorm:"acl"`
+	Permissions string    `xorm:"permissions"`
+	Deleted     time.Time `xorm:"'deleted'"`
+	DeletedBy   int64     `xorm:"deleted_by"`
+}
+
+func (r trashedDashboardRow) TableName() string {
+	return "dashboard_trash"
+}
+
+type trashedPermission struct {
+	RoleID int64  `json:"roleId"`
+	Action string `json:"action"`
+	Scope  string `json:"scope"`
+}
+
+// moveToTrash stores everything needed to restore the dashboard, and the
+// dashboards of the folder, before they are deleted.
+func moveToTrash(sess *db.Session, dialect migrator.Dialect, dashboard *models.Dashboard, deletedBy int64) error {
+	folderUID := ""
+	if dashboard.FolderId > 0 {
+		if _, err := sess.Table("dashboard").Where("id = ?", dashboard.FolderId).Cols("uid").Get(&folderUID); err != nil {
+			return err
+		}
+	}
+
+	deleted := time.Now()
+	row, err := trashDashboard(sess, dialect, dashboard, folderUID, 0, deleted, deletedBy)
+	if err != nil {
+		return err
+	}
+
+	if dashboard.IsFolder {
+		var children []*models.Dashboard
+		if err := sess.Where("org_id = ? AND folder_id = ?", dashboard.OrgId, dashboard.Id).Find(&children); err != nil {
+			return err
+		}
+		for _, child := range children {
+			if _, err := trashDashboard(sess, dialect, child, dashboard.Uid, row.ID, deleted, deletedBy); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// trashDashboard adds the row of the dashboard to the trash and marks its versions as deleted,
+// a previously deleted dashboard with the same uid is replaced.
+func trashDashboard(sess *db.Session, dialect migrator.Dialect, dashboard *models.Dashboard, folderUID string, parentID int64, deleted time.Time, deletedBy int64) (*trashedDashboardRow, error) {
+	if _, err := deleteTrashedDashboards(sess, dialect, "org_id = ? AND uid = ?", dashboard.OrgId, dashboard.Uid); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	var acl []*models.DashboardACL
+	if err := sess.Where("dashboard_id = ?", dashboard.Id).Find(&acl); err != nil {
+		return nil, err
+	}
+	encodedACL, err := json.Marshal(acl)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := ac.GetResourceScopeUID("dashboards", dashboard.Uid)
+	if dashboard.IsFolder {
+		scope = dashboards.ScopeFoldersProvider.GetResourceScopeUID(dashboard.Uid)
+	}
+	var permissions []ac.Permission
+	if err := sess.Where("scope = ?", scope).Find(&permissions); err != nil {
+		return nil, err
+	}
+	trashed := make([]trashedPermission, 0, len(permissions))
+	for _, p := range permissions {
+		trashed = append(trashed, trashedPermission{RoleID: p.RoleID, Action: p.Action, Scope: p.Scope})
+	}
+	encodedPermissions, err := json.Marshal(trashed)
+	if err != nil {
+		return nil, err
+	}
+
+	row := &trashedDashboardRow{
+		OrgID:       dashboard.OrgId,
+		UID:         dashboard.Uid,
+		Title:       dashboard.Title,
+		IsFolder:    dashboard.IsFolder,
+		FolderUID:   folderUID,
+		ParentID:    parentID,
+		DashboardID: dashboard.Id,
+		Data:        string(data),
+		ACL:         string(encodedACL),
+		Permissions: string(encodedPermissions),
+		Deleted:     deleted,
+		DeletedBy:   deletedBy,
+	}
+	if _, err := sess.Insert(row); err != nil {
+		return nil, err
+	}
+
+	_, err = sess.Exec("UPDATE dashboard_version SET deleted = "+dialect.BooleanStr(true)+" WHERE dashboard_id = ?", dashboard.Id)
+	return row, err
+}
+
+// deleteTrashedDashboards permanently removes the matching rows from the trash, together with
+// the dashboards deleted with a folder and the versions kept for them. It returns the number of removed rows.
+func deleteTrashedDashboards(sess *db.Session, dialect migrator.Dialect, where string, args ...interface{}) (int64, error) {
+	var rows []*trashedDashboardRow
+	if err := sess.Where(where, args...).Cols("id").Find(&rows); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, row := range rows {
+		_, err := sess.Exec("DELETE FROM dashboard_version WHERE deleted = "+dialect.BooleanStr(true)+
+			" AND dashboard_id IN (SELECT dashboard_id FROM dashboard_trash WHERE id = ? OR parent_id = ?)", row.ID, row.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		res, err := sess.Exec("DELETE FROM dashboard_trash WHERE id = ? OR parent_id = ?", row.ID, row.ID)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		deleted += affected
+	}
+	return deleted, nil
+}
+
+func (d *DashboardStore) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	result := make([]*dashboards.TrashedDashboard, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		// the dashboards of a deleted folder are restored with it
+		return sess.Table("dashboard_trash").Where("org_id = ? AND parent_id = 0", query.OrgID).Desc("deleted").Find(&result)
+	})
+	return result, err
+}
+
+func (d *DashboardStore) RestoreTrashedDashboard(ctx context.Context, cmd *dashboards.RestoreTrashedDashboardCommand) (*models.Dashboard, error) {
+	var result *models.Dashboard
+	err := d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		row := trashedDashboardRow{}
+		has, err := sess.Where("org_id = ? AND uid = ?", cmd.OrgID, cmd.UID).Get(&row)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrTrashedDashboardNotFound
+		}
+
+		folderUID := cmd.FolderUID
+		if folderUID == "" {
+			folderUID = row.FolderUID
+		}
+		if row.IsFolder && folderUID != "" {
+			return dashboards.ErrDashboardFolderCannotHaveParent
+		}
+
+		var folderID int64
+		if folderUID != "" {
+			folder := models.Dashboard{}
+			has, err := sess.Where("org_id = ? AND uid = ? AND is_folder = ?", cmd.OrgID, folderUID, d.store.GetDialect().BooleanStr(true)).Get(&folder)
+			if err != nil {
+				return err
+			}
+			switch {
+			case has:
+				folderID = folder.Id
+			case cmd.FolderUID != "":
+				return dashboards.ErrFolderNotFound
+			}
+			// the original folder does not exist anymore, so the dashboard is restored into the General folder
+		}
+
+		dash, err := restoreTrashedDashboard(sess, d.store.GetDialect(), &row, folderID, true)
+		if err != nil {
+			return err
+		}
+		restored := []*models.Dashboard{dash}
+
+		if row.IsFolder {
+			var children []*trashedDashboardRow
+			if err := sess.Where("parent_id = ?", row.ID).Find(&children); err != nil {
+				return err
+			}
+			for _, child := range children {
+				childDash, err := restoreTrashedDashboard(sess, d.store.GetDialect(), child, dash.Id, false)
+				if err != nil {
+					return err
+				}
+				restored = append(restored, childDash)
+			}
+		}
+
+		if _, err := sess.Exec("DELETE FROM dashboard_trash WHERE id = ? OR parent_id = ?", row.ID, row.ID); err != nil {
+			return err
+		}
+
+		if d.emitEntityEvent() {
+			for _, dash := range restored {
+				if _, err := sess.Insert(createEntityEvent(dash, store.EntityEventTypeCreate)); err != nil {
+					return err
+				}
+			}
+		}
+
+		result = dash
+		return nil
+	})
+	return result, err
+}
+
+// restoreTrashedDashboard inserts the dashboard with a new id, and the tags, versions and
+// permissions which belong to it. The title is only checked for the dashboard being restored,
+// the dashboards restored with their folder cannot conflict.
+func restoreTrashedDashboard(sess *db.Session, dialect migrator.Dialect, row *trashedDashboardRow, folderID int64, checkTitle bool) (*models.Dashboard, error) {
+	dash := &models.Dashboard{}
+	if err := json.Unmarshal([]byte(row.Data), dash); err != nil {
+		return nil, err
+	}
+	dash.FolderId = folderID
+
+	if checkTitle {
+		if _, err := getExistingDashboardByTitleAndFolder(sess, dash, dialect, false, false); err != nil {
+			return nil, err
+		}
+	}
+
+	exists, err := sess.Table("dashboard").Where("org_id = ? AND uid = ?", dash.OrgId, dash.Uid).Exist()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, dashboards.ErrDashboardWithSameUIDExists
+	}
+
+	dash.Id = 0
+	if _, err := sess.Insert(dash); err != nil {
+		return nil, err
+	}
+	dash.SetId(dash.Id)
+	if _, err := sess.ID(dash.Id).Cols("data").Update(dash); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range dash.GetTags() {
+		if _, err := sess.Insert(DashboardTag{DashboardId: dash.Id, Term: tag}); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = sess.Exec("UPDATE dashboard_version SET dashboard_id = ?, deleted = "+dialect.BooleanStr(false)+
+		" WHERE dashboard_id = ? AND deleted = "+dialect.BooleanStr(true), dash.Id, row.DashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	var acl []*models.DashboardACL
+	if row.ACL != "" {
+		if err := json.Unmarshal([]byte(row.ACL), &acl); err != nil {
+			return nil, err
+		}
+	}
+	for _, item := range acl {
+		item.Id = 0
+		item.DashboardID = dash.Id
+		sess.Nullable("user_id", "team_id")
+		if _, err := sess.Insert(item); err != nil {
+			return nil, err
+		}
+	}
+
+	var permissions []trashedPermission
+	if row.Permissions != "" {
+		if err := json.Unmarshal([]byte(row.Permissions), &permissions); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	for _, p := range permissions {
+		// the managed role of a user or team is removed together with it
+		exists, err := sess.Table("role").Where("id = ?", p.RoleID).Exist()
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		if _, err := sess.Insert(&ac.Permission{RoleID: p.RoleID, Action: p.Action, Scope: p.Scope, Created: now, Updated: now}); err != nil {
+			return nil, err
+		}
+	}
+
+	return dash, nil
+}
+
+func (d *DashboardStore) PurgeTrashedDashboard(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := deleteTrashedDashboards(sess, d.store.GetDialect(), "org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return dashboards.ErrTrashedDashboardNotFound
+		}
+		return nil
+	})
+}
+
+func (d *DashboardStore) DeleteExpiredTrashedDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredTrashedDashboardsCommand) error {
+	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		cmd.DeletedRows, err = deleteTrashedDashboards(sess, d.store.GetDialect(), "deleted < ?", cmd.OlderThan)
+		return err
+	})
+}
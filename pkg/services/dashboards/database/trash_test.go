@@ -0,0 +1,264 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationDashboardTrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var sqlStore *sqlstore.SQLStore
+	var dashboardStore *DashboardStore
+
+	setup := func() {
+		var cfg *setting.Cfg
+		sqlStore, cfg = db.InitTestDBwithCfg(t)
+		var err error
+		dashboardStore, err = ProvideDashboardStore(sqlStore, cfg, testFeatureToggles, tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+	}
+
+	moveToTrash := func(t *testing.T, dash *models.Dashboard) {
+		t.Helper()
+		err := dashboardStore.DeleteDashboard(context.Background(), &models.DeleteDashboardCommand{
+			Id:          dash.Id,
+			OrgId:       dash.OrgId,
+			MoveToTrash: true,
+			DeletedBy:   2,
+		})
+		require.NoError(t, err)
+	}
+
+	getDashboard := func(t *testing.T, uid string) (*models.Dashboard, error) {
+		t.Helper()
+		return dashboardStore.GetDashboard(context.Background(), &models.GetDashboardQuery{Uid: uid, OrgId: 1})
+	}
+
+	countRows := func(t *testing.T, table string, where string, args ...interface{}) int64 {
+		t.Helper()
+		var count int64
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table(table).Where(where, args...).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Should move a deleted dashboard to the trash and restore it", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, folder.Id, false, "prod")
+		dash.Data.Set("version", dash.Version)
+		_, err := dashboardStore.SaveDashboard(context.Background(), models.SaveDashboardCommand{OrgId: 1, FolderId: folder.Id, Dashboard: dash.Data})
+		require.NoError(t, err)
+		err = updateDashboardACL(t, dashboardStore, dash.Id, models.DashboardACL{
+			OrgID: 1, DashboardID: dash.Id, UserID: 3, Permission: models.PERMISSION_EDIT,
+		})
+		require.NoError(t, err)
+		role := insertTestPermission(t, sqlStore, ac.GetResourceScopeUID("dashboards", dash.Uid))
+
+		moveToTrash(t, dash)
+
+		_, err = getDashboard(t, dash.Uid)
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		// the versions are kept in the version table until the dashboard is removed from the trash
+		assert.Equal(t, int64(2), countRows(t, "dashboard_version", "dashboard_id = ? AND deleted = ?", dash.Id, true))
+
+		trashed, err := dashboardStore.GetTrashedDashboards(context.Background(), &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, dash.Uid, trashed[0].UID)
+		assert.Equal(t, "dash", trashed[0].Title)
+		assert.Equal(t, folder.Uid, trashed[0].FolderUID)
+		assert.Equal(t, int64(2), trashed[0].DeletedBy)
+
+		restored, err := dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, dash.Uid, restored.Uid)
+		assert.Equal(t, folder.Id, restored.FolderId)
+
+		result, err := getDashboard(t, dash.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, "dash", result.Title)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, result.Id, result.Data.Get("id").MustInt64())
+
+		assert.Equal(t, int64(2), countRows(t, "dashboard_version", "dashboard_id = ? AND deleted = ?", result.Id, false))
+		assert.Equal(t, int64(0), countRows(t, "dashboard_version", "dashboard_id = ?", dash.Id))
+		assert.Equal(t, int64(1), countRows(t, "dashboard_tag", "dashboard_id = ? AND term = ?", result.Id, "prod"))
+		assert.Equal(t, int64(1), countRows(t, "dashboard_acl", "dashboard_id = ? AND user_id = ?", result.Id, 3))
+		assert.Equal(t, int64(1), countRows(t, "permission", "role_id = ? AND scope = ?", role.ID, ac.GetResourceScopeUID("dashboards", dash.Uid)))
+
+		trashed, err = dashboardStore.GetTrashedDashboards(context.Background(), &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, trashed)
+	})
+
+	t.Run("Should restore a deleted folder with its dashboards", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, folder.Id, false)
+		insertTestPermission(t, sqlStore, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid))
+
+		moveToTrash(t, folder)
+
+		_, err := getDashboard(t, dash.Uid)
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+
+		// every dashboard has its own row, only the folder is listed
+		assert.Equal(t, int64(2), countRows(t, "dashboard_trash", "org_id = ?", 1))
+		trashed, err := dashboardStore.GetTrashedDashboards(context.Background(), &dashboards.GetTrashedDashboardsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, folder.Uid, trashed[0].UID)
+
+		restored, err := dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: folder.Uid})
+		require.NoError(t, err)
+		assert.True(t, restored.IsFolder)
+
+		result, err := getDashboard(t, dash.Uid)
+		require.NoError(t, err)
+		assert.Equal(t, restored.Id, result.FolderId)
+		assert.Equal(t, int64(1), countRows(t, "dashboard_version", "dashboard_id = ?", result.Id))
+		assert.Equal(t, int64(1), countRows(t, "permission", "scope = ?", dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.Uid)))
+		assert.Equal(t, int64(0), countRows(t, "dashboard_trash", "org_id = ?", 1))
+
+		// the search index is told about the folder and every dashboard in it
+		for _, entityID := range []string{
+			store.CreateDatabaseEntityId(folder.Uid, 1, store.EntityTypeFolder),
+			store.CreateDatabaseEntityId(dash.Uid, 1, store.EntityTypeDashboard),
+		} {
+			assert.Equal(t, int64(1), countRows(t, "entity_event", "entity_id = ? AND event_type = ?", entityID, store.EntityEventTypeCreate), entityID)
+		}
+	})
+
+	t.Run("Should restore a dashboard into another folder", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+		otherFolder := insertTestDashboard(t, dashboardStore, "other folder", 1, 0, true)
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, folder.Id, false)
+
+		moveToTrash(t, dash)
+
+		_, err := dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid, FolderUID: "missing"})
+		require.ErrorIs(t, err, dashboards.ErrFolderNotFound)
+
+		restored, err := dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid, FolderUID: otherFolder.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, otherFolder.Id, restored.FolderId)
+	})
+
+	t.Run("Should restore a dashboard into the General folder when its folder was deleted", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, folder.Id, false)
+
+		moveToTrash(t, dash)
+		moveToTrash(t, folder)
+
+		restored, err := dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), restored.FolderId)
+	})
+
+	t.Run("Should not restore a dashboard when its uid is used", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, 0, false)
+
+		moveToTrash(t, dash)
+
+		dash.Data.Set("id", nil)
+		dash.Data.Set("title", "new dash")
+		_, err := dashboardStore.SaveDashboard(context.Background(), models.SaveDashboardCommand{OrgId: 1, Dashboard: dash.Data})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.ErrorIs(t, err, dashboards.ErrDashboardWithSameUIDExists)
+	})
+
+	t.Run("Should not keep dashboards deleted without the trash", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, 0, false)
+
+		err := dashboardStore.DeleteDashboard(context.Background(), &models.DeleteDashboardCommand{Id: dash.Id, OrgId: 1})
+		require.NoError(t, err)
+
+		_, err = dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.ErrorIs(t, err, dashboards.ErrTrashedDashboardNotFound)
+	})
+
+	t.Run("Should purge a trashed dashboard", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, 0, false)
+		moveToTrash(t, dash)
+
+		err := dashboardStore.PurgeTrashedDashboard(context.Background(), &dashboards.PurgeTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), countRows(t, "dashboard_version", "dashboard_id = ?", dash.Id))
+
+		err = dashboardStore.PurgeTrashedDashboard(context.Background(), &dashboards.PurgeTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.ErrorIs(t, err, dashboards.ErrTrashedDashboardNotFound)
+	})
+
+	t.Run("Should purge a trashed folder with its dashboards", func(t *testing.T) {
+		setup()
+		folder := insertTestDashboard(t, dashboardStore, "folder", 1, 0, true)
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, folder.Id, false)
+		moveToTrash(t, folder)
+
+		err := dashboardStore.PurgeTrashedDashboard(context.Background(), &dashboards.PurgeTrashedDashboardCommand{OrgID: 1, UID: folder.Uid})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), countRows(t, "dashboard_trash", "org_id = ?", 1))
+		assert.Equal(t, int64(0), countRows(t, "dashboard_version", "dashboard_id = ?", dash.Id))
+
+		_, err = dashboardStore.RestoreTrashedDashboard(context.Background(), &dashboards.RestoreTrashedDashboardCommand{OrgID: 1, UID: dash.Uid})
+		require.ErrorIs(t, err, dashboards.ErrTrashedDashboardNotFound)
+	})
+
+	t.Run("Should delete expired trashed dashboards", func(t *testing.T) {
+		setup()
+		dash := insertTestDashboard(t, dashboardStore, "dash", 1, 0, false)
+		moveToTrash(t, dash)
+
+		cmd := dashboards.DeleteExpiredTrashedDashboardsCommand{OlderThan: time.Now().Add(-time.Hour)}
+		require.NoError(t, dashboardStore.DeleteExpiredTrashedDashboards(context.Background(), &cmd))
+		assert.Equal(t, int64(0), cmd.DeletedRows)
+
+		cmd = dashboards.DeleteExpiredTrashedDashboardsCommand{OlderThan: time.Now().Add(time.Hour)}
+		require.NoError(t, dashboardStore.DeleteExpiredTrashedDashboards(context.Background(), &cmd))
+		assert.Equal(t, int64(1), cmd.DeletedRows)
+	})
+}
+
+func insertTestPermission(t *testing.T, sqlStore db.DB, scope string) *ac.Role {
+	t.Helper()
+	role := &ac.Role{OrgID: 1, UID: "managed_1_users_3", Name: "managed:users:3:permissions", Created: time.Now(), Updated: time.Now()}
+	err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		if _, err := sess.Insert(role); err != nil {
+			return err
+		}
+		_, err := sess.Insert(&ac.Permission{RoleID: role.ID, Action: dashboards.ActionDashboardsWrite, Scope: scope, Created: time.Now(), Updated: time.Now()})
+		return err
+	})
+	require.NoError(t, err)
+	return role
+}
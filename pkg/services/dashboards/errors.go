@@ -125,6 +125,11 @@ var (
 		StatusCode: 404,
 		Status:     "not-found",
 	}
+	ErrTrashedDashboardNotFound = DashboardErr{
+		Reason:     "Deleted dashboard not found",
+		StatusCode: 404,
+		Status:     "not-found",
+	}
 
 	ErrFolderNotFound                = errors.New("folder not found")
 	ErrFolderVersionMismatch         = errors.New("the folder has been changed by someone else")
@@ -48,3 +48,38 @@ type CountDashboardsInFolderRequest struct {
 	FolderID int64
 	OrgID    int64
 }
+
+// TrashedDashboard is a deleted dashboard or folder, which can be restored
+// until it is removed from the trash.
+type TrashedDashboard struct {
+	ID        int64     `xorm:"pk autoincr 'id'"`
+	OrgID     int64     `xorm:"org_id"`
+	UID       string    `xorm:"uid"`
+	Title     string    `xorm:"title"`
+	IsFolder  bool      `xorm:"is_folder"`
+	FolderUID string    `xorm:"folder_uid"`
+	Deleted   time.Time `xorm:"'deleted'"`
+	DeletedBy int64     `xorm:"deleted_by"`
+}
+
+type GetTrashedDashboardsQuery struct {
+	OrgID int64
+}
+
+type RestoreTrashedDashboardCommand struct {
+	OrgID int64
+	UID   string
+	// FolderUID of the folder to restore the dashboard into. The dashboard
+	// is restored into its original folder when empty.
+	FolderUID string
+}
+
+type PurgeTrashedDashboardCommand struct {
+	OrgID int64
+	UID   string
+}
+
+type DeleteExpiredTrashedDashboardsCommand struct {
+	OlderThan   time.Time
+	DeletedRows int64
+}
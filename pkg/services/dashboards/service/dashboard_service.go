@@ -446,6 +446,13 @@ func (dr *DashboardServiceImpl) deleteDashboard(ctx context.Context, dashboardId
 		}
 	}
 	cmd := &models.DeleteDashboardCommand{OrgId: orgId, Id: dashboardId}
+	// provisioned dashboards are recreated from their files, so only the ones deleted by users are kept in the trash
+	if validateProvisionedDashboard && dr.cfg.DashboardTrashRetention > 0 {
+		cmd.MoveToTrash = true
+		if u, err := appcontext.User(ctx); err == nil {
+			cmd.DeletedBy = u.UserID
+		}
+	}
 	return dr.dashboardStore.DeleteDashboard(ctx, cmd)
 }
 
@@ -630,3 +637,19 @@ func (dr DashboardServiceImpl) CountDashboardsInFolder(ctx context.Context, quer
 
 	return dr.dashboardStore.CountDashboardsInFolder(ctx, &dashboards.CountDashboardsInFolderRequest{FolderID: folder.ID, OrgID: u.OrgID})
 }
+
+func (dr *DashboardServiceImpl) GetTrashedDashboards(ctx context.Context, query *dashboards.GetTrashedDashboardsQuery) ([]*dashboards.TrashedDashboard, error) {
+	return dr.dashboardStore.GetTrashedDashboards(ctx, query)
+}
+
+func (dr *DashboardServiceImpl) RestoreTrashedDashboard(ctx context.Context, cmd *dashboards.RestoreTrashedDashboardCommand) (*models.Dashboard, error) {
+	return dr.dashboardStore.RestoreTrashedDashboard(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) PurgeTrashedDashboard(ctx context.Context, cmd *dashboards.PurgeTrashedDashboardCommand) error {
+	return dr.dashboardStore.PurgeTrashedDashboard(ctx, cmd)
+}
+
+func (dr *DashboardServiceImpl) DeleteExpiredTrashedDashboards(ctx context.Context, cmd *dashboards.DeleteExpiredTrashedDashboardsCommand) error {
+	return dr.dashboardStore.DeleteExpiredTrashedDashboards(ctx, cmd)
+}
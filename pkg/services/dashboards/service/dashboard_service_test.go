@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -217,6 +218,18 @@ func TestDashboardService(t *testing.T) {
 				require.NoError(t, err)
 			})
 
+			t.Run("DeleteDashboard should move it to the trash when the trash is enabled", func(t *testing.T) {
+				service.cfg.DashboardTrashRetention = time.Hour
+				t.Cleanup(func() { service.cfg.DashboardTrashRetention = 0 })
+
+				args := &models.DeleteDashboardCommand{OrgId: 1, Id: 1, MoveToTrash: true, DeletedBy: 2}
+				fakeStore.On("DeleteDashboard", mock.Anything, args).Return(nil).Once()
+				fakeStore.On("GetProvisionedDataByDashboardID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
+				ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{UserID: 2, OrgID: 1})
+				err := service.DeleteDashboard(ctx, 1, 1)
+				require.NoError(t, err)
+			})
+
 			// t.Run("Delete ACL by user", func(t *testing.T) {
 			// 	fakeStore := dashboards.FakeDashboardStore{}
 			// 	args := 1
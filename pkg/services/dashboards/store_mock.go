@@ -65,6 +65,20 @@ func (_m *FakeDashboardStore) DeleteDashboard(ctx context.Context, cmd *models.D
 	return r0
 }
 
+// DeleteExpiredTrashedDashboards provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) DeleteExpiredTrashedDashboards(ctx context.Context, cmd *DeleteExpiredTrashedDashboardsCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteExpiredTrashedDashboardsCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteOrphanedProvisionedDashboards provides a mock function with given fields: ctx, cmd
 func (_m *FakeDashboardStore) DeleteOrphanedProvisionedDashboards(ctx context.Context, cmd *models.DeleteOrphanedProvisionedDashboardsCommand) error {
 	ret := _m.Called(ctx, cmd)
@@ -333,6 +347,29 @@ func (_m *FakeDashboardStore) GetProvisionedDataByDashboardUID(ctx context.Conte
 	return r0, r1
 }
 
+// GetTrashedDashboards provides a mock function with given fields: ctx, query
+func (_m *FakeDashboardStore) GetTrashedDashboards(ctx context.Context, query *GetTrashedDashboardsQuery) ([]*TrashedDashboard, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*TrashedDashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *GetTrashedDashboardsQuery) []*TrashedDashboard); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*TrashedDashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *GetTrashedDashboardsQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HasAdminPermissionInDashboardsOrFolders provides a mock function with given fields: ctx, query
 func (_m *FakeDashboardStore) HasAdminPermissionInDashboardsOrFolders(ctx context.Context, query *models.HasAdminPermissionInDashboardsOrFoldersQuery) error {
 	ret := _m.Called(ctx, query)
@@ -361,6 +398,43 @@ func (_m *FakeDashboardStore) HasEditPermissionInFolders(ctx context.Context, qu
 	return r0
 }
 
+// PurgeTrashedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) PurgeTrashedDashboard(ctx context.Context, cmd *PurgeTrashedDashboardCommand) error {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *PurgeTrashedDashboardCommand) error); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreTrashedDashboard provides a mock function with given fields: ctx, cmd
+func (_m *FakeDashboardStore) RestoreTrashedDashboard(ctx context.Context, cmd *RestoreTrashedDashboardCommand) (*models.Dashboard, error) {
+	ret := _m.Called(ctx, cmd)
+
+	var r0 *models.Dashboard
+	if rf, ok := ret.Get(0).(func(context.Context, *RestoreTrashedDashboardCommand) *models.Dashboard); ok {
+		r0 = rf(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Dashboard)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *RestoreTrashedDashboardCommand) error); ok {
+		r1 = rf(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveAlerts provides a mock function with given fields: ctx, dashID, alerts
 func (_m *FakeDashboardStore) SaveAlerts(ctx context.Context, dashID int64, alerts []*models.Alert) error {
 	ret := _m.Called(ctx, dashID, alerts)
@@ -24,6 +24,17 @@ func ProvideStore(db db.DB) *DashboardSnapshotStore {
 	return &DashboardSnapshotStore{store: db, log: log.New("dashboardsnapshot.store")}
 }
 
+// snapshotTeam is a team which can view a snapshot with teams access.
+type snapshotTeam struct {
+	ID         int64 `xorm:"pk autoincr 'id'"`
+	SnapshotID int64 `xorm:"snapshot_id"`
+	TeamID     int64 `xorm:"team_id"`
+}
+
+func (t snapshotTeam) TableName() string {
+	return "dashboard_snapshot_team"
+}
+
 // DeleteExpiredSnapshots removes snapshots with old expiry dates.
 // SnapShotRemoveExpired is deprecated and should be removed in the future.
 // Snapshot expiry is decided by the user when they share the snapshot.
@@ -34,8 +45,18 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 			return nil
 		}
 
+		now := time.Now()
+		if err := sess.Table("dashboard_snapshot").Where("expires < ? AND payload_path IS NOT NULL AND payload_path <> ''", now).
+			Cols("payload_path").Find(&cmd.PayloadPaths); err != nil {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM dashboard_snapshot_team WHERE snapshot_id IN (SELECT id FROM dashboard_snapshot WHERE expires < ?)", now); err != nil {
+			return err
+		}
+
 		deleteExpiredSQL := "DELETE FROM dashboard_snapshot WHERE expires < ?"
-		expiredResponse, err := sess.Exec(deleteExpiredSQL, time.Now())
+		expiredResponse, err := sess.Exec(deleteExpiredSQL, now)
 		if err != nil {
 			return err
 		}
@@ -47,6 +68,11 @@ func (d *DashboardSnapshotStore) DeleteExpiredSnapshots(ctx context.Context, cmd
 
 func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		access := cmd.Access
+		if access == "" {
+			access = dashboardsnapshots.AccessPublic
+		}
+
 		var expires = time.Now().Add(time.Hour * 24 * 365 * 50)
 		if cmd.Expires > 0 {
 			expires = time.Now().Add(time.Second * time.Duration(cmd.Expires))
@@ -63,19 +89,36 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			ExternalDeleteUrl:  cmd.ExternalDeleteUrl,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
+			PayloadPath:        cmd.PayloadPath,
+			Access:             access,
 			Expires:            expires,
 			Created:            time.Now(),
 			Updated:            time.Now(),
 		}
-		_, err := sess.Insert(snapshot)
-		cmd.Result = snapshot
+		if _, err := sess.Insert(snapshot); err != nil {
+			return err
+		}
 
-		return err
+		if access == dashboardsnapshots.AccessTeams {
+			for _, teamID := range cmd.TeamIDs {
+				if _, err := sess.Insert(&snapshotTeam{SnapshotID: snapshot.Id, TeamID: teamID}); err != nil {
+					return err
+				}
+			}
+			snapshot.TeamIDs = cmd.TeamIDs
+		}
+
+		cmd.Result = snapshot
+		return nil
 	})
 }
 
 func (d *DashboardSnapshotStore) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
 	return d.store.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM dashboard_snapshot_team WHERE snapshot_id IN (SELECT id FROM dashboard_snapshot WHERE delete_key=?)", cmd.DeleteKey); err != nil {
+			return err
+		}
+
 		var rawSQL = "DELETE FROM dashboard_snapshot WHERE delete_key=?"
 		_, err := sess.Exec(rawSQL, cmd.DeleteKey)
 		return err
@@ -93,6 +136,13 @@ func (d *DashboardSnapshotStore) GetDashboardSnapshot(ctx context.Context, query
 			return dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
 		}
 
+		if snapshot.Access == dashboardsnapshots.AccessTeams {
+			if err := sess.Table("dashboard_snapshot_team").Where("snapshot_id = ?", snapshot.Id).
+				Cols("team_id").Find(&snapshot.TeamIDs); err != nil {
+				return err
+			}
+		}
+
 		query.Result = &snapshot
 		return nil
 	})
@@ -191,6 +191,84 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 	})
 }
 
+func TestIntegrationDashboardSnapshotAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := ProvideStore(sqlstore)
+
+	countTeams := func(t *testing.T, snapshotID int64) int64 {
+		t.Helper()
+		var count int64
+		err := sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			var err error
+			count, err = sess.Table("dashboard_snapshot_team").Where("snapshot_id = ?", snapshotID).Count()
+			return err
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Should store the teams of a snapshot", func(t *testing.T) {
+		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:         "teams",
+			DeleteKey:   "deleteteams",
+			OrgId:       1,
+			UserId:      1000,
+			Access:      dashboardsnapshots.AccessTeams,
+			TeamIDs:     []int64{1, 2},
+			PayloadPath: "/1/payload",
+		}
+		require.NoError(t, dashStore.CreateDashboardSnapshot(context.Background(), &cmd))
+
+		query := dashboardsnapshots.GetDashboardSnapshotQuery{Key: "teams"}
+		require.NoError(t, dashStore.GetDashboardSnapshot(context.Background(), &query))
+		assert.Equal(t, dashboardsnapshots.AccessTeams, query.Result.Access)
+		assert.ElementsMatch(t, []int64{1, 2}, query.Result.TeamIDs)
+		assert.Equal(t, "/1/payload", query.Result.PayloadPath)
+
+		search := dashboardsnapshots.GetDashboardSnapshotsQuery{OrgId: 1, SignedInUser: &user.SignedInUser{OrgRole: org.RoleAdmin}}
+		require.NoError(t, dashStore.SearchDashboardSnapshots(context.Background(), &search))
+		require.Len(t, search.Result, 1)
+		assert.Equal(t, dashboardsnapshots.AccessTeams, search.Result[0].Access)
+
+		require.NoError(t, dashStore.DeleteDashboardSnapshot(context.Background(), &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: "deleteteams"}))
+		assert.Equal(t, int64(0), countTeams(t, cmd.Result.Id))
+	})
+
+	t.Run("Should default to public access", func(t *testing.T) {
+		snapshot := createTestSnapshot(t, dashStore, "public", 48000)
+		assert.Equal(t, dashboardsnapshots.AccessPublic, snapshot.Access)
+		assert.Equal(t, int64(0), countTeams(t, snapshot.Id))
+	})
+
+	t.Run("Should return the payload paths of expired snapshots", func(t *testing.T) {
+		setting.SnapShotRemoveExpired = true
+
+		cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+			Key:         "expired",
+			DeleteKey:   "deleteexpired",
+			OrgId:       1,
+			Access:      dashboardsnapshots.AccessTeams,
+			TeamIDs:     []int64{1},
+			PayloadPath: "/1/expired",
+		}
+		require.NoError(t, dashStore.CreateDashboardSnapshot(context.Background(), &cmd))
+		err := sqlstore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			_, err := sess.Exec("UPDATE dashboard_snapshot SET expires = ? WHERE id = ?", time.Now().Add(-time.Hour), cmd.Result.Id)
+			return err
+		})
+		require.NoError(t, err)
+
+		deleteCmd := dashboardsnapshots.DeleteExpiredSnapshotsCommand{}
+		require.NoError(t, dashStore.DeleteExpiredSnapshots(context.Background(), &deleteCmd))
+		assert.Equal(t, int64(1), deleteCmd.DeletedRows)
+		assert.Equal(t, []string{"/1/expired"}, deleteCmd.PayloadPaths)
+		assert.Equal(t, int64(0), countTeams(t, cmd.Result.Id))
+	})
+}
+
 func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key string, expires int64) *dashboardsnapshots.DashboardSnapshot {
 	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
 		Key:       key,
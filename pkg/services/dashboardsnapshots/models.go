@@ -7,6 +7,16 @@ import (
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
+// Who can view a snapshot by its key.
+const (
+	// AccessPublic allows anyone with the key to view the snapshot.
+	AccessPublic = "public"
+	// AccessOrg allows the members of the organization of the snapshot.
+	AccessOrg = "org"
+	// AccessTeams allows the members of the teams of the snapshot, its creator and the organization admins.
+	AccessTeams = "teams"
+)
+
 // DashboardSnapshot model
 type DashboardSnapshot struct {
 	Id                int64
@@ -18,6 +28,11 @@ type DashboardSnapshot struct {
 	External          bool
 	ExternalUrl       string
 	ExternalDeleteUrl string
+	Access            string
+	// PayloadPath is the path of the encrypted dashboard in the snapshot
+	// storage, it is empty when the dashboard is stored in the database.
+	PayloadPath string
+	TeamIDs     []int64 `xorm:"-"`
 
 	Expires time.Time
 	Created time.Time
@@ -36,6 +51,7 @@ type DashboardSnapshotDTO struct {
 	UserId      int64  `json:"userId"`
 	External    bool   `json:"external"`
 	ExternalUrl string `json:"externalUrl"`
+	Access      string `json:"access"`
 
 	Expires time.Time `json:"expires"`
 	Created time.Time `json:"created"`
@@ -72,11 +88,21 @@ type CreateDashboardSnapshotCommand struct {
 	// Unique key used to delete the snapshot. It is different from the `key` so that only the creator can delete the snapshot. Required if `external` is `true`.
 	// required:false
 	DeleteKey string `json:"deleteKey"`
+	// Who can view the snapshot: `public` for anyone with the key, `org` for the members of the organization
+	// or `teams` for the members of the teams in `teamIds`. External snapshots are always `public`.
+	// required:false
+	// default: public
+	// enum: public,org,teams
+	Access string `json:"access"`
+	// The teams which can view the snapshot when `access` is `teams`.
+	// required:false
+	TeamIDs []int64 `json:"teamIds"`
 
 	OrgId  int64 `json:"-"`
 	UserId int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
+	PayloadPath        string `json:"-"`
 
 	Result *DashboardSnapshot
 }
@@ -87,6 +113,8 @@ type DeleteDashboardSnapshotCommand struct {
 
 type DeleteExpiredSnapshotsCommand struct {
 	DeletedRows int64
+	// PayloadPaths are the storage paths of the deleted snapshots.
+	PayloadPaths []string
 }
 
 type GetDashboardSnapshotQuery struct {
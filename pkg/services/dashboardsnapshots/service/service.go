@@ -2,27 +2,46 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/google/uuid"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 type ServiceImpl struct {
 	store          dashboardsnapshots.Store
 	secretsService secrets.Service
+	// storage holds the encrypted dashboards of the snapshots, it is nil when
+	// they are stored in the database.
+	storage filestorage.FileStorage
+	log     log.Logger
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service) *ServiceImpl {
+func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, cfg *setting.Cfg) (*ServiceImpl, error) {
 	s := &ServiceImpl{
 		store:          store,
 		secretsService: secretsService,
+		log:            log.New("dashboardsnapshots"),
+	}
+
+	storage, err := newPayloadStorage(cfg, s.log)
+	if err != nil {
+		return nil, err
 	}
+	s.storage = storage
 
-	return s
+	return s, nil
 }
 
 func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) error {
@@ -38,7 +57,28 @@ func (s *ServiceImpl) CreateDashboardSnapshot(ctx context.Context, cmd *dashboar
 
 	cmd.DashboardEncrypted = encryptedDashboard
 
-	return s.store.CreateDashboardSnapshot(ctx, cmd)
+	if s.storage == nil || cmd.External {
+		return s.store.CreateDashboardSnapshot(ctx, cmd)
+	}
+
+	cmd.PayloadPath = path.Join("/", strconv.FormatInt(cmd.OrgId, 10), uuid.NewString())
+	if err := s.storage.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     cmd.PayloadPath,
+		MimeType: "application/octet-stream",
+		Contents: encryptedDashboard,
+	}); err != nil {
+		return fmt.Errorf("failed to store snapshot: %w", err)
+	}
+	cmd.DashboardEncrypted = nil
+
+	if err := s.store.CreateDashboardSnapshot(ctx, cmd); err != nil {
+		if err := s.storage.Delete(ctx, cmd.PayloadPath); err != nil {
+			s.log.Warn("Failed to delete snapshot payload", "path", cmd.PayloadPath, "error", err)
+		}
+		return err
+	}
+
+	return nil
 }
 
 func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) error {
@@ -47,6 +87,14 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 		return err
 	}
 
+	if query.Result.PayloadPath != "" {
+		encrypted, err := s.getPayload(ctx, query.Result.PayloadPath)
+		if err != nil {
+			return err
+		}
+		query.Result.DashboardEncrypted = encrypted
+	}
+
 	if query.Result.DashboardEncrypted != nil {
 		decryptedDashboard, err := s.secretsService.Decrypt(ctx, query.Result.DashboardEncrypted)
 		if err != nil {
@@ -64,8 +112,37 @@ func (s *ServiceImpl) GetDashboardSnapshot(ctx context.Context, query *dashboard
 	return err
 }
 
+func (s *ServiceImpl) getPayload(ctx context.Context, payloadPath string) ([]byte, error) {
+	if s.storage == nil {
+		return nil, fmt.Errorf("snapshot is stored in %s but the snapshot storage is not configured", payloadPath)
+	}
+
+	file, found, err := s.storage.Get(ctx, payloadPath, &filestorage.GetFileOptions{WithContents: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if !found {
+		return nil, dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot payload %s not found", payloadPath)
+	}
+
+	return file.Contents, nil
+}
+
 func (s *ServiceImpl) DeleteDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.DeleteDashboardSnapshotCommand) error {
-	return s.store.DeleteDashboardSnapshot(ctx, cmd)
+	query := dashboardsnapshots.GetDashboardSnapshotQuery{DeleteKey: cmd.DeleteKey}
+	if err := s.store.GetDashboardSnapshot(ctx, &query); err != nil {
+		return err
+	}
+
+	if err := s.store.DeleteDashboardSnapshot(ctx, cmd); err != nil {
+		return err
+	}
+
+	if query.Result.PayloadPath != "" && s.storage != nil {
+		return s.storage.Delete(ctx, query.Result.PayloadPath)
+	}
+
+	return nil
 }
 
 func (s *ServiceImpl) SearchDashboardSnapshots(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotsQuery) error {
@@ -73,5 +150,19 @@ func (s *ServiceImpl) SearchDashboardSnapshots(ctx context.Context, query *dashb
 }
 
 func (s *ServiceImpl) DeleteExpiredSnapshots(ctx context.Context, cmd *dashboardsnapshots.DeleteExpiredSnapshotsCommand) error {
-	return s.store.DeleteExpiredSnapshots(ctx, cmd)
+	if err := s.store.DeleteExpiredSnapshots(ctx, cmd); err != nil {
+		return err
+	}
+
+	if s.storage == nil {
+		return nil
+	}
+
+	for _, payloadPath := range cmd.PayloadPaths {
+		if err := s.storage.Delete(ctx, payloadPath); err != nil {
+			s.log.Warn("Failed to delete expired snapshot payload", "path", payloadPath, "error", err)
+		}
+	}
+
+	return nil
 }
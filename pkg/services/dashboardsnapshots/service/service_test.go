@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
 	"github.com/grafana/grafana/pkg/services/secrets/database"
@@ -19,7 +21,8 @@ func TestDashboardSnapshotsService(t *testing.T) {
 	sqlStore := db.InitTestDB(t)
 	dsStore := dashsnapdb.ProvideStore(sqlStore)
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
-	s := ProvideService(dsStore, secretsService)
+	s, err := ProvideService(dsStore, secretsService, setting.NewCfg())
+	require.NoError(t, err)
 
 	origSecret := setting.SecretKey
 	setting.SecretKey = "dashboard_snapshot_service_test"
@@ -68,3 +71,58 @@ func TestDashboardSnapshotsService(t *testing.T) {
 		require.Equal(t, rawDashboard, decrypted)
 	})
 }
+
+func TestDashboardSnapshotsServiceWithStorage(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	dsStore := dashsnapdb.ProvideStore(sqlStore)
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	cfg := setting.NewCfg()
+	cfg.SnapshotStorageType = "disk"
+	cfg.SnapshotStoragePath = filepath.Join(t.TempDir(), "snapshots")
+	s, err := ProvideService(dsStore, secretsService, cfg)
+	require.NoError(t, err)
+
+	rawDashboard := []byte(`{"id":123}`)
+	dashboard, err := simplejson.NewJson(rawDashboard)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Key:       "12345",
+		DeleteKey: "54321",
+		OrgId:     1,
+		Dashboard: dashboard,
+	}
+	require.NoError(t, s.CreateDashboardSnapshot(ctx, &cmd))
+
+	t.Run("create dashboard snapshot should store the dashboard in the storage", func(t *testing.T) {
+		require.NotEmpty(t, cmd.Result.PayloadPath)
+		require.Nil(t, cmd.Result.DashboardEncrypted)
+
+		file, found, err := s.storage.Get(ctx, cmd.Result.PayloadPath, &filestorage.GetFileOptions{WithContents: true})
+		require.NoError(t, err)
+		require.True(t, found)
+
+		decrypted, err := s.secretsService.Decrypt(ctx, file.Contents)
+		require.NoError(t, err)
+		require.Equal(t, rawDashboard, decrypted)
+	})
+
+	t.Run("get dashboard snapshot should return the dashboard from the storage", func(t *testing.T) {
+		query := dashboardsnapshots.GetDashboardSnapshotQuery{Key: "12345"}
+		require.NoError(t, s.GetDashboardSnapshot(ctx, &query))
+
+		decrypted, err := query.Result.Dashboard.Encode()
+		require.NoError(t, err)
+		require.Equal(t, rawDashboard, decrypted)
+	})
+
+	t.Run("delete dashboard snapshot should remove the dashboard from the storage", func(t *testing.T) {
+		require.NoError(t, s.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: "54321"}))
+
+		_, found, err := s.storage.Get(ctx, cmd.Result.PayloadPath, &filestorage.GetFileOptions{})
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/azureblob"
+	"gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// newPayloadStorage returns the storage for the snapshot payloads, or nil when
+// the payloads are stored in the database.
+func newPayloadStorage(cfg *setting.Cfg, logger log.Logger) (filestorage.FileStorage, error) {
+	var bucket *blob.Bucket
+	var err error
+
+	switch cfg.SnapshotStorageType {
+	case "disk":
+		bucket, err = fileblob.OpenBucket(cfg.SnapshotStoragePath, &fileblob.Options{CreateDir: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot storage directory %s: %w", cfg.SnapshotStoragePath, err)
+		}
+	case "blob":
+		bucket, err = blob.OpenBucket(context.Background(), cfg.SnapshotStorageBucketURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot storage bucket: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	return filestorage.NewCdkBlobStorage(logger, bucket, "", nil), nil
+}
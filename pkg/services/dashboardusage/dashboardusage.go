@@ -0,0 +1,17 @@
+package dashboardusage
+
+import (
+	"context"
+	"time"
+)
+
+type Service interface {
+	// RecordView counts a view of a dashboard by a user. Usage is buffered
+	// and written to the database periodically.
+	RecordView(ctx context.Context, orgID int64, dashboardUID string, userID int64)
+	// RecordQueries counts panel queries run from a dashboard by a user.
+	RecordQueries(ctx context.Context, orgID int64, dashboardUID string, userID int64, count int64)
+	GetDashboardUsage(ctx context.Context, query *GetDashboardUsageQuery) (*DashboardUsage, error)
+	GetUsageSummaries(ctx context.Context, query *GetUsageSummariesQuery) (map[string]*UsageSummary, error)
+	DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
+}
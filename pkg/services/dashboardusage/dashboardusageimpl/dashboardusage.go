@@ -0,0 +1,146 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// flushInterval is how often the buffered usage is written to the database
+const flushInterval = time.Minute
+
+type usageKey struct {
+	orgID        int64
+	dashboardUID string
+	userID       int64
+	day          int64
+}
+
+type Service struct {
+	store   store
+	cfg     *setting.Cfg
+	log     log.Logger
+	now     func() time.Time
+	mu      sync.Mutex
+	pending map[usageKey]*dashboardusage.DailyUsage
+}
+
+func ProvideService(db db.DB, cfg *setting.Cfg) *Service {
+	return &Service{
+		store: &sqlStore{
+			db: db,
+		},
+		cfg:     cfg,
+		log:     log.New("dashboardusage"),
+		now:     time.Now,
+		pending: make(map[usageKey]*dashboardusage.DailyUsage),
+	}
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.DashboardUsageEnabled
+}
+
+// Run writes the buffered usage to the database until the context is cancelled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.flush(ctx); err != nil {
+				s.log.Error("Failed to write dashboard usage", "error", err)
+			}
+		case <-ctx.Done():
+			// write what is left with a fresh context, the server is shutting down
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := s.flush(flushCtx); err != nil {
+				s.log.Error("Failed to write dashboard usage", "error", err)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) RecordView(_ context.Context, orgID int64, dashboardUID string, userID int64) {
+	if s.IsDisabled() || dashboardUID == "" {
+		return
+	}
+	now := s.now()
+	s.record(orgID, dashboardUID, userID, now, func(usage *dashboardusage.DailyUsage) {
+		usage.Views++
+		usage.LastViewed = now.Unix()
+	})
+}
+
+func (s *Service) RecordQueries(_ context.Context, orgID int64, dashboardUID string, userID int64, count int64) {
+	if s.IsDisabled() || dashboardUID == "" || count <= 0 {
+		return
+	}
+	s.record(orgID, dashboardUID, userID, s.now(), func(usage *dashboardusage.DailyUsage) {
+		usage.Queries += count
+	})
+}
+
+func (s *Service) record(orgID int64, dashboardUID string, userID int64, now time.Time, update func(*dashboardusage.DailyUsage)) {
+	key := usageKey{
+		orgID:        orgID,
+		dashboardUID: dashboardUID,
+		userID:       userID,
+		day:          dashboardusage.StartOfDay(now).Unix(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage, ok := s.pending[key]
+	if !ok {
+		usage = &dashboardusage.DailyUsage{
+			OrgID:        key.orgID,
+			DashboardUID: key.dashboardUID,
+			UserID:       key.userID,
+			Day:          key.day,
+		}
+		s.pending[key] = usage
+	}
+	update(usage)
+}
+
+func (s *Service) flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]*dashboardusage.DailyUsage)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usages := make([]*dashboardusage.DailyUsage, 0, len(pending))
+	for _, usage := range pending {
+		usages = append(usages, usage)
+	}
+	return s.store.Upsert(ctx, usages)
+}
+
+func (s *Service) GetDashboardUsage(ctx context.Context, query *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error) {
+	if query.DashboardUID == "" {
+		return nil, dashboardusage.ErrDashboardUIDRequired
+	}
+	return s.store.GetDashboardUsage(ctx, query)
+}
+
+func (s *Service) GetUsageSummaries(ctx context.Context, query *dashboardusage.GetUsageSummariesQuery) (map[string]*dashboardusage.UsageSummary, error) {
+	return s.store.GetUsageSummaries(ctx, query)
+}
+
+func (s *Service) DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	return s.store.DeleteOlderThan(ctx, olderThan)
+}
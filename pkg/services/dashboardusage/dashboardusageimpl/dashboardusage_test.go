@@ -0,0 +1,84 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeStore struct {
+	upserted []*dashboardusage.DailyUsage
+}
+
+func (f *fakeStore) Upsert(_ context.Context, usages []*dashboardusage.DailyUsage) error {
+	f.upserted = append(f.upserted, usages...)
+	return nil
+}
+
+func (f *fakeStore) GetDashboardUsage(_ context.Context, _ *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) GetUsageSummaries(_ context.Context, _ *dashboardusage.GetUsageSummariesQuery) (map[string]*dashboardusage.UsageSummary, error) {
+	return nil, nil
+}
+
+func (f *fakeStore) DeleteOlderThan(_ context.Context, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestService_Record(t *testing.T) {
+	now := time.Date(2022, 10, 19, 10, 0, 0, 0, time.UTC)
+	newService := func(enabled bool) (*Service, *fakeStore) {
+		st := &fakeStore{}
+		cfg := setting.NewCfg()
+		cfg.DashboardUsageEnabled = enabled
+		return &Service{
+			store:   st,
+			cfg:     cfg,
+			log:     log.New("dashboardusage.test"),
+			now:     func() time.Time { return now },
+			pending: make(map[usageKey]*dashboardusage.DailyUsage),
+		}, st
+	}
+
+	t.Run("buffers usage per dashboard, user and day", func(t *testing.T) {
+		s, st := newService(true)
+		s.RecordView(context.Background(), 1, "dash", 1)
+		s.RecordView(context.Background(), 1, "dash", 1)
+		s.RecordQueries(context.Background(), 1, "dash", 1, 4)
+		s.RecordQueries(context.Background(), 1, "dash", 2, 1)
+		s.RecordQueries(context.Background(), 1, "", 2, 1)
+
+		require.NoError(t, s.flush(context.Background()))
+		require.Len(t, st.upserted, 2)
+
+		byUser := map[int64]*dashboardusage.DailyUsage{}
+		for _, u := range st.upserted {
+			byUser[u.UserID] = u
+		}
+		require.Equal(t, &dashboardusage.DailyUsage{
+			OrgID: 1, DashboardUID: "dash", UserID: 1, Day: now.Truncate(24 * time.Hour).Unix(),
+			Views: 2, Queries: 4, LastViewed: now.Unix(),
+		}, byUser[1])
+		require.Equal(t, int64(0), byUser[2].Views)
+		require.Equal(t, int64(1), byUser[2].Queries)
+
+		// the buffer is empty after a flush
+		require.NoError(t, s.flush(context.Background()))
+		require.Len(t, st.upserted, 2)
+	})
+
+	t.Run("does not record when disabled", func(t *testing.T) {
+		s, st := newService(false)
+		s.RecordView(context.Background(), 1, "dash", 1)
+		require.NoError(t, s.flush(context.Background()))
+		require.Empty(t, st.upserted)
+	})
+}
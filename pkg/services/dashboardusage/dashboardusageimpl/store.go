@@ -0,0 +1,16 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+)
+
+type store interface {
+	// Upsert adds the views and queries to the existing daily usage rows
+	Upsert(context.Context, []*dashboardusage.DailyUsage) error
+	GetDashboardUsage(context.Context, *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error)
+	GetUsageSummaries(context.Context, *dashboardusage.GetUsageSummariesQuery) (map[string]*dashboardusage.UsageSummary, error)
+	DeleteOlderThan(context.Context, time.Time) (int64, error)
+}
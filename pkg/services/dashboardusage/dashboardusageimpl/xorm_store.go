@@ -0,0 +1,152 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Upsert(ctx context.Context, usages []*dashboardusage.DailyUsage) error {
+	upsertSQL := s.upsertSQL()
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, usage := range usages {
+			if _, err := sess.Exec(upsertSQL,
+				usage.OrgID, usage.DashboardUID, usage.UserID, usage.Day,
+				usage.Views, usage.Queries, usage.LastViewed); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// upsertSQL inserts a daily usage row or adds to the existing one in a single statement,
+// concurrent writes of the same row would otherwise conflict on the unique index
+func (s *sqlStore) upsertSQL() string {
+	insert := `INSERT INTO dashboard_usage_daily (org_id, dashboard_uid, user_id, day, views, queries, last_viewed)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+	if s.db.GetDialect().DriverName() == migrator.MySQL {
+		return insert + ` ON DUPLICATE KEY UPDATE
+			views = views + VALUES(views),
+			queries = queries + VALUES(queries),
+			last_viewed = GREATEST(last_viewed, VALUES(last_viewed))`
+	}
+	return insert + ` ON CONFLICT (org_id, dashboard_uid, user_id, day) DO UPDATE SET
+		views = dashboard_usage_daily.views + excluded.views,
+		queries = dashboard_usage_daily.queries + excluded.queries,
+		last_viewed = CASE WHEN dashboard_usage_daily.last_viewed < excluded.last_viewed THEN excluded.last_viewed ELSE dashboard_usage_daily.last_viewed END`
+}
+
+func (s *sqlStore) GetDashboardUsage(ctx context.Context, query *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error) {
+	result := &dashboardusage.DashboardUsage{
+		DashboardUID: query.DashboardUID,
+		From:         query.From,
+		To:           query.To,
+		Days:         make([]*dashboardusage.DayUsage, 0),
+		Users:        make([]*dashboardusage.UserUsage, 0),
+	}
+	from := dashboardusage.StartOfDay(query.From).Unix()
+	to := query.To.Unix()
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		err := sess.SQL(`SELECT day, SUM(views) AS views, SUM(queries) AS queries, SUM(CASE WHEN views > 0 THEN 1 ELSE 0 END) AS viewers
+			FROM dashboard_usage_daily
+			WHERE org_id = ? AND dashboard_uid = ? AND day >= ? AND day <= ?
+			GROUP BY day
+			ORDER BY day ASC`, query.OrgID, query.DashboardUID, from, to).
+			Find(&result.Days)
+		if err != nil {
+			return err
+		}
+
+		userTable := s.db.GetDialect().Quote("user")
+		return sess.SQL(`SELECT du.user_id, COALESCE(u.login, '') AS login, SUM(du.views) AS views, SUM(du.queries) AS queries, MAX(du.last_viewed) AS last_viewed
+			FROM dashboard_usage_daily AS du
+			LEFT JOIN `+userTable+` AS u ON u.id = du.user_id
+			WHERE du.org_id = ? AND du.dashboard_uid = ? AND du.day >= ? AND du.day <= ?
+			GROUP BY du.user_id, u.login
+			ORDER BY views DESC, du.user_id ASC`, query.OrgID, query.DashboardUID, from, to).
+			Find(&result.Users)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, day := range result.Days {
+		day.Day = time.Unix(day.DayUnix, 0).UTC().Format("2006-01-02")
+		result.Views += day.Views
+		result.Queries += day.Queries
+	}
+	for _, user := range result.Users {
+		if user.Views > 0 {
+			result.Viewers++
+		}
+		if user.LastViewedUnix > 0 {
+			lastViewed := time.Unix(user.LastViewedUnix, 0)
+			user.LastViewed = &lastViewed
+			if result.LastViewed == nil || lastViewed.After(*result.LastViewed) {
+				result.LastViewed = &lastViewed
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *sqlStore) GetUsageSummaries(ctx context.Context, query *dashboardusage.GetUsageSummariesQuery) (map[string]*dashboardusage.UsageSummary, error) {
+	since := dashboardusage.StartOfDay(query.Since).Unix()
+	rows := make([]*dashboardusage.UsageSummary, 0)
+
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		// views and queries are counted since the given time, last viewed is all time
+		sql := strings.Builder{}
+		params := []interface{}{since, since, query.OrgID}
+		sql.WriteString(`SELECT dashboard_uid,
+				SUM(CASE WHEN day >= ? THEN views ELSE 0 END) AS views,
+				SUM(CASE WHEN day >= ? THEN queries ELSE 0 END) AS queries,
+				MAX(last_viewed) AS last_viewed
+			FROM dashboard_usage_daily
+			WHERE org_id = ?`)
+		if len(query.UIDs) > 0 {
+			sql.WriteString(" AND dashboard_uid IN (?" + strings.Repeat(",?", len(query.UIDs)-1) + ")")
+			for _, uid := range query.UIDs {
+				params = append(params, uid)
+			}
+		}
+		sql.WriteString(" GROUP BY dashboard_uid")
+		return sess.SQL(sql.String(), params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[string]*dashboardusage.UsageSummary, len(rows))
+	for _, row := range rows {
+		if row.LastViewedUnix > 0 {
+			lastViewed := time.Unix(row.LastViewedUnix, 0)
+			row.LastViewed = &lastViewed
+		}
+		summaries[row.DashboardUID] = row
+	}
+	return summaries, nil
+}
+
+func (s *sqlStore) DeleteOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	var affected int64
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_usage_daily WHERE day < ?", dashboardusage.StartOfDay(olderThan).Unix())
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected, err
+}
@@ -0,0 +1,101 @@
+package dashboardusageimpl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+)
+
+func TestIntegrationDashboardUsageDataAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ss := db.InitTestDB(t)
+	usageStore := &sqlStore{db: ss}
+	ctx := context.Background()
+
+	today := dashboardusage.StartOfDay(time.Now())
+	yesterday := today.Add(-24 * time.Hour)
+	lastMonth := today.Add(-40 * 24 * time.Hour)
+
+	err := usageStore.Upsert(ctx, []*dashboardusage.DailyUsage{
+		{OrgID: 1, DashboardUID: "dash", UserID: 1, Day: today.Unix(), Views: 2, Queries: 10, LastViewed: today.Add(time.Hour).Unix()},
+		{OrgID: 1, DashboardUID: "dash", UserID: 2, Day: today.Unix(), Queries: 3},
+		{OrgID: 1, DashboardUID: "dash", UserID: 1, Day: yesterday.Unix(), Views: 1, Queries: 4, LastViewed: yesterday.Unix()},
+		{OrgID: 1, DashboardUID: "old", UserID: 1, Day: lastMonth.Unix(), Views: 5, LastViewed: lastMonth.Unix()},
+		{OrgID: 2, DashboardUID: "dash", UserID: 3, Day: today.Unix(), Views: 7, LastViewed: today.Unix()},
+	})
+	require.NoError(t, err)
+
+	t.Run("Upsert should add to existing usage", func(t *testing.T) {
+		err := usageStore.Upsert(ctx, []*dashboardusage.DailyUsage{
+			{OrgID: 1, DashboardUID: "dash", UserID: 1, Day: today.Unix(), Views: 1, Queries: 1, LastViewed: today.Add(2 * time.Hour).Unix()},
+		})
+		require.NoError(t, err)
+
+		usage, err := usageStore.GetDashboardUsage(ctx, &dashboardusage.GetDashboardUsageQuery{
+			OrgID: 1, DashboardUID: "dash", From: today, To: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, usage.Days, 1)
+		require.Equal(t, int64(3), usage.Days[0].Views)
+		require.Equal(t, int64(14), usage.Days[0].Queries)
+		require.Equal(t, today.Add(2*time.Hour).Unix(), usage.LastViewed.Unix())
+	})
+
+	t.Run("GetDashboardUsage should aggregate per day and per user", func(t *testing.T) {
+		usage, err := usageStore.GetDashboardUsage(ctx, &dashboardusage.GetDashboardUsageQuery{
+			OrgID: 1, DashboardUID: "dash", From: yesterday, To: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, int64(4), usage.Views)
+		require.Equal(t, int64(18), usage.Queries)
+		require.Equal(t, int64(1), usage.Viewers)
+
+		require.Len(t, usage.Days, 2)
+		require.Equal(t, yesterday.Format("2006-01-02"), usage.Days[0].Day)
+		require.Equal(t, int64(1), usage.Days[0].Viewers)
+		require.Equal(t, today.Format("2006-01-02"), usage.Days[1].Day)
+
+		require.Len(t, usage.Users, 2)
+		require.Equal(t, int64(1), usage.Users[0].UserID)
+		require.Equal(t, int64(4), usage.Users[0].Views)
+		require.Equal(t, int64(2), usage.Users[1].UserID)
+		require.Equal(t, int64(3), usage.Users[1].Queries)
+		require.Nil(t, usage.Users[1].LastViewed)
+	})
+
+	t.Run("GetUsageSummaries should count views since a time", func(t *testing.T) {
+		summaries, err := usageStore.GetUsageSummaries(ctx, &dashboardusage.GetUsageSummariesQuery{
+			OrgID: 1, Since: today.Add(-30 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, summaries, 2)
+		require.Equal(t, int64(4), summaries["dash"].Views)
+		require.Equal(t, int64(0), summaries["old"].Views)
+		require.Equal(t, lastMonth.Unix(), summaries["old"].LastViewed.Unix())
+
+		summaries, err = usageStore.GetUsageSummaries(ctx, &dashboardusage.GetUsageSummariesQuery{
+			OrgID: 1, UIDs: []string{"old"}, Since: today.Add(-30 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+	})
+
+	t.Run("DeleteOlderThan should delete old days", func(t *testing.T) {
+		deleted, err := usageStore.DeleteOlderThan(ctx, today.Add(-30*24*time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), deleted)
+
+		summaries, err := usageStore.GetUsageSummaries(ctx, &dashboardusage.GetUsageSummariesQuery{OrgID: 1, Since: lastMonth})
+		require.NoError(t, err)
+		require.NotContains(t, summaries, "old")
+	})
+}
@@ -0,0 +1,45 @@
+package dashboardusagetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+)
+
+type FakeDashboardUsageService struct {
+	ExpectedDashboardUsage *dashboardusage.DashboardUsage
+	ExpectedSummaries      map[string]*dashboardusage.UsageSummary
+	ExpectedDeleted        int64
+	ExpectedError          error
+
+	Views   map[string]int64
+	Queries map[string]int64
+}
+
+func NewDashboardUsageServiceFake() *FakeDashboardUsageService {
+	return &FakeDashboardUsageService{
+		Views:   make(map[string]int64),
+		Queries: make(map[string]int64),
+	}
+}
+
+func (f *FakeDashboardUsageService) RecordView(_ context.Context, _ int64, dashboardUID string, _ int64) {
+	f.Views[dashboardUID]++
+}
+
+func (f *FakeDashboardUsageService) RecordQueries(_ context.Context, _ int64, dashboardUID string, _ int64, count int64) {
+	f.Queries[dashboardUID] += count
+}
+
+func (f *FakeDashboardUsageService) GetDashboardUsage(_ context.Context, _ *dashboardusage.GetDashboardUsageQuery) (*dashboardusage.DashboardUsage, error) {
+	return f.ExpectedDashboardUsage, f.ExpectedError
+}
+
+func (f *FakeDashboardUsageService) GetUsageSummaries(_ context.Context, _ *dashboardusage.GetUsageSummariesQuery) (map[string]*dashboardusage.UsageSummary, error) {
+	return f.ExpectedSummaries, f.ExpectedError
+}
+
+func (f *FakeDashboardUsageService) DeleteOlderThan(_ context.Context, _ time.Time) (int64, error) {
+	return f.ExpectedDeleted, f.ExpectedError
+}
@@ -0,0 +1,88 @@
+package dashboardusage
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrDashboardUIDRequired = errors.New("dashboard uid is required")
+
+// DailyUsage is the usage of a dashboard by a user during a day.
+type DailyUsage struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	DashboardUID string `xorm:"dashboard_uid"`
+	UserID       int64  `xorm:"user_id"`
+	// Day is the unix timestamp of the start of the day (UTC)
+	Day        int64 `xorm:"day"`
+	Views      int64 `xorm:"views"`
+	Queries    int64 `xorm:"queries"`
+	LastViewed int64 `xorm:"last_viewed"`
+}
+
+func (u DailyUsage) TableName() string {
+	return "dashboard_usage_daily"
+}
+
+// StartOfDay returns the start of the UTC day of t.
+func StartOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// ----------------------
+// QUERIES
+
+type GetDashboardUsageQuery struct {
+	OrgID        int64
+	DashboardUID string
+	From         time.Time
+	To           time.Time
+}
+
+type GetUsageSummariesQuery struct {
+	OrgID int64
+	// UIDs limits the summaries to some dashboards, all dashboards when empty
+	UIDs  []string
+	Since time.Time
+}
+
+// ----------------------
+// DTOs
+
+type DashboardUsage struct {
+	DashboardUID string       `json:"dashboardUid"`
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Views        int64        `json:"views"`
+	Queries      int64        `json:"queries"`
+	Viewers      int64        `json:"viewers"`
+	LastViewed   *time.Time   `json:"lastViewed"`
+	Days         []*DayUsage  `json:"days"`
+	Users        []*UserUsage `json:"users"`
+}
+
+type DayUsage struct {
+	Day     string `json:"day" xorm:"-"`
+	DayUnix int64  `json:"-" xorm:"day"`
+	Views   int64  `json:"views" xorm:"views"`
+	Queries int64  `json:"queries" xorm:"queries"`
+	Viewers int64  `json:"viewers" xorm:"viewers"`
+}
+
+type UserUsage struct {
+	UserID         int64      `json:"userId" xorm:"user_id"`
+	Login          string     `json:"login" xorm:"login"`
+	Views          int64      `json:"views" xorm:"views"`
+	Queries        int64      `json:"queries" xorm:"queries"`
+	LastViewed     *time.Time `json:"lastViewed" xorm:"-"`
+	LastViewedUnix int64      `json:"-" xorm:"last_viewed"`
+}
+
+// UsageSummary is the total usage of a dashboard since a point in time.
+type UsageSummary struct {
+	DashboardUID   string     `xorm:"dashboard_uid"`
+	Views          int64      `xorm:"views"`
+	Queries        int64      `xorm:"queries"`
+	LastViewed     *time.Time `xorm:"-"`
+	LastViewedUnix int64      `xorm:"last_viewed"`
+}
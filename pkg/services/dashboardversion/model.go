@@ -24,6 +24,9 @@ type DashboardVersion struct {
 
 	Message string           `json:"message" db:"message"`
 	Data    *simplejson.Json `json:"data" db:"data"`
+
+	// Deleted is set while the dashboard of the version is in the trash
+	Deleted bool `json:"-" db:"deleted"`
 }
 
 type GetDashboardVersionQuery struct {
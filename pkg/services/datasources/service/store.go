@@ -16,8 +16,10 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -36,8 +38,9 @@ type Store interface {
 }
 
 type SqlStore struct {
-	db     db.DB
-	logger log.Logger
+	db       db.DB
+	logger   log.Logger
+	features featuremgmt.FeatureToggles
 }
 
 func CreateStore(db db.DB, logger log.Logger) *SqlStore {
@@ -151,6 +154,10 @@ func (ss *SqlStore) DeleteDataSource(ctx context.Context, cmd *datasources.Delet
 				ac.Scope(datasources.ScopeProvider.GetResourceScope(dsQuery.Result.Uid))); errDeletingPerms != nil {
 				return errDeletingPerms
 			}
+
+			if err := ss.insertEntityEvent(sess, ds.OrgId, ds.Uid, store.EntityEventTypeDelete); err != nil {
+				return err
+			}
 		}
 
 		if cmd.UpdateSecretFn != nil {
@@ -271,6 +278,10 @@ func (ss *SqlStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataS
 			return err
 		}
 
+		if err := ss.insertEntityEvent(sess, ds.OrgId, ds.Uid, store.EntityEventTypeCreate); err != nil {
+			return err
+		}
+
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
 				// ss.logger.Error("Failed to update datasource secrets -- rolling back update", "name", cmd.Name, "type", cmd.Type, "orgId", cmd.OrgId)
@@ -365,6 +376,10 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 
 		err = updateIsDefaultFlag(ds, sess)
 
+		if eventErr := ss.insertUpdateEntityEvent(sess, ds); eventErr != nil {
+			return eventErr
+		}
+
 		if cmd.UpdateSecretFn != nil {
 			if err := cmd.UpdateSecretFn(); err != nil {
 				ss.logger.Error("Failed to update datasource secrets -- rolling back update", "UID", cmd.Uid, "name", cmd.Name, "type", cmd.Type, "orgId", cmd.OrgId)
@@ -377,6 +392,29 @@ func (ss *SqlStore) UpdateDataSource(ctx context.Context, cmd *datasources.Updat
 	})
 }
 
+// insertEntityEvent records the change of a data source so that the search index can follow it.
+func (ss *SqlStore) insertEntityEvent(sess *db.Session, orgID int64, uid string, eventType store.EntityEventType) error {
+	if !store.EntityEventsEnabled(ss.features) {
+		return nil
+	}
+	_, err := sess.Insert(store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeDatasource, eventType))
+	return err
+}
+
+// insertUpdateEntityEvent records the update of a data source, which can be updated without its uid.
+func (ss *SqlStore) insertUpdateEntityEvent(sess *db.Session, ds *datasources.DataSource) error {
+	if !store.EntityEventsEnabled(ss.features) {
+		return nil
+	}
+	uid := ds.Uid
+	if uid == "" {
+		if _, err := sess.Table("data_source").Where("id=? AND org_id=?", ds.Id, ds.OrgId).Cols("uid").Get(&uid); err != nil {
+			return err
+		}
+	}
+	return ss.insertEntityEvent(sess, ds.OrgId, uid, store.EntityEventTypeUpdate)
+}
+
 func generateNewDatasourceUid(sess *db.Session, orgId int64) (string, error) {
 	for i := 0; i < 3; i++ {
 		uid := generateNewUid()
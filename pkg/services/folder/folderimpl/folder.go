@@ -524,6 +524,12 @@ func (s *Service) Delete(ctx context.Context, cmd *folder.DeleteFolderCommand) e
 
 func (s *Service) legacyDelete(ctx context.Context, cmd *folder.DeleteFolderCommand, dashFolder *folder.Folder) error {
 	deleteCmd := models.DeleteDashboardCommand{OrgId: cmd.OrgID, Id: dashFolder.ID, ForceDeleteFolderRules: cmd.ForceDeleteRules}
+	if s.cfg.DashboardTrashRetention > 0 {
+		deleteCmd.MoveToTrash = true
+		if cmd.SignedInUser != nil {
+			deleteCmd.DeletedBy = cmd.SignedInUser.UserID
+		}
+	}
 
 	if err := s.dashboardStore.DeleteDashboard(ctx, &deleteCmd); err != nil {
 		return toFolderError(err)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -248,6 +249,31 @@ func TestIntegrationFolderService(t *testing.T) {
 				require.Equal(t, expectedForceDeleteRules, actualCmd.ForceDeleteFolderRules)
 			})
 
+			t.Run("When deleting folder by uid should move it to the trash when the trash is enabled", func(t *testing.T) {
+				service.cfg.DashboardTrashRetention = time.Hour
+				t.Cleanup(func() { service.cfg.DashboardTrashRetention = 0 })
+
+				f := folder.NewFolder(util.GenerateShortUID(), "")
+				f.ID = rand.Int63()
+				f.UID = util.GenerateShortUID()
+				dashStore.On("GetFolderByUID", mock.Anything, orgID, f.UID).Return(f, nil)
+
+				var actualCmd *models.DeleteDashboardCommand
+				dashStore.On("DeleteDashboard", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					actualCmd = args.Get(1).(*models.DeleteDashboardCommand)
+				}).Return(nil).Once()
+
+				err := service.Delete(context.Background(), &folder.DeleteFolderCommand{
+					UID:          f.UID,
+					OrgID:        orgID,
+					SignedInUser: usr,
+				})
+				require.NoError(t, err)
+				require.NotNil(t, actualCmd)
+				require.True(t, actualCmd.MoveToTrash)
+				require.Equal(t, usr.UserID, actualCmd.DeletedBy)
+			})
+
 			t.Cleanup(func() {
 				guardian.New = origNewGuardian
 			})
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/search"
 	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -148,7 +149,7 @@ func (l *LibraryElementService) createLibraryElement(c context.Context, signedIn
 			}
 			return err
 		}
-		return nil
+		return l.insertEntityEvent(session, element.OrgID, element.UID, element.Kind, store.EntityEventTypeCreate)
 	})
 
 	dto := LibraryElementDTO{
@@ -218,6 +219,9 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 		} else if rowsAffected != 1 {
 			return ErrLibraryElementNotFound
 		}
+		if err := l.insertEntityEvent(session, element.OrgID, element.UID, element.Kind, store.EntityEventTypeDelete); err != nil {
+			return err
+		}
 
 		elementID = element.ID
 		return nil
@@ -225,6 +229,15 @@ func (l *LibraryElementService) deleteLibraryElement(c context.Context, signedIn
 	return elementID, err
 }
 
+// insertEntityEvent records the change of a library panel so that the search index can follow it.
+func (l *LibraryElementService) insertEntityEvent(session *db.Session, orgID int64, uid string, kind int64, eventType store.EntityEventType) error {
+	if kind != int64(models.PanelElement) || !store.EntityEventsEnabled(l.features) {
+		return nil
+	}
+	_, err := session.Insert(store.NewDatabaseEntityEvent(uid, orgID, store.EntityTypeLibraryPanel, eventType))
+	return err
+}
+
 // getLibraryElements gets a Library Element where param == value
 func getLibraryElements(c context.Context, store db.DB, cfg *setting.Cfg, signedInUser *user.SignedInUser, params []Pair) ([]LibraryElementDTO, error) {
 	libraryElements := make([]LibraryElementWithMeta, 0)
@@ -525,6 +538,9 @@ func (l *LibraryElementService) patchLibraryElement(c context.Context, signedInU
 		} else if rowsAffected != 1 {
 			return ErrLibraryElementNotFound
 		}
+		if err := l.insertEntityEvent(session, elementInDB.OrgID, elementInDB.UID, elementInDB.Kind, store.EntityEventTypeUpdate); err != nil {
+			return err
+		}
 
 		dto = LibraryElementDTO{
 			ID:          libraryElement.ID,
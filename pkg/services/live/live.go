@@ -54,6 +54,8 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/gobwas/glob"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana-plugin-sdk-go/live"
 	jsoniter "github.com/json-iterator/go"
 	"golang.org/x/sync/errgroup"
@@ -77,7 +79,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 	dataSourceCache datasources.CacheService, sqlStore db.DB, secretsService secrets.Service,
 	usageStatsService usagestats.Service, queryDataService *query.Service, toggles featuremgmt.FeatureToggles,
 	accessControl accesscontrol.AccessControl, dashboardService dashboards.DashboardService, annotationsRepo annotations.Repository,
-	orgService org.Service) (*GrafanaLive, error) {
+	orgService org.Service, redisClient *redis.Client, frameHistory managedstream.FrameHistory) (*GrafanaLive, error) {
 	g := &GrafanaLive{
 		Cfg:                   cfg,
 		Features:              toggles,
@@ -95,6 +97,9 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		},
 		usageStatsService: usageStatsService,
 		orgService:        orgService,
+		redisClient:       redisClient,
+		frameHistory:      frameHistory,
+		ChannelAuthorizer: pipeline.NewAccessControlAuthorizer(accessControl),
 	}
 
 	logger.Debug("GrafanaLive initialization", "ha", g.IsHA())
@@ -160,23 +165,21 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 
 	var managedStreamRunner *managedstream.Runner
 	if g.IsHA() {
-		redisClient := redis.NewClient(&redis.Options{
-			Addr: g.Cfg.LiveHAEngineAddress,
-		})
-		cmd := redisClient.Ping(context.Background())
-		if _, err := cmd.Result(); err != nil {
-			return nil, fmt.Errorf("error pinging Redis: %v", err)
+		if redisClient == nil {
+			return nil, errors.New("live HA engine requires a Redis client")
 		}
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewRedisFrameCache(redisClient),
+			frameHistory,
 		)
 	} else {
 		managedStreamRunner = managedstream.NewRunner(
 			g.Publish,
 			channelLocalPublisher,
 			managedstream.NewMemoryFrameCache(),
+			frameHistory,
 		)
 	}
 
@@ -203,6 +206,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 				Storage:              storage,
 				ChannelHandlerGetter: g,
 				SecretsService:       g.SecretsService,
+				AccessControl:        accessControl,
 			}
 		}
 		channelRuleGetter := pipeline.NewCacheSegmentedTree(builder)
@@ -337,7 +341,7 @@ func ProvideService(plugCtxProvider *plugincontext.Provider, cfg *setting.Cfg, r
 		CheckOrigin:     checkOrigin,
 	})
 
-	pushWSHandler := pushws.NewHandler(g.ManagedStreamRunner, pushws.Config{
+	pushWSHandler := pushws.NewHandler(g.ManagedStreamRunner, g.ChannelAuthorizer, pushws.Config{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 		CheckOrigin:     checkOrigin,
@@ -409,6 +413,8 @@ type GrafanaLive struct {
 	pluginStore           plugins.Store
 	queryDataService      *query.Service
 	orgService            org.Service
+	redisClient           *redis.Client
+	frameHistory          managedstream.FrameHistory
 
 	node         *centrifuge.Node
 	surveyCaller *survey.Caller
@@ -427,7 +433,11 @@ type GrafanaLive struct {
 
 	ManagedStreamRunner *managedstream.Runner
 	Pipeline            *pipeline.Pipeline
-	pipelineStorage     pipeline.Storage
+	// ChannelAuthorizer checks live:subscribe and live:publish permissions
+	// for stream scope channels.
+	ChannelAuthorizer *pipeline.AccessControlAuthorizer
+
+	pipelineStorage pipeline.Storage
 
 	contextGetter    *liveplugin.ContextGetter
 	runStreamManager *runstream.Manager
@@ -449,6 +459,14 @@ func (g *GrafanaLive) getStreamPlugin(ctx context.Context, pluginID string) (bac
 }
 
 func (g *GrafanaLive) Run(ctx context.Context) error {
+	if g.redisClient != nil {
+		defer func() {
+			if err := g.redisClient.Close(); err != nil {
+				logger.Warn("Failed to close Redis client", "error", err)
+			}
+		}()
+	}
+
 	eGroup, eCtx := errgroup.WithContext(ctx)
 
 	eGroup.Go(func() error {
@@ -465,6 +483,12 @@ func (g *GrafanaLive) Run(ctx context.Context) error {
 		}
 	})
 
+	if h, ok := g.frameHistory.(*managedstream.MemoryFrameHistory); ok {
+		eGroup.Go(func() error {
+			return h.Run(eCtx)
+		})
+	}
+
 	if g.runStreamManager != nil {
 		// Only run stream manager if GrafanaLive properly initialized.
 		eGroup.Go(func() error {
@@ -646,7 +670,10 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 		ruleFound = ok
 		if ok {
 			if rule.SubscribeAuth != nil {
-				ok, err := rule.SubscribeAuth.CanSubscribe(client.Context(), user)
+				ok, err := rule.SubscribeAuth.CanSubscribe(client.Context(), user, pipeline.Vars{
+					OrgID:   orgID,
+					Channel: channel,
+				})
 				if err != nil {
 					logger.Error("Error checking subscribe permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
 					return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
@@ -685,6 +712,21 @@ func (g *GrafanaLive) handleOnSubscribe(ctx context.Context, client *centrifuge.
 			logger.Error("Error getting channel handler", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
 			return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
 		}
+		if addr.Scope == live.ScopeStream {
+			ok, err := g.ChannelAuthorizer.CanSubscribe(client.Context(), user, pipeline.Vars{
+				OrgID:   orgID,
+				Channel: channel,
+			})
+			if err != nil {
+				logger.Error("Error checking subscribe permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
+				return centrifuge.SubscribeReply{}, centrifuge.ErrorInternal
+			}
+			if !ok {
+				// using HTTP error codes for WS errors too.
+				code, text := subscribeStatusToHTTPError(backend.SubscribeStreamStatusPermissionDenied)
+				return centrifuge.SubscribeReply{}, &centrifuge.Error{Code: uint32(code), Message: text}
+			}
+		}
 		reply, status, err = handler.OnSubscribe(client.Context(), user, models.SubscribeEvent{
 			Channel: channel,
 			Path:    addr.Path,
@@ -742,7 +784,10 @@ func (g *GrafanaLive) handleOnPublish(ctx context.Context, client *centrifuge.Cl
 		}
 		if ok {
 			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(client.Context(), user)
+				ok, err := rule.PublishAuth.CanPublish(client.Context(), user, pipeline.Vars{
+					OrgID:   orgID,
+					Channel: channel,
+				})
 				if err != nil {
 					logger.Error("Error checking publish permissions", "user", client.UserID(), "client", client.ID(), "channel", e.Channel, "error", err)
 					return centrifuge.PublishReply{}, centrifuge.ErrorInternal
@@ -976,7 +1021,10 @@ func (g *GrafanaLive) HandleHTTPPublish(ctx *models.ReqContext) response.Respons
 		}
 		if ok {
 			if rule.PublishAuth != nil {
-				ok, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), user)
+				ok, err := rule.PublishAuth.CanPublish(ctx.Req.Context(), user, pipeline.Vars{
+					OrgID:   user.OrgID,
+					Channel: channel,
+				})
 				if err != nil {
 					logger.Error("Error checking publish permissions", "user", user, "channel", channel, "error", err)
 					return response.Error(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), nil)
@@ -1047,6 +1095,47 @@ func (g *GrafanaLive) HandleListHTTP(c *models.ReqContext) response.Response {
 	return response.JSONStreaming(http.StatusOK, info)
 }
 
+// HandleHistoryHTTP returns managed stream channel history as a data frame.
+func (g *GrafanaLive) HandleHistoryHTTP(ctx *models.ReqContext) response.Response {
+	channel := web.Params(ctx.Req)["*"]
+	ch, err := live.ParseChannel(channel)
+	if err != nil || ch.Scope != live.ScopeStream {
+		return response.Error(http.StatusBadRequest, "invalid stream channel", err)
+	}
+	ok, err := g.ChannelAuthorizer.CanSubscribe(ctx.Req.Context(), ctx.SignedInUser, pipeline.Vars{
+		OrgID:   ctx.OrgID,
+		Channel: channel,
+	})
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "error checking channel permissions", err)
+	}
+	if !ok {
+		return response.Error(http.StatusForbidden, http.StatusText(http.StatusForbidden), nil)
+	}
+	if g.Cfg.LiveHistoryWindow <= 0 {
+		return response.Error(http.StatusNotFound, "channel history is disabled", nil)
+	}
+	last := g.Cfg.LiveHistoryWindow
+	if lastParam := ctx.Query("last"); lastParam != "" {
+		last, err = gtime.ParseDuration(lastParam)
+		if err != nil || last <= 0 {
+			return response.Error(http.StatusBadRequest, "invalid last parameter", err)
+		}
+	}
+	frame, err := g.ManagedStreamRunner.GetHistory(ctx.Req.Context(), ctx.OrgID, channel, time.Now().Add(-last))
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "error getting channel history", err)
+	}
+	if frame == nil {
+		return response.Error(http.StatusNotFound, "no channel history", nil)
+	}
+	frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "error encoding channel history", err)
+	}
+	return response.Respond(http.StatusOK, frameJSON).SetHeader("Content-Type", "application/json")
+}
+
 // HandleInfoHTTP special http response for
 func (g *GrafanaLive) HandleInfoHTTP(ctx *models.ReqContext) response.Response {
 	path := web.Params(ctx.Req)["*"]
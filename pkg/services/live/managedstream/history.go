@@ -0,0 +1,114 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// FrameHistory keeps a bounded, time-windowed history of frames pushed
+// into managed stream channels.
+type FrameHistory interface {
+	// Add appends full JSON frame to channel history.
+	Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error
+	// Get returns JSON frames added to channel history since the provided time, oldest first.
+	Get(ctx context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error)
+}
+
+// ProvideFrameHistory creates the managed stream channel history configured in
+// the [live] section. History is kept in Redis when Live runs in HA mode, and in
+// memory otherwise. Returns nil when history is disabled.
+func ProvideFrameHistory(cfg *setting.Cfg, redisClient *redis.Client) FrameHistory {
+	if cfg.LiveHistoryWindow <= 0 {
+		return nil
+	}
+	if redisClient != nil {
+		return NewRedisFrameHistory(redisClient, cfg.LiveHistoryWindow, cfg.LiveHistoryMaxFrames)
+	}
+	return NewMemoryFrameHistory(cfg.LiveHistoryWindow, cfg.LiveHistoryMaxFrames)
+}
+
+// ReadHistory returns frames added to channel history since the provided time
+// merged into a single frame. Returns nil frame if history is disabled or empty.
+func ReadHistory(ctx context.Context, h FrameHistory, orgID int64, channel string, since time.Time) (*data.Frame, error) {
+	if h == nil {
+		return nil, nil
+	}
+	items, err := h.Get(ctx, orgID, channel, since)
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]*data.Frame, 0, len(items))
+	for _, item := range items {
+		var frame data.Frame
+		if err := json.Unmarshal(item, &frame); err != nil {
+			return nil, err
+		}
+		frames = append(frames, &frame)
+	}
+	return mergeFrames(frames)
+}
+
+// subscribeOptions can be passed by a client in subscribe request data.
+type subscribeOptions struct {
+	// History is a duration string, i.e. "5m", to get channel history for on join.
+	History string `json:"history,omitempty"`
+}
+
+// historyFromSubscribeData extracts history duration requested by a client.
+// Invalid options are ignored, so a subscriber gets the last frame only.
+func historyFromSubscribeData(d json.RawMessage) (time.Duration, bool) {
+	if len(d) == 0 {
+		return 0, false
+	}
+	var opts subscribeOptions
+	if err := json.Unmarshal(d, &opts); err != nil || opts.History == "" {
+		return 0, false
+	}
+	history, err := time.ParseDuration(opts.History)
+	if err != nil || history <= 0 {
+		logger.Debug("Invalid history in subscribe data", "history", opts.History)
+		return 0, false
+	}
+	return history, true
+}
+
+// mergeFrames merges frames with the schema of the last frame into a single frame.
+// Frames with a different schema (pushed before schema change) are skipped.
+func mergeFrames(frames []*data.Frame) (*data.Frame, error) {
+	if len(frames) == 0 {
+		return nil, nil
+	}
+	last := frames[len(frames)-1]
+	merged := last.EmptyCopy()
+	for _, frame := range frames {
+		if !sameFrameSchema(frame, last) {
+			continue
+		}
+		rowLen, err := frame.RowLen()
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < rowLen; i++ {
+			merged.AppendRow(frame.RowCopy(i)...)
+		}
+	}
+	return merged, nil
+}
+
+func sameFrameSchema(a, b *data.Frame) bool {
+	if a.Name != b.Name || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i := range a.Fields {
+		if a.Fields[i].Name != b.Fields[i].Name || a.Fields[i].Type() != b.Fields[i].Type() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const memoryHistorySweepInterval = time.Minute
+
+type historyItem struct {
+	time      time.Time
+	frameJSON json.RawMessage
+}
+
+// MemoryFrameHistory keeps channel history in process memory.
+type MemoryFrameHistory struct {
+	mu        sync.RWMutex
+	window    time.Duration
+	maxFrames int
+	items     map[int64]map[string][]historyItem
+}
+
+// NewMemoryFrameHistory creates MemoryFrameHistory which keeps at most maxFrames
+// frames not older than window per channel.
+func NewMemoryFrameHistory(window time.Duration, maxFrames int) *MemoryFrameHistory {
+	return &MemoryFrameHistory{
+		window:    window,
+		maxFrames: maxFrames,
+		items:     map[int64]map[string][]historyItem{},
+	}
+}
+
+func (h *MemoryFrameHistory) Add(_ context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.items[orgID]; !ok {
+		h.items[orgID] = map[string][]historyItem{}
+	}
+	items := append(h.items[orgID][channel], historyItem{time: t, frameJSON: frameJSON})
+
+	// Trim items which are out of window or exceed max number of frames.
+	minTime := t.Add(-h.window)
+	start := 0
+	for start < len(items) && items[start].time.Before(minTime) {
+		start++
+	}
+	if len(items)-start > h.maxFrames {
+		start = len(items) - h.maxFrames
+	}
+	if start > 0 {
+		items = append([]historyItem(nil), items[start:]...)
+	}
+	h.items[orgID][channel] = items
+	return nil
+}
+
+// Run periodically drops frames which are out of window, so channels which
+// stopped publishing do not keep their history in memory.
+func (h *MemoryFrameHistory) Run(ctx context.Context) error {
+	ticker := time.NewTicker(memoryHistorySweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.sweep(time.Now())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (h *MemoryFrameHistory) sweep(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	minTime := now.Add(-h.window)
+	for orgID, channels := range h.items {
+		for channel, items := range channels {
+			start := 0
+			for start < len(items) && items[start].time.Before(minTime) {
+				start++
+			}
+			switch {
+			case start == len(items):
+				delete(channels, channel)
+			case start > 0:
+				channels[channel] = append([]historyItem(nil), items[start:]...)
+			}
+		}
+		if len(channels) == 0 {
+			delete(h.items, orgID)
+		}
+	}
+}
+
+func (h *MemoryFrameHistory) Get(_ context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	minTime := time.Now().Add(-h.window)
+	if since.Before(minTime) {
+		since = minTime
+	}
+	var result []json.RawMessage
+	for _, item := range h.items[orgID][channel] {
+		if item.time.Before(since) {
+			continue
+		}
+		result = append(result, item.frameJSON)
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testFrameHistory(t *testing.T, h FrameHistory) {
+	ctx := context.Background()
+	now := time.Now()
+
+	items, err := h.Get(ctx, 1, "stream/test/history", now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, items)
+
+	for i := 0; i < 3; i++ {
+		frameJSON := json.RawMessage(`{"n":` + strconv.Itoa(i) + `}`)
+		err := h.Add(ctx, 1, "stream/test/history", now, frameJSON)
+		require.NoError(t, err)
+	}
+
+	items, err = h.Get(ctx, 1, "stream/test/history", now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	require.JSONEq(t, `{"n":0}`, string(items[0]))
+	require.JSONEq(t, `{"n":2}`, string(items[2]))
+
+	// Different org.
+	items, err = h.Get(ctx, 2, "stream/test/history", now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestMemoryFrameHistory(t *testing.T) {
+	testFrameHistory(t, NewMemoryFrameHistory(time.Hour, 100))
+}
+
+func TestMemoryFrameHistory_Bounds(t *testing.T) {
+	ctx := context.Background()
+	h := NewMemoryFrameHistory(time.Minute, 2)
+	now := time.Now()
+
+	require.NoError(t, h.Add(ctx, 1, "stream/test/a", now.Add(-2*time.Minute), json.RawMessage(`1`)))
+	require.NoError(t, h.Add(ctx, 1, "stream/test/a", now, json.RawMessage(`2`)))
+	// Out of window item is trimmed.
+	items, err := h.Get(ctx, 1, "stream/test/a", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{json.RawMessage(`2`)}, items)
+
+	require.NoError(t, h.Add(ctx, 1, "stream/test/a", now, json.RawMessage(`3`)))
+	require.NoError(t, h.Add(ctx, 1, "stream/test/a", now, json.RawMessage(`4`)))
+	// Only max number of frames kept.
+	items, err = h.Get(ctx, 1, "stream/test/a", now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, []json.RawMessage{json.RawMessage(`3`), json.RawMessage(`4`)}, items)
+}
+
+func TestMemoryFrameHistory_Sweep(t *testing.T) {
+	h := NewMemoryFrameHistory(time.Minute, 100)
+	ctx := context.Background()
+	now := time.Now()
+
+	require.NoError(t, h.Add(ctx, 1, "stream/test/stale", now.Add(-2*time.Minute), json.RawMessage(`{"n":0}`)))
+	require.NoError(t, h.Add(ctx, 1, "stream/test/active", now.Add(-2*time.Minute), json.RawMessage(`{"n":1}`)))
+	require.NoError(t, h.Add(ctx, 1, "stream/test/active", now, json.RawMessage(`{"n":2}`)))
+	require.NoError(t, h.Add(ctx, 2, "stream/test/stale", now.Add(-2*time.Minute), json.RawMessage(`{"n":3}`)))
+
+	h.sweep(now)
+
+	require.NotContains(t, h.items[1], "stream/test/stale")
+	require.NotContains(t, h.items, int64(2))
+	require.Len(t, h.items[1]["stream/test/active"], 1)
+	require.JSONEq(t, `{"n":2}`, string(h.items[1]["stream/test/active"][0].frameJSON))
+}
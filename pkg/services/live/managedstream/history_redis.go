@@ -0,0 +1,76 @@
+package managedstream
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/services/live/orgchannel"
+)
+
+// RedisFrameHistory keeps channel history in Redis streams, so it's
+// shared between Grafana instances in HA setup.
+type RedisFrameHistory struct {
+	redisClient *redis.Client
+	window      time.Duration
+	maxFrames   int
+}
+
+// NewRedisFrameHistory creates RedisFrameHistory which keeps approximately maxFrames
+// frames not older than window per channel.
+func NewRedisFrameHistory(redisClient *redis.Client, window time.Duration, maxFrames int) *RedisFrameHistory {
+	return &RedisFrameHistory{
+		redisClient: redisClient,
+		window:      window,
+		maxFrames:   maxFrames,
+	}
+}
+
+func (h *RedisFrameHistory) Add(ctx context.Context, orgID int64, channel string, t time.Time, frameJSON json.RawMessage) error {
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+
+	pipe := h.redisClient.TxPipeline()
+	defer func() { _ = pipe.Close() }()
+
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: key,
+		MaxLen: int64(h.maxFrames),
+		Approx: true,
+		Values: map[string]interface{}{
+			"time":  t.UnixMilli(),
+			"frame": string(frameJSON),
+		},
+	})
+	pipe.Expire(ctx, key, h.window)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (h *RedisFrameHistory) Get(ctx context.Context, orgID int64, channel string, since time.Time) ([]json.RawMessage, error) {
+	minTime := time.Now().Add(-h.window)
+	if since.Before(minTime) {
+		since = minTime
+	}
+	key := getHistoryKey(orgchannel.PrependOrgID(orgID, channel))
+	// Stream entry IDs are prefixed with a millisecond timestamp of insertion.
+	messages, err := h.redisClient.XRange(ctx, key, strconv.FormatInt(since.UnixMilli(), 10), "+").Result()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]json.RawMessage, 0, len(messages))
+	for _, msg := range messages {
+		frame, ok := msg.Values["frame"].(string)
+		if !ok {
+			continue
+		}
+		result = append(result, json.RawMessage(frame))
+	}
+	return result, nil
+}
+
+func getHistoryKey(channelID string) string {
+	return "gf_live.managed_stream_history." + channelID
+}
@@ -0,0 +1,23 @@
+//go:build redis
+// +build redis
+
+package managedstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisFrameHistory(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	require.NoError(t, redisClient.Del(context.Background(), getHistoryKey("1/stream/test/history")).Err())
+	h := NewRedisFrameHistory(redisClient, time.Hour, 100)
+	require.NotNil(t, h)
+	testFrameHistory(t, h)
+}
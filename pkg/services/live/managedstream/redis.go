@@ -0,0 +1,29 @@
+package managedstream
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ProvideRedisClient connects to the Redis server of the Live HA engine. The client
+// is shared by the managed stream frame cache and channel history, so each Grafana
+// instance keeps a single connection pool. Returns nil client when Live does not run
+// in HA mode.
+func ProvideRedisClient(cfg *setting.Cfg) (*redis.Client, error) {
+	if cfg.LiveHAEngine == "" {
+		return nil, nil
+	}
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: cfg.LiveHAEngineAddress,
+	})
+	cmd := redisClient.Ping(context.Background())
+	if _, err := cmd.Result(); err != nil {
+		_ = redisClient.Close()
+		return nil, fmt.Errorf("error pinging Redis: %v", err)
+	}
+	return redisClient, nil
+}
@@ -42,19 +42,21 @@ type Runner struct {
 	publisher      models.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 }
 
 type LocalPublisher interface {
 	PublishLocal(channel string, data []byte) error
 }
 
-// NewRunner creates new Runner.
-func NewRunner(publisher models.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache) *Runner {
+// NewRunner creates new Runner. If frameHistory is nil then channel history is not kept.
+func NewRunner(publisher models.ChannelPublisher, localPublisher LocalPublisher, frameCache FrameCache, frameHistory FrameHistory) *Runner {
 	return &Runner{
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		streams:        map[int64]map[string]*NamespaceStream{},
 		frameCache:     frameCache,
+		frameHistory:   frameHistory,
 	}
 }
 
@@ -125,12 +127,21 @@ func (r *Runner) GetOrCreateStream(orgID int64, scope string, namespace string)
 	prefix := scope + "/" + namespace
 	s, ok := r.streams[orgID][prefix]
 	if !ok {
-		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache)
+		s = NewNamespaceStream(orgID, scope, namespace, r.publisher, r.localPublisher, r.frameCache, r.frameHistory)
 		r.streams[orgID][prefix] = s
 	}
 	return s, nil
 }
 
+// GetHistory returns frames pushed into a managed stream channel since the provided
+// time merged into a single frame. Returns nil frame if history is disabled or empty.
+func (r *Runner) GetHistory(ctx context.Context, orgID int64, channel string, since time.Time) (*data.Frame, error) {
+	if _, err := live.ParseChannel(channel); err != nil {
+		return nil, err
+	}
+	return ReadHistory(ctx, r.frameHistory, orgID, channel, since)
+}
+
 // NamespaceStream holds the state of a managed stream.
 type NamespaceStream struct {
 	orgID          int64
@@ -139,6 +150,7 @@ type NamespaceStream struct {
 	publisher      models.ChannelPublisher
 	localPublisher LocalPublisher
 	frameCache     FrameCache
+	frameHistory   FrameHistory
 	rateMu         sync.RWMutex
 	rates          map[string][60]rateEntry
 }
@@ -156,7 +168,7 @@ type ManagedChannel struct {
 }
 
 // NewNamespaceStream creates new NamespaceStream.
-func NewNamespaceStream(orgID int64, scope string, namespace string, publisher models.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache) *NamespaceStream {
+func NewNamespaceStream(orgID int64, scope string, namespace string, publisher models.ChannelPublisher, localPublisher LocalPublisher, schemaUpdater FrameCache, frameHistory FrameHistory) *NamespaceStream {
 	return &NamespaceStream{
 		orgID:          orgID,
 		scope:          scope,
@@ -164,6 +176,7 @@ func NewNamespaceStream(orgID int64, scope string, namespace string, publisher m
 		publisher:      publisher,
 		localPublisher: localPublisher,
 		frameCache:     schemaUpdater,
+		frameHistory:   frameHistory,
 		rates:          map[string][60]rateEntry{},
 	}
 }
@@ -186,6 +199,14 @@ func (s *NamespaceStream) Push(ctx context.Context, path string, frame *data.Fra
 		return err
 	}
 
+	// History is best-effort, a failure to save it should not prevent publishing.
+	if s.frameHistory != nil {
+		err := s.frameHistory.Add(ctx, s.orgID, channel, time.Now(), jsonFrameCache.Bytes(data.IncludeAll))
+		if err != nil {
+			logger.Error("Error adding frame to managed stream history", "channel", channel, "error", err)
+		}
+	}
+
 	// When the schema has not changed, just send the data.
 	include := data.IncludeDataOnly
 	if isUpdated {
@@ -235,12 +256,33 @@ func (s *NamespaceStream) minuteRate(path string) int64 {
 	return total
 }
 
+// History returns frames pushed into a stream path since the provided time
+// merged into a single frame. Returns nil frame if there is no history.
+func (s *NamespaceStream) History(ctx context.Context, path string, since time.Time) (*data.Frame, error) {
+	channel := live.Channel{Scope: s.scope, Namespace: s.namespace, Path: path}.String()
+	return ReadHistory(ctx, s.frameHistory, s.orgID, channel, since)
+}
+
 func (s *NamespaceStream) GetHandlerForPath(_ string) (models.ChannelHandler, error) {
 	return s, nil
 }
 
 func (s *NamespaceStream) OnSubscribe(ctx context.Context, u *user.SignedInUser, e models.SubscribeEvent) (models.SubscribeReply, backend.SubscribeStreamStatus, error) {
 	reply := models.SubscribeReply{}
+	if history, ok := historyFromSubscribeData(e.Data); ok && s.frameHistory != nil {
+		frame, err := s.History(ctx, e.Path, time.Now().Add(-history))
+		if err != nil {
+			return reply, 0, err
+		}
+		if frame != nil {
+			frameJSON, err := data.FrameToJSON(frame, data.IncludeAll)
+			if err != nil {
+				return reply, 0, err
+			}
+			reply.Data = frameJSON
+			return reply, backend.SubscribeStreamStatusOK, nil
+		}
+	}
 	frameJSON, ok, err := s.frameCache.GetFrame(ctx, u.OrgID, e.Channel)
 	if err != nil {
 		return reply, 0, err
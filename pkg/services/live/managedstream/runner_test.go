@@ -2,11 +2,17 @@ package managedstream
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type testPublisher struct {
@@ -19,13 +25,13 @@ func (p *testPublisher) publish(_ int64, _ string, _ []byte) error {
 
 func TestNewManagedStream(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(), nil)
 	require.NotNil(t, c)
 }
 
 func TestManagedStreamMinuteRate(t *testing.T) {
 	publisher := &testPublisher{t: t}
-	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache())
+	c := NewNamespaceStream(1, "stream", "a", publisher.publish, nil, NewMemoryFrameCache(), nil)
 	require.NotNil(t, c)
 
 	c.incRate("test1", time.Now().Unix())
@@ -48,7 +54,7 @@ func TestManagedStreamMinuteRate(t *testing.T) {
 func TestGetManagedStreams(t *testing.T) {
 	publisher := &testPublisher{t: t}
 	frameCache := NewMemoryFrameCache()
-	runner := NewRunner(publisher.publish, nil, frameCache)
+	runner := NewRunner(publisher.publish, nil, frameCache, nil)
 	s1, err := runner.GetOrCreateStream(1, "stream", "test1")
 	require.NoError(t, err)
 	s2, err := runner.GetOrCreateStream(1, "stream", "test2")
@@ -83,3 +89,76 @@ func TestGetManagedStreams(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, managedChannels, 7) // Not affected by other org.
 }
+
+func TestManagedStreamHistory(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	runner := NewRunner(publisher.publish, nil, NewMemoryFrameCache(), NewMemoryFrameHistory(time.Hour, 100))
+	s, err := runner.GetOrCreateStream(1, "stream", "test")
+	require.NoError(t, err)
+
+	err = s.Push(context.Background(), "cpu", data.NewFrame("cpu", data.NewField("value", nil, []float64{1})))
+	require.NoError(t, err)
+	// Frames with outdated schema are not included into history.
+	err = s.Push(context.Background(), "cpu", data.NewFrame("cpu", data.NewField("value", nil, []float64{2}), data.NewField("host", nil, []string{"a"})))
+	require.NoError(t, err)
+	err = s.Push(context.Background(), "cpu", data.NewFrame("cpu", data.NewField("value", nil, []float64{3, 4}), data.NewField("host", nil, []string{"b", "c"})))
+	require.NoError(t, err)
+
+	frame, err := runner.GetHistory(context.Background(), 1, "stream/test/cpu", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, frame)
+	require.Len(t, frame.Fields, 2)
+	require.Equal(t, 3, frame.Fields[0].Len())
+	require.Equal(t, []float64{2, 3, 4}, []float64{frame.Fields[0].At(0).(float64), frame.Fields[0].At(1).(float64), frame.Fields[0].At(2).(float64)})
+
+	u := &user.SignedInUser{OrgID: 1}
+	reply, status, err := s.OnSubscribe(context.Background(), u, models.SubscribeEvent{
+		Channel: "stream/test/cpu",
+		Path:    "cpu",
+		Data:    json.RawMessage(`{"history": "5m"}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, backend.SubscribeStreamStatusOK, status)
+	var subscribeFrame data.Frame
+	require.NoError(t, json.Unmarshal(reply.Data, &subscribeFrame))
+	require.Equal(t, 3, subscribeFrame.Fields[0].Len())
+
+	// Without history only last frame returned.
+	reply, _, err = s.OnSubscribe(context.Background(), u, models.SubscribeEvent{
+		Channel: "stream/test/cpu",
+		Path:    "cpu",
+	})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(reply.Data, &subscribeFrame))
+	require.Equal(t, 2, subscribeFrame.Fields[0].Len())
+}
+
+type failingFrameHistory struct{}
+
+func (failingFrameHistory) Add(context.Context, int64, string, time.Time, json.RawMessage) error {
+	return errors.New("history unavailable")
+}
+
+func (failingFrameHistory) Get(context.Context, int64, string, time.Time) ([]json.RawMessage, error) {
+	return nil, errors.New("history unavailable")
+}
+
+func TestManagedStreamHistoryIsBestEffort(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	runner := NewRunner(publisher.publish, nil, NewMemoryFrameCache(), failingFrameHistory{})
+	s, err := runner.GetOrCreateStream(1, "stream", "test")
+	require.NoError(t, err)
+
+	err = s.Push(context.Background(), "cpu", data.NewFrame("cpu", data.NewField("value", nil, []float64{1})))
+	require.NoError(t, err)
+}
+
+func TestGetHistoryDoesNotCreateStreams(t *testing.T) {
+	publisher := &testPublisher{t: t}
+	runner := NewRunner(publisher.publish, nil, NewMemoryFrameCache(), NewMemoryFrameHistory(time.Hour, 100))
+
+	frame, err := runner.GetHistory(context.Background(), 1, "stream/unknown/cpu", time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, frame)
+	require.Empty(t, runner.streams)
+}
@@ -3,6 +3,7 @@ package pipeline
 import (
 	"context"
 
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/user"
 )
@@ -15,10 +16,30 @@ func NewRoleCheckAuthorizer(role org.RoleType) *RoleCheckAuthorizer {
 	return &RoleCheckAuthorizer{role: role}
 }
 
-func (s *RoleCheckAuthorizer) CanSubscribe(_ context.Context, u *user.SignedInUser) (bool, error) {
+func (s *RoleCheckAuthorizer) CanSubscribe(_ context.Context, u *user.SignedInUser, _ Vars) (bool, error) {
 	return u.HasRole(s.role), nil
 }
 
-func (s *RoleCheckAuthorizer) CanPublish(_ context.Context, u *user.SignedInUser) (bool, error) {
+func (s *RoleCheckAuthorizer) CanPublish(_ context.Context, u *user.SignedInUser, _ Vars) (bool, error) {
 	return u.HasRole(s.role), nil
 }
+
+// AccessControlAuthorizer checks live:subscribe and live:publish permissions
+// scoped to a channel. For example, a user with live:publish permission on
+// live:stream/factory/* scope can publish to all channels in stream/factory
+// namespace.
+type AccessControlAuthorizer struct {
+	accessControl accesscontrol.AccessControl
+}
+
+func NewAccessControlAuthorizer(accessControl accesscontrol.AccessControl) *AccessControlAuthorizer {
+	return &AccessControlAuthorizer{accessControl: accessControl}
+}
+
+func (s *AccessControlAuthorizer) CanSubscribe(ctx context.Context, u *user.SignedInUser, vars Vars) (bool, error) {
+	return s.accessControl.Evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionLiveSubscribe, accesscontrol.ScopeLiveChannel(vars.Channel)))
+}
+
+func (s *AccessControlAuthorizer) CanPublish(ctx context.Context, u *user.SignedInUser, vars Vars) (bool, error) {
+	return s.accessControl.Evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionLivePublish, accesscontrol.ScopeLiveChannel(vars.Channel)))
+}
@@ -0,0 +1,136 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type testAuthStorage struct {
+	Storage
+	channelRules []ChannelRule
+}
+
+func (s *testAuthStorage) ListChannelRules(_ context.Context, _ int64) ([]ChannelRule, error) {
+	return s.channelRules, nil
+}
+
+func (s *testAuthStorage) ListWriteConfigs(_ context.Context, _ int64) ([]WriteConfig, error) {
+	return nil, nil
+}
+
+func TestAccessControlAuthorizer(t *testing.T) {
+	authorizer := NewAccessControlAuthorizer(acimpl.ProvideAccessControl(setting.NewCfg()))
+
+	u := &user.SignedInUser{
+		OrgID:   1,
+		OrgRole: org.RoleViewer,
+		Permissions: map[int64]map[string][]string{
+			1: {
+				accesscontrol.ActionLivePublish:   {accesscontrol.ScopeLiveChannel("stream/factory/*")},
+				accesscontrol.ActionLiveSubscribe: {accesscontrol.ScopeLiveStreamsAll},
+			},
+		},
+	}
+
+	testCases := []struct {
+		channel      string
+		canPublish   bool
+		canSubscribe bool
+	}{
+		{channel: "stream/factory/line1", canPublish: true, canSubscribe: true},
+		{channel: "stream/factory/line1/temp", canPublish: true, canSubscribe: true},
+		{channel: "stream/warehouse/line1", canPublish: false, canSubscribe: true},
+		{channel: "plugin/testdata/random-2s-stream", canPublish: false, canSubscribe: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.channel, func(t *testing.T) {
+			vars := Vars{OrgID: 1, Channel: tc.channel}
+			ok, err := authorizer.CanPublish(context.Background(), u, vars)
+			require.NoError(t, err)
+			require.Equal(t, tc.canPublish, ok)
+			ok, err = authorizer.CanSubscribe(context.Background(), u, vars)
+			require.NoError(t, err)
+			require.Equal(t, tc.canSubscribe, ok)
+		})
+	}
+}
+
+func TestStorageRuleBuilder_AuthCheck(t *testing.T) {
+	builder := &StorageRuleBuilder{
+		Storage: &testAuthStorage{
+			channelRules: []ChannelRule{
+				{
+					Pattern: "stream/factory/:line",
+					Settings: ChannelRuleSettings{
+						Auth: &ChannelAuthConfig{
+							Subscribe: &ChannelAuthCheckConfig{RequireRole: org.RoleEditor},
+							Publish:   &ChannelAuthCheckConfig{RequirePermission: true},
+						},
+					},
+				},
+			},
+		},
+		AccessControl: acimpl.ProvideAccessControl(setting.NewCfg()),
+	}
+	tree := NewCacheSegmentedTree(builder)
+
+	rule, ok, err := tree.Get(1, "stream/factory/line1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.IsType(t, &RoleCheckAuthorizer{}, rule.SubscribeAuth)
+	require.IsType(t, &AccessControlAuthorizer{}, rule.PublishAuth)
+
+	serviceAccount := &user.SignedInUser{
+		OrgID:   1,
+		OrgRole: org.RoleViewer,
+		Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionLivePublish: {accesscontrol.ScopeLiveChannel("stream/factory/line1")}},
+		},
+	}
+	editor := &user.SignedInUser{OrgID: 1, OrgRole: org.RoleEditor}
+
+	ok, err = rule.PublishAuth.CanPublish(context.Background(), serviceAccount, Vars{OrgID: 1, Channel: "stream/factory/line1"})
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = rule.PublishAuth.CanPublish(context.Background(), serviceAccount, Vars{OrgID: 1, Channel: "stream/factory/line2"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = rule.PublishAuth.CanPublish(context.Background(), editor, Vars{OrgID: 1, Channel: "stream/factory/line1"})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = rule.SubscribeAuth.CanSubscribe(context.Background(), serviceAccount, Vars{OrgID: 1, Channel: "stream/factory/line1"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = rule.SubscribeAuth.CanSubscribe(context.Background(), editor, Vars{OrgID: 1, Channel: "stream/factory/line1"})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestStorageRuleBuilder_AuthCheckConflict(t *testing.T) {
+	builder := &StorageRuleBuilder{
+		Storage: &testAuthStorage{
+			channelRules: []ChannelRule{
+				{
+					Pattern: "stream/factory/:line",
+					Settings: ChannelRuleSettings{
+						Auth: &ChannelAuthConfig{
+							Publish: &ChannelAuthCheckConfig{RequireRole: org.RoleEditor, RequirePermission: true},
+						},
+					},
+				},
+			},
+		},
+		AccessControl: acimpl.ProvideAccessControl(setting.NewCfg()),
+	}
+	_, err := builder.BuildRules(context.Background(), 1)
+	require.Error(t, err)
+}
@@ -8,6 +8,9 @@ import (
 // ChannelAuthCheckConfig is used to define auth rules for a channel.
 type ChannelAuthCheckConfig struct {
 	RequireRole org.RoleType `json:"role,omitempty"`
+	// RequirePermission enables access control check of live:subscribe or
+	// live:publish permission scoped to a channel, i.e. live:stream/factory/line1.
+	RequirePermission bool `json:"permission,omitempty"`
 }
 
 type ChannelAuthConfig struct {
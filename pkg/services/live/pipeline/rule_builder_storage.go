@@ -2,9 +2,11 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/centrifugal/centrifuge"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
@@ -16,6 +18,26 @@ type StorageRuleBuilder struct {
 	Storage              Storage
 	ChannelHandlerGetter ChannelHandlerGetter
 	SecretsService       secrets.Service
+	AccessControl        accesscontrol.AccessControl
+}
+
+// authChecker is implemented by both RoleCheckAuthorizer and AccessControlAuthorizer.
+type authChecker interface {
+	SubscribeAuthChecker
+	PublishAuthChecker
+}
+
+func (f *StorageRuleBuilder) extractAuthChecker(config *ChannelAuthCheckConfig) (authChecker, error) {
+	if config.RequirePermission {
+		if config.RequireRole != "" {
+			return nil, errors.New("role and permission auth checks can't be used together")
+		}
+		if f.AccessControl == nil {
+			return nil, errors.New("access control is not configured")
+		}
+		return NewAccessControlAuthorizer(f.AccessControl), nil
+	}
+	return NewRoleCheckAuthorizer(config.RequireRole), nil
 }
 
 func (f *StorageRuleBuilder) extractSubscriber(config *SubscriberConfig) (Subscriber, error) {
@@ -317,16 +339,22 @@ func (f *StorageRuleBuilder) BuildRules(ctx context.Context, orgID int64) ([]*Li
 			Pattern: ruleConfig.Pattern,
 		}
 
+		var err error
+
 		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Subscribe != nil {
-			rule.SubscribeAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Subscribe.RequireRole)
+			rule.SubscribeAuth, err = f.extractAuthChecker(ruleConfig.Settings.Auth.Subscribe)
+			if err != nil {
+				return nil, fmt.Errorf("error building subscribe auth for %s: %w", rule.Pattern, err)
+			}
 		}
 
 		if ruleConfig.Settings.Auth != nil && ruleConfig.Settings.Auth.Publish != nil {
-			rule.PublishAuth = NewRoleCheckAuthorizer(ruleConfig.Settings.Auth.Publish.RequireRole)
+			rule.PublishAuth, err = f.extractAuthChecker(ruleConfig.Settings.Auth.Publish)
+			if err != nil {
+				return nil, fmt.Errorf("error building publish auth for %s: %w", rule.Pattern, err)
+			}
 		}
 
-		var err error
-
 		rule.Converter, err = f.extractConverter(ruleConfig.Settings.Converter)
 		if err != nil {
 			return nil, fmt.Errorf("error building converter for %s: %w", rule.Pattern, err)
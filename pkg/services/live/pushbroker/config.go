@@ -0,0 +1,106 @@
+package pushbroker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	brokerTypeMQTT = "mqtt"
+	brokerTypeNATS = "nats"
+)
+
+// Subscription binds a broker topic (may contain wildcards) to a Live stream channel.
+type Subscription struct {
+	// Topic is an MQTT topic filter or a NATS subject to subscribe to.
+	Topic string
+	// Converter is a Live Pipeline converter type used to turn message
+	// payload into frames: jsonAuto, influxAuto or jsonFrame.
+	Converter string
+	// Channel is a stream channel prefix, i.e. stream/factory. Topic levels matched
+	// by wildcards are appended to it as channel path.
+	Channel string
+}
+
+// Config of a single broker connection.
+type Config struct {
+	Enabled       bool
+	URL           string
+	ClientID      string
+	Username      string
+	Password      string
+	OrgID         int64
+	FrameFormat   string
+	Subscriptions []Subscription
+}
+
+func readConfig(cfg *setting.Cfg, brokerType string, defaultURL string) (Config, error) {
+	section := cfg.SectionWithEnvOverrides("live." + brokerType)
+	c := Config{
+		Enabled:     section.Key("enabled").MustBool(false),
+		URL:         section.Key("url").MustString(defaultURL),
+		ClientID:    section.Key("client_id").MustString("grafana"),
+		Username:    section.Key("username").MustString(""),
+		Password:    section.Key("password").MustString(""),
+		OrgID:       section.Key("org_id").MustInt64(1),
+		FrameFormat: section.Key("frame_format").MustString("labels_column"),
+	}
+	if !c.Enabled {
+		return c, nil
+	}
+	syntax := mqttTopicSyntax
+	if brokerType == brokerTypeNATS {
+		syntax = natsTopicSyntax
+	}
+	subscriptions, err := parseSubscriptions(section.Key("subscriptions").MustString(""), syntax)
+	if err != nil {
+		return c, fmt.Errorf("invalid [live.%s] subscriptions: %w", brokerType, err)
+	}
+	c.Subscriptions = subscriptions
+	return c, nil
+}
+
+// parseSubscriptions parses a comma-separated list of subscriptions where
+// each subscription is defined as topic|converter|channel.
+func parseSubscriptions(value string, syntax topicSyntax) ([]Subscription, error) {
+	var subscriptions []Subscription
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "|")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("subscription %q must be in topic|converter|channel format", entry)
+		}
+		sub := Subscription{
+			Topic:     strings.TrimSpace(parts[0]),
+			Converter: strings.TrimSpace(parts[1]),
+			Channel:   strings.Trim(strings.TrimSpace(parts[2]), "/"),
+		}
+		if err := syntax.validateFilter(sub.Topic); err != nil {
+			return nil, err
+		}
+		switch sub.Converter {
+		case pipeline.ConverterTypeJsonAuto, pipeline.ConverterTypeInfluxAuto, pipeline.ConverterTypeJsonFrame:
+		default:
+			return nil, fmt.Errorf("unsupported converter %q for topic %s", sub.Converter, sub.Topic)
+		}
+		channelID := sub.Channel
+		if syntax.hasWildcard(sub.Topic) {
+			// Channel may be set without path, path is then built from matched topic levels.
+			channelID += "/_"
+		}
+		channel, err := live.ParseChannel(channelID)
+		if err != nil || channel.Scope != live.ScopeStream {
+			return nil, fmt.Errorf("channel for topic %s must be a stream channel, got %q", sub.Topic, sub.Channel)
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+	return subscriptions, nil
+}
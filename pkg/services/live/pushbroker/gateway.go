@@ -0,0 +1,182 @@
+package pushbroker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	gflive "github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var (
+	logger = log.New("live.push_broker")
+)
+
+// messageHandler is called for every message received over a broker subscription.
+type messageHandler func(sub Subscription, topic string, payload []byte)
+
+type brokerClient interface {
+	Connect() error
+	Close()
+}
+
+type broker struct {
+	name       string
+	config     Config
+	syntax     topicSyntax
+	converters map[string]pipeline.Converter
+	client     brokerClient
+	// failed is set when the broker could not be connected, its messages are not ingested.
+	failed atomic.Bool
+}
+
+func ProvideService(cfg *setting.Cfg, live *gflive.GrafanaLive) (*Gateway, error) {
+	g := &Gateway{
+		GrafanaLive: live,
+	}
+	mqttConfig, err := readConfig(cfg, brokerTypeMQTT, "tcp://127.0.0.1:1883")
+	if err != nil {
+		return nil, err
+	}
+	if mqttConfig.Enabled {
+		b := newBroker(brokerTypeMQTT, mqttConfig, mqttTopicSyntax)
+		b.client = newMQTTClient(mqttConfig, g.messageHandler(b))
+		g.brokers = append(g.brokers, b)
+	}
+	natsConfig, err := readConfig(cfg, brokerTypeNATS, "nats://127.0.0.1:4222")
+	if err != nil {
+		return nil, err
+	}
+	if natsConfig.Enabled {
+		b := newBroker(brokerTypeNATS, natsConfig, natsTopicSyntax)
+		b.client = newNATSClient(natsConfig, g.messageHandler(b))
+		g.brokers = append(g.brokers, b)
+	}
+	return g, nil
+}
+
+func newBroker(name string, c Config, syntax topicSyntax) *broker {
+	return &broker{
+		name:   name,
+		config: c,
+		syntax: syntax,
+		converters: map[string]pipeline.Converter{
+			pipeline.ConverterTypeJsonAuto:   pipeline.NewAutoJsonConverter(pipeline.AutoJsonConverterConfig{}),
+			pipeline.ConverterTypeJsonFrame:  pipeline.NewJsonFrameConverter(pipeline.JsonFrameConverterConfig{}),
+			pipeline.ConverterTypeInfluxAuto: pipeline.NewAutoInfluxConverter(pipeline.AutoInfluxConverterConfig{FrameFormat: c.FrameFormat}),
+		},
+	}
+}
+
+// Gateway subscribes to MQTT topics and NATS subjects and translates
+// received messages to Grafana Live stream publications.
+type Gateway struct {
+	GrafanaLive *gflive.GrafanaLive
+
+	brokers []*broker
+}
+
+// Run Gateway. The gateway is optional, so a broker which can't be connected is
+// marked as failed and does not stop Grafana.
+func (g *Gateway) Run(ctx context.Context) error {
+	if len(g.brokers) == 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	eGroup, eCtx := errgroup.WithContext(ctx)
+	for _, b := range g.brokers {
+		b := b
+		eGroup.Go(func() error {
+			logger.Info("Live broker gateway initialization", "broker", b.name, "url", b.config.URL)
+			if err := b.client.Connect(); err != nil {
+				logger.Error("Live broker gateway failed, broker messages won't be ingested", "broker", b.name, "url", b.config.URL, "error", err)
+				b.client.Close()
+				b.failed.Store(true)
+				<-eCtx.Done()
+				return eCtx.Err()
+			}
+			defer b.client.Close()
+			<-eCtx.Done()
+			return eCtx.Err()
+		})
+	}
+	return eGroup.Wait()
+}
+
+func (g *Gateway) messageHandler(b *broker) messageHandler {
+	return func(sub Subscription, topic string, payload []byte) {
+		logger.Debug("Live broker message",
+			"broker", b.name,
+			"topic", topic,
+			"bodyLength", len(payload),
+		)
+		err := g.processMessage(context.Background(), b, sub, topic, payload)
+		if err != nil {
+			logger.Error("Error processing broker message", "broker", b.name, "topic", topic, "error", err)
+		}
+	}
+}
+
+func (g *Gateway) processMessage(ctx context.Context, b *broker, sub Subscription, topic string, payload []byte) error {
+	channelID, ok, err := b.syntax.channelForTopic(sub, topic)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+
+	// Channel rules of Live Pipeline take precedence over subscription converter.
+	if g.GrafanaLive.Pipeline != nil {
+		ruleFound, err := g.GrafanaLive.Pipeline.ProcessInput(ctx, b.config.OrgID, channelID, payload)
+		if err != nil {
+			return fmt.Errorf("pipeline input processing error: %w", err)
+		}
+		if ruleFound {
+			return nil
+		}
+	}
+
+	converter, ok := b.converters[sub.Converter]
+	if !ok {
+		return fmt.Errorf("unknown converter type: %s", sub.Converter)
+	}
+	channel, err := live.ParseChannel(channelID)
+	if err != nil {
+		return err
+	}
+	channelFrames, err := converter.Convert(ctx, pipeline.Vars{
+		OrgID:     b.config.OrgID,
+		Channel:   channelID,
+		Scope:     channel.Scope,
+		Namespace: channel.Namespace,
+		Path:      channel.Path,
+	}, payload)
+	if err != nil {
+		return fmt.Errorf("error converting %s message: %w", sub.Converter, err)
+	}
+
+	for _, cf := range channelFrames {
+		frameChannel := channel
+		if cf.Channel != "" {
+			frameChannel, err = live.ParseChannel(cf.Channel)
+			if err != nil {
+				return err
+			}
+		}
+		stream, err := g.GrafanaLive.ManagedStreamRunner.GetOrCreateStream(b.config.OrgID, frameChannel.Scope, frameChannel.Namespace)
+		if err != nil {
+			return fmt.Errorf("error getting stream: %w", err)
+		}
+		if err := stream.Push(ctx, frameChannel.Path, cf.Frame); err != nil {
+			return fmt.Errorf("error pushing frame: %w", err)
+		}
+	}
+	return nil
+}
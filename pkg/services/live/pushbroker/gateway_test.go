@@ -0,0 +1,131 @@
+package pushbroker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	gflive "github.com/grafana/grafana/pkg/services/live"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+)
+
+type testPublisher struct {
+	mu       sync.Mutex
+	channels []string
+}
+
+func (p *testPublisher) publish(_ int64, channel string, _ []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.channels = append(p.channels, channel)
+	return nil
+}
+
+func (p *testPublisher) published() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.channels...)
+}
+
+func newTestGateway(publisher *testPublisher) *Gateway {
+	return &Gateway{
+		GrafanaLive: &gflive.GrafanaLive{
+			ManagedStreamRunner: managedstream.NewRunner(publisher.publish, nil, managedstream.NewMemoryFrameCache(), nil),
+		},
+	}
+}
+
+func TestGateway_ProcessMessage(t *testing.T) {
+	publisher := &testPublisher{}
+	g := newTestGateway(publisher)
+	b := newBroker(brokerTypeMQTT, Config{OrgID: 1, FrameFormat: "labels_column"}, mqttTopicSyntax)
+
+	err := g.processMessage(context.Background(), b, Subscription{
+		Topic:     "factory/#",
+		Converter: "jsonAuto",
+		Channel:   "stream/factory",
+	}, "factory/line1", []byte(`{"temperature": 21.5}`))
+	require.NoError(t, err)
+
+	err = g.processMessage(context.Background(), b, Subscription{
+		Topic:     "telegraf/+",
+		Converter: "influxAuto",
+		Channel:   "stream/telegraf",
+	}, "telegraf/host1", []byte(`cpu,host=host1 usage=0.5 1636119600000000000`))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"stream/factory/line1", "stream/telegraf/host1/cpu"}, publisher.published())
+}
+
+func TestGateway_ProcessMessage_NoMatch(t *testing.T) {
+	publisher := &testPublisher{}
+	g := newTestGateway(publisher)
+	b := newBroker(brokerTypeNATS, Config{OrgID: 1}, natsTopicSyntax)
+
+	err := g.processMessage(context.Background(), b, Subscription{
+		Topic:     "factory.*",
+		Converter: "jsonAuto",
+		Channel:   "stream/factory",
+	}, "other.line1", []byte(`{"temperature": 21.5}`))
+	require.NoError(t, err)
+	require.Empty(t, publisher.published())
+}
+
+func TestGateway_ProcessMessage_InvalidPayload(t *testing.T) {
+	g := newTestGateway(&testPublisher{})
+	b := newBroker(brokerTypeMQTT, Config{OrgID: 1}, mqttTopicSyntax)
+
+	err := g.processMessage(context.Background(), b, Subscription{
+		Topic:     "factory/#",
+		Converter: "jsonFrame",
+		Channel:   "stream/factory",
+	}, "factory/line1", []byte(`not a frame`))
+	require.Error(t, err)
+}
+
+type testBrokerClient struct {
+	connectErr error
+	closed     chan struct{}
+}
+
+func (c *testBrokerClient) Connect() error {
+	return c.connectErr
+}
+
+func (c *testBrokerClient) Close() {
+	close(c.closed)
+}
+
+func TestGateway_RunKeepsRunningWhenBrokerFails(t *testing.T) {
+	g := newTestGateway(&testPublisher{})
+	failing := newBroker(brokerTypeMQTT, Config{OrgID: 1}, mqttTopicSyntax)
+	failing.client = &testBrokerClient{connectErr: errors.New("not authorized"), closed: make(chan struct{})}
+	connected := newBroker(brokerTypeNATS, Config{OrgID: 1}, natsTopicSyntax)
+	connectedClient := &testBrokerClient{closed: make(chan struct{})}
+	connected.client = connectedClient
+	g.brokers = []*broker{failing, connected}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- g.Run(ctx)
+	}()
+
+	require.Eventually(t, failing.failed.Load, time.Second, 10*time.Millisecond)
+	select {
+	case err := <-done:
+		t.Fatalf("gateway stopped after a broker failed: %v", err)
+	case <-connectedClient.closed:
+		t.Fatal("connected broker closed after another broker failed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.False(t, connected.failed.Load())
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	<-connectedClient.closed
+}
@@ -0,0 +1,69 @@
+package pushbroker
+
+import (
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// mqttClient subscribes to MQTT topics. Subscriptions are (re)established
+// every time the client connects to the broker.
+type mqttClient struct {
+	config  Config
+	handler messageHandler
+	client  mqtt.Client
+}
+
+func newMQTTClient(c Config, handler messageHandler) *mqttClient {
+	return &mqttClient{config: c, handler: handler}
+}
+
+func (c *mqttClient) Connect() error {
+	opts := mqtt.NewClientOptions().
+		AddBroker(c.config.URL).
+		SetClientID(c.config.ClientID).
+		SetUsername(c.config.Username).
+		SetPassword(c.config.Password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second).
+		SetOnConnectHandler(c.subscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Warn("MQTT connection lost", "url", c.config.URL, "error", err)
+		})
+	c.client = mqtt.NewClient(opts)
+	token := c.client.Connect()
+	if !token.WaitTimeout(10 * time.Second) {
+		// With connect retry enabled client keeps connecting in background.
+		logger.Warn("MQTT broker is not available yet, will keep trying", "url", c.config.URL)
+		return nil
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("error connecting to MQTT broker %s: %w", c.config.URL, err)
+	}
+	return nil
+}
+
+func (c *mqttClient) subscribe(client mqtt.Client) {
+	logger.Info("Connected to MQTT broker", "url", c.config.URL)
+	for _, sub := range c.config.Subscriptions {
+		sub := sub
+		token := client.Subscribe(sub.Topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+			c.handler(sub, msg.Topic(), msg.Payload())
+		})
+		// Called from paho's connection goroutine, so we can't block on token here.
+		go func() {
+			token.Wait()
+			if err := token.Error(); err != nil {
+				logger.Error("Error subscribing to MQTT topic", "topic", sub.Topic, "error", err)
+			}
+		}()
+	}
+}
+
+func (c *mqttClient) Close() {
+	if c.client != nil {
+		c.client.Disconnect(250)
+	}
+}
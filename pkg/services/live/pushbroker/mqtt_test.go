@@ -0,0 +1,40 @@
+//go:build mqtt
+// +build mqtt
+
+package pushbroker
+
+import (
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/stretchr/testify/require"
+)
+
+// Run devenv/docker/blocks/mqtt to start a local MQTT broker for this test.
+func TestMQTTClient(t *testing.T) {
+	publisher := &testPublisher{}
+	g := newTestGateway(publisher)
+	c := Config{
+		URL:           "tcp://127.0.0.1:1883",
+		ClientID:      "grafana-test",
+		OrgID:         1,
+		Subscriptions: []Subscription{{Topic: "grafana-test/#", Converter: "jsonAuto", Channel: "stream/test"}},
+	}
+	b := newBroker(brokerTypeMQTT, c, mqttTopicSyntax)
+	client := newMQTTClient(c, g.messageHandler(b))
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	producer := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(c.URL).SetClientID("grafana-test-producer"))
+	token := producer.Connect()
+	require.True(t, token.WaitTimeout(5*time.Second))
+	require.NoError(t, token.Error())
+	defer producer.Disconnect(250)
+
+	require.Eventually(t, func() bool {
+		producer.Publish("grafana-test/line1/temp", 0, false, `{"value": 1}`).Wait()
+		return len(publisher.published()) > 0
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, "stream/test/line1/temp", publisher.published()[0])
+}
@@ -0,0 +1,58 @@
+package pushbroker
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsClient subscribes to NATS subjects. NATS client restores
+// subscriptions automatically upon reconnect.
+type natsClient struct {
+	config  Config
+	handler messageHandler
+	conn    *nats.Conn
+}
+
+func newNATSClient(c Config, handler messageHandler) *natsClient {
+	return &natsClient{config: c, handler: handler}
+}
+
+func (c *natsClient) Connect() error {
+	opts := []nats.Option{
+		nats.Name(c.config.ClientID),
+		nats.MaxReconnects(-1),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				logger.Warn("NATS connection lost", "url", c.config.URL, "error", err)
+			}
+		}),
+	}
+	if c.config.Username != "" {
+		opts = append(opts, nats.UserInfo(c.config.Username, c.config.Password))
+	}
+	conn, err := nats.Connect(c.config.URL, opts...)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS server %s: %w", c.config.URL, err)
+	}
+	c.conn = conn
+	for _, sub := range c.config.Subscriptions {
+		sub := sub
+		_, err := conn.Subscribe(sub.Topic, func(msg *nats.Msg) {
+			c.handler(sub, msg.Subject, msg.Data)
+		})
+		if err != nil {
+			conn.Close()
+			return fmt.Errorf("error subscribing to NATS subject %s: %w", sub.Topic, err)
+		}
+	}
+	logger.Info("Subscribed to NATS subjects", "url", c.config.URL, "numSubscriptions", len(c.config.Subscriptions))
+	return nil
+}
+
+func (c *natsClient) Close() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
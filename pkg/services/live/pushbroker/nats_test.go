@@ -0,0 +1,38 @@
+//go:build nats
+// +build nats
+
+package pushbroker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+)
+
+// Run devenv/docker/blocks/nats to start a local NATS server for this test.
+func TestNATSClient(t *testing.T) {
+	publisher := &testPublisher{}
+	g := newTestGateway(publisher)
+	c := Config{
+		URL:           nats.DefaultURL,
+		ClientID:      "grafana-test",
+		OrgID:         1,
+		Subscriptions: []Subscription{{Topic: "grafana-test.>", Converter: "jsonAuto", Channel: "stream/test"}},
+	}
+	b := newBroker(brokerTypeNATS, c, natsTopicSyntax)
+	client := newNATSClient(c, g.messageHandler(b))
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	producer, err := nats.Connect(c.URL)
+	require.NoError(t, err)
+	defer producer.Close()
+
+	require.Eventually(t, func() bool {
+		require.NoError(t, producer.Publish("grafana-test.line1.temp", []byte(`{"value": 1}`)))
+		return len(publisher.published()) > 0
+	}, 5*time.Second, 100*time.Millisecond)
+	require.Equal(t, "stream/test/line1/temp", publisher.published()[0])
+}
@@ -0,0 +1,117 @@
+package pushbroker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/live"
+)
+
+// topicSyntax describes how a message broker separates topic levels and
+// which tokens it uses as single-level and multi-level wildcards.
+type topicSyntax struct {
+	separator   string
+	singleLevel string
+	multiLevel  string
+}
+
+var (
+	// mqttTopicSyntax is used for MQTT topic filters, i.e. factory/+/metrics or factory/#.
+	mqttTopicSyntax = topicSyntax{separator: "/", singleLevel: "+", multiLevel: "#"}
+	// natsTopicSyntax is used for NATS subjects, i.e. factory.*.metrics or factory.>.
+	natsTopicSyntax = topicSyntax{separator: ".", singleLevel: "*", multiLevel: ">"}
+)
+
+// validateFilter checks that wildcards are used as whole levels and that a
+// multi-level wildcard appears only at the end of a topic filter.
+func (s topicSyntax) validateFilter(filter string) error {
+	if filter == "" {
+		return fmt.Errorf("empty topic")
+	}
+	levels := strings.Split(filter, s.separator)
+	for i, level := range levels {
+		if level == s.multiLevel {
+			if i != len(levels)-1 {
+				return fmt.Errorf("multi-level wildcard %q must be the last level in topic %q", s.multiLevel, filter)
+			}
+			continue
+		}
+		if level != s.singleLevel && (strings.Contains(level, s.singleLevel) || strings.Contains(level, s.multiLevel)) {
+			return fmt.Errorf("wildcard must occupy an entire level in topic %q", filter)
+		}
+	}
+	return nil
+}
+
+func (s topicSyntax) hasWildcard(filter string) bool {
+	for _, level := range strings.Split(filter, s.separator) {
+		if level == s.singleLevel || level == s.multiLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// match checks whether topic matches filter. If it does then match returns topic
+// levels starting from the first wildcard level of the filter – those levels
+// are used to build a Live channel path.
+func (s topicSyntax) match(filter string, topic string) ([]string, bool) {
+	filterLevels := strings.Split(filter, s.separator)
+	topicLevels := strings.Split(topic, s.separator)
+
+	wildcardIndex := -1
+	for i, level := range filterLevels {
+		if level == s.multiLevel {
+			if len(topicLevels) <= i {
+				return nil, false
+			}
+			if wildcardIndex < 0 {
+				wildcardIndex = i
+			}
+			return topicLevels[wildcardIndex:], true
+		}
+		if i >= len(topicLevels) {
+			return nil, false
+		}
+		if level == s.singleLevel {
+			if wildcardIndex < 0 {
+				wildcardIndex = i
+			}
+			continue
+		}
+		if level != topicLevels[i] {
+			return nil, false
+		}
+	}
+	if len(filterLevels) != len(topicLevels) {
+		return nil, false
+	}
+	if wildcardIndex < 0 {
+		return nil, true
+	}
+	return topicLevels[wildcardIndex:], true
+}
+
+// channelForTopic maps a topic received over subscription to a Live channel.
+// Topic levels matched by wildcards are appended to the subscription channel
+// as channel path parts. For example, MQTT message with topic factory/line1/temp
+// received over factory/# subscription with stream/factory channel will be
+// published into stream/factory/line1/temp.
+func (s topicSyntax) channelForTopic(sub Subscription, topic string) (string, bool, error) {
+	levels, ok := s.match(sub.Topic, topic)
+	if !ok {
+		return "", false, nil
+	}
+	channelID := sub.Channel
+	if len(levels) > 0 {
+		channelID = channelID + "/" + strings.Join(levels, "/")
+	}
+	channel, err := live.ParseChannel(channelID)
+	if err != nil {
+		return "", true, err
+	}
+	if channel.Scope != live.ScopeStream {
+		return "", true, fmt.Errorf("topic %s resolved to a non-stream channel %s", topic, channelID)
+	}
+	return channelID, true, nil
+}
@@ -0,0 +1,118 @@
+package pushbroker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicSyntax_ValidateFilter(t *testing.T) {
+	require.NoError(t, mqttTopicSyntax.validateFilter("factory/+/metrics"))
+	require.NoError(t, mqttTopicSyntax.validateFilter("factory/#"))
+	require.Error(t, mqttTopicSyntax.validateFilter(""))
+	require.Error(t, mqttTopicSyntax.validateFilter("factory/#/metrics"))
+	require.Error(t, mqttTopicSyntax.validateFilter("factory/line+/metrics"))
+
+	require.NoError(t, natsTopicSyntax.validateFilter("factory.*.metrics"))
+	require.NoError(t, natsTopicSyntax.validateFilter("factory.>"))
+	require.Error(t, natsTopicSyntax.validateFilter("factory.>.metrics"))
+}
+
+func TestTopicSyntax_ChannelForTopic(t *testing.T) {
+	testCases := []struct {
+		name    string
+		syntax  topicSyntax
+		sub     Subscription
+		topic   string
+		channel string
+		matched bool
+		err     bool
+	}{
+		{
+			name:    "mqtt exact topic",
+			syntax:  mqttTopicSyntax,
+			sub:     Subscription{Topic: "factory/temp", Channel: "stream/factory/temp"},
+			topic:   "factory/temp",
+			channel: "stream/factory/temp",
+			matched: true,
+		},
+		{
+			name:    "mqtt single level wildcard",
+			syntax:  mqttTopicSyntax,
+			sub:     Subscription{Topic: "factory/+/temp", Channel: "stream/factory"},
+			topic:   "factory/line1/temp",
+			channel: "stream/factory/line1/temp",
+			matched: true,
+		},
+		{
+			name:    "mqtt multi level wildcard",
+			syntax:  mqttTopicSyntax,
+			sub:     Subscription{Topic: "factory/#", Channel: "stream/factory"},
+			topic:   "factory/line1/machine2/temp",
+			channel: "stream/factory/line1/machine2/temp",
+			matched: true,
+		},
+		{
+			name:    "mqtt no match",
+			syntax:  mqttTopicSyntax,
+			sub:     Subscription{Topic: "factory/+/temp", Channel: "stream/factory"},
+			topic:   "factory/line1/humidity",
+			matched: false,
+		},
+		{
+			name:    "mqtt invalid channel path",
+			syntax:  mqttTopicSyntax,
+			sub:     Subscription{Topic: "factory/#", Channel: "stream/factory"},
+			topic:   "factory/line 1",
+			matched: true,
+			err:     true,
+		},
+		{
+			name:    "nats wildcards",
+			syntax:  natsTopicSyntax,
+			sub:     Subscription{Topic: "svc.*.>", Channel: "stream/services"},
+			topic:   "svc.api.requests.total",
+			channel: "stream/services/api/requests/total",
+			matched: true,
+		},
+		{
+			name:    "nats multi level wildcard requires a token",
+			syntax:  natsTopicSyntax,
+			sub:     Subscription{Topic: "svc.>", Channel: "stream/services"},
+			topic:   "svc",
+			matched: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			channel, matched, err := tc.syntax.channelForTopic(tc.sub, tc.topic)
+			require.Equal(t, tc.matched, matched)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.channel, channel)
+		})
+	}
+}
+
+func TestParseSubscriptions(t *testing.T) {
+	subs, err := parseSubscriptions("factory/#|influxAuto|stream/factory, sensors/temp|jsonAuto|stream/sensors/temp", mqttTopicSyntax)
+	require.NoError(t, err)
+	require.Equal(t, []Subscription{
+		{Topic: "factory/#", Converter: "influxAuto", Channel: "stream/factory"},
+		{Topic: "sensors/temp", Converter: "jsonAuto", Channel: "stream/sensors/temp"},
+	}, subs)
+
+	_, err = parseSubscriptions("factory/#|jsonAuto", mqttTopicSyntax)
+	require.Error(t, err)
+	_, err = parseSubscriptions("factory/#|jsonExact|stream/factory", mqttTopicSyntax)
+	require.Error(t, err)
+	_, err = parseSubscriptions("factory/#|jsonAuto|plugin/factory", mqttTopicSyntax)
+	require.Error(t, err)
+	// Exact topic requires channel with path.
+	_, err = parseSubscriptions("factory/temp|jsonAuto|stream/factory", mqttTopicSyntax)
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/live"
 	"github.com/grafana/grafana/pkg/services/live/convert"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
 	"github.com/grafana/grafana/pkg/services/live/pushurl"
 	"github.com/grafana/grafana/pkg/setting"
 
@@ -86,6 +87,19 @@ func (g *Gateway) Handle(ctx *models.ReqContext) {
 	// TODO -- make sure all packets are combined together!
 	// interval = "1s" vs flush_interval = "5s"
 
+	for _, mf := range metricFrames {
+		ok, err := g.canPublish(ctx, liveDto.ScopeStream+"/"+streamID+"/"+mf.Key())
+		if err != nil {
+			logger.Error("Error checking publish permissions", "error", err, "streamId", streamID)
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			ctx.Resp.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
 	for _, mf := range metricFrames {
 		err := stream.Push(ctx.Req.Context(), mf.Key(), mf.Frame())
 		if err != nil {
@@ -113,6 +127,19 @@ func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 		"bodyLength", len(body),
 	)
 
+	if channel, err := liveDto.ParseChannel(channelID); err == nil && channel.Scope == liveDto.ScopeStream {
+		ok, err := g.canPublish(ctx, channelID)
+		if err != nil {
+			logger.Error("Error checking publish permissions", "error", err, "channel", channelID)
+			ctx.Resp.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !ok {
+			ctx.Resp.WriteHeader(http.StatusForbidden)
+			return
+		}
+	}
+
 	ruleFound, err := g.GrafanaLive.Pipeline.ProcessInput(ctx.Req.Context(), ctx.OrgID, channelID, body)
 	if err != nil {
 		logger.Error("Pipeline input processing error", "error", err, "body", string(body))
@@ -131,3 +158,11 @@ func (g *Gateway) HandlePipelinePush(ctx *models.ReqContext) {
 
 	ctx.Resp.WriteHeader(http.StatusOK)
 }
+
+// canPublish checks whether a user has live:publish permission for a stream channel.
+func (g *Gateway) canPublish(ctx *models.ReqContext, channel string) (bool, error) {
+	return g.GrafanaLive.ChannelAuthorizer.CanPublish(ctx.Req.Context(), ctx.SignedInUser, pipeline.Vars{
+		OrgID:   ctx.OrgID,
+		Channel: channel,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/live/convert"
 	"github.com/grafana/grafana/pkg/services/live/livecontext"
 	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/live/pipeline"
 	"github.com/grafana/grafana/pkg/services/live/pushurl"
 
 	"github.com/gorilla/websocket"
@@ -15,13 +16,14 @@ import (
 // Handler handles WebSocket client connections that push data to Live.
 type Handler struct {
 	managedStreamRunner *managedstream.Runner
+	authorizer          *pipeline.AccessControlAuthorizer
 	config              Config
 	upgrade             *websocket.Upgrader
 	converter           *convert.Converter
 }
 
 // NewHandler creates new Handler.
-func NewHandler(managedStreamRunner *managedstream.Runner, c Config) *Handler {
+func NewHandler(managedStreamRunner *managedstream.Runner, authorizer *pipeline.AccessControlAuthorizer, c Config) *Handler {
 	if c.CheckOrigin == nil {
 		c.CheckOrigin = sameHostOriginCheck()
 	}
@@ -32,6 +34,7 @@ func NewHandler(managedStreamRunner *managedstream.Runner, c Config) *Handler {
 	}
 	return &Handler{
 		managedStreamRunner: managedStreamRunner,
+		authorizer:          authorizer,
 		config:              c,
 		upgrade:             upgrade,
 		converter:           convert.NewConverter(),
@@ -91,7 +94,20 @@ func (s *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, mf := range metricFrames {
-			err := stream.Push(r.Context(), mf.Key(), mf.Frame())
+			channel := liveDto.ScopeStream + "/" + streamID + "/" + mf.Key()
+			ok, err := s.authorizer.CanPublish(r.Context(), user, pipeline.Vars{
+				OrgID:   user.OrgID,
+				Channel: channel,
+			})
+			if err != nil {
+				logger.Error("Error checking publish permissions", "error", err, "channel", channel)
+				return
+			}
+			if !ok {
+				logger.Warn("Push to a channel is not permitted", "user", user.UserID, "channel", channel)
+				continue
+			}
+			err = stream.Push(r.Context(), mf.Key(), mf.Frame())
 			if err != nil {
 				logger.Error("Error pushing frame", "error", err, "data", string(body))
 				return
@@ -13,6 +13,7 @@ import (
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -62,10 +63,25 @@ func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUI
 			return err
 		}
 		logger.Debug("deleted alert instances", "count", rows)
+
+		for _, uid := range ruleUID {
+			if err := st.insertAlertRuleEvent(sess, orgID, uid, storesrv.EntityEventTypeDelete); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 }
 
+// insertAlertRuleEvent records the change of an alert rule so that the search index can follow it.
+func (st DBstore) insertAlertRuleEvent(sess *db.Session, orgID int64, ruleUID string, eventType storesrv.EntityEventType) error {
+	if !storesrv.EntityEventsEnabled(st.FeatureToggles) {
+		return nil
+	}
+	_, err := sess.Insert(storesrv.NewDatabaseEntityEvent(ruleUID, orgID, storesrv.EntityTypeAlertRule, eventType))
+	return err
+}
+
 // IncreaseVersionForAllRulesInNamespace Increases version for all rules that have specified namespace. Returns all rules that belong to the namespace
 func (st DBstore) IncreaseVersionForAllRulesInNamespace(ctx context.Context, orgID int64, namespaceUID string) ([]ngmodels.AlertRuleKeyWithVersion, error) {
 	var keys []ngmodels.AlertRuleKeyWithVersion
@@ -162,6 +178,9 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 					return fmt.Errorf("failed to create new rules: %w", err)
 				}
 				ids[newRules[i].UID] = newRules[i].ID
+				if err := st.insertAlertRuleEvent(sess, newRules[i].OrgID, newRules[i].UID, storesrv.EntityEventTypeCreate); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -198,6 +217,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				}
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
+			if err := st.insertAlertRuleEvent(sess, r.New.OrgID, r.New.UID, storesrv.EntityEventTypeUpdate); err != nil {
+				return err
+			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:        r.New.OrgID,
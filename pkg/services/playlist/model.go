@@ -1,7 +1,10 @@
 package playlist
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/grafana/grafana/pkg/kinds/playlist"
 )
@@ -25,6 +28,14 @@ type Playlist struct {
 type PlaylistDTO = playlist.Playlist
 type PlaylistItemDTO = playlist.PlaylistItem
 type PlaylistItemType = playlist.PlaylistItemType
+type PlaylistItemVariable = playlist.PlaylistItemVariable
+
+const (
+	PlaylistItemTypeDashboardById     = playlist.PlaylistItemTypeDashboardById
+	PlaylistItemTypeDashboardBySearch = playlist.PlaylistItemTypeDashboardBySearch
+	PlaylistItemTypeDashboardByTag    = playlist.PlaylistItemTypeDashboardByTag
+	PlaylistItemTypeDashboardByUid    = playlist.PlaylistItemTypeDashboardByUid
+)
 
 type PlaylistItem struct {
 	Id         int64  `db:"id"`
@@ -33,6 +44,44 @@ type PlaylistItem struct {
 	Value      string `json:"value" db:"value"`
 	Order      int    `json:"order" db:"order"`
 	Title      string `json:"title" db:"title"`
+
+	// Optional overrides of the playlist settings for this item
+	Interval  string                `json:"interval,omitempty" db:"interval"`
+	TimeFrom  string                `json:"timeFrom,omitempty" db:"time_from"`
+	TimeTo    string                `json:"timeTo,omitempty" db:"time_to"`
+	Variables PlaylistItemVariables `json:"variables,omitempty" db:"variables"`
+}
+
+// PlaylistItemVariables is stored as JSON in the playlist_item table
+type PlaylistItemVariables []PlaylistItemVariable
+
+// Value implements driver.Valuer
+func (v PlaylistItemVariables) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// Scan implements sql.Scanner
+func (v *PlaylistItemVariables) Scan(src interface{}) error {
+	var raw []byte
+	switch t := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		raw = t
+	case string:
+		raw = []byte(t)
+	default:
+		return fmt.Errorf("unsupported type for playlist item variables: %T", src)
+	}
+	if len(raw) == 0 {
+		*v = nil
+		return nil
+	}
+	return json.Unmarshal(raw, v)
 }
 
 type Playlists []*Playlist
@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/store/entity"
 )
 
@@ -21,11 +22,13 @@ func ProvideService(db db.DB, toggles featuremgmt.FeatureToggles, objserver enti
 	// 🐢🐢🐢 pick the store
 	if toggles.IsEnabled(featuremgmt.FlagNewDBLibrary) { // hymmm not a registered feature flag
 		sqlstore = &sqlxStore{
-			sess: db.GetSqlxSession(),
+			sess:            db.GetSqlxSession(),
+			emitEntityEvent: storesrv.EntityEventsEnabled(toggles),
 		}
 	} else {
 		sqlstore = &sqlStore{
-			db: db,
+			db:              db,
+			emitEntityEvent: storesrv.EntityEventsEnabled(toggles),
 		}
 	}
 	svc := &Service{store: sqlstore}
@@ -78,6 +81,21 @@ func (s *Service) Get(ctx context.Context, q *playlist.GetPlaylistByUidQuery) (*
 		if title != "" {
 			items[i].Title = &title
 		}
+
+		// Item overrides are only set when they differ from the playlist
+		if interval := rawItems[i].Interval; interval != "" {
+			items[i].Interval = &interval
+		}
+		if timeFrom := rawItems[i].TimeFrom; timeFrom != "" {
+			items[i].TimeFrom = &timeFrom
+		}
+		if timeTo := rawItems[i].TimeTo; timeTo != "" {
+			items[i].TimeTo = &timeTo
+		}
+		if len(rawItems[i].Variables) > 0 {
+			variables := []playlist.PlaylistItemVariable(rawItems[i].Variables)
+			items[i].Variables = &variables
+		}
 	}
 	return &playlist.PlaylistDTO{
 		Uid:      v.UID,
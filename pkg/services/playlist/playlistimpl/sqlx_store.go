@@ -8,10 +8,12 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/playlist"
 	"github.com/grafana/grafana/pkg/services/sqlstore/session"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 )
 
 type sqlxStore struct {
-	sess *session.SessionDB
+	sess            *session.SessionDB
+	emitEntityEvent bool
 }
 
 func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
@@ -46,15 +48,19 @@ func (s *sqlxStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComm
 					Value:      item.Value,
 					Order:      order + 1,
 					Title:      item.Title,
+					Interval:   item.Interval,
+					TimeFrom:   item.TimeFrom,
+					TimeTo:     item.TimeTo,
+					Variables:  item.Variables,
 				})
 			}
-			query := `INSERT INTO playlist_item (playlist_id, type, value, title, "order") VALUES (:playlist_id, :type, :value, :title, :order)`
+			query := `INSERT INTO playlist_item (playlist_id, type, value, title, "order", "interval", time_from, time_to, variables) VALUES (:playlist_id, :type, :value, :title, :order, :interval, :time_from, :time_to, :variables)`
 			_, err = tx.NamedExec(ctx, query, playlistItems)
 			if err != nil {
 				return err
 			}
 		}
-		return nil
+		return s.insertEntityEvent(ctx, tx, p.OrgId, p.UID, storesrv.EntityEventTypeCreate)
 	})
 
 	return &p, err
@@ -98,11 +104,18 @@ func (s *sqlxStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComm
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeFrom:   item.TimeFrom,
+				TimeTo:     item.TimeTo,
+				Variables:  item.Variables,
 			})
 		}
-		query = `INSERT INTO playlist_item (playlist_id, type, value, title, "order") VALUES (:playlist_id, :type, :value, :title, :order)`
+		query = `INSERT INTO playlist_item (playlist_id, type, value, title, "order", "interval", time_from, time_to, variables) VALUES (:playlist_id, :type, :value, :title, :order, :interval, :time_from, :time_to, :variables)`
 		_, err = tx.NamedExec(ctx, query, playlistItems)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.insertEntityEvent(ctx, tx, p.OrgId, p.UID, storesrv.EntityEventTypeUpdate)
 	})
 
 	return &dto, err
@@ -145,12 +158,22 @@ func (s *sqlxStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComm
 		if _, err := tx.Exec(ctx, "DELETE FROM playlist_item WHERE playlist_id = ?", p.Id); err != nil {
 			return err
 		}
-		return nil
+		return s.insertEntityEvent(ctx, tx, cmd.OrgId, cmd.UID, storesrv.EntityEventTypeDelete)
 	})
 
 	return err
 }
 
+// insertEntityEvent records the change of a playlist so that the search index can follow it.
+func (s *sqlxStore) insertEntityEvent(ctx context.Context, tx *session.SessionTx, orgID int64, uid string, eventType storesrv.EntityEventType) error {
+	if !s.emitEntityEvent {
+		return nil
+	}
+	e := storesrv.NewDatabaseEntityEvent(uid, orgID, storesrv.EntityTypePlaylist, eventType)
+	_, err := tx.Exec(ctx, "INSERT INTO entity_event (entity_id, event_type, created) VALUES (?, ?, ?)", e.EntityId, e.EventType, e.Created)
+	return err
+}
+
 func (s *sqlxStore) List(ctx context.Context, query *playlist.GetPlaylistsQuery) (playlist.Playlists, error) {
 	playlists := make(playlist.Playlists, 0)
 	if query.OrgId == 0 {
@@ -14,3 +14,12 @@ func TestIntegrationSQLxPlaylistDataAccess(t *testing.T) {
 		return &sqlxStore{sess: ss.GetSqlxSession()}
 	})
 }
+
+func TestIntegrationSQLxPlaylistEntityEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testIntegrationPlaylistEntityEvents(t, func(ss db.DB) store {
+		return &sqlxStore{sess: ss.GetSqlxSession(), emitEntityEvent: true}
+	})
+}
@@ -2,12 +2,14 @@ package playlistimpl
 
 import (
 	"context"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 )
 
 type getStore func(db.DB) store
@@ -64,6 +66,43 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 		})
 	})
 
+	t.Run("Can store item overrides", func(t *testing.T) {
+		items := []playlist.PlaylistItem{
+			{Title: "prod", Value: `{"tags":["prod"],"sort":"name_sort"}`, Type: "dashboard_by_search",
+				Interval: "30s", TimeFrom: "now-1h", TimeTo: "now",
+				Variables: playlist.PlaylistItemVariables{{Name: "env", Values: []string{"prod", "staging"}}}},
+			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag"},
+		}
+		cmd := playlist.CreatePlaylistCommand{Name: "TV wall", Interval: "5m", OrgId: 1, Items: items}
+		p, err := playlistStore.Insert(context.Background(), &cmd)
+		require.NoError(t, err)
+
+		get := &playlist.GetPlaylistItemsByUidQuery{PlaylistUID: p.UID, OrgId: 1}
+		stored, err := playlistStore.GetItems(context.Background(), get)
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		sort.Slice(stored, func(i, j int) bool { return stored[i].Order < stored[j].Order })
+
+		require.Equal(t, "30s", stored[0].Interval)
+		require.Equal(t, "now-1h", stored[0].TimeFrom)
+		require.Equal(t, "now", stored[0].TimeTo)
+		require.Equal(t, items[0].Variables, stored[0].Variables)
+		require.Empty(t, stored[1].Interval)
+		require.Empty(t, stored[1].Variables)
+
+		update := playlist.UpdatePlaylistCommand{Name: "TV wall", OrgId: 1, UID: p.UID, Interval: "5m", Items: items[1:]}
+		_, err = playlistStore.Update(context.Background(), &update)
+		require.NoError(t, err)
+
+		stored, err = playlistStore.GetItems(context.Background(), get)
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		require.Empty(t, stored[0].Variables)
+
+		err = playlistStore.Delete(context.Background(), &playlist.DeletePlaylistCommand{UID: p.UID, OrgId: 1})
+		require.NoError(t, err)
+	})
+
 	t.Run("Search playlist", func(t *testing.T) {
 		items := []playlist.PlaylistItem{
 			{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag"},
@@ -124,3 +163,29 @@ func testIntegrationPlaylistDataAccess(t *testing.T, fn getStore) {
 		}
 	})
 }
+
+func testIntegrationPlaylistEntityEvents(t *testing.T, fn getStore) {
+	t.Helper()
+
+	ss := db.InitTestDB(t)
+	playlistStore := fn(ss)
+
+	items := []playlist.PlaylistItem{{Title: "graphite", Value: "graphite", Type: "dashboard_by_tag"}}
+	p, err := playlistStore.Insert(context.Background(), &playlist.CreatePlaylistCommand{Name: "NYC office", Interval: "10m", OrgId: 1, Items: items})
+	require.NoError(t, err)
+	_, err = playlistStore.Update(context.Background(), &playlist.UpdatePlaylistCommand{Name: "LA office", Interval: "10m", OrgId: 1, UID: p.UID, Items: items})
+	require.NoError(t, err)
+	err = playlistStore.Delete(context.Background(), &playlist.DeletePlaylistCommand{UID: p.UID, OrgId: 1})
+	require.NoError(t, err)
+
+	events := make([]*storesrv.EntityEvent, 0)
+	err = ss.WithDbSession(context.Background(), func(sess *db.Session) error {
+		return sess.OrderBy("id").Find(&events)
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	for i, eventType := range []storesrv.EntityEventType{storesrv.EntityEventTypeCreate, storesrv.EntityEventTypeUpdate, storesrv.EntityEventTypeDelete} {
+		require.Equal(t, eventType, events[i].EventType)
+		require.Equal(t, "database/1/playlist/"+p.UID, events[i].EntityId)
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/playlist"
+	storesrv "github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 type sqlStore struct {
-	db db.DB
+	db              db.DB
+	emitEntityEvent bool
 }
 
 func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistCommand) (*playlist.Playlist, error) {
@@ -41,12 +43,19 @@ func (s *sqlStore) Insert(ctx context.Context, cmd *playlist.CreatePlaylistComma
 				Value:      item.Value,
 				Order:      order + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeFrom:   item.TimeFrom,
+				TimeTo:     item.TimeTo,
+				Variables:  item.Variables,
 			})
 		}
 
 		_, err = sess.Insert(&playlistItems)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return s.insertEntityEvent(sess, p.OrgId, p.UID, storesrv.EntityEventTypeCreate)
 	})
 	return &p, err
 }
@@ -95,11 +104,19 @@ func (s *sqlStore) Update(ctx context.Context, cmd *playlist.UpdatePlaylistComma
 				Value:      item.Value,
 				Order:      index + 1,
 				Title:      item.Title,
+				Interval:   item.Interval,
+				TimeFrom:   item.TimeFrom,
+				TimeTo:     item.TimeTo,
+				Variables:  item.Variables,
 			})
 		}
 
 		_, err = sess.Insert(&playlistItems)
-		return err
+		if err != nil {
+			return err
+		}
+
+		return s.insertEntityEvent(sess, p.OrgId, p.UID, storesrv.EntityEventTypeUpdate)
 	})
 	return &dto, err
 }
@@ -142,11 +159,23 @@ func (s *sqlStore) Delete(ctx context.Context, cmd *playlist.DeletePlaylistComma
 
 		var rawItemSQL = "DELETE FROM playlist_item WHERE playlist_id = ?"
 		_, err = sess.Exec(rawItemSQL, playlist.Id)
+		if err != nil {
+			return err
+		}
 
-		return err
+		return s.insertEntityEvent(sess, cmd.OrgId, cmd.UID, storesrv.EntityEventTypeDelete)
 	})
 }
 
+// insertEntityEvent records the change of a playlist so that the search index can follow it.
+func (s *sqlStore) insertEntityEvent(sess *db.Session, orgID int64, uid string, eventType storesrv.EntityEventType) error {
+	if !s.emitEntityEvent {
+		return nil
+	}
+	_, err := sess.Insert(storesrv.NewDatabaseEntityEvent(uid, orgID, storesrv.EntityTypePlaylist, eventType))
+	return err
+}
+
 func (s *sqlStore) List(ctx context.Context, query *playlist.GetPlaylistsQuery) (playlist.Playlists, error) {
 	playlists := make(playlist.Playlists, 0)
 	if query.OrgId == 0 {
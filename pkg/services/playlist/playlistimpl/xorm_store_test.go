@@ -14,3 +14,12 @@ func TestIntegrationXormPlaylistDataAccess(t *testing.T) {
 		return &sqlStore{db: ss}
 	})
 }
+
+func TestIntegrationXormPlaylistEntityEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	testIntegrationPlaylistEntityEvents(t, func(ss db.DB) store {
+		return &sqlStore{db: ss, emitEntityEvent: true}
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/plugins/repo"
 	"github.com/grafana/grafana/pkg/services/oauthtoken"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/clientmiddleware"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginuser"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -43,6 +44,8 @@ var WireSet = wire.NewSet(
 	repo.ProvideService,
 	wire.Bind(new(repo.Service), new(*repo.Manager)),
 	plugincontext.ProvideService,
+	pluginuser.ProvideResolver,
+	wire.Bind(new(pluginuser.Resolver), new(*pluginuser.ResolverImpl)),
 )
 
 // WireExtensionSet provides a wire.ProviderSet of plugin providers that can be
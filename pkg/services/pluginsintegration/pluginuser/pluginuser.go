@@ -0,0 +1,91 @@
+// Package pluginuser resolves the Grafana user behind a plugin request, so core
+// plugins can check the permissions of the caller.
+package pluginuser
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var ErrUserNotFound = errors.New("plugin request user not found")
+
+// Resolver returns the signed in user of a plugin request with the permissions
+// of the user in the org of the request.
+type Resolver interface {
+	SignedInUser(ctx context.Context, orgID int64, backendUser *backend.User) (*user.SignedInUser, error)
+}
+
+type ResolverImpl struct {
+	cfg         *setting.Cfg
+	userService user.Service
+	orgService  org.Service
+	acService   accesscontrol.Service
+	log         log.Logger
+}
+
+func ProvideResolver(cfg *setting.Cfg, userService user.Service, orgService org.Service, acService accesscontrol.Service) *ResolverImpl {
+	return &ResolverImpl{
+		cfg:         cfg,
+		userService: userService,
+		orgService:  orgService,
+		acService:   acService,
+		log:         log.New("plugin.user"),
+	}
+}
+
+func (r *ResolverImpl) SignedInUser(ctx context.Context, orgID int64, backendUser *backend.User) (*user.SignedInUser, error) {
+	var usr *user.SignedInUser
+	if backendUser == nil || (backendUser.Login == "" && backendUser.Email == "") {
+		if !r.cfg.AnonymousEnabled {
+			return nil, ErrUserNotFound
+		}
+		orga, err := r.orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: r.cfg.AnonymousOrgName})
+		if err != nil {
+			return nil, err
+		}
+		if orga.ID != orgID {
+			return nil, ErrUserNotFound
+		}
+		usr = &user.SignedInUser{
+			OrgID:       orga.ID,
+			OrgName:     orga.Name,
+			OrgRole:     org.RoleType(r.cfg.AnonymousOrgRole),
+			IsAnonymous: true,
+		}
+	} else {
+		var err error
+		usr, err = r.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{
+			Login: backendUser.Login,
+			Email: backendUser.Email,
+			OrgID: orgID,
+		})
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			return nil, err
+		}
+		if usr.OrgID != orgID {
+			return nil, ErrUserNotFound
+		}
+	}
+
+	permissions, err := r.acService.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		r.log.Error("Failed to retrieve user permissions", "error", err, "login", usr.Login)
+		return nil, err
+	}
+	if usr.Permissions == nil {
+		usr.Permissions = make(map[int64]map[string][]string)
+	}
+	usr.Permissions[orgID] = accesscontrol.GroupScopesByAction(permissions)
+	return usr, nil
+}
@@ -0,0 +1,61 @@
+package pluginuser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestResolver_SignedInUser(t *testing.T) {
+	permissions := []accesscontrol.Permission{{Action: "datasources:query", Scope: "datasources:uid:abc"}}
+	acService := &actest.FakeService{ExpectedPermissions: permissions}
+
+	t.Run("should resolve the user with permissions in the org of the request", func(t *testing.T) {
+		userService := &usertest.FakeUserService{GetSignedInUserFn: func(_ context.Context, q *user.GetSignedInUserQuery) (*user.SignedInUser, error) {
+			assert.Equal(t, "editor", q.Login)
+			return &user.SignedInUser{UserID: 2, Login: q.Login, OrgID: q.OrgID, IsGrafanaAdmin: true}, nil
+		}}
+		r := ProvideResolver(setting.NewCfg(), userService, orgtest.NewOrgServiceFake(), acService)
+
+		usr, err := r.SignedInUser(context.Background(), 3, &backend.User{Login: "editor"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), usr.UserID)
+		assert.True(t, usr.IsGrafanaAdmin)
+		assert.Equal(t, []string{"datasources:uid:abc"}, usr.Permissions[3]["datasources:query"])
+	})
+
+	t.Run("should not resolve a user that is not a member of the org", func(t *testing.T) {
+		userService := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 2, Login: "editor", OrgID: 1}}
+		r := ProvideResolver(setting.NewCfg(), userService, orgtest.NewOrgServiceFake(), acService)
+
+		_, err := r.SignedInUser(context.Background(), 3, &backend.User{Login: "editor"})
+		require.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("should resolve anonymous users only when anonymous access is enabled", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		orgService := &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1, Name: "Main Org."}}
+		r := ProvideResolver(cfg, usertest.NewUserServiceFake(), orgService, acService)
+
+		_, err := r.SignedInUser(context.Background(), 1, &backend.User{})
+		require.ErrorIs(t, err, ErrUserNotFound)
+
+		cfg.AnonymousEnabled = true
+		cfg.AnonymousOrgRole = string(org.RoleViewer)
+		usr, err := r.SignedInUser(context.Background(), 1, &backend.User{})
+		require.NoError(t, err)
+		assert.True(t, usr.IsAnonymous)
+		assert.Equal(t, org.RoleViewer, usr.OrgRole)
+	})
+}
@@ -96,6 +96,7 @@ func (dc *DatasourceProvisioner) apply(ctx context.Context, cfg *configs) error
 			if len(ds.Correlations) > 0 {
 				if err := dc.correlationsStore.DeleteCorrelationsBySourceUID(ctx, correlations.DeleteCorrelationsBySourceUIDCommand{
 					SourceUID: cmd.Result.Uid,
+					OrgId:     cmd.Result.OrgId,
 				}); err != nil {
 					return err
 				}
@@ -191,12 +192,14 @@ func (dc *DatasourceProvisioner) deleteDatasources(ctx context.Context, dsToDele
 		if getDsQuery.Result != nil {
 			if err := dc.correlationsStore.DeleteCorrelationsBySourceUID(ctx, correlations.DeleteCorrelationsBySourceUIDCommand{
 				SourceUID: getDsQuery.Result.Uid,
+				OrgId:     getDsQuery.Result.OrgId,
 			}); err != nil {
 				return err
 			}
 
 			if err := dc.correlationsStore.DeleteCorrelationsByTargetUID(ctx, correlations.DeleteCorrelationsByTargetUIDCommand{
 				TargetUID: getDsQuery.Result.Uid,
+				OrgId:     getDsQuery.Result.OrgId,
 			}); err != nil {
 				return err
 			}
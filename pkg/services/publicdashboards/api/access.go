@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// defaultUsageDays is the number of days summarised by the usage api when not set
+const defaultUsageDays = 30
+
+// recordAccess wraps a handler of a public dashboard to record its requests in the access log
+func (api *Api) recordAccess(kind string, handler func(c *models.ReqContext) response.Response) func(c *models.ReqContext) response.Response {
+	return func(c *models.ReqContext) response.Response {
+		start := time.Now()
+		resp := handler(c)
+
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !tokens.IsValidAccessToken(accessToken) {
+			return resp
+		}
+
+		// the panel id was validated by the handler when the request succeeded
+		panelId, _ := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
+
+		access := PublicDashboardAccessDTO{
+			Kind:     kind,
+			PanelId:  panelId,
+			Status:   resp.Status(),
+			Duration: time.Since(start),
+			ClientIp: c.RemoteAddr(),
+			Referer:  c.Req.Referer(),
+		}
+		api.PublicDashboardService.RecordAccess(c.Req.Context(), accessToken, access)
+
+		return resp
+	}
+}
+
+// GetUsage Summarises the usage of the public dashboards of an org over the last days
+// GET /api/dashboards/public-dashboards/usage
+func (api *Api) GetUsage(c *models.ReqContext) response.Response {
+	days := c.QueryInt("days")
+	if days == 0 {
+		days = defaultUsageDays
+	}
+	if days < 0 {
+		return response.Err(ErrBadRequest.Errorf("GetUsage: invalid number of days %d", days))
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	usage, err := api.PublicDashboardService.FindUsage(c.Req.Context(), c.SignedInUser, c.OrgID, since)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, usage)
+}
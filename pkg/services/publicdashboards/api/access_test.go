@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAPIRecordAccess(t *testing.T) {
+	service := publicdashboards.NewFakePublicDashboardService(t)
+	service.On("Authorize", mock.Anything, validAccessToken, "").Return(nil, nil)
+	service.On("GetQueryDataResponse", mock.Anything, mock.Anything, mock.Anything, int64(2), validAccessToken).
+		Return(nil, ErrPanelQueriesNotFound.Errorf("no queries"))
+	service.On("RecordAccess", mock.Anything, validAccessToken, mock.MatchedBy(func(access PublicDashboardAccessDTO) bool {
+		return access.Kind == AccessKindQuery &&
+			access.PanelId == 2 &&
+			access.Status == http.StatusBadRequest &&
+			access.ClientIp == "10.0.0.1" &&
+			access.Referer == "https://example.com/page"
+	}))
+
+	cfg := setting.NewCfg()
+	cfg.RBACEnabled = false
+	server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/public/dashboards/%s/panels/2/query", validAccessToken), strings.NewReader("{}"))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("Referer", "https://example.com/page")
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestAPIGetUsage(t *testing.T) {
+	t.Run("summarises the last 30 days by default", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("FindUsage", mock.Anything, mock.Anything, int64(1), mock.MatchedBy(func(since time.Time) bool {
+			return time.Since(since) > 29*24*time.Hour && time.Since(since) < 31*24*time.Hour
+		})).Return([]PublicDashboardUsage{{
+			PublicDashboardListResponse: PublicDashboardListResponse{Uid: "pubdash", IsEnabled: true},
+			Abandoned:                   true,
+		}}, nil)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userAdmin)
+
+		response := callAPI(server, http.MethodGet, "/api/dashboards/public-dashboards/usage", nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var usage []PublicDashboardUsage
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &usage))
+		require.Len(t, usage, 1)
+		assert.Equal(t, "pubdash", usage[0].Uid)
+		assert.True(t, usage[0].Abandoned)
+	})
+
+	t.Run("rejects a negative number of days", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userAdmin)
+
+		response := callAPI(server, http.MethodGet, "/api/dashboards/public-dashboards/usage?days=-1", nil, t)
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
+	t.Run("requires an org admin", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, userViewer)
+
+		response := callAPI(server, http.MethodGet, "/api/dashboards/public-dashboards/usage", nil, t)
+		require.Equal(t, http.StatusForbidden, response.Code)
+	})
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -55,9 +56,12 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.recordAccess(AccessKindView, api.ViewPublicDashboard)))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.recordAccess(AccessKindQuery, api.QueryPublicDashboard)))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.recordAccess(AccessKindAnnotations, api.GetAnnotations)))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/session", routing.Wrap(api.ConfirmViewerSession))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/session", routing.Wrap(api.CreateViewerSession))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/session/link", routing.Wrap(api.RequestViewerLink))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -66,6 +70,9 @@ func (api *Api) RegisterAPIEndpoints() {
 	// List public dashboards for org
 	api.RouteRegister.Get("/api/dashboards/public-dashboards", middleware.ReqSignedIn, routing.Wrap(api.ListPublicDashboards))
 
+	// Summarise usage of public dashboards for org
+	api.RouteRegister.Get("/api/dashboards/public-dashboards/usage", middleware.ReqOrgAdmin, routing.Wrap(api.GetUsage))
+
 	// Get public dashboard
 	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards",
 		auth(middleware.ReqSignedIn, accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
@@ -85,6 +92,20 @@ func (api *Api) RegisterAPIEndpoints() {
 	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid",
 		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
 		routing.Wrap(api.DeletePublicDashboard))
+
+	// Viewers invited to public dashboards shared by email
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.ListViewers))
+	api.RouteRegister.Post("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.InviteViewer))
+	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers/events",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.ListViewerEvents))
+	api.RouteRegister.Delete("/api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers/:viewerUid",
+		auth(middleware.ReqOrgAdmin, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, uidScope)),
+		routing.Wrap(api.RevokeViewer))
 }
 
 // ListPublicDashboards Gets list of public dashboards by orgId
@@ -164,12 +185,13 @@ func (api *Api) UpdatePublicDashboard(c *models.ReqContext) response.Response {
 		return response.Err(ErrInvalidUid.Errorf("UpdatePublicDashboard: invalid Uid %s", uid))
 	}
 
-	pd := &PublicDashboard{}
-	if err := web.Bind(c.Req, pd); err != nil {
+	req := &updatePublicDashboardRequest{}
+	if err := web.Bind(c.Req, req); err != nil {
 		return response.Err(ErrBadRequest.Errorf("UpdatePublicDashboard: bad request data %v", err))
 	}
 
 	// Always set the orgID and userID from the session
+	pd := &req.PublicDashboard
 	pd.OrgId = c.OrgID
 	pd.Uid = uid
 	dto := SavePublicDashboardDTO{
@@ -177,6 +199,8 @@ func (api *Api) UpdatePublicDashboard(c *models.ReqContext) response.Response {
 		OrgId:           c.OrgID,
 		DashboardUid:    dashboardUid,
 		PublicDashboard: pd,
+		ExpiresAtSet:    req.isSet("expiresAt"),
+		RateLimitSet:    req.isSet("rateLimit"),
 	}
 
 	// Update the public dashboard
@@ -188,6 +212,25 @@ func (api *Api) UpdatePublicDashboard(c *models.ReqContext) response.Response {
 	return response.JSON(http.StatusOK, pd)
 }
 
+// updatePublicDashboardRequest is the body of a request updating a public dashboard, with the fields it sets so
+// settings which aren't in the body can be kept
+type updatePublicDashboardRequest struct {
+	PublicDashboard
+	fields map[string]json.RawMessage
+}
+
+func (r *updatePublicDashboardRequest) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &r.PublicDashboard); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, &r.fields)
+}
+
+func (r *updatePublicDashboardRequest) isSet(field string) bool {
+	_, ok := r.fields[field]
+	return ok
+}
+
 // Delete a public dashboard
 // DELETE /api/dashboards/uid/:dashboardUid/public-dashboards/:uid
 func (api *Api) DeletePublicDashboard(c *models.ReqContext) response.Response {
@@ -641,3 +641,23 @@ func TestAPIUpdatePublicDashboard(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIUpdatePublicDashboardKeepsSettingsNotInTheBody(t *testing.T) {
+	adminUser := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleEditor, Login: "testEditorUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsPublicWrite: {dashboards.ScopeDashboardsAll}}}}
+
+	for body, expected := range map[string][2]bool{
+		`{"isEnabled": true}`:                                   {false, false},
+		`{"isEnabled": true, "expiresAt": null}`:                {true, false},
+		`{"isEnabled": true, "rateLimit": 0}`:                   {false, true},
+		`{"expiresAt": "2030-01-01T00:00:00Z", "rateLimit": 5}`: {true, true},
+	} {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("Update", mock.Anything, mock.Anything, mock.MatchedBy(func(dto *SavePublicDashboardDTO) bool {
+			return dto.ExpiresAtSet == expected[0] && dto.RateLimitSet == expected[1]
+		})).Return(&PublicDashboard{Uid: "success"}, nil)
+
+		testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, adminUser)
+		response := callAPI(testServer, http.MethodPut, "/api/dashboards/uid/abc1234/public-dashboards/1234asdfasdf", strings.NewReader(body), t)
+		assert.Equal(t, http.StatusOK, response.Code, body)
+	}
+}
@@ -20,6 +20,11 @@ func (api *Api) ViewPublicDashboard(c *models.ReqContext) response.Response {
 		return response.Err(ErrInvalidAccessToken.Errorf("ViewPublicDashboard: invalid access token"))
 	}
 
+	viewer, err := api.authorize(c, accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+
 	pubdash, dash, err := api.PublicDashboardService.FindPublicDashboardAndDashboardByAccessToken(
 		c.Req.Context(),
 		accessToken,
@@ -46,6 +51,12 @@ func (api *Api) ViewPublicDashboard(c *models.ReqContext) response.Response {
 		PublicDashboardTimeSelectionEnabled: pubdash.TimeSelectionEnabled,
 	}
 
+	if viewer != nil {
+		if err := api.PublicDashboardService.RecordViewerView(c.Req.Context(), viewer); err != nil {
+			api.Log.Error("Failed to record public dashboard view", "publicDashboardUid", pubdash.Uid, "error", err)
+		}
+	}
+
 	dto := dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}
 
 	return response.JSON(http.StatusOK, dto)
@@ -59,6 +70,10 @@ func (api *Api) QueryPublicDashboard(c *models.ReqContext) response.Response {
 		return response.Err(ErrInvalidAccessToken.Errorf("QueryPublicDashboard: invalid access token"))
 	}
 
+	if _, err := api.authorize(c, accessToken); err != nil {
+		return response.Err(err)
+	}
+
 	panelId, err := strconv.ParseInt(web.Params(c.Req)[":panelId"], 10, 64)
 	if err != nil {
 		return response.Err(ErrInvalidPanelId.Errorf("QueryPublicDashboard: error parsing panelId %v", err))
@@ -85,6 +100,10 @@ func (api *Api) GetAnnotations(c *models.ReqContext) response.Response {
 		return response.Err(ErrInvalidAccessToken.Errorf("GetAnnotations: invalid access token"))
 	}
 
+	if _, err := api.authorize(c, accessToken); err != nil {
+		return response.Err(err)
+	}
+
 	reqDTO := AnnotationsQueryDTO{
 		From: c.QueryInt64("from"),
 		To:   c.QueryInt64("to"),
@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	datasourcesService "github.com/grafana/grafana/pkg/services/datasources/service"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	publicdashboardsStore "github.com/grafana/grafana/pkg/services/publicdashboards/database"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -85,6 +86,8 @@ func TestAPIViewPublicDashboard(t *testing.T) {
 			service := publicdashboards.NewFakePublicDashboardService(t)
 			service.On("FindPublicDashboardAndDashboardByAccessToken", mock.Anything, mock.AnythingOfType("string")).
 				Return(&PublicDashboard{}, test.DashboardResult, test.Err).Maybe()
+			service.On("Authorize", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			service.On("RecordAccess", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
@@ -185,6 +188,8 @@ func TestAPIQueryPublicDashboard(t *testing.T) {
 
 	setup := func(enabled bool) (*web.Mux, *publicdashboards.FakePublicDashboardService) {
 		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("Authorize", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+		service.On("RecordAccess", mock.Anything, mock.Anything, mock.Anything).Maybe()
 		cfg := setting.NewCfg()
 		cfg.RBACEnabled = false
 
@@ -322,7 +327,7 @@ func TestIntegrationUnauthenticatedUserCanGetPubdashPanelQueryData(t *testing.T)
 	cfg := setting.NewCfg()
 	ac := acmock.New()
 	cfg.RBACEnabled = false
-	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac)
+	service := publicdashboardsService.ProvideService(cfg, store, qds, annotationsService, ac, &notifications.NotificationServiceMock{})
 	pubdash, err := service.Create(context.Background(), &user.SignedInUser{}, savePubDashboardCmd)
 	require.NoError(t, err)
 
@@ -412,6 +417,8 @@ func TestAPIGetAnnotations(t *testing.T) {
 			cfg := setting.NewCfg()
 			cfg.RBACEnabled = false
 			service := publicdashboards.NewFakePublicDashboardService(t)
+			service.On("Authorize", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+			service.On("RecordAccess", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 			if test.ExpectedServiceCalled {
 				service.On("FindAnnotations", mock.Anything, mock.Anything, mock.AnythingOfType("string")).
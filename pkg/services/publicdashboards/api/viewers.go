@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/middleware/cookies"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ViewerSessionCookieName is the cookie with the session token of an invited viewer. The cookie is scoped to the api
+// of the public dashboard, so viewers can have sessions for several public dashboards.
+const ViewerSessionCookieName = "grafana_public_dashboard_session"
+
+// authorize checks that the public dashboard of the access token can be viewed, and returns the invited viewer of
+// public dashboards shared by email
+func (api *Api) authorize(c *models.ReqContext, accessToken string) (*PublicDashboardViewer, error) {
+	return api.PublicDashboardService.Authorize(c.Req.Context(), accessToken, c.GetCookie(ViewerSessionCookieName))
+}
+
+// viewerSessionConfirmPage is opened by magic links. It posts the token of the link to create the session, so email
+// link scanners which follow the link don't use the token up before the viewer does.
+var viewerSessionConfirmPage = template.Must(template.New("viewerSessionConfirm").Parse(`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <meta name="robots" content="noindex, nofollow">
+    <title>Open public dashboard - Grafana</title>
+  </head>
+  <body>
+    <form method="post" action="{{.Action}}">
+      <input type="hidden" name="token" value="{{.Token}}">
+      <p>You have been invited to view a public dashboard.</p>
+      <button type="submit">Open dashboard</button>
+    </form>
+  </body>
+</html>
+`))
+
+// ConfirmViewerSession Renders the page of a magic link, which asks the invited viewer to open the public dashboard
+// GET /api/public/dashboards/:accessToken/session
+func (api *Api) ConfirmViewerSession(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !tokens.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("ConfirmViewerSession: invalid access token"))
+	}
+
+	var page bytes.Buffer
+	err := viewerSessionConfirmPage.Execute(&page, map[string]string{
+		"Action": setting.AppSubUrl + "/api/public/dashboards/" + accessToken + "/session",
+		"Token":  c.Query("token"),
+	})
+	if err != nil {
+		return response.Err(ErrInternalServerError.Errorf("ConfirmViewerSession: failed to render page: %w", err))
+	}
+
+	return response.Respond(http.StatusOK, page.Bytes()).
+		SetHeader("Content-Type", "text/html; charset=UTF-8").
+		SetHeader("Cache-Control", "no-store").
+		SetHeader("Referrer-Policy", "no-referrer")
+}
+
+// CreateViewerSession Exchanges the token of a magic link for a session of the invited viewer, and redirects to the
+// public dashboard
+// POST /api/public/dashboards/:accessToken/session
+func (api *Api) CreateViewerSession(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !tokens.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("CreateViewerSession: invalid access token"))
+	}
+
+	sessionToken, err := api.PublicDashboardService.CreateViewerSession(c.Req.Context(), accessToken, c.Req.PostFormValue("token"))
+	if err != nil {
+		return response.Err(err)
+	}
+
+	cookies.WriteCookie(c.Resp, ViewerSessionCookieName, sessionToken, int(ViewerSessionLifetime.Seconds()), func() cookies.CookieOptions {
+		options := cookies.NewCookieOptions()
+		options.Path = setting.AppSubUrl + "/api/public/dashboards/" + accessToken
+		return options
+	})
+
+	return response.Redirect(setting.AppSubUrl + "/public-dashboards/" + accessToken)
+}
+
+// RequestViewerLink Sends a new magic link to an invited viewer. The response doesn't tell whether the email is
+// invited.
+// POST /api/public/dashboards/:accessToken/session/link
+func (api *Api) RequestViewerLink(c *models.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !tokens.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("RequestViewerLink: invalid access token"))
+	}
+
+	dto := RequestViewerLinkDTO{}
+	if err := web.Bind(c.Req, &dto); err != nil {
+		return response.Err(ErrBadRequest.Errorf("RequestViewerLink: bad request data %v", err))
+	}
+
+	if err := api.PublicDashboardService.SendViewerLink(c.Req.Context(), accessToken, dto.Email); err != nil {
+		return response.Err(err)
+	}
+
+	return response.Success("If the email is invited to the dashboard, a link has been sent to it")
+}
+
+// ListViewers Gets the viewers invited to a public dashboard
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers
+func (api *Api) ListViewers(c *models.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "ListViewers")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	viewers, err := api.PublicDashboardService.FindViewers(c.Req.Context(), c.OrgID, dashboardUid, uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, viewers)
+}
+
+// InviteViewer Invites a viewer by email to a public dashboard
+// POST /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers
+func (api *Api) InviteViewer(c *models.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "InviteViewer")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	dto := InviteViewerDTO{}
+	if err := web.Bind(c.Req, &dto); err != nil {
+		return response.Err(ErrBadRequest.Errorf("InviteViewer: bad request data %v", err))
+	}
+
+	viewer, err := api.PublicDashboardService.InviteViewer(c.Req.Context(), c.SignedInUser, dashboardUid, uid, dto.Email)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, viewer)
+}
+
+// RevokeViewer Removes a viewer from a public dashboard and ends their sessions
+// DELETE /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers/:viewerUid
+func (api *Api) RevokeViewer(c *models.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "RevokeViewer")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	viewerUid := web.Params(c.Req)[":viewerUid"]
+	if !tokens.IsValidShortUID(viewerUid) {
+		return response.Err(ErrInvalidUid.Errorf("RevokeViewer: invalid viewer Uid %s", viewerUid))
+	}
+
+	if err := api.PublicDashboardService.RevokeViewer(c.Req.Context(), c.OrgID, dashboardUid, uid, viewerUid); err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, nil)
+}
+
+// ListViewerEvents Gets the usage log of the viewers invited to a public dashboard
+// GET /api/dashboards/uid/:dashboardUid/public-dashboards/:uid/viewers/events
+func (api *Api) ListViewerEvents(c *models.ReqContext) response.Response {
+	dashboardUid, uid, err := publicDashboardParams(c, "ListViewerEvents")
+	if err != nil {
+		return response.Err(err)
+	}
+
+	events, err := api.PublicDashboardService.FindViewerEvents(c.Req.Context(), c.OrgID, dashboardUid, uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	return response.JSON(http.StatusOK, events)
+}
+
+// publicDashboardParams returns the dashboard uid and public dashboard uid of the request
+func publicDashboardParams(c *models.ReqContext, handler string) (string, string, error) {
+	dashboardUid := web.Params(c.Req)[":dashboardUid"]
+	if !tokens.IsValidShortUID(dashboardUid) {
+		return "", "", ErrInvalidUid.Errorf("%s: invalid dashboard Uid %s", handler, dashboardUid)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	if !tokens.IsValidShortUID(uid) {
+		return "", "", ErrInvalidUid.Errorf("%s: invalid Uid %s", handler, uid)
+	}
+
+	return dashboardUid, uid, nil
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAPIConfirmViewerSession(t *testing.T) {
+	service := publicdashboards.NewFakePublicDashboardService(t)
+
+	cfg := setting.NewCfg()
+	cfg.RBACEnabled = false
+	server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+	response := callAPI(server, http.MethodGet, fmt.Sprintf("/api/public/dashboards/%s/session?token=link-token", validAccessToken), nil, t)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/html; charset=UTF-8", response.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", response.Header().Get("Cache-Control"))
+	assert.Contains(t, response.Body.String(), fmt.Sprintf(`action="/api/public/dashboards/%s/session"`, validAccessToken))
+	assert.Contains(t, response.Body.String(), `name="token" value="link-token"`)
+	// the token is only exchanged when the viewer submits the page
+	service.AssertNotCalled(t, "CreateViewerSession", mock.Anything, mock.Anything, mock.Anything)
+	require.Empty(t, response.Result().Cookies())
+}
+
+func TestAPICreateViewerSession(t *testing.T) {
+	accessToken := validAccessToken
+
+	postToken := func(server http.Handler, token string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/public/dashboards/%s/session", accessToken), strings.NewReader("token="+token))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, req)
+		return response
+	}
+
+	t.Run("sets the session cookie and redirects to the public dashboard", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("CreateViewerSession", mock.Anything, accessToken, "link-token").Return("session-token", nil)
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+		response := postToken(server, "link-token")
+		require.Equal(t, http.StatusFound, response.Code)
+		assert.Equal(t, "/public-dashboards/"+accessToken, response.Header().Get("Location"))
+
+		cookies := response.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, ViewerSessionCookieName, cookies[0].Name)
+		assert.Equal(t, "session-token", cookies[0].Value)
+		assert.Equal(t, "/api/public/dashboards/"+accessToken, cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+	})
+
+	t.Run("returns an error when the link is invalid", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		service.On("CreateViewerSession", mock.Anything, accessToken, "").Return("", ErrInvalidViewerLink.Errorf("invalid"))
+
+		cfg := setting.NewCfg()
+		cfg.RBACEnabled = false
+		server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+		response := postToken(server, "")
+		require.Equal(t, http.StatusUnauthorized, response.Code)
+		require.Empty(t, response.Result().Cookies())
+	})
+}
+
+func TestAPIRequiresViewerSession(t *testing.T) {
+	service := publicdashboards.NewFakePublicDashboardService(t)
+	service.On("Authorize", mock.Anything, validAccessToken, "session-token").
+		Return(nil, ErrViewerSessionRequired.Errorf("no session"))
+	service.On("RecordAccess", mock.Anything, validAccessToken, mock.Anything)
+
+	cfg := setting.NewCfg()
+	cfg.RBACEnabled = false
+	server := setupTestServer(t, cfg, featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, nil, anonymousUser)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/api/public/dashboards/%s/panels/1/query", validAccessToken), strings.NewReader("{}"))
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: ViewerSessionCookieName, Value: "session-token"})
+	response := httptest.NewRecorder()
+	server.ServeHTTP(response, req)
+
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Body.String(), "publicdashboards.viewerSessionRequired")
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -21,6 +22,10 @@ type PublicDashboardStoreImpl struct {
 
 var LogPrefix = "publicdashboards.store"
 
+// accessLogBatchSize is the number of rows of the access log inserted per statement, below the limit of SQLite on
+// the number of parameters of a statement
+const accessLogBatchSize = 50
+
 // Gives us a compile time error if our database does not adhere to contract of
 // the interface
 var _ publicdashboards.Store = (*PublicDashboardStoreImpl)(nil)
@@ -233,11 +238,19 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 			return err
 		}
 
-		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, time_settings = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
+		var expiresAt interface{}
+		if cmd.PublicDashboard.ExpiresAt != nil {
+			expiresAt = cmd.PublicDashboard.ExpiresAt.UTC().Format("2006-01-02 15:04:05")
+		}
+
+		sqlResult, err := sess.Exec("UPDATE dashboard_public SET is_enabled = ?, annotations_enabled = ?, time_selection_enabled = ?, time_settings = ?, share = ?, expires_at = ?, rate_limit = ?, updated_by = ?, updated_at = ? WHERE uid = ?",
 			cmd.PublicDashboard.IsEnabled,
 			cmd.PublicDashboard.AnnotationsEnabled,
 			cmd.PublicDashboard.TimeSelectionEnabled,
 			string(timeSettingsJSON),
+			cmd.PublicDashboard.Share,
+			expiresAt,
+			cmd.PublicDashboard.RateLimit,
 			cmd.PublicDashboard.UpdatedBy,
 			cmd.PublicDashboard.UpdatedAt.UTC().Format("2006-01-02 15:04:05"),
 			cmd.PublicDashboard.Uid)
@@ -254,16 +267,298 @@ func (d *PublicDashboardStoreImpl) Update(ctx context.Context, cmd SavePublicDas
 	return affectedRows, err
 }
 
-// Deletes a public dashboard
+// Deletes a public dashboard with its invited viewers and access log
 func (d *PublicDashboardStoreImpl) Delete(ctx context.Context, orgId int64, uid string) (int64, error) {
 	dashboard := &PublicDashboard{OrgId: orgId, Uid: uid}
 	var affectedRows int64
-	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		var err error
 		affectedRows, err = sess.Delete(dashboard)
+		if err != nil || affectedRows == 0 {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM dashboard_public_viewer_token WHERE viewer_id IN (SELECT id FROM dashboard_public_viewer WHERE public_dashboard_uid = ?)", uid); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("DELETE FROM dashboard_public_viewer WHERE public_dashboard_uid = ?", uid); err != nil {
+			return err
+		}
+		if _, err := sess.Exec("DELETE FROM dashboard_public_viewer_event WHERE public_dashboard_uid = ?", uid); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM dashboard_public_access_log WHERE public_dashboard_uid = ?", uid)
+
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// CreateViewer Invites a viewer to a public dashboard
+func (d *PublicDashboardStoreImpl) CreateViewer(ctx context.Context, viewer *PublicDashboardViewer) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(viewer)
+		return err
+	})
+}
+
+// FindViewer Returns the viewer of a public dashboard by email or nil if not found
+func (d *PublicDashboardStoreImpl) FindViewer(ctx context.Context, publicDashboardUid string, email string) (*PublicDashboardViewer, error) {
+	if publicDashboardUid == "" || email == "" {
+		return nil, nil
+	}
+
+	var found bool
+	viewer := &PublicDashboardViewer{PublicDashboardUid: publicDashboardUid, Email: email}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(viewer)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return viewer, nil
+}
+
+// FindViewers Returns the viewers of a public dashboard ordered by email
+func (d *PublicDashboardStoreImpl) FindViewers(ctx context.Context, publicDashboardUid string) ([]PublicDashboardViewer, error) {
+	viewers := make([]PublicDashboardViewer, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).OrderBy("email").Find(&viewers)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return viewers, nil
+}
+
+// FindViewerByToken Returns the viewer of a public dashboard with an unexpired token of a kind or nil if not found
+func (d *PublicDashboardStoreImpl) FindViewerByToken(ctx context.Context, publicDashboardUid string, kind string, tokenHash string, now time.Time) (*PublicDashboardViewer, error) {
+	if publicDashboardUid == "" || tokenHash == "" {
+		return nil, nil
+	}
+
+	var found bool
+	viewer := &PublicDashboardViewer{}
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Table("dashboard_public_viewer").Select("dashboard_public_viewer.*").
+			Join("INNER", "dashboard_public_viewer_token", "dashboard_public_viewer_token.viewer_id = dashboard_public_viewer.id").
+			Where("dashboard_public_viewer.public_dashboard_uid = ? AND dashboard_public_viewer_token.kind = ? AND dashboard_public_viewer_token.token_hash = ? AND dashboard_public_viewer_token.expires_at > ?",
+				publicDashboardUid, kind, tokenHash, now.Unix()).
+			Get(viewer)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return viewer, nil
+}
+
+// UpdateViewerLastViewedAt Sets the time a viewer last viewed a public dashboard
+func (d *PublicDashboardStoreImpl) UpdateViewerLastViewedAt(ctx context.Context, viewerId int64, lastViewedAt time.Time) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("UPDATE dashboard_public_viewer SET last_viewed_at = ? WHERE id = ?",
+			lastViewedAt.UTC().Format("2006-01-02 15:04:05"), viewerId)
+		return err
+	})
+}
+
+// DeleteViewer Deletes the viewer of a public dashboard by uid with its tokens, and returns the deleted viewer or nil
+// if not found
+func (d *PublicDashboardStoreImpl) DeleteViewer(ctx context.Context, publicDashboardUid string, uid string) (*PublicDashboardViewer, error) {
+	var found bool
+	viewer := &PublicDashboardViewer{PublicDashboardUid: publicDashboardUid, Uid: uid}
+	err := d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		found, err = sess.Get(viewer)
+		if err != nil || !found {
+			return err
+		}
+
+		if _, err := sess.Exec("DELETE FROM dashboard_public_viewer_token WHERE viewer_id = ?", viewer.Id); err != nil {
+			return err
+		}
+		_, err = sess.Exec("DELETE FROM dashboard_public_viewer WHERE id = ?", viewer.Id)
+		return err
+	})
 
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return viewer, nil
+}
+
+// CreateViewerToken Creates a token for a viewer, and deletes the expired tokens of the viewer
+func (d *PublicDashboardStoreImpl) CreateViewerToken(ctx context.Context, token *PublicDashboardViewerToken) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM dashboard_public_viewer_token WHERE viewer_id = ? AND expires_at <= ?", token.ViewerId, token.CreatedAt); err != nil {
+			return err
+		}
+		_, err := sess.Insert(token)
+		return err
+	})
+}
+
+// ExistsViewerToken Returns true if a viewer has an unexpired token of a kind
+func (d *PublicDashboardStoreImpl) ExistsViewerToken(ctx context.Context, viewerId int64, kind string, now time.Time) (bool, error) {
+	var exists bool
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		exists, err = sess.Where("viewer_id = ? AND kind = ? AND expires_at > ?", viewerId, kind, now.Unix()).Exist(&PublicDashboardViewerToken{})
+		return err
+	})
+
+	return exists, err
+}
+
+// DeleteViewerToken Deletes a token of a viewer by hash
+func (d *PublicDashboardStoreImpl) DeleteViewerToken(ctx context.Context, tokenHash string) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Delete(&PublicDashboardViewerToken{TokenHash: tokenHash})
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// CreateViewerEvent Adds an event to the usage log of the viewers of a public dashboard
+func (d *PublicDashboardStoreImpl) CreateViewerEvent(ctx context.Context, event *PublicDashboardViewerEvent) error {
+	return d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(event)
+		return err
+	})
+}
+
+// FindViewerEvents Returns the latest events of the usage log of the viewers of a public dashboard, most recent first
+func (d *PublicDashboardStoreImpl) FindViewerEvents(ctx context.Context, publicDashboardUid string, limit int) ([]PublicDashboardViewerEvent, error) {
+	events := make([]PublicDashboardViewerEvent, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("public_dashboard_uid = ?", publicDashboardUid).OrderBy("created_at DESC, id DESC").Limit(limit).Find(&events)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// DeleteViewerEventsOlderThan Deletes the events of the usage log of the viewers of all public dashboards before a time
+func (d *PublicDashboardStoreImpl) DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		affectedRows, err = sess.Where("created_at < ?", olderThan).Delete(&PublicDashboardViewerEvent{})
+		return err
+	})
+
+	return affectedRows, err
+}
+
+// CreateAccessLogs Adds requests to the access log of public dashboards, in batches of accessLogBatchSize rows
+func (d *PublicDashboardStoreImpl) CreateAccessLogs(ctx context.Context, entries []*PublicDashboardAccessLog) error {
+	return d.sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for start := 0; start < len(entries); start += accessLogBatchSize {
+			end := start + accessLogBatchSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			if _, err := sess.InsertMulti(entries[start:end]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteAccessLogsOlderThan Deletes the requests of the access log of all public dashboards made before a time
+func (d *PublicDashboardStoreImpl) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	var affectedRows int64
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_public_access_log WHERE created_at < ?", olderThan.Unix())
+		if err != nil {
+			return err
+		}
+
+		affectedRows, err = res.RowsAffected()
 		return err
 	})
 
 	return affectedRows, err
 }
+
+// FindAccessSummaries Returns the aggregated access logs of the public dashboards of an org accessed since a time
+func (d *PublicDashboardStoreImpl) FindAccessSummaries(ctx context.Context, orgId int64, since time.Time) ([]PublicDashboardAccessSummary, error) {
+	summaries := make([]PublicDashboardAccessSummary, 0)
+	err := d.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rawSQL := `SELECT
+				public_dashboard_uid,
+				COUNT(*) AS requests,
+				SUM(CASE WHEN kind = ? THEN 1 ELSE 0 END) AS queries,
+				SUM(CASE WHEN status >= 400 THEN 1 ELSE 0 END) AS failed_requests,
+				SUM(CASE WHEN status = 429 THEN 1 ELSE 0 END) AS rate_limited_requests,
+				COUNT(DISTINCT client_ip_hash) AS unique_clients,
+				SUM(CASE WHEN kind = ? THEN duration_ms ELSE 0 END) AS query_duration_ms,
+				MAX(created_at) AS last_accessed_at
+			FROM dashboard_public_access_log
+			WHERE org_id = ? AND created_at >= ?
+			GROUP BY public_dashboard_uid`
+		if err := sess.SQL(rawSQL, AccessKindQuery, AccessKindQuery, orgId, since.Unix()).Find(&summaries); err != nil {
+			return err
+		}
+
+		// the number of requests of the busiest client of each public dashboard
+		topClients := make([]PublicDashboardAccessSummary, 0)
+		rawSQL = `SELECT public_dashboard_uid, MAX(requests) AS top_client_requests
+			FROM (
+				SELECT public_dashboard_uid, client_ip_hash, COUNT(*) AS requests
+				FROM dashboard_public_access_log
+				WHERE org_id = ? AND created_at >= ?
+				GROUP BY public_dashboard_uid, client_ip_hash
+			) clients
+			GROUP BY public_dashboard_uid`
+		if err := sess.SQL(rawSQL, orgId, since.Unix()).Find(&topClients); err != nil {
+			return err
+		}
+
+		requestsByUid := make(map[string]int64, len(topClients))
+		for _, c := range topClients {
+			requestsByUid[c.PublicDashboardUid] = c.TopClientRequests
+		}
+		for i := range summaries {
+			summaries[i].TopClientRequests = requestsByUid[summaries[i].PublicDashboardUid]
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
@@ -666,6 +666,139 @@ func TestIntegrationDelete(t *testing.T) {
 	})
 }
 
+func TestIntegrationViewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedPublicDashboard *PublicDashboard
+	ctx := context.Background()
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore)
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+		savedPublicDashboard = insertPublicDashboard(t, publicdashboardStore, savedDashboard.Uid, savedDashboard.OrgId, true)
+	}
+
+	insertViewer := func(email string) *PublicDashboardViewer {
+		viewer := &PublicDashboardViewer{
+			Uid:                util.GenerateShortUID(),
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			OrgId:              savedPublicDashboard.OrgId,
+			Email:              email,
+			CreatedBy:          1,
+			CreatedAt:          DefaultTime,
+		}
+		require.NoError(t, publicdashboardStore.CreateViewer(ctx, viewer))
+		return viewer
+	}
+
+	t.Run("finds viewers by email and token", func(t *testing.T) {
+		setup()
+		viewer := insertViewer("b@example.com")
+		insertViewer("a@example.com")
+
+		found, err := publicdashboardStore.FindViewer(ctx, savedPublicDashboard.Uid, "b@example.com")
+		require.NoError(t, err)
+		require.Equal(t, viewer.Uid, found.Uid)
+
+		viewers, err := publicdashboardStore.FindViewers(ctx, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		require.Len(t, viewers, 2)
+		assert.Equal(t, "a@example.com", viewers[0].Email)
+
+		now := time.Now()
+		err = publicdashboardStore.CreateViewerToken(ctx, &PublicDashboardViewerToken{
+			ViewerId:  viewer.Id,
+			Kind:      ViewerTokenSession,
+			TokenHash: "hash",
+			CreatedAt: now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+
+		found, err = publicdashboardStore.FindViewerByToken(ctx, savedPublicDashboard.Uid, ViewerTokenSession, "hash", now)
+		require.NoError(t, err)
+		require.Equal(t, viewer.Uid, found.Uid)
+
+		// tokens of another kind, or expired tokens, aren't found
+		found, err = publicdashboardStore.FindViewerByToken(ctx, savedPublicDashboard.Uid, ViewerTokenMagicLink, "hash", now)
+		require.NoError(t, err)
+		require.Nil(t, found)
+		found, err = publicdashboardStore.FindViewerByToken(ctx, savedPublicDashboard.Uid, ViewerTokenSession, "hash", now.Add(2*time.Hour))
+		require.NoError(t, err)
+		require.Nil(t, found)
+
+		deleted, err := publicdashboardStore.DeleteViewerToken(ctx, "hash")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+		deleted, err = publicdashboardStore.DeleteViewerToken(ctx, "hash")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+	})
+
+	t.Run("deleting a viewer deletes their tokens", func(t *testing.T) {
+		setup()
+		viewer := insertViewer("a@example.com")
+		now := time.Now()
+		err := publicdashboardStore.CreateViewerToken(ctx, &PublicDashboardViewerToken{
+			ViewerId:  viewer.Id,
+			Kind:      ViewerTokenSession,
+			TokenHash: "hash",
+			CreatedAt: now.Unix(),
+			ExpiresAt: now.Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+
+		deletedViewer, err := publicdashboardStore.DeleteViewer(ctx, savedPublicDashboard.Uid, viewer.Uid)
+		require.NoError(t, err)
+		require.Equal(t, "a@example.com", deletedViewer.Email)
+
+		deleted, err := publicdashboardStore.DeleteViewerToken(ctx, "hash")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+
+		deletedViewer, err = publicdashboardStore.DeleteViewer(ctx, savedPublicDashboard.Uid, viewer.Uid)
+		require.NoError(t, err)
+		require.Nil(t, deletedViewer)
+	})
+
+	t.Run("finds the latest viewer events", func(t *testing.T) {
+		setup()
+		for i, event := range []string{ViewerEventInvited, ViewerEventLinkSent, ViewerEventSignedIn} {
+			err := publicdashboardStore.CreateViewerEvent(ctx, &PublicDashboardViewerEvent{
+				PublicDashboardUid: savedPublicDashboard.Uid,
+				OrgId:              savedPublicDashboard.OrgId,
+				Email:              "a@example.com",
+				Event:              event,
+				CreatedAt:          DefaultTime.Add(time.Duration(i) * time.Minute),
+			})
+			require.NoError(t, err)
+		}
+
+		events, err := publicdashboardStore.FindViewerEvents(ctx, savedPublicDashboard.Uid, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, ViewerEventSignedIn, events[0].Event)
+		assert.Equal(t, ViewerEventLinkSent, events[1].Event)
+	})
+
+	t.Run("deleting the public dashboard deletes its viewers", func(t *testing.T) {
+		setup()
+		insertViewer("a@example.com")
+
+		_, err := publicdashboardStore.Delete(ctx, savedPublicDashboard.OrgId, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		viewers, err := publicdashboardStore.FindViewers(ctx, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+		require.Empty(t, viewers)
+	})
+}
+
 // helper function to insert a dashboard
 func insertTestDashboard(t *testing.T, dashboardStore *dashboardsDB.DashboardStore, title string, orgId int64,
 	folderId int64, isFolder bool, tags ...interface{}) *models.Dashboard {
@@ -719,3 +852,109 @@ func insertPublicDashboard(t *testing.T, publicdashboardStore *PublicDashboardSt
 
 	return pubdash
 }
+
+func TestIntegrationAccessLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	var publicdashboardStore *PublicDashboardStoreImpl
+	var savedPublicDashboard *PublicDashboard
+	ctx := context.Background()
+	now := time.Now()
+
+	setup := func() {
+		sqlStore, cfg := db.InitTestDBwithCfg(t)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		publicdashboardStore = ProvideStore(sqlStore)
+		savedDashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true)
+		savedPublicDashboard = insertPublicDashboard(t, publicdashboardStore, savedDashboard.Uid, savedDashboard.OrgId, true)
+	}
+
+	insertAccessLog := func(kind string, status int, durationMs int64, clientIpHash string, createdAt time.Time) {
+		require.NoError(t, publicdashboardStore.CreateAccessLogs(ctx, []*PublicDashboardAccessLog{{
+			PublicDashboardUid: savedPublicDashboard.Uid,
+			OrgId:              savedPublicDashboard.OrgId,
+			Kind:               kind,
+			Status:             status,
+			DurationMs:         durationMs,
+			ClientIpHash:       clientIpHash,
+			CreatedAt:          createdAt.Unix(),
+		}}))
+	}
+
+	t.Run("summarises the access log since a time", func(t *testing.T) {
+		setup()
+		insertAccessLog(AccessKindView, 200, 5, "a", now.Add(-time.Hour))
+		insertAccessLog(AccessKindQuery, 200, 100, "a", now.Add(-time.Hour))
+		insertAccessLog(AccessKindQuery, 200, 300, "a", now.Add(-time.Minute))
+		insertAccessLog(AccessKindQuery, 429, 0, "b", now.Add(-time.Minute))
+		insertAccessLog(AccessKindQuery, 200, 1000, "c", now.Add(-48*time.Hour))
+
+		summaries, err := publicdashboardStore.FindAccessSummaries(ctx, savedPublicDashboard.OrgId, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, PublicDashboardAccessSummary{
+			PublicDashboardUid:  savedPublicDashboard.Uid,
+			Requests:            4,
+			Queries:             3,
+			FailedRequests:      1,
+			RateLimitedRequests: 1,
+			UniqueClients:       2,
+			TopClientRequests:   3,
+			QueryDurationMs:     400,
+			LastAccessedAt:      now.Add(-time.Minute).Unix(),
+		}, summaries[0])
+
+		summaries, err = publicdashboardStore.FindAccessSummaries(ctx, 2, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, summaries)
+	})
+
+	t.Run("inserts requests in batches", func(t *testing.T) {
+		setup()
+		entries := make([]*PublicDashboardAccessLog, 2*accessLogBatchSize+1)
+		for i := range entries {
+			entries[i] = &PublicDashboardAccessLog{
+				PublicDashboardUid: savedPublicDashboard.Uid,
+				OrgId:              savedPublicDashboard.OrgId,
+				Kind:               AccessKindQuery,
+				Status:             200,
+				CreatedAt:          now.Unix(),
+			}
+		}
+		require.NoError(t, publicdashboardStore.CreateAccessLogs(ctx, entries))
+
+		summaries, err := publicdashboardStore.FindAccessSummaries(ctx, savedPublicDashboard.OrgId, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.EqualValues(t, len(entries), summaries[0].Requests)
+	})
+
+	t.Run("deletes old requests of the access log", func(t *testing.T) {
+		setup()
+		insertAccessLog(AccessKindView, 200, 5, "a", now.Add(-48*time.Hour))
+		insertAccessLog(AccessKindView, 200, 5, "a", now.Add(-time.Minute))
+
+		deleted, err := publicdashboardStore.DeleteAccessLogsOlderThan(ctx, now.Add(-24*time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		summaries, err := publicdashboardStore.FindAccessSummaries(ctx, savedPublicDashboard.OrgId, now.Add(-72*time.Hour))
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		require.EqualValues(t, 1, summaries[0].Requests)
+	})
+
+	t.Run("deletes the access log with the public dashboard", func(t *testing.T) {
+		setup()
+		insertAccessLog(AccessKindView, 200, 5, "a", now.Add(-time.Minute))
+
+		_, err := publicdashboardStore.Delete(ctx, savedPublicDashboard.OrgId, savedPublicDashboard.Uid)
+		require.NoError(t, err)
+
+		summaries, err := publicdashboardStore.FindAccessSummaries(ctx, savedPublicDashboard.OrgId, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, summaries)
+	})
+}
@@ -1,6 +1,9 @@
 package tokens
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -27,3 +30,18 @@ func IsValidAccessToken(token string) bool {
 func IsValidShortUID(uid string) bool {
 	return uid != "" && util.IsValidShortUID(uid)
 }
+
+// HashToken returns the hash of a magic link or session token of a public dashboard viewer, which is stored instead
+// of the token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashClientIp returns the keyed hash of the IP of a client of a public dashboard, which is stored in the access log
+// instead of the IP. The key prevents recovering IPs by hashing the whole address space.
+func HashClientIp(ip string, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil))
+}
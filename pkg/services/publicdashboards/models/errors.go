@@ -8,6 +8,11 @@ var (
 	ErrPublicDashboardNotFound = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.notFound", errutil.WithPublicMessage("Public dashboard not found"))
 	ErrDashboardNotFound       = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.dashboardNotFound", errutil.WithPublicMessage("Dashboard not found"))
 	ErrPanelNotFound           = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.panelNotFound", errutil.WithPublicMessage("Public dashboard panel not found"))
+	ErrViewerNotFound          = errutil.NewBase(errutil.StatusNotFound, "publicdashboards.viewerNotFound", errutil.WithPublicMessage("Public dashboard viewer not found"))
+
+	ErrViewerSessionRequired = errutil.NewBase(errutil.StatusUnauthorized, "publicdashboards.viewerSessionRequired", errutil.WithPublicMessage("Public dashboard is only available to invited viewers"))
+	ErrInvalidViewerLink     = errutil.NewBase(errutil.StatusUnauthorized, "publicdashboards.invalidViewerLink", errutil.WithPublicMessage("Invalid or expired link"))
+	ErrRateLimitExceeded     = errutil.NewBase(errutil.StatusTooManyRequests, "publicdashboards.rateLimitExceeded", errutil.WithPublicMessage("Too many requests to public dashboard"))
 
 	ErrBadRequest           = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.badRequest")
 	ErrPanelQueriesNotFound = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.panelQueriesNotFound", errutil.WithPublicMessage("Failed to extract queries from panel"))
@@ -16,8 +21,12 @@ var (
 	ErrInvalidUid           = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidUid", errutil.WithPublicMessage("Invalid Uid"))
 
 	ErrPublicDashboardIdentifierNotSet     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.identifierNotSet", errutil.WithPublicMessage("No Uid for public dashboard specified"))
-	ErrPublicDashboardHasTemplateVariables = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.hasTemplateVariables", errutil.WithPublicMessage("Public dashboard has template variables"))
+	ErrPublicDashboardHasTemplateVariables = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.hasTemplateVariables", errutil.WithPublicMessage("Public dashboard has unsupported template variables"))
+	ErrInvalidTemplateVariableValue        = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTemplateVariableValue", errutil.WithPublicMessage("Invalid template variable value"))
 	ErrInvalidInterval                     = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidInterval", errutil.WithPublicMessage("intervalMS should be greater than 0"))
 	ErrInvalidMaxDataPoints                = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.maxDataPoints", errutil.WithPublicMessage("maxDataPoints should be greater than 0"))
 	ErrInvalidTimeRange                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidTimeRange", errutil.WithPublicMessage("Invalid time range"))
+	ErrInvalidShareType                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidShareType", errutil.WithPublicMessage("Invalid share type"))
+	ErrInvalidRateLimit                    = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidRateLimit", errutil.WithPublicMessage("rateLimit should not be negative"))
+	ErrInvalidEmail                        = errutil.NewBase(errutil.StatusBadRequest, "publicdashboards.invalidEmail", errutil.WithPublicMessage("Invalid email address"))
 )
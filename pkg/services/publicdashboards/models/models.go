@@ -30,6 +30,40 @@ const QueryFailure = "failure"
 
 var QueryResultStatuses = []string{QuerySuccess, QueryFailure}
 
+const (
+	ShareTypePublic = "public"
+	ShareTypeEmail  = "email"
+)
+
+// Kinds of the tokens of invited viewers
+const (
+	ViewerTokenMagicLink = "magic_link"
+	ViewerTokenSession   = "session"
+)
+
+// ViewerSessionLifetime is how long an invited viewer can view a public dashboard after using a magic link
+const ViewerSessionLifetime = 7 * 24 * time.Hour
+
+// Events of the usage log of invited viewers
+const (
+	ViewerEventInvited  = "invited"
+	ViewerEventLinkSent = "link_sent"
+	ViewerEventSignedIn = "signed_in"
+	ViewerEventViewed   = "viewed"
+	ViewerEventRevoked  = "revoked"
+)
+
+// Kinds of the requests of the access log of public dashboards
+const (
+	AccessKindView        = "view"
+	AccessKindQuery       = "query"
+	AccessKindAnnotations = "annotations"
+)
+
+// UsageScrapingMinRequests is the number of requests from a single client over the period of a usage summary from
+// which a public dashboard is flagged as possibly scraped
+const UsageScrapingMinRequests = 10000
+
 type PublicDashboard struct {
 	Uid                  string        `json:"uid" xorm:"pk uid"`
 	DashboardUid         string        `json:"dashboardUid" xorm:"dashboard_uid"`
@@ -39,6 +73,13 @@ type PublicDashboard struct {
 	AccessToken          string        `json:"accessToken" xorm:"access_token"`
 	AnnotationsEnabled   bool          `json:"annotationsEnabled" xorm:"annotations_enabled"`
 	TimeSelectionEnabled bool          `json:"timeSelectionEnabled" xorm:"time_selection_enabled"`
+	// Share is ShareTypePublic when anyone with the access token can view the public dashboard, or ShareTypeEmail
+	// when only invited viewers can
+	Share string `json:"share" xorm:"share"`
+	// ExpiresAt is the time after which the public dashboard can't be viewed anymore, if set
+	ExpiresAt *time.Time `json:"expiresAt" xorm:"expires_at"`
+	// RateLimit is the maximum number of requests per minute to the public dashboard, or 0 for no limit
+	RateLimit int64 `json:"rateLimit" xorm:"rate_limit"`
 
 	CreatedBy int64 `json:"createdBy" xorm:"created_by"`
 	UpdatedBy int64 `json:"updatedBy" xorm:"updated_by"`
@@ -47,6 +88,87 @@ type PublicDashboard struct {
 	UpdatedAt time.Time `json:"updatedAt" xorm:"updated_at"`
 }
 
+// IsExpired returns true when the public dashboard has an expiry time before t
+func (pd PublicDashboard) IsExpired(t time.Time) bool {
+	return pd.ExpiresAt != nil && !pd.ExpiresAt.After(t)
+}
+
+// PublicDashboardViewer is a viewer invited by email to a public dashboard shared with ShareTypeEmail
+type PublicDashboardViewer struct {
+	Id                 int64      `json:"-" xorm:"pk autoincr 'id'"`
+	Uid                string     `json:"uid" xorm:"uid"`
+	PublicDashboardUid string     `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64      `json:"-" xorm:"org_id"`
+	Email              string     `json:"email" xorm:"email"`
+	CreatedBy          int64      `json:"createdBy" xorm:"created_by"`
+	CreatedAt          time.Time  `json:"createdAt" xorm:"created_at"`
+	LastViewedAt       *time.Time `json:"lastViewedAt" xorm:"last_viewed_at"`
+}
+
+func (v PublicDashboardViewer) TableName() string {
+	return "dashboard_public_viewer"
+}
+
+// PublicDashboardViewerToken is a magic link or session token of an invited viewer. Only the hash of the token is
+// stored.
+type PublicDashboardViewerToken struct {
+	Id        int64  `xorm:"pk autoincr 'id'"`
+	ViewerId  int64  `xorm:"viewer_id"`
+	Kind      string `xorm:"kind"`
+	TokenHash string `xorm:"token_hash"`
+	CreatedAt int64  `xorm:"created_at"`
+	ExpiresAt int64  `xorm:"expires_at"`
+}
+
+func (t PublicDashboardViewerToken) TableName() string {
+	return "dashboard_public_viewer_token"
+}
+
+// PublicDashboardViewerEvent is an entry of the usage log of the invited viewers of a public dashboard
+type PublicDashboardViewerEvent struct {
+	Id                 int64     `json:"-" xorm:"pk autoincr 'id'"`
+	PublicDashboardUid string    `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	OrgId              int64     `json:"-" xorm:"org_id"`
+	Email              string    `json:"email" xorm:"email"`
+	Event              string    `json:"event" xorm:"event"`
+	CreatedAt          time.Time `json:"createdAt" xorm:"created_at"`
+}
+
+func (e PublicDashboardViewerEvent) TableName() string {
+	return "dashboard_public_viewer_event"
+}
+
+// PublicDashboardAccessLog is a request to a public dashboard. Client IPs are only stored hashed.
+type PublicDashboardAccessLog struct {
+	Id                 int64  `xorm:"pk autoincr 'id'"`
+	PublicDashboardUid string `xorm:"public_dashboard_uid"`
+	OrgId              int64  `xorm:"org_id"`
+	Kind               string `xorm:"kind"`
+	PanelId            int64  `xorm:"panel_id"`
+	Status             int    `xorm:"status"`
+	DurationMs         int64  `xorm:"duration_ms"`
+	ClientIpHash       string `xorm:"client_ip_hash"`
+	Referer            string `xorm:"referer"`
+	CreatedAt          int64  `xorm:"created_at"`
+}
+
+func (l PublicDashboardAccessLog) TableName() string {
+	return "dashboard_public_access_log"
+}
+
+// PublicDashboardAccessSummary is the aggregated access log of a public dashboard over a period
+type PublicDashboardAccessSummary struct {
+	PublicDashboardUid  string `xorm:"public_dashboard_uid"`
+	Requests            int64  `xorm:"requests"`
+	Queries             int64  `xorm:"queries"`
+	FailedRequests      int64  `xorm:"failed_requests"`
+	RateLimitedRequests int64  `xorm:"rate_limited_requests"`
+	UniqueClients       int64  `xorm:"unique_clients"`
+	TopClientRequests   int64  `xorm:"top_client_requests"`
+	QueryDurationMs     int64  `xorm:"query_duration_ms"`
+	LastAccessedAt      int64  `xorm:"last_accessed_at"`
+}
+
 // Alias the generated type
 type DashAnnotation = dashboard.AnnotationQuery
 
@@ -119,12 +241,54 @@ type SavePublicDashboardDTO struct {
 	OrgId           int64
 	UserId          int64
 	PublicDashboard *PublicDashboard
+	// ExpiresAtSet and RateLimitSet are true when an update sets the expiration or the rate limit of the public
+	// dashboard, which are kept otherwise
+	ExpiresAtSet bool
+	RateLimitSet bool
 }
 
 type PublicDashboardQueryDTO struct {
 	IntervalMs    int64
 	MaxDataPoints int64
 	TimeRange     TimeSettings
+	// Variables are the values of the template variables selected by the viewer, by variable name
+	Variables map[string][]string
+}
+
+type InviteViewerDTO struct {
+	Email string `json:"email"`
+}
+
+type RequestViewerLinkDTO struct {
+	Email string `json:"email"`
+}
+
+// PublicDashboardAccessDTO is a request to a public dashboard to record in its access log
+type PublicDashboardAccessDTO struct {
+	Kind     string
+	PanelId  int64
+	Status   int
+	Duration time.Duration
+	ClientIp string
+	Referer  string
+}
+
+// PublicDashboardUsage is the usage of a public dashboard since a time
+type PublicDashboardUsage struct {
+	PublicDashboardListResponse
+	Requests            int64      `json:"requests"`
+	Queries             int64      `json:"queries"`
+	FailedRequests      int64      `json:"failedRequests"`
+	RateLimitedRequests int64      `json:"rateLimitedRequests"`
+	UniqueClients       int64      `json:"uniqueClients"`
+	TopClientRequests   int64      `json:"topClientRequests"`
+	AvgQueryDurationMs  int64      `json:"avgQueryDurationMs"`
+	LastAccessedAt      *time.Time `json:"lastAccessedAt"`
+	// Abandoned is set on enabled public dashboards which haven't been accessed since the start of the period
+	Abandoned bool `json:"abandoned"`
+	// SuspectedScraping is set on public dashboards with requests rejected by their rate limit, or with a single
+	// client making more than UsageScrapingMinRequests requests
+	SuspectedScraping bool `json:"suspectedScraping"`
 }
 
 type AnnotationsQueryDTO struct {
@@ -17,6 +17,8 @@ import (
 
 	testing "testing"
 
+	time "time"
+
 	user "github.com/grafana/grafana/pkg/services/user"
 )
 
@@ -25,6 +27,29 @@ type FakePublicDashboardService struct {
 	mock.Mock
 }
 
+// Authorize provides a mock function with given fields: ctx, accessToken, sessionToken
+func (_m *FakePublicDashboardService) Authorize(ctx context.Context, accessToken string, sessionToken string) (*models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, accessToken, sessionToken)
+
+	var r0 *models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, accessToken, sessionToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accessToken, sessionToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -48,6 +73,27 @@ func (_m *FakePublicDashboardService) Create(ctx context.Context, u *user.Signed
 	return r0, r1
 }
 
+// CreateViewerSession provides a mock function with given fields: ctx, accessToken, linkToken
+func (_m *FakePublicDashboardService) CreateViewerSession(ctx context.Context, accessToken string, linkToken string) (string, error) {
+	ret := _m.Called(ctx, accessToken, linkToken)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, accessToken, linkToken)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accessToken, linkToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ctx, orgId, uid
 func (_m *FakePublicDashboardService) Delete(ctx context.Context, orgId int64, uid string) error {
 	ret := _m.Called(ctx, orgId, uid)
@@ -62,6 +108,48 @@ func (_m *FakePublicDashboardService) Delete(ctx context.Context, orgId int64, u
 	return r0
 }
 
+// DeleteAccessLogsOlderThan provides a mock function with given fields: ctx, olderThan
+func (_m *FakePublicDashboardService) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteViewerEventsOlderThan provides a mock function with given fields: ctx, olderThan
+func (_m *FakePublicDashboardService) DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardService) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -228,6 +316,75 @@ func (_m *FakePublicDashboardService) FindPublicDashboardAndDashboardByAccessTok
 	return r0, r1, r2
 }
 
+// FindUsage provides a mock function with given fields: ctx, u, orgId, since
+func (_m *FakePublicDashboardService) FindUsage(ctx context.Context, u *user.SignedInUser, orgId int64, since time.Time) ([]models.PublicDashboardUsage, error) {
+	ret := _m.Called(ctx, u, orgId, since)
+
+	var r0 []models.PublicDashboardUsage
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, int64, time.Time) []models.PublicDashboardUsage); ok {
+		r0 = rf(ctx, u, orgId, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardUsage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, int64, time.Time) error); ok {
+		r1 = rf(ctx, u, orgId, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindViewerEvents provides a mock function with given fields: ctx, orgId, dashboardUid, uid
+func (_m *FakePublicDashboardService) FindViewerEvents(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]models.PublicDashboardViewerEvent, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid)
+
+	var r0 []models.PublicDashboardViewerEvent
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []models.PublicDashboardViewerEvent); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardViewerEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindViewers provides a mock function with given fields: ctx, orgId, dashboardUid, uid
+func (_m *FakePublicDashboardService) FindViewers(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid)
+
+	var r0 []models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) []models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, orgId, dashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMetricRequest provides a mock function with given fields: ctx, dashboard, publicDashboard, panelId, reqDTO
 func (_m *FakePublicDashboardService) GetMetricRequest(ctx context.Context, dashboard *pkgmodels.Dashboard, publicDashboard *models.PublicDashboard, panelId int64, reqDTO models.PublicDashboardQueryDTO) (dtos.MetricRequest, error) {
 	ret := _m.Called(ctx, dashboard, publicDashboard, panelId, reqDTO)
@@ -293,6 +450,29 @@ func (_m *FakePublicDashboardService) GetQueryDataResponse(ctx context.Context,
 	return r0, r1
 }
 
+// InviteViewer provides a mock function with given fields: ctx, u, dashboardUid, uid, email
+func (_m *FakePublicDashboardService) InviteViewer(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, email string) (*models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, u, dashboardUid, uid, email)
+
+	var r0 *models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, *user.SignedInUser, string, string, string) *models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, u, dashboardUid, uid, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *user.SignedInUser, string, string, string) error); ok {
+		r1 = rf(ctx, u, dashboardUid, uid, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPublicDashboardAccessToken provides a mock function with given fields: ctx
 func (_m *FakePublicDashboardService) NewPublicDashboardAccessToken(ctx context.Context) (string, error) {
 	ret := _m.Called(ctx)
@@ -335,6 +515,53 @@ func (_m *FakePublicDashboardService) NewPublicDashboardUid(ctx context.Context)
 	return r0, r1
 }
 
+// RecordAccess provides a mock function with given fields: ctx, accessToken, access
+func (_m *FakePublicDashboardService) RecordAccess(ctx context.Context, accessToken string, access models.PublicDashboardAccessDTO) {
+	_m.Called(ctx, accessToken, access)
+}
+
+// RecordViewerView provides a mock function with given fields: ctx, viewer
+func (_m *FakePublicDashboardService) RecordViewerView(ctx context.Context, viewer *models.PublicDashboardViewer) error {
+	ret := _m.Called(ctx, viewer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardViewer) error); ok {
+		r0 = rf(ctx, viewer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeViewer provides a mock function with given fields: ctx, orgId, dashboardUid, uid, viewerUid
+func (_m *FakePublicDashboardService) RevokeViewer(ctx context.Context, orgId int64, dashboardUid string, uid string, viewerUid string) error {
+	ret := _m.Called(ctx, orgId, dashboardUid, uid, viewerUid)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string, string) error); ok {
+		r0 = rf(ctx, orgId, dashboardUid, uid, viewerUid)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendViewerLink provides a mock function with given fields: ctx, accessToken, email
+func (_m *FakePublicDashboardService) SendViewerLink(ctx context.Context, accessToken string, email string) error {
+	ret := _m.Called(ctx, accessToken, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, accessToken, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, u, dto
 func (_m *FakePublicDashboardService) Update(ctx context.Context, u *user.SignedInUser, dto *models.SavePublicDashboardDTO) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, u, dto)
@@ -11,6 +11,8 @@ import (
 	pkgmodels "github.com/grafana/grafana/pkg/models"
 
 	testing "testing"
+
+	time "time"
 )
 
 // FakePublicDashboardStore is an autogenerated mock type for the Store type
@@ -39,6 +41,62 @@ func (_m *FakePublicDashboardStore) Create(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// CreateAccessLogs provides a mock function with given fields: ctx, entries
+func (_m *FakePublicDashboardStore) CreateAccessLogs(ctx context.Context, entries []*models.PublicDashboardAccessLog) error {
+	ret := _m.Called(ctx, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*models.PublicDashboardAccessLog) error); ok {
+		r0 = rf(ctx, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateViewer provides a mock function with given fields: ctx, viewer
+func (_m *FakePublicDashboardStore) CreateViewer(ctx context.Context, viewer *models.PublicDashboardViewer) error {
+	ret := _m.Called(ctx, viewer)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardViewer) error); ok {
+		r0 = rf(ctx, viewer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateViewerEvent provides a mock function with given fields: ctx, event
+func (_m *FakePublicDashboardStore) CreateViewerEvent(ctx context.Context, event *models.PublicDashboardViewerEvent) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardViewerEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateViewerToken provides a mock function with given fields: ctx, token
+func (_m *FakePublicDashboardStore) CreateViewerToken(ctx context.Context, token *models.PublicDashboardViewerToken) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PublicDashboardViewerToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, orgId, uid
 func (_m *FakePublicDashboardStore) Delete(ctx context.Context, orgId int64, uid string) (int64, error) {
 	ret := _m.Called(ctx, orgId, uid)
@@ -60,6 +118,92 @@ func (_m *FakePublicDashboardStore) Delete(ctx context.Context, orgId int64, uid
 	return r0, r1
 }
 
+// DeleteAccessLogsOlderThan provides a mock function with given fields: ctx, olderThan
+func (_m *FakePublicDashboardStore) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteViewer provides a mock function with given fields: ctx, publicDashboardUid, uid
+func (_m *FakePublicDashboardStore) DeleteViewer(ctx context.Context, publicDashboardUid string, uid string) (*models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, publicDashboardUid, uid)
+
+	var r0 *models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, uid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteViewerEventsOlderThan provides a mock function with given fields: ctx, olderThan
+func (_m *FakePublicDashboardStore) DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	ret := _m.Called(ctx, olderThan)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, olderThan)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteViewerToken provides a mock function with given fields: ctx, tokenHash
+func (_m *FakePublicDashboardStore) DeleteViewerToken(ctx context.Context, tokenHash string) (int64, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsEnabledByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -102,6 +246,27 @@ func (_m *FakePublicDashboardStore) ExistsEnabledByDashboardUid(ctx context.Cont
 	return r0, r1
 }
 
+// ExistsViewerToken provides a mock function with given fields: ctx, viewerId, kind, now
+func (_m *FakePublicDashboardStore) ExistsViewerToken(ctx context.Context, viewerId int64, kind string, now time.Time) (bool, error) {
+	ret := _m.Called(ctx, viewerId, kind, now)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) bool); ok {
+		r0 = rf(ctx, viewerId, kind, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, time.Time) error); ok {
+		r1 = rf(ctx, viewerId, kind, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Find provides a mock function with given fields: ctx, uid
 func (_m *FakePublicDashboardStore) Find(ctx context.Context, uid string) (*models.PublicDashboard, error) {
 	ret := _m.Called(ctx, uid)
@@ -125,6 +290,29 @@ func (_m *FakePublicDashboardStore) Find(ctx context.Context, uid string) (*mode
 	return r0, r1
 }
 
+// FindAccessSummaries provides a mock function with given fields: ctx, orgId, since
+func (_m *FakePublicDashboardStore) FindAccessSummaries(ctx context.Context, orgId int64, since time.Time) ([]models.PublicDashboardAccessSummary, error) {
+	ret := _m.Called(ctx, orgId, since)
+
+	var r0 []models.PublicDashboardAccessSummary
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) []models.PublicDashboardAccessSummary); ok {
+		r0 = rf(ctx, orgId, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardAccessSummary)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, orgId, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindAll provides a mock function with given fields: ctx, orgId
 func (_m *FakePublicDashboardStore) FindAll(ctx context.Context, orgId int64) ([]models.PublicDashboardListResponse, error) {
 	ret := _m.Called(ctx, orgId)
@@ -217,6 +405,98 @@ func (_m *FakePublicDashboardStore) FindDashboard(ctx context.Context, orgId int
 	return r0, r1
 }
 
+// FindViewer provides a mock function with given fields: ctx, publicDashboardUid, email
+func (_m *FakePublicDashboardStore) FindViewer(ctx context.Context, publicDashboardUid string, email string) (*models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, publicDashboardUid, email)
+
+	var r0 *models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, publicDashboardUid, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindViewerByToken provides a mock function with given fields: ctx, publicDashboardUid, kind, tokenHash, now
+func (_m *FakePublicDashboardStore) FindViewerByToken(ctx context.Context, publicDashboardUid string, kind string, tokenHash string, now time.Time) (*models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, publicDashboardUid, kind, tokenHash, now)
+
+	var r0 *models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) *models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, publicDashboardUid, kind, tokenHash, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, time.Time) error); ok {
+		r1 = rf(ctx, publicDashboardUid, kind, tokenHash, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindViewerEvents provides a mock function with given fields: ctx, publicDashboardUid, limit
+func (_m *FakePublicDashboardStore) FindViewerEvents(ctx context.Context, publicDashboardUid string, limit int) ([]models.PublicDashboardViewerEvent, error) {
+	ret := _m.Called(ctx, publicDashboardUid, limit)
+
+	var r0 []models.PublicDashboardViewerEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []models.PublicDashboardViewerEvent); ok {
+		r0 = rf(ctx, publicDashboardUid, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardViewerEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, publicDashboardUid, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindViewers provides a mock function with given fields: ctx, publicDashboardUid
+func (_m *FakePublicDashboardStore) FindViewers(ctx context.Context, publicDashboardUid string) ([]models.PublicDashboardViewer, error) {
+	ret := _m.Called(ctx, publicDashboardUid)
+
+	var r0 []models.PublicDashboardViewer
+	if rf, ok := ret.Get(0).(func(context.Context, string) []models.PublicDashboardViewer); ok {
+		r0 = rf(ctx, publicDashboardUid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PublicDashboardViewer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, publicDashboardUid)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetOrgIdByAccessToken provides a mock function with given fields: ctx, accessToken
 func (_m *FakePublicDashboardStore) GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error) {
 	ret := _m.Called(ctx, accessToken)
@@ -259,6 +539,20 @@ func (_m *FakePublicDashboardStore) Update(ctx context.Context, cmd models.SaveP
 	return r0, r1
 }
 
+// UpdateViewerLastViewedAt provides a mock function with given fields: ctx, viewerId, lastViewedAt
+func (_m *FakePublicDashboardStore) UpdateViewerLastViewedAt(ctx context.Context, viewerId int64, lastViewedAt time.Time) error {
+	ret := _m.Called(ctx, viewerId, lastViewedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, viewerId, lastViewedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewFakePublicDashboardStore creates a new instance of FakePublicDashboardStore. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewFakePublicDashboardStore(t testing.TB) *FakePublicDashboardStore {
 	mock := &FakePublicDashboardStore{}
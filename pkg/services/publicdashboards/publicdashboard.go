@@ -2,6 +2,7 @@ package publicdashboards
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana/pkg/api/dtos"
@@ -32,6 +33,20 @@ type Service interface {
 
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	Authorize(ctx context.Context, accessToken string, sessionToken string) (*PublicDashboardViewer, error)
+	RecordViewerView(ctx context.Context, viewer *PublicDashboardViewer) error
+	InviteViewer(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, email string) (*PublicDashboardViewer, error)
+	FindViewers(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]PublicDashboardViewer, error)
+	FindViewerEvents(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]PublicDashboardViewerEvent, error)
+	RevokeViewer(ctx context.Context, orgId int64, dashboardUid string, uid string, viewerUid string) error
+	SendViewerLink(ctx context.Context, accessToken string, email string) error
+	CreateViewerSession(ctx context.Context, accessToken string, linkToken string) (string, error)
+	DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
+
+	RecordAccess(ctx context.Context, accessToken string, access PublicDashboardAccessDTO)
+	FindUsage(ctx context.Context, u *user.SignedInUser, orgId int64, since time.Time) ([]PublicDashboardUsage, error)
+	DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
 }
 
 //go:generate mockery --name Store --structname FakePublicDashboardStore --inpackage --filename public_dashboard_store_mock.go
@@ -48,4 +63,21 @@ type Store interface {
 	GetOrgIdByAccessToken(ctx context.Context, accessToken string) (int64, error)
 	ExistsEnabledByAccessToken(ctx context.Context, accessToken string) (bool, error)
 	ExistsEnabledByDashboardUid(ctx context.Context, dashboardUid string) (bool, error)
+
+	CreateViewer(ctx context.Context, viewer *PublicDashboardViewer) error
+	FindViewer(ctx context.Context, publicDashboardUid string, email string) (*PublicDashboardViewer, error)
+	FindViewers(ctx context.Context, publicDashboardUid string) ([]PublicDashboardViewer, error)
+	FindViewerByToken(ctx context.Context, publicDashboardUid string, kind string, tokenHash string, now time.Time) (*PublicDashboardViewer, error)
+	UpdateViewerLastViewedAt(ctx context.Context, viewerId int64, lastViewedAt time.Time) error
+	DeleteViewer(ctx context.Context, publicDashboardUid string, uid string) (*PublicDashboardViewer, error)
+	CreateViewerToken(ctx context.Context, token *PublicDashboardViewerToken) error
+	ExistsViewerToken(ctx context.Context, viewerId int64, kind string, now time.Time) (bool, error)
+	DeleteViewerToken(ctx context.Context, tokenHash string) (int64, error)
+	CreateViewerEvent(ctx context.Context, event *PublicDashboardViewerEvent) error
+	FindViewerEvents(ctx context.Context, publicDashboardUid string, limit int) ([]PublicDashboardViewerEvent, error)
+	DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
+
+	CreateAccessLogs(ctx context.Context, entries []*PublicDashboardAccessLog) error
+	DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error)
+	FindAccessSummaries(ctx context.Context, orgId int64, since time.Time) ([]PublicDashboardAccessSummary, error)
 }
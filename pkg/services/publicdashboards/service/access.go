@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	// maxRefererLength is the length of the referer column of the access log
+	maxRefererLength = 255
+	// accessLogFlushInterval is how often the buffered requests are written to the access log
+	accessLogFlushInterval = 10 * time.Second
+	// maxBufferedAccessLogs bounds the requests buffered between two writes, requests over it aren't recorded
+	maxBufferedAccessLogs = 10000
+)
+
+// bufferedAccessLog is a request to the public dashboard of an access token waiting to be written to the access log
+type bufferedAccessLog struct {
+	accessToken string
+	duration    time.Duration
+	entry       PublicDashboardAccessLog
+}
+
+// RecordAccess Buffers a request to add it to the access log and the metrics of the public dashboard of the access
+// token. Run writes the buffered requests, so requests don't wait for the database. Requests to unknown access tokens
+// aren't recorded.
+func (pd *PublicDashboardServiceImpl) RecordAccess(_ context.Context, accessToken string, access PublicDashboardAccessDTO) {
+	entry := PublicDashboardAccessLog{
+		Kind:         access.Kind,
+		PanelId:      access.PanelId,
+		Status:       access.Status,
+		DurationMs:   access.Duration.Milliseconds(),
+		ClientIpHash: tokens.HashClientIp(access.ClientIp, pd.secretKey()),
+		Referer:      sanitizeReferer(access.Referer),
+		CreatedAt:    time.Now().Unix(),
+	}
+
+	pd.accessLogsMu.Lock()
+	defer pd.accessLogsMu.Unlock()
+
+	if len(pd.accessLogs) >= maxBufferedAccessLogs {
+		pd.droppedAccessLogs++
+		return
+	}
+	pd.accessLogs = append(pd.accessLogs, bufferedAccessLog{accessToken: accessToken, duration: access.Duration, entry: entry})
+}
+
+// Run writes the buffered requests to the access log until the context is cancelled
+func (pd *PublicDashboardServiceImpl) Run(ctx context.Context) error {
+	ticker := time.NewTicker(accessLogFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := pd.flushAccessLogs(ctx); err != nil {
+				pd.log.Error("Failed to write public dashboard access logs", "error", err)
+			}
+		case <-ctx.Done():
+			// write what is left with a fresh context, the server is shutting down
+			flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := pd.flushAccessLogs(flushCtx); err != nil {
+				pd.log.Error("Failed to write public dashboard access logs", "error", err)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// flushAccessLogs writes the buffered requests to the access log and the metrics, finding the public dashboard of each
+// access token once
+func (pd *PublicDashboardServiceImpl) flushAccessLogs(ctx context.Context) error {
+	pd.accessLogsMu.Lock()
+	buffered, dropped := pd.accessLogs, pd.droppedAccessLogs
+	pd.accessLogs, pd.droppedAccessLogs = nil, 0
+	pd.accessLogsMu.Unlock()
+
+	if dropped > 0 {
+		pd.log.Warn("Public dashboard requests not recorded in the access log, too many requests", "count", dropped)
+	}
+
+	if len(buffered) == 0 {
+		return nil
+	}
+
+	pubdashes := make(map[string]*PublicDashboard)
+	entries := make([]*PublicDashboardAccessLog, 0, len(buffered))
+	for i := range buffered {
+		pubdash, ok := pubdashes[buffered[i].accessToken]
+		if !ok {
+			var err error
+			pubdash, err = pd.store.FindByAccessToken(ctx, buffered[i].accessToken)
+			if err != nil {
+				return ErrInternalServerError.Errorf("flushAccessLogs: failed to find a public dashboard: %w", err)
+			}
+			pubdashes[buffered[i].accessToken] = pubdash
+		}
+
+		if pubdash == nil {
+			continue
+		}
+
+		entry := &buffered[i].entry
+		LogAccess(pubdash.Uid, entry.Kind, entry.Status, buffered[i].duration)
+		entry.PublicDashboardUid = pubdash.Uid
+		entry.OrgId = pubdash.OrgId
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := pd.store.CreateAccessLogs(ctx, entries); err != nil {
+		return ErrInternalServerError.Errorf("flushAccessLogs: failed to create access logs: %w", err)
+	}
+
+	return nil
+}
+
+// FindUsage Returns the usage since a time of the public dashboards of an org the user can read, flagging the
+// abandoned and possibly scraped ones
+func (pd *PublicDashboardServiceImpl) FindUsage(ctx context.Context, u *user.SignedInUser, orgId int64, since time.Time) ([]PublicDashboardUsage, error) {
+	publicDashboards, err := pd.FindAll(ctx, u, orgId)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := pd.store.FindAccessSummaries(ctx, orgId, since)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindUsage: failed to summarise access logs: %w", err)
+	}
+
+	summariesByUid := make(map[string]PublicDashboardAccessSummary, len(summaries))
+	for _, s := range summaries {
+		summariesByUid[s.PublicDashboardUid] = s
+	}
+
+	usages := make([]PublicDashboardUsage, 0, len(publicDashboards))
+	for _, p := range publicDashboards {
+		usage := PublicDashboardUsage{PublicDashboardListResponse: p}
+
+		if s, ok := summariesByUid[p.Uid]; ok {
+			usage.Requests = s.Requests
+			usage.Queries = s.Queries
+			usage.FailedRequests = s.FailedRequests
+			usage.RateLimitedRequests = s.RateLimitedRequests
+			usage.UniqueClients = s.UniqueClients
+			usage.TopClientRequests = s.TopClientRequests
+			if s.Queries > 0 {
+				usage.AvgQueryDurationMs = s.QueryDurationMs / s.Queries
+			}
+			lastAccessedAt := time.Unix(s.LastAccessedAt, 0)
+			usage.LastAccessedAt = &lastAccessedAt
+		}
+
+		usage.Abandoned = p.IsEnabled && usage.Requests == 0
+		usage.SuspectedScraping = usage.RateLimitedRequests > 0 || usage.TopClientRequests > UsageScrapingMinRequests
+
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+// DeleteAccessLogsOlderThan Deletes the requests of the access log of all public dashboards made before a time
+func (pd *PublicDashboardServiceImpl) DeleteAccessLogsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	return pd.store.DeleteAccessLogsOlderThan(ctx, olderThan)
+}
+
+// secretKey returns the key of the hashes of client IPs
+func (pd *PublicDashboardServiceImpl) secretKey() string {
+	if pd.cfg == nil {
+		return ""
+	}
+	return pd.cfg.SecretKey
+}
+
+// sanitizeReferer removes the query and fragment of a referer, which may contain personal data, and truncates it to
+// the length of the access log column
+func sanitizeReferer(referer string) string {
+	if u, err := url.Parse(referer); err == nil {
+		u.RawQuery = ""
+		u.Fragment = ""
+		u.User = nil
+		referer = u.String()
+	}
+
+	if len(referer) > maxRefererLength {
+		referer = referer[:maxRefererLength]
+	}
+
+	return referer
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -34,3 +37,12 @@ func getLabelName(datasources []string) string {
 		return "mixed"
 	}
 }
+
+// LogAccess updates the metrics of a request to a public dashboard. The public dashboard uid is resolved from the access
+// token, so there is at most a series per existing public dashboard.
+func LogAccess(publicDashboardUid string, kind string, status int, duration time.Duration) {
+	metrics.MPublicDashboardAccessCount.WithLabelValues(publicDashboardUid, kind, strconv.Itoa(status)).Inc()
+	if kind == models.AccessKindQuery {
+		metrics.MPublicDashboardQueryDuration.WithLabelValues(publicDashboardUid).Observe(duration.Seconds())
+	}
+}
@@ -160,9 +160,16 @@ func (pd *PublicDashboardServiceImpl) buildMetricRequest(ctx context.Context, da
 
 	ts := publicDashboard.BuildTimeSettings(dashboard, reqDTO)
 
+	anonymousUser := buildAnonymousUser(ctx, dashboard)
+	variables, err := resolveTemplateVariables(getTemplateVariables(dashboard.Data), reqDTO.Variables, pd.queryTemplateVariableOptions(ctx, anonymousUser, ts))
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+
 	// determine safe resolution to query data at
 	safeInterval, safeResolution := pd.getSafeIntervalAndMaxDataPoints(reqDTO, ts)
 	for i := range queries {
+		queries[i] = interpolateTemplateVariables(queries[i], variables)
 		queries[i].Set("intervalMs", safeInterval)
 		queries[i].Set("maxDataPoints", safeResolution)
 	}
@@ -233,6 +240,19 @@ func getUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 		}
 	}
 
+	// the options of query variables can be queried with the data source of the variable
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if variable.Get("type").MustString() != "query" {
+			continue
+		}
+		uid := getDataSourceUidFromJson(variable)
+		if _, ok := exists[uid]; !ok && uid != "" {
+			datasourceUids = append(datasourceUids, uid)
+			exists[uid] = true
+		}
+	}
+
 	return datasourceUids
 }
 
@@ -254,7 +274,9 @@ func groupQueriesByPanelId(dashboard *simplejson.Json) map[int64][]*simplejson.J
 				// if query target has no datasource, set it to have the datasource on the panel
 				if _, ok := query.CheckGet("datasource"); !ok {
 					uid := getDataSourceUidFromJson(panel)
-					datasource := map[string]interface{}{"type": "public-ds", "uid": uid}
+					// the type of the data source formats template variables, old panels only have a uid
+					dsType := panel.Get("datasource").Get("type").MustString("public-ds")
+					datasource := map[string]interface{}{"type": dsType, "uid": uid}
 					query.Set("datasource", datasource)
 				}
 
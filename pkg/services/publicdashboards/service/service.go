@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
@@ -21,6 +23,7 @@ import (
 	"github.com/grafana/grafana/pkg/tsdb/intervalv2"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 	"github.com/grafana/grafana/pkg/util"
+	"golang.org/x/time/rate"
 )
 
 // PublicDashboardServiceImpl Define the Service Implementation. We're generating mock implementation
@@ -33,6 +36,18 @@ type PublicDashboardServiceImpl struct {
 	QueryDataService   *query.Service
 	AnnotationsRepo    annotations.Repository
 	ac                 accesscontrol.AccessControl
+	emailSender        notifications.EmailSender
+
+	// limiters are the rate limiters of the public dashboards with a rate limit, by access token
+	limiters map[string]*rate.Limiter
+	// viewerLinkLimiters are the rate limiters of the magic links requested by viewers, by email and by access token
+	viewerLinkLimiters map[string]*rate.Limiter
+	limitersMu         sync.Mutex
+
+	// accessLogs are the requests buffered until they're written to the access log
+	accessLogs        []bufferedAccessLog
+	droppedAccessLogs int
+	accessLogsMu      sync.Mutex
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -49,6 +64,7 @@ func ProvideService(
 	qds *query.Service,
 	anno annotations.Repository,
 	ac accesscontrol.AccessControl,
+	emailSender notifications.EmailSender,
 ) *PublicDashboardServiceImpl {
 	return &PublicDashboardServiceImpl{
 		log:                log.New(LogPrefix),
@@ -58,6 +74,9 @@ func ProvideService(
 		QueryDataService:   qds,
 		AnnotationsRepo:    anno,
 		ac:                 ac,
+		emailSender:        emailSender,
+		limiters:           make(map[string]*rate.Limiter),
+		viewerLinkLimiters: make(map[string]*rate.Limiter),
 	}
 }
 
@@ -90,6 +109,10 @@ func (pd *PublicDashboardServiceImpl) FindPublicDashboardAndDashboardByAccessTok
 		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindPublicDashboardAndDashboardByAccessToken: Public dashboard is disabled accessToken: %s", accessToken)
 	}
 
+	if pubdash.IsExpired(time.Now()) {
+		return nil, nil, ErrPublicDashboardNotFound.Errorf("FindPublicDashboardAndDashboardByAccessToken: Public dashboard is expired accessToken: %s", accessToken)
+	}
+
 	dash, err := pd.store.FindDashboard(ctx, pubdash.OrgId, pubdash.DashboardUid)
 	if err != nil {
 		return nil, nil, err
@@ -129,6 +152,11 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 		dto.PublicDashboard.TimeSettings = &TimeSettings{}
 	}
 
+	// public dashboards are shared with anyone with the access token by default
+	if dto.PublicDashboard.Share == "" {
+		dto.PublicDashboard.Share = ShareTypePublic
+	}
+
 	// validate fields
 	err = validation.ValidatePublicDashboard(dto, dashboard)
 	if err != nil {
@@ -162,6 +190,9 @@ func (pd *PublicDashboardServiceImpl) Create(ctx context.Context, u *user.Signed
 			IsEnabled:          dto.PublicDashboard.IsEnabled,
 			AnnotationsEnabled: dto.PublicDashboard.AnnotationsEnabled,
 			TimeSettings:       dto.PublicDashboard.TimeSettings,
+			Share:              dto.PublicDashboard.Share,
+			ExpiresAt:          dto.PublicDashboard.ExpiresAt,
+			RateLimit:          dto.PublicDashboard.RateLimit,
 			CreatedBy:          dto.UserId,
 			CreatedAt:          time.Now(),
 			AccessToken:        accessToken,
@@ -211,6 +242,17 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 		return nil, ErrPublicDashboardNotFound.Errorf("Update: public dashboard not found by uid: %s", dto.PublicDashboard.Uid)
 	}
 
+	// keep how the public dashboard is shared, its expiration and its rate limit when they're not set
+	if dto.PublicDashboard.Share == "" {
+		dto.PublicDashboard.Share = existingPubdash.Share
+	}
+	if !dto.ExpiresAtSet {
+		dto.PublicDashboard.ExpiresAt = existingPubdash.ExpiresAt
+	}
+	if !dto.RateLimitSet {
+		dto.PublicDashboard.RateLimit = existingPubdash.RateLimit
+	}
+
 	// validate dashboard
 	err = validation.ValidatePublicDashboard(dto, dashboard)
 	if err != nil {
@@ -225,6 +267,9 @@ func (pd *PublicDashboardServiceImpl) Update(ctx context.Context, u *user.Signed
 			AnnotationsEnabled:   dto.PublicDashboard.AnnotationsEnabled,
 			TimeSelectionEnabled: dto.PublicDashboard.TimeSelectionEnabled,
 			TimeSettings:         dto.PublicDashboard.TimeSettings,
+			Share:                dto.PublicDashboard.Share,
+			ExpiresAt:            dto.PublicDashboard.ExpiresAt,
+			RateLimit:            dto.PublicDashboard.RateLimit,
 			UpdatedBy:            dto.UserId,
 			UpdatedAt:            time.Now(),
 		},
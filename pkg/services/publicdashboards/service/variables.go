@@ -0,0 +1,439 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const allValue = "$__all"
+
+// templateVariable is a template variable of a dashboard, with the values viewers of the public dashboard can select.
+type templateVariable struct {
+	multi      bool
+	includeAll bool
+	allValue   string
+	current    []string
+	options    []string
+	// query and datasource are set for query variables without saved options, whose options are queried when needed
+	query      *simplejson.Json
+	datasource interface{}
+}
+
+// variableValue is the value of a template variable used in the queries of a public dashboard.
+type variableValue struct {
+	values []string
+	// raw is true when the value is the custom all value of a variable, which is used as is.
+	raw bool
+	// multi is true for variables with multiple values or all, whose values data sources quote or join by default.
+	multi bool
+}
+
+// regexDataSourceTypes and sqlDataSourceTypes are the data source types which format the values of multi value
+// variables as a regex or as quoted strings when queries don't specify a format.
+var (
+	regexDataSourceTypes = map[string]bool{"prometheus": true, "loki": true}
+	sqlDataSourceTypes   = map[string]bool{"mysql": true, "postgres": true, "grafana-postgresql-datasource": true, "mssql": true}
+)
+
+// variableRegex matches $var, [[var]], [[var:format]], ${var} and ${var:format}, like the frontend template service.
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\$\{(\w+)(?::([^}]+))?\}`)
+
+// getTemplateVariables returns the template variables of a dashboard by name. Only the variable types supported by
+// public dashboards are returned, and their options are read from the saved dashboard so viewers can't select
+// values the dashboard doesn't allow.
+func getTemplateVariables(dashboard *simplejson.Json) map[string]templateVariable {
+	variables := make(map[string]templateVariable)
+
+	for _, obj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(obj)
+		varType := variable.Get("type").MustString()
+		if !validation.SupportedTemplateVariableTypes[varType] {
+			continue
+		}
+
+		v := templateVariable{
+			multi:      variable.Get("multi").MustBool(),
+			includeAll: variable.Get("includeAll").MustBool(),
+			allValue:   variable.Get("allValue").MustString(),
+			current:    jsonStrings(variable.GetPath("current", "value")),
+		}
+
+		if varType == "constant" {
+			v.current = []string{variable.Get("query").MustString()}
+			v.options = v.current
+			variables[variable.Get("name").MustString()] = v
+			continue
+		}
+
+		for _, option := range variable.Get("options").MustArray() {
+			for _, value := range jsonStrings(simplejson.NewFromAny(option).Get("value")) {
+				if !strings.HasPrefix(value, "$__") {
+					v.options = append(v.options, value)
+				}
+			}
+		}
+		// query variables of data sources with standard variable support have a data source query
+		if len(v.options) == 0 && varType == "query" {
+			if _, err := variable.Get("query").Map(); err == nil {
+				v.query = variable.Get("query")
+				v.datasource = variable.Get("datasource").Interface()
+			}
+		}
+		// custom and interval variables aren't always saved with their options
+		if len(v.options) == 0 && varType != "query" {
+			for _, value := range strings.Split(variable.Get("query").MustString(), ",") {
+				// custom variables can have a text and a value, written "text : value"
+				if parts := strings.SplitN(value, " : ", 2); len(parts) == 2 {
+					value = parts[1]
+				}
+				if value = strings.TrimSpace(value); value != "" {
+					v.options = append(v.options, value)
+				}
+			}
+		}
+
+		variables[variable.Get("name").MustString()] = v
+	}
+
+	return variables
+}
+
+// jsonStrings returns the value of a template variable, which is either a string or an array of strings.
+func jsonStrings(value *simplejson.Json) []string {
+	if s, err := value.String(); err == nil {
+		return []string{s}
+	}
+	return value.MustStringArray()
+}
+
+// resolveTemplateVariables returns the values of the template variables selected by the viewer of a public
+// dashboard, or the current values of the dashboard for variables the viewer didn't select. Values which aren't
+// options of the variable are rejected. The options of query variables without saved options are only queried
+// with queryOptions when they are needed, after the variables their query refers to are resolved.
+func resolveTemplateVariables(variables map[string]templateVariable, selected map[string][]string, queryOptions func(templateVariable, map[string]variableValue) ([]string, error)) (map[string]variableValue, error) {
+	order, err := sortTemplateVariables(variables)
+	if err != nil {
+		return nil, err
+	}
+	resolved := make(map[string]variableValue, len(variables))
+
+	for _, name := range order {
+		v := variables[name]
+		values := selected[name]
+		if v.query != nil && (len(values) > 0 || isAllValue(v.current) && v.allValue == "") {
+			options, err := queryOptions(v, resolved)
+			if err != nil {
+				return nil, err
+			}
+			v.options = options
+		}
+
+		if len(values) > 0 {
+			if err := validateSelectedValues(name, v, values); err != nil {
+				return nil, err
+			}
+		} else {
+			// the current values are saved by the dashboard owner
+			values = v.current
+		}
+
+		multi := v.multi || v.includeAll
+		switch {
+		case isAllValue(values) && v.allValue != "":
+			resolved[name] = variableValue{values: []string{v.allValue}, raw: true, multi: multi}
+		case isAllValue(values):
+			resolved[name] = variableValue{values: v.options, multi: multi}
+		default:
+			resolved[name] = variableValue{values: values, multi: multi}
+		}
+	}
+
+	return resolved, nil
+}
+
+// sortTemplateVariables returns the names of the variables in dependency order, so that the variables the query of
+// a variable refers to come before the variable.
+func sortTemplateVariables(variables map[string]templateVariable) ([]string, error) {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	order := make([]string, 0, len(variables))
+	// visited is false while the dependencies of a variable are visited, and true once the variable is sorted
+	visited := make(map[string]bool, len(variables))
+	var visit func(name string) error
+	visit = func(name string) error {
+		if sorted, ok := visited[name]; ok {
+			if !sorted {
+				return models.ErrPublicDashboardHasTemplateVariables.Errorf("sortTemplateVariables: variable %s has a circular dependency", name)
+			}
+			return nil
+		}
+		visited[name] = false
+		if v := variables[name]; v.query != nil {
+			for _, ref := range variableReferences(v.query.Interface(), nil) {
+				if _, ok := variables[ref]; ok && ref != name {
+					if err := visit(ref); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		visited[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// variableReferences appends the names of the variables referred to by the strings of value to refs.
+func variableReferences(value interface{}, refs []string) []string {
+	switch v := value.(type) {
+	case string:
+		for _, groups := range variableRegex.FindAllStringSubmatch(v, -1) {
+			refs = append(refs, groups[1]+groups[2]+groups[4])
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			refs = variableReferences(item, refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			refs = variableReferences(item, refs)
+		}
+	}
+	return refs
+}
+
+func isAllValue(values []string) bool {
+	return len(values) == 1 && values[0] == allValue
+}
+
+func validateSelectedValues(name string, v templateVariable, values []string) error {
+	if isAllValue(values) {
+		if !v.includeAll {
+			return models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: variable %s doesn't include all", name)
+		}
+		return nil
+	}
+
+	if len(values) > 1 && !v.multi {
+		return models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: variable %s has multiple values", name)
+	}
+	for _, value := range values {
+		if !contains(v.options, value) {
+			return models.ErrInvalidTemplateVariableValue.Errorf("resolveTemplateVariables: %q is not an option of variable %s", value, name)
+		}
+	}
+	return nil
+}
+
+// queryTemplateVariableOptions returns a function which runs the query of a query variable with the data source of
+// the variable, and returns the values of the results like the frontend does for standard variable support. The
+// query is interpolated with the resolved values of the variables it refers to.
+func (pd *PublicDashboardServiceImpl) queryTemplateVariableOptions(ctx context.Context, user *user.SignedInUser, ts models.TimeSettings) func(templateVariable, map[string]variableValue) ([]string, error) {
+	return func(v templateVariable, resolved map[string]variableValue) ([]string, error) {
+		dsType := simplejson.NewFromAny(v.datasource).Get("type").MustString()
+		query := simplejson.NewFromAny(interpolateValue(v.query.Interface(), resolved, dsType))
+		if _, ok := query.CheckGet("refId"); !ok {
+			query.Set("refId", "variable")
+		}
+		query.Set("datasource", v.datasource)
+
+		res, err := pd.QueryDataService.QueryData(ctx, user, false, dtos.MetricRequest{
+			From:    ts.From,
+			To:      ts.To,
+			Queries: []*simplejson.Json{query},
+		})
+		if err != nil {
+			return nil, models.ErrInternalServerError.Errorf("queryTemplateVariableOptions: failed to query options: %w", err)
+		}
+
+		var options []string
+		for _, r := range res.Responses {
+			if r.Error != nil {
+				return nil, models.ErrInternalServerError.Errorf("queryTemplateVariableOptions: failed to query options: %w", r.Error)
+			}
+			for _, frame := range r.Frames {
+				field := variableValueField(frame)
+				if field == nil {
+					continue
+				}
+				for i := 0; i < field.Len(); i++ {
+					if value, ok := field.ConcreteAt(i); ok {
+						options = append(options, fmt.Sprint(value))
+					}
+				}
+			}
+		}
+		return options, nil
+	}
+}
+
+// variableValueField returns the field of a frame with the values of a variable: the __value or value field, or
+// else the first string field.
+func variableValueField(frame *data.Frame) *data.Field {
+	for _, name := range []string{"__value", "value"} {
+		if field, idx := frame.FieldByName(name); idx != -1 {
+			return field
+		}
+	}
+	for _, field := range frame.Fields {
+		if field.Type().NonNullableType() == data.FieldTypeString {
+			return field
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// interpolateTemplateVariables returns a copy of query where the template variables in strings are replaced by
+// their values, formatted like the data source of the query formats them. Variables which aren't template variables
+// of the dashboard, such as $__interval, are kept.
+func interpolateTemplateVariables(query *simplejson.Json, variables map[string]variableValue) *simplejson.Json {
+	if len(variables) == 0 {
+		return query
+	}
+	dsType := query.Get("datasource").Get("type").MustString()
+	return simplejson.NewFromAny(interpolateValue(query.Interface(), variables, dsType))
+}
+
+func interpolateValue(value interface{}, variables map[string]variableValue, dsType string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return interpolateString(v, variables, dsType)
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = interpolateValue(item, variables, dsType)
+		}
+		return m
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i, item := range v {
+			a[i] = interpolateValue(item, variables, dsType)
+		}
+		return a
+	default:
+		return v
+	}
+}
+
+func interpolateString(s string, variables map[string]variableValue, dsType string) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name, format := groups[1]+groups[2]+groups[4], groups[3]+groups[5]
+
+		value, ok := variables[name]
+		if !ok {
+			return match
+		}
+		if value.raw {
+			return value.values[0]
+		}
+		if format == "" {
+			format = defaultVariableFormat(dsType, value)
+		}
+		return formatVariableValue(name, value.values, format)
+	})
+}
+
+// defaultVariableFormat returns the format of a variable without a format in a query, which depends on the data
+// source like in the frontend: Prometheus and Loki format multi value variables as a regex, SQL data sources as
+// quoted strings and other data sources as a glob.
+func defaultVariableFormat(dsType string, value variableValue) string {
+	switch {
+	case value.multi && regexDataSourceTypes[dsType]:
+		return "regex"
+	case value.multi && sqlDataSourceTypes[dsType]:
+		return "sqlstring"
+	default:
+		return "glob"
+	}
+}
+
+// formatVariableValue formats the values of a variable like the frontend template service formats them.
+func formatVariableValue(name string, values []string, format string) string {
+	switch format {
+	case "csv", "raw", "text":
+		return strings.Join(values, ",")
+	case "pipe":
+		return strings.Join(values, "|")
+	case "regex":
+		escaped := make([]string, len(values))
+		for i, v := range values {
+			escaped[i] = regexp.QuoteMeta(v)
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "singlequote", "sqlstring":
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			if format == "sqlstring" {
+				quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+			} else {
+				quoted[i] = "'" + strings.ReplaceAll(v, "'", `\'`) + "'"
+			}
+		}
+		return strings.Join(quoted, ",")
+	case "doublequote":
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+		}
+		return strings.Join(quoted, ",")
+	case "json":
+		var b []byte
+		if len(values) == 1 {
+			b, _ = json.Marshal(values[0])
+		} else {
+			b, _ = json.Marshal(values)
+		}
+		return string(b)
+	case "percentencode":
+		return url.QueryEscape(strings.Join(values, ","))
+	case "queryparam":
+		params := make([]string, len(values))
+		for i, v := range values {
+			params[i] = fmt.Sprintf("var-%s=%s", url.QueryEscape(name), url.QueryEscape(v))
+		}
+		return strings.Join(params, "&")
+	default:
+		// glob, which the frontend uses when the data source doesn't format the values
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const dashboardWithTemplateVariables = `{
+	"templating": {
+		"list": [
+			{
+				"name": "env",
+				"type": "custom",
+				"query": "Production : prod,dev",
+				"current": {"text": "Production", "value": "prod"}
+			},
+			{
+				"name": "host",
+				"type": "query",
+				"multi": true,
+				"includeAll": true,
+				"current": {"text": ["a"], "value": ["a"]},
+				"options": [
+					{"text": "All", "value": "$__all"},
+					{"text": "a", "value": "a"},
+					{"text": "b.example", "value": "b.example"}
+				]
+			},
+			{
+				"name": "region",
+				"type": "custom",
+				"includeAll": true,
+				"allValue": ".*",
+				"current": {"text": "All", "value": "$__all"},
+				"options": [{"text": "eu", "value": "eu"}]
+			},
+			{"name": "job", "type": "constant", "query": "api"},
+			{
+				"name": "pod",
+				"type": "query",
+				"refresh": 1,
+				"datasource": {"type": "mysql", "uid": "ds1"},
+				"query": {"rawSql": "SELECT pod FROM pods WHERE host IN ($host)"},
+				"current": {"text": "pod-1", "value": "pod-1"}
+			},
+			{"name": "ds", "type": "datasource", "query": "prometheus"}
+		]
+	}
+}`
+
+func TestGetTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+
+	variables := getTemplateVariables(dashboard)
+	require.Len(t, variables, 5)
+	assert.Equal(t, templateVariable{current: []string{"prod"}, options: []string{"prod", "dev"}}, variables["env"])
+	assert.Equal(t, templateVariable{multi: true, includeAll: true, current: []string{"a"}, options: []string{"a", "b.example"}}, variables["host"])
+	assert.Equal(t, templateVariable{current: []string{"api"}, options: []string{"api"}}, variables["job"])
+	assert.Equal(t, "SELECT pod FROM pods WHERE host IN ($host)", variables["pod"].query.Get("rawSql").MustString())
+	assert.Equal(t, map[string]interface{}{"type": "mysql", "uid": "ds1"}, variables["pod"].datasource)
+	assert.NotContains(t, variables, "ds")
+}
+
+func TestResolveTemplateVariables(t *testing.T) {
+	dashboard, err := simplejson.NewJson([]byte(dashboardWithTemplateVariables))
+	require.NoError(t, err)
+	variables := getTemplateVariables(dashboard)
+
+	t.Run("uses the current values when no values are selected", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(variables, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, variableValue{values: []string{"prod"}}, resolved["env"])
+		assert.Equal(t, variableValue{values: []string{"a"}, multi: true}, resolved["host"])
+		assert.Equal(t, variableValue{values: []string{".*"}, raw: true, multi: true}, resolved["region"])
+		assert.Equal(t, variableValue{values: []string{"api"}}, resolved["job"])
+	})
+
+	t.Run("uses the selected values", func(t *testing.T) {
+		resolved, err := resolveTemplateVariables(variables, map[string][]string{"env": {"dev"}, "host": {"$__all"}}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, variableValue{values: []string{"dev"}}, resolved["env"])
+		assert.Equal(t, variableValue{values: []string{"a", "b.example"}, multi: true}, resolved["host"])
+	})
+
+	t.Run("queries the options of query variables only when needed", func(t *testing.T) {
+		queried := 0
+		queryOptions := func(v templateVariable, resolved map[string]variableValue) ([]string, error) {
+			queried++
+			// the variables the query refers to are resolved first
+			query := interpolateValue(v.query.Interface(), resolved, "mysql")
+			assert.Equal(t, map[string]interface{}{"rawSql": "SELECT pod FROM pods WHERE host IN ('b.example')"}, query)
+			return []string{"pod-1", "pod-2"}, nil
+		}
+
+		resolved, err := resolveTemplateVariables(variables, nil, queryOptions)
+		require.NoError(t, err)
+		assert.Equal(t, variableValue{values: []string{"pod-1"}}, resolved["pod"])
+		assert.Equal(t, 0, queried)
+
+		resolved, err = resolveTemplateVariables(variables, map[string][]string{"pod": {"pod-2"}, "host": {"b.example"}}, queryOptions)
+		require.NoError(t, err)
+		assert.Equal(t, variableValue{values: []string{"pod-2"}}, resolved["pod"])
+		assert.Equal(t, 1, queried)
+
+		_, err = resolveTemplateVariables(variables, map[string][]string{"pod": {"pod-3"}, "host": {"b.example"}}, queryOptions)
+		require.ErrorContains(t, err, ErrInvalidTemplateVariableValue.Error())
+	})
+
+	t.Run("rejects values which are not options", func(t *testing.T) {
+		for _, selected := range []map[string][]string{
+			{"env": {"staging"}},
+			{"env": {"prod", "dev"}},
+			{"env": {"$__all"}},
+			{"job": {"other"}},
+		} {
+			_, err := resolveTemplateVariables(variables, selected, nil)
+			require.ErrorContains(t, err, ErrInvalidTemplateVariableValue.Error())
+		}
+	})
+
+	t.Run("rejects circular dependencies", func(t *testing.T) {
+		circular := map[string]templateVariable{
+			"a": {query: simplejson.NewFromAny(map[string]interface{}{"expr": "label_values(up{b=\"$b\"}, a)"})},
+			"b": {query: simplejson.NewFromAny(map[string]interface{}{"expr": "label_values(up{a=\"${a}\"}, b)"})},
+		}
+		_, err := resolveTemplateVariables(circular, nil, nil)
+		require.ErrorContains(t, err, ErrPublicDashboardHasTemplateVariables.Error())
+	})
+}
+
+func TestSortTemplateVariables(t *testing.T) {
+	variables := map[string]templateVariable{
+		"a":   {query: simplejson.NewFromAny(map[string]interface{}{"expr": "label_values(up{b=\"$b\", c=\"[[c]]\"}, a)"})},
+		"b":   {query: simplejson.NewFromAny(map[string]interface{}{"expr": "label_values(up{c=\"${c:regex}\"}, b)"})},
+		"c":   {options: []string{"c"}},
+		"all": {query: simplejson.NewFromAny(map[string]interface{}{"expr": "label_values(up{a=\"$a\"}, all) $__interval"})},
+	}
+	order, err := sortTemplateVariables(variables)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a", "all"}, order)
+}
+
+func TestInterpolateTemplateVariables(t *testing.T) {
+	variables := map[string]variableValue{
+		"env":    {values: []string{"prod"}},
+		"host":   {values: []string{"a", "b.example"}, multi: true},
+		"region": {values: []string{".*"}, raw: true, multi: true},
+		"pod":    {values: []string{"pod.1"}, multi: true},
+	}
+
+	query := simplejson.NewFromAny(map[string]interface{}{
+		"refId":   "A",
+		"expr":    `up{env="$env", host=~"${host:regex}", region=~"[[region]]"}[$__interval]`,
+		"rawSql":  "SELECT * FROM hosts WHERE host IN (${host:sqlstring})",
+		"targets": []interface{}{map[string]interface{}{"target": "servers.${host}.$unknown"}},
+		"hide":    false,
+	})
+	interpolated := interpolateTemplateVariables(query, variables)
+
+	assert.Equal(t, `up{env="prod", host=~"(a|b\.example)", region=~".*"}[$__interval]`, interpolated.Get("expr").MustString())
+	assert.Equal(t, "SELECT * FROM hosts WHERE host IN ('a','b.example')", interpolated.Get("rawSql").MustString())
+	assert.Equal(t, "servers.{a,b.example}.$unknown", interpolated.Get("targets").GetIndex(0).Get("target").MustString())
+	assert.False(t, interpolated.Get("hide").MustBool(true))
+
+	// the query of the dashboard is not modified
+	assert.Equal(t, "servers.${host}.$unknown", query.Get("targets").GetIndex(0).Get("target").MustString())
+
+	t.Run("formats variables without a format like the data source", func(t *testing.T) {
+		for dsType, expected := range map[string]string{
+			"prometheus": `env=prod host=(a|b\.example) pod=pod\.1`,
+			"loki":       `env=prod host=(a|b\.example) pod=pod\.1`,
+			"mysql":      `env=prod host='a','b.example' pod='pod.1'`,
+			"postgres":   `env=prod host='a','b.example' pod='pod.1'`,
+			"graphite":   `env=prod host={a,b.example} pod=pod.1`,
+		} {
+			query := simplejson.NewFromAny(map[string]interface{}{
+				"datasource": map[string]interface{}{"type": dsType, "uid": "ds"},
+				"expr":       "env=$env host=$host pod=${pod}",
+			})
+			assert.Equal(t, expected, interpolateTemplateVariables(query, variables).Get("expr").MustString(), dsType)
+		}
+	})
+}
+
+func TestVariableValueField(t *testing.T) {
+	frame := data.NewFrame("", data.NewField("count", nil, []int64{1}), data.NewField("name", nil, []string{"a"}))
+	require.Equal(t, "name", variableValueField(frame).Name)
+
+	frame.Fields = append(frame.Fields, data.NewField("__value", nil, []int64{2}))
+	require.Equal(t, "__value", variableValueField(frame).Name)
+
+	require.Nil(t, variableValueField(data.NewFrame("", data.NewField("count", nil, []int64{1}))))
+}
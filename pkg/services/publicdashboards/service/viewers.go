@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/internal/tokens"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// viewerLinkLifetime is how long the magic link sent to an invited viewer can be used
+	viewerLinkLifetime = 15 * time.Minute
+	// viewerEventsLimit is the number of events of the usage log returned
+	viewerEventsLimit = 1000
+	// viewerLinkEmailLimit and viewerLinkAccessTokenLimit are the number of magic links which can be requested per
+	// hour for an email and for a public dashboard, whatever the rate limit of the public dashboard
+	viewerLinkEmailLimit       = 5
+	viewerLinkAccessTokenLimit = 60
+	// maxViewerLinkLimiters bounds the number of rate limiters of magic links kept in memory
+	maxViewerLinkLimiters = 10000
+)
+
+// Authorize checks that a public dashboard can be viewed with its access token: it must be enabled, not expired and
+// under its rate limit. Public dashboards shared by email also require the session token of an invited viewer, who
+// is returned.
+func (pd *PublicDashboardServiceImpl) Authorize(ctx context.Context, accessToken string, sessionToken string) (*PublicDashboardViewer, error) {
+	pubdash, err := pd.findEnabledByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pd.allowRequest(pubdash) {
+		return nil, ErrRateLimitExceeded.Errorf("Authorize: rate limit of %d requests per minute exceeded accessToken: %s", pubdash.RateLimit, accessToken)
+	}
+
+	if pubdash.Share != ShareTypeEmail {
+		return nil, nil
+	}
+
+	if sessionToken == "" {
+		return nil, ErrViewerSessionRequired.Errorf("Authorize: no viewer session accessToken: %s", accessToken)
+	}
+
+	viewer, err := pd.store.FindViewerByToken(ctx, pubdash.Uid, ViewerTokenSession, tokens.HashToken(sessionToken), time.Now())
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("Authorize: failed to find viewer session: %w", err)
+	}
+
+	if viewer == nil {
+		return nil, ErrViewerSessionRequired.Errorf("Authorize: viewer session not found or expired accessToken: %s", accessToken)
+	}
+
+	return viewer, nil
+}
+
+// RecordViewerView adds a view of the public dashboard by an invited viewer to the usage log
+func (pd *PublicDashboardServiceImpl) RecordViewerView(ctx context.Context, viewer *PublicDashboardViewer) error {
+	now := time.Now()
+	if err := pd.store.UpdateViewerLastViewedAt(ctx, viewer.Id, now); err != nil {
+		return ErrInternalServerError.Errorf("RecordViewerView: failed to update viewer: %w", err)
+	}
+
+	return pd.logViewerEvent(ctx, viewer, ViewerEventViewed, now)
+}
+
+// InviteViewer invites a viewer by email to a public dashboard and sends them a magic link. Inviting a viewer who
+// is already invited sends a new magic link.
+func (pd *PublicDashboardServiceImpl) InviteViewer(ctx context.Context, u *user.SignedInUser, dashboardUid string, uid string, email string) (*PublicDashboardViewer, error) {
+	pubdash, err := pd.findForDashboard(ctx, u.OrgID, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !util.IsEmail(email) {
+		return nil, ErrInvalidEmail.Errorf("InviteViewer: invalid email %q", email)
+	}
+
+	viewer, err := pd.store.FindViewer(ctx, pubdash.Uid, email)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("InviteViewer: failed to find viewer: %w", err)
+	}
+
+	if viewer == nil {
+		viewer = &PublicDashboardViewer{
+			Uid:                util.GenerateShortUID(),
+			PublicDashboardUid: pubdash.Uid,
+			OrgId:              pubdash.OrgId,
+			Email:              email,
+			CreatedBy:          u.UserID,
+			CreatedAt:          time.Now(),
+		}
+		if err := pd.store.CreateViewer(ctx, viewer); err != nil {
+			return nil, ErrInternalServerError.Errorf("InviteViewer: failed to create viewer: %w", err)
+		}
+		if err := pd.logViewerEvent(ctx, viewer, ViewerEventInvited, viewer.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := pd.sendViewerLink(ctx, pubdash, viewer); err != nil {
+		return nil, err
+	}
+
+	return viewer, nil
+}
+
+// FindViewers returns the viewers invited to a public dashboard
+func (pd *PublicDashboardServiceImpl) FindViewers(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]PublicDashboardViewer, error) {
+	pubdash, err := pd.findForDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	viewers, err := pd.store.FindViewers(ctx, pubdash.Uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindViewers: failed to find viewers: %w", err)
+	}
+
+	return viewers, nil
+}
+
+// FindViewerEvents returns the latest events of the usage log of the viewers invited to a public dashboard
+func (pd *PublicDashboardServiceImpl) FindViewerEvents(ctx context.Context, orgId int64, dashboardUid string, uid string) ([]PublicDashboardViewerEvent, error) {
+	pubdash, err := pd.findForDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := pd.store.FindViewerEvents(ctx, pubdash.Uid, viewerEventsLimit)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("FindViewerEvents: failed to find viewer events: %w", err)
+	}
+
+	return events, nil
+}
+
+// RevokeViewer removes a viewer from a public dashboard and ends their sessions
+func (pd *PublicDashboardServiceImpl) RevokeViewer(ctx context.Context, orgId int64, dashboardUid string, uid string, viewerUid string) error {
+	pubdash, err := pd.findForDashboard(ctx, orgId, dashboardUid, uid)
+	if err != nil {
+		return err
+	}
+
+	viewer, err := pd.store.DeleteViewer(ctx, pubdash.Uid, viewerUid)
+	if err != nil {
+		return ErrInternalServerError.Errorf("RevokeViewer: failed to delete viewer: %w", err)
+	}
+
+	if viewer == nil {
+		return ErrViewerNotFound.Errorf("RevokeViewer: viewer not found by uid: %s", viewerUid)
+	}
+
+	return pd.logViewerEvent(ctx, viewer, ViewerEventRevoked, time.Now())
+}
+
+// SendViewerLink sends a new magic link to an invited viewer of a public dashboard shared by email. Nothing is sent
+// to emails which aren't invited, without returning an error, so the emails of the viewers can't be guessed, nor to
+// viewers who already have an unexpired magic link, which they can still use.
+func (pd *PublicDashboardServiceImpl) SendViewerLink(ctx context.Context, accessToken string, email string) error {
+	pubdash, err := pd.findEnabledByAccessToken(ctx, accessToken)
+	if err != nil {
+		return err
+	}
+
+	if !pd.allowRequest(pubdash) {
+		return ErrRateLimitExceeded.Errorf("SendViewerLink: rate limit of %d requests per minute exceeded accessToken: %s", pubdash.RateLimit, accessToken)
+	}
+
+	if pubdash.Share != ShareTypeEmail {
+		return nil
+	}
+
+	// limited before looking up the viewer, so the limits don't reveal which emails are invited
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !pd.allowViewerLink(accessToken, email) {
+		return ErrRateLimitExceeded.Errorf("SendViewerLink: too many magic links requested accessToken: %s", accessToken)
+	}
+
+	viewer, err := pd.store.FindViewer(ctx, pubdash.Uid, email)
+	if err != nil {
+		return ErrInternalServerError.Errorf("SendViewerLink: failed to find viewer: %w", err)
+	}
+
+	if viewer == nil {
+		pd.log.Debug("Magic link requested for an email which isn't invited", "publicDashboardUid", pubdash.Uid)
+		return nil
+	}
+
+	hasLink, err := pd.store.ExistsViewerToken(ctx, viewer.Id, ViewerTokenMagicLink, time.Now())
+	if err != nil {
+		return ErrInternalServerError.Errorf("SendViewerLink: failed to find magic link: %w", err)
+	}
+
+	if hasLink {
+		pd.log.Debug("Magic link requested for a viewer with an unexpired magic link", "publicDashboardUid", pubdash.Uid)
+		return nil
+	}
+
+	return pd.sendViewerLink(ctx, pubdash, viewer)
+}
+
+// DeleteViewerEventsOlderThan Deletes the events of the usage log of the viewers of all public dashboards before a time
+func (pd *PublicDashboardServiceImpl) DeleteViewerEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	return pd.store.DeleteViewerEventsOlderThan(ctx, olderThan)
+}
+
+// CreateViewerSession exchanges the token of a magic link for the token of a session of the invited viewer. Magic
+// links can only be used once.
+func (pd *PublicDashboardServiceImpl) CreateViewerSession(ctx context.Context, accessToken string, linkToken string) (string, error) {
+	pubdash, err := pd.findEnabledByAccessToken(ctx, accessToken)
+	if err != nil {
+		return "", err
+	}
+
+	if pubdash.Share != ShareTypeEmail || linkToken == "" {
+		return "", ErrInvalidViewerLink.Errorf("CreateViewerSession: public dashboard isn't shared by email accessToken: %s", accessToken)
+	}
+
+	linkTokenHash := tokens.HashToken(linkToken)
+	viewer, err := pd.store.FindViewerByToken(ctx, pubdash.Uid, ViewerTokenMagicLink, linkTokenHash, time.Now())
+	if err != nil {
+		return "", ErrInternalServerError.Errorf("CreateViewerSession: failed to find magic link: %w", err)
+	}
+
+	if viewer == nil {
+		return "", ErrInvalidViewerLink.Errorf("CreateViewerSession: magic link not found or expired accessToken: %s", accessToken)
+	}
+
+	// only one of concurrent uses of the magic link deletes the token
+	deleted, err := pd.store.DeleteViewerToken(ctx, linkTokenHash)
+	if err != nil {
+		return "", ErrInternalServerError.Errorf("CreateViewerSession: failed to delete magic link: %w", err)
+	}
+
+	if deleted == 0 {
+		return "", ErrInvalidViewerLink.Errorf("CreateViewerSession: magic link already used accessToken: %s", accessToken)
+	}
+
+	sessionToken, err := pd.createViewerToken(ctx, viewer, ViewerTokenSession, ViewerSessionLifetime)
+	if err != nil {
+		return "", err
+	}
+
+	if err := pd.logViewerEvent(ctx, viewer, ViewerEventSignedIn, time.Now()); err != nil {
+		return "", err
+	}
+
+	return sessionToken, nil
+}
+
+// findEnabledByAccessToken returns the public dashboard of an access token when it's enabled and not expired
+func (pd *PublicDashboardServiceImpl) findEnabledByAccessToken(ctx context.Context, accessToken string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.FindByAccessToken(ctx, accessToken)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("findEnabledByAccessToken: failed to find a public dashboard: %w", err)
+	}
+
+	if pubdash == nil || !pubdash.IsEnabled || pubdash.IsExpired(time.Now()) {
+		return nil, ErrPublicDashboardNotFound.Errorf("findEnabledByAccessToken: Public dashboard not found, disabled or expired accessToken: %s", accessToken)
+	}
+
+	return pubdash, nil
+}
+
+// findForDashboard returns a public dashboard by uid when it belongs to the dashboard of the org
+func (pd *PublicDashboardServiceImpl) findForDashboard(ctx context.Context, orgId int64, dashboardUid string, uid string) (*PublicDashboard, error) {
+	pubdash, err := pd.store.Find(ctx, uid)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("findForDashboard: failed to find public dashboard by uid: %s: %w", uid, err)
+	}
+
+	if pubdash == nil || pubdash.OrgId != orgId || pubdash.DashboardUid != dashboardUid {
+		return nil, ErrPublicDashboardNotFound.Errorf("findForDashboard: public dashboard not found by orgId: %d, dashboardUid: %s and uid: %s", orgId, dashboardUid, uid)
+	}
+
+	return pubdash, nil
+}
+
+// allowRequest returns false when a request to a public dashboard exceeds its rate limit. The burst is the rate
+// limit, so dashboards with many panels can be loaded at once.
+func (pd *PublicDashboardServiceImpl) allowRequest(pubdash *PublicDashboard) bool {
+	if pubdash.RateLimit <= 0 {
+		return true
+	}
+
+	limit := rate.Limit(float64(pubdash.RateLimit) / 60)
+	burst := int(pubdash.RateLimit)
+
+	pd.limitersMu.Lock()
+	defer pd.limitersMu.Unlock()
+
+	if pd.limiters == nil {
+		pd.limiters = make(map[string]*rate.Limiter)
+	}
+
+	limiter, ok := pd.limiters[pubdash.AccessToken]
+	if !ok || limiter.Limit() != limit || limiter.Burst() != burst {
+		limiter = rate.NewLimiter(limit, burst)
+		pd.limiters[pubdash.AccessToken] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// allowViewerLink returns false when magic links were requested too often for an email or for a public dashboard.
+// Each magic link is sent by email, so these limits apply to public dashboards without a rate limit too.
+func (pd *PublicDashboardServiceImpl) allowViewerLink(accessToken string, email string) bool {
+	now := time.Now()
+
+	pd.limitersMu.Lock()
+	defer pd.limitersMu.Unlock()
+
+	if pd.viewerLinkLimiters == nil {
+		pd.viewerLinkLimiters = make(map[string]*rate.Limiter)
+	}
+
+	if len(pd.viewerLinkLimiters) >= maxViewerLinkLimiters {
+		// full limiters behave like new ones, so they can be dropped
+		for key, limiter := range pd.viewerLinkLimiters {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(pd.viewerLinkLimiters, key)
+			}
+		}
+	}
+
+	accessTokenLimiter := pd.viewerLinkLimiter("accessToken:"+accessToken, viewerLinkAccessTokenLimit)
+	emailLimiter := pd.viewerLinkLimiter("email:"+email, viewerLinkEmailLimit)
+	if accessTokenLimiter == nil || emailLimiter == nil {
+		return false
+	}
+
+	return accessTokenLimiter.AllowN(now, 1) && emailLimiter.AllowN(now, 1)
+}
+
+// viewerLinkLimiter returns the rate limiter of magic links of a key, or nil when there are too many rate limiters.
+// limitersMu must be held.
+func (pd *PublicDashboardServiceImpl) viewerLinkLimiter(key string, perHour int) *rate.Limiter {
+	limiter, ok := pd.viewerLinkLimiters[key]
+	if !ok {
+		if len(pd.viewerLinkLimiters) >= maxViewerLinkLimiters {
+			return nil
+		}
+		limiter = rate.NewLimiter(rate.Every(time.Hour/time.Duration(perHour)), perHour)
+		pd.viewerLinkLimiters[key] = limiter
+	}
+
+	return limiter
+}
+
+// sendViewerLink creates a magic link for a viewer and sends it by email
+func (pd *PublicDashboardServiceImpl) sendViewerLink(ctx context.Context, pubdash *PublicDashboard, viewer *PublicDashboardViewer) error {
+	dashboard, err := pd.FindDashboard(ctx, pubdash.OrgId, pubdash.DashboardUid)
+	if err != nil {
+		return err
+	}
+
+	linkToken, err := pd.createViewerToken(ctx, viewer, ViewerTokenMagicLink, viewerLinkLifetime)
+	if err != nil {
+		return err
+	}
+
+	emailCmd := models.SendEmailCommand{
+		To:       []string{viewer.Email},
+		Template: "public_dashboard_link",
+		Data: map[string]interface{}{
+			"Title":     dashboard.Title,
+			"LinkUrl":   setting.ToAbsUrl(fmt.Sprintf("api/public/dashboards/%s/session?token=%s", pubdash.AccessToken, linkToken)),
+			"ExpiresIn": fmt.Sprintf("%d minutes", int(viewerLinkLifetime.Minutes())),
+		},
+	}
+
+	if err := pd.emailSender.SendEmailCommandHandler(ctx, &emailCmd); err != nil {
+		return ErrInternalServerError.Errorf("sendViewerLink: failed to send magic link: %w", err)
+	}
+
+	return pd.logViewerEvent(ctx, viewer, ViewerEventLinkSent, time.Now())
+}
+
+// createViewerToken creates a token of a kind for a viewer, and returns the token, whose hash is stored
+func (pd *PublicDashboardServiceImpl) createViewerToken(ctx context.Context, viewer *PublicDashboardViewer, kind string, lifetime time.Duration) (string, error) {
+	token, err := tokens.GenerateAccessToken()
+	if err != nil {
+		return "", ErrInternalServerError.Errorf("createViewerToken: failed to generate token: %w", err)
+	}
+
+	now := time.Now()
+	err = pd.store.CreateViewerToken(ctx, &PublicDashboardViewerToken{
+		ViewerId:  viewer.Id,
+		Kind:      kind,
+		TokenHash: tokens.HashToken(token),
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(lifetime).Unix(),
+	})
+	if err != nil {
+		return "", ErrInternalServerError.Errorf("createViewerToken: failed to create token: %w", err)
+	}
+
+	return token, nil
+}
+
+func (pd *PublicDashboardServiceImpl) logViewerEvent(ctx context.Context, viewer *PublicDashboardViewer, event string, t time.Time) error {
+	err := pd.store.CreateViewerEvent(ctx, &PublicDashboardViewerEvent{
+		PublicDashboardUid: viewer.PublicDashboardUid,
+		OrgId:              viewer.OrgId,
+		Email:              viewer.Email,
+		Event:              event,
+		CreatedAt:          t,
+	})
+	if err != nil {
+		return ErrInternalServerError.Errorf("logViewerEvent: failed to log %s event: %w", event, err)
+	}
+
+	return nil
+}
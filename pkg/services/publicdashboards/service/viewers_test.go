@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/database"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestPublicDashboardViewers(t *testing.T) {
+	ctx := context.Background()
+	admin := &user.SignedInUser{UserID: 1, OrgID: 1, Login: "admin"}
+
+	setup := func(t *testing.T, share string, expiresAt *time.Time, rateLimit int64) (*PublicDashboardServiceImpl, *notifications.NotificationServiceMock, *PublicDashboard) {
+		sqlStore := db.InitTestDB(t)
+		dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, sqlStore.Cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, sqlStore.Cfg), quotatest.New(false, nil))
+		require.NoError(t, err)
+		dashboard := insertTestDashboard(t, dashboardStore, "testDashie", 1, 0, true, []map[string]interface{}{}, nil)
+
+		emailSender := &notifications.NotificationServiceMock{}
+		service := &PublicDashboardServiceImpl{
+			log:         log.New("test.logger"),
+			store:       database.ProvideStore(sqlStore),
+			emailSender: emailSender,
+		}
+
+		pubdash, err := service.Create(ctx, admin, &SavePublicDashboardDTO{
+			DashboardUid: dashboard.Uid,
+			OrgId:        dashboard.OrgId,
+			UserId:       admin.UserID,
+			PublicDashboard: &PublicDashboard{
+				IsEnabled: true,
+				Share:     share,
+				ExpiresAt: expiresAt,
+				RateLimit: rateLimit,
+			},
+		})
+		require.NoError(t, err)
+
+		return service, emailSender, pubdash
+	}
+
+	// linkToken returns the token of the magic link of the last email
+	linkToken := func(t *testing.T, emailSender *notifications.NotificationServiceMock) string {
+		link, err := url.Parse(emailSender.Email.Data["LinkUrl"].(string))
+		require.NoError(t, err)
+		return link.Query().Get("token")
+	}
+
+	t.Run("public dashboards shared publicly don't need a session", func(t *testing.T) {
+		service, _, pubdash := setup(t, "", nil, 0)
+		require.Equal(t, ShareTypePublic, pubdash.Share)
+
+		viewer, err := service.Authorize(ctx, pubdash.AccessToken, "")
+		require.NoError(t, err)
+		require.Nil(t, viewer)
+	})
+
+	t.Run("expired public dashboards are not found", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+		service, _, pubdash := setup(t, ShareTypePublic, &expiresAt, 0)
+
+		_, err := service.Authorize(ctx, pubdash.AccessToken, "")
+		require.ErrorContains(t, err, ErrPublicDashboardNotFound.Error())
+
+		_, _, err = service.FindPublicDashboardAndDashboardByAccessToken(ctx, pubdash.AccessToken)
+		require.ErrorContains(t, err, ErrPublicDashboardNotFound.Error())
+	})
+
+	t.Run("requests over the rate limit are rejected", func(t *testing.T) {
+		service, _, pubdash := setup(t, ShareTypePublic, nil, 2)
+
+		for i := 0; i < 2; i++ {
+			_, err := service.Authorize(ctx, pubdash.AccessToken, "")
+			require.NoError(t, err)
+		}
+		_, err := service.Authorize(ctx, pubdash.AccessToken, "")
+		require.ErrorContains(t, err, ErrRateLimitExceeded.Error())
+	})
+
+	t.Run("invited viewers sign in with a magic link which can only be used once", func(t *testing.T) {
+		service, emailSender, pubdash := setup(t, ShareTypeEmail, nil, 0)
+
+		_, err := service.Authorize(ctx, pubdash.AccessToken, "")
+		require.ErrorContains(t, err, ErrViewerSessionRequired.Error())
+
+		viewer, err := service.InviteViewer(ctx, admin, pubdash.DashboardUid, pubdash.Uid, " Viewer@Example.com ")
+		require.NoError(t, err)
+		assert.Equal(t, "viewer@example.com", viewer.Email)
+		assert.Equal(t, []string{"viewer@example.com"}, emailSender.Email.To)
+		assert.Equal(t, "public_dashboard_link", emailSender.Email.Template)
+
+		token := linkToken(t, emailSender)
+		sessionToken, err := service.CreateViewerSession(ctx, pubdash.AccessToken, token)
+		require.NoError(t, err)
+
+		_, err = service.CreateViewerSession(ctx, pubdash.AccessToken, token)
+		require.ErrorContains(t, err, ErrInvalidViewerLink.Error())
+
+		authorized, err := service.Authorize(ctx, pubdash.AccessToken, sessionToken)
+		require.NoError(t, err)
+		require.Equal(t, viewer.Uid, authorized.Uid)
+		require.NoError(t, service.RecordViewerView(ctx, authorized))
+
+		viewers, err := service.FindViewers(ctx, admin.OrgID, pubdash.DashboardUid, pubdash.Uid)
+		require.NoError(t, err)
+		require.Len(t, viewers, 1)
+		require.NotNil(t, viewers[0].LastViewedAt)
+
+		require.NoError(t, service.RevokeViewer(ctx, admin.OrgID, pubdash.DashboardUid, pubdash.Uid, viewer.Uid))
+		_, err = service.Authorize(ctx, pubdash.AccessToken, sessionToken)
+		require.ErrorContains(t, err, ErrViewerSessionRequired.Error())
+
+		events, err := service.FindViewerEvents(ctx, admin.OrgID, pubdash.DashboardUid, pubdash.Uid)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range events {
+			names = append(names, e.Event)
+		}
+		assert.ElementsMatch(t, []string{ViewerEventInvited, ViewerEventLinkSent, ViewerEventSignedIn, ViewerEventViewed, ViewerEventRevoked}, names)
+	})
+
+	t.Run("links are only sent to invited viewers", func(t *testing.T) {
+		service, emailSender, pubdash := setup(t, ShareTypeEmail, nil, 0)
+		_, err := service.InviteViewer(ctx, admin, pubdash.DashboardUid, pubdash.Uid, "viewer@example.com")
+		require.NoError(t, err)
+		_, err = service.CreateViewerSession(ctx, pubdash.AccessToken, linkToken(t, emailSender))
+		require.NoError(t, err)
+		emailSender.Email = models.SendEmailCommand{}
+
+		require.NoError(t, service.SendViewerLink(ctx, pubdash.AccessToken, "other@example.com"))
+		require.Empty(t, emailSender.Email.To)
+
+		require.NoError(t, service.SendViewerLink(ctx, pubdash.AccessToken, "VIEWER@example.com"))
+		require.Equal(t, []string{"viewer@example.com"}, emailSender.Email.To)
+		require.NotEmpty(t, linkToken(t, emailSender))
+	})
+
+	t.Run("no new link is sent while the last link is unexpired", func(t *testing.T) {
+		service, emailSender, pubdash := setup(t, ShareTypeEmail, nil, 0)
+		_, err := service.InviteViewer(ctx, admin, pubdash.DashboardUid, pubdash.Uid, "viewer@example.com")
+		require.NoError(t, err)
+		token := linkToken(t, emailSender)
+		emailSender.Email = models.SendEmailCommand{}
+
+		require.NoError(t, service.SendViewerLink(ctx, pubdash.AccessToken, "viewer@example.com"))
+		require.Empty(t, emailSender.Email.To)
+
+		_, err = service.CreateViewerSession(ctx, pubdash.AccessToken, token)
+		require.NoError(t, err)
+	})
+
+	t.Run("links requests are limited per email and per public dashboard", func(t *testing.T) {
+		service, _, pubdash := setup(t, ShareTypeEmail, nil, 0)
+
+		for i := 0; i < viewerLinkEmailLimit; i++ {
+			require.NoError(t, service.SendViewerLink(ctx, pubdash.AccessToken, "other@example.com"))
+		}
+		err := service.SendViewerLink(ctx, pubdash.AccessToken, "OTHER@example.com")
+		require.ErrorContains(t, err, ErrRateLimitExceeded.Error())
+
+		// the rejected request counts for the public dashboard
+		for i := viewerLinkEmailLimit + 1; i < viewerLinkAccessTokenLimit; i++ {
+			require.NoError(t, service.SendViewerLink(ctx, pubdash.AccessToken, fmt.Sprintf("other-%d@example.com", i)))
+		}
+		err = service.SendViewerLink(ctx, pubdash.AccessToken, "another@example.com")
+		require.ErrorContains(t, err, ErrRateLimitExceeded.Error())
+	})
+
+	t.Run("updates keep the expiration and rate limit which are not set", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		service, _, pubdash := setup(t, ShareTypeEmail, &expiresAt, 10)
+
+		updated, err := service.Update(ctx, admin, &SavePublicDashboardDTO{
+			DashboardUid:    pubdash.DashboardUid,
+			UserId:          admin.UserID,
+			PublicDashboard: &PublicDashboard{Uid: pubdash.Uid, IsEnabled: false},
+		})
+		require.NoError(t, err)
+		require.False(t, updated.IsEnabled)
+		require.Equal(t, ShareTypeEmail, updated.Share)
+		require.Equal(t, int64(10), updated.RateLimit)
+		require.NotNil(t, updated.ExpiresAt)
+		require.True(t, expiresAt.Equal(*updated.ExpiresAt))
+
+		updated, err = service.Update(ctx, admin, &SavePublicDashboardDTO{
+			DashboardUid:    pubdash.DashboardUid,
+			UserId:          admin.UserID,
+			PublicDashboard: &PublicDashboard{Uid: pubdash.Uid, IsEnabled: true},
+			ExpiresAtSet:    true,
+			RateLimitSet:    true,
+		})
+		require.NoError(t, err)
+		require.Nil(t, updated.ExpiresAt)
+		require.Equal(t, int64(0), updated.RateLimit)
+	})
+
+	t.Run("old events are deleted", func(t *testing.T) {
+		service, _, pubdash := setup(t, ShareTypeEmail, nil, 0)
+		_, err := service.InviteViewer(ctx, admin, pubdash.DashboardUid, pubdash.Uid, "viewer@example.com")
+		require.NoError(t, err)
+
+		deleted, err := service.DeleteViewerEventsOlderThan(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(0), deleted)
+
+		deleted, err = service.DeleteViewerEventsOlderThan(ctx, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		events, err := service.FindViewerEvents(ctx, admin.OrgID, pubdash.DashboardUid, pubdash.Uid)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+
+	t.Run("viewers can't be invited to public dashboards of other dashboards", func(t *testing.T) {
+		service, _, pubdash := setup(t, ShareTypeEmail, nil, 0)
+
+		_, err := service.InviteViewer(ctx, admin, "other", pubdash.Uid, "viewer@example.com")
+		require.ErrorContains(t, err, ErrPublicDashboardNotFound.Error())
+
+		_, err = service.InviteViewer(ctx, admin, pubdash.DashboardUid, pubdash.Uid, "not an email")
+		require.ErrorContains(t, err, ErrInvalidEmail.Error())
+	})
+}
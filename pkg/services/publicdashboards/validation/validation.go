@@ -1,23 +1,50 @@
 package validation
 
 import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/models"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
 	"github.com/grafana/grafana/pkg/tsdb/legacydata"
 )
 
+// SupportedTemplateVariableTypes are the types of template variables public dashboards support. The options of these
+// variables are saved in the dashboard, so viewers can only select values the dashboard allows.
+var SupportedTemplateVariableTypes = map[string]bool{
+	"constant": true,
+	"custom":   true,
+	"interval": true,
+	"query":    true,
+}
+
 func ValidatePublicDashboard(dto *SavePublicDashboardDTO, dashboard *models.Dashboard) error {
-	if hasTemplateVariables(dashboard) {
-		return ErrPublicDashboardHasTemplateVariables.Errorf("ValidateSavePublicDashboard: public dashboard has template variables")
+	if pd := dto.PublicDashboard; pd != nil {
+		if pd.Share != "" && pd.Share != ShareTypePublic && pd.Share != ShareTypeEmail {
+			return ErrInvalidShareType.Errorf("ValidateSavePublicDashboard: invalid share type %q", pd.Share)
+		}
+
+		if pd.RateLimit < 0 {
+			return ErrInvalidRateLimit.Errorf("ValidateSavePublicDashboard: rate limit %d is negative", pd.RateLimit)
+		}
 	}
 
-	return nil
-}
+	for _, obj := range dashboard.Data.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(obj)
+		name := variable.Get("name").MustString()
+		varType := variable.Get("type").MustString()
+
+		if !SupportedTemplateVariableTypes[varType] {
+			return ErrPublicDashboardHasTemplateVariables.Errorf("ValidateSavePublicDashboard: template variable %s has unsupported type %q", name, varType)
+		}
 
-func hasTemplateVariables(dashboard *models.Dashboard) bool {
-	templateVariables := dashboard.Data.Get("templating").Get("list").MustArray()
+		// the options of query variables are either saved, or queried with a data source query
+		if varType == "query" && len(variable.Get("options").MustArray()) == 0 {
+			if _, err := variable.Get("query").Map(); err != nil {
+				return ErrPublicDashboardHasTemplateVariables.Errorf("ValidateSavePublicDashboard: query variable %s has no saved options or data source query", name)
+			}
+		}
+	}
 
-	return len(templateVariables) > 0
+	return nil
 }
 
 func ValidateQueryPublicDashboardRequest(req PublicDashboardQueryDTO, pd *PublicDashboard) error {
@@ -10,7 +10,7 @@ import (
 )
 
 func TestValidatePublicDashboard(t *testing.T) {
-	t.Run("Returns validation error when dashboard has template variables", func(t *testing.T) {
+	t.Run("Returns validation error when dashboard has unsupported template variables", func(t *testing.T) {
 		templateVars := []byte(`{
 			"templating": {
 				 "list": [
@@ -28,6 +28,59 @@ func TestValidatePublicDashboard(t *testing.T) {
 		require.ErrorContains(t, err, ErrPublicDashboardHasTemplateVariables.Error())
 	})
 
+	t.Run("Returns validation error when query variable has no saved options or data source query", func(t *testing.T) {
+		templateVars := []byte(`{
+			"templating": {
+				 "list": [
+				   {
+					  "name": "host",
+					  "type": "query",
+					  "refresh": 1
+				   }
+				]
+			}
+		}`)
+		dashboardData, _ := simplejson.NewJson(templateVars)
+		dashboard := models.NewDashboardFromJson(dashboardData)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: nil}
+
+		err := ValidatePublicDashboard(dto, dashboard)
+		require.ErrorContains(t, err, ErrPublicDashboardHasTemplateVariables.Error())
+	})
+
+	t.Run("Returns no validation error when dashboard has supported template variables", func(t *testing.T) {
+		templateVars := []byte(`{
+			"templating": {
+				 "list": [
+				   {"name": "env", "type": "custom", "query": "prod,dev"},
+				   {"name": "job", "type": "constant", "query": "api"},
+				   {"name": "interval", "type": "interval", "query": "1m,5m"},
+				   {"name": "host", "type": "query", "refresh": 0, "options": [{"text": "a", "value": "a"}]},
+				   {"name": "pod", "type": "query", "refresh": 1, "query": {"rawSql": "SELECT pod FROM pods"}}
+				]
+			}
+		}`)
+		dashboardData, _ := simplejson.NewJson(templateVars)
+		dashboard := models.NewDashboardFromJson(dashboardData)
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: nil}
+
+		err := ValidatePublicDashboard(dto, dashboard)
+		require.NoError(t, err)
+	})
+
+	t.Run("Returns validation error when share type or rate limit are invalid", func(t *testing.T) {
+		dashboard := models.NewDashboardFromJson(simplejson.New())
+
+		dto := &SavePublicDashboardDTO{DashboardUid: "abc123", OrgId: 1, UserId: 1, PublicDashboard: &PublicDashboard{Share: "team"}}
+		require.ErrorContains(t, ValidatePublicDashboard(dto, dashboard), ErrInvalidShareType.Error())
+
+		dto.PublicDashboard = &PublicDashboard{Share: ShareTypeEmail, RateLimit: -1}
+		require.ErrorContains(t, ValidatePublicDashboard(dto, dashboard), ErrInvalidRateLimit.Error())
+
+		dto.PublicDashboard = &PublicDashboard{Share: ShareTypeEmail, RateLimit: 60}
+		require.NoError(t, ValidatePublicDashboard(dto, dashboard))
+	})
+
 	t.Run("Returns no validation error when dashboard has no template variables", func(t *testing.T) {
 		templateVars := []byte(`{
 			"templating": {
@@ -0,0 +1,263 @@
+package query
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	// HeaderCache reports whether the response of a query request was served from the query cache.
+	HeaderCache = "X-Cache"
+	// HeaderCacheSkip makes query requests bypass reading from the query cache.
+	HeaderCacheSkip = "X-Cache-Skip"
+)
+
+const (
+	cacheStatusHit    = "HIT"
+	cacheStatusMiss   = "MISS"
+	cacheStatusBypass = "BYPASS"
+)
+
+// volatileQueryKeys are query properties which change with every request without changing the results.
+var volatileQueryKeys = []string{"requestId", "key"}
+
+var queryCacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "query_cache",
+		Name:      "requests_total",
+		Help:      "A counter for data source query requests of data sources with query caching enabled, by cache status",
+	},
+	[]string{"datasource_type", "status"},
+)
+
+var queryCacheErrors = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "query_cache",
+		Name:      "errors_total",
+		Help:      "A counter for failures to read or write cached query responses",
+	},
+	[]string{"operation"},
+)
+
+// queryCachingTTL returns how long responses of the data source are cached, or false if the data source didn't
+// enable query caching.
+func (s *Service) queryCachingTTL(ds *datasources.DataSource) (time.Duration, bool) {
+	if s.cacheStorage == nil || !s.cfg.QueryCache.Enabled || ds.JsonData == nil {
+		return 0, false
+	}
+	if !ds.JsonData.Get("queryCachingEnabled").MustBool() {
+		return 0, false
+	}
+	ttl := s.cfg.QueryCache.TTL
+	if raw := ds.JsonData.Get("queryCachingTTL").MustString(); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+	return ttl, ttl > 0
+}
+
+// queryDataCached queries the data source of the request, serving responses from the query cache when the data source
+// enabled query caching. Only responses without errors are cached. skipCache, or the X-Cache-Skip request header,
+// bypasses reading from the cache, but the response is still cached.
+func (s *Service) queryDataCached(ctx context.Context, user *user.SignedInUser, ds *datasources.DataSource, skipCache bool, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	ttl, ok := s.queryCachingTTL(ds)
+	if !ok {
+		return s.pluginClient.QueryData(ctx, req)
+	}
+
+	key, err := queryCacheKey(user, ds, req.Queries)
+	if err != nil {
+		s.log.Warn("Failed to build query cache key", "datasource", ds.Uid, "error", err)
+		queryCacheErrors.WithLabelValues("key").Inc()
+		return s.pluginClient.QueryData(ctx, req)
+	}
+
+	skipCache = skipCache || requestSkipsCache(ctx)
+	if !skipCache {
+		if resp, ok := s.getCachedResponse(ctx, key); ok {
+			s.setCacheStatus(ctx, cacheStatusHit)
+			queryCacheRequests.WithLabelValues(ds.Type, cacheStatusHit).Inc()
+			return resp, nil
+		}
+	}
+
+	status := cacheStatusMiss
+	if skipCache {
+		status = cacheStatusBypass
+	}
+	s.setCacheStatus(ctx, status)
+	queryCacheRequests.WithLabelValues(ds.Type, status).Inc()
+
+	resp, err := s.pluginClient.QueryData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.setCachedResponse(ctx, key, ttl, resp)
+	return resp, nil
+}
+
+func (s *Service) getCachedResponse(ctx context.Context, key string) (*backend.QueryDataResponse, bool) {
+	value, err := s.cacheStorage.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			s.log.Warn("Failed to read cached query response", "error", err)
+			queryCacheErrors.WithLabelValues("get").Inc()
+		}
+		return nil, false
+	}
+	raw, ok := value.([]byte)
+	if !ok {
+		return nil, false
+	}
+	resp := &backend.QueryDataResponse{}
+	if err := resp.UnmarshalJSON(raw); err != nil {
+		s.log.Warn("Failed to decode cached query response", "error", err)
+		queryCacheErrors.WithLabelValues("decode").Inc()
+		return nil, false
+	}
+	return resp, true
+}
+
+func (s *Service) setCachedResponse(ctx context.Context, key string, ttl time.Duration, resp *backend.QueryDataResponse) {
+	for _, res := range resp.Responses {
+		if res.Error != nil {
+			return
+		}
+	}
+	raw, err := resp.MarshalJSON()
+	if err != nil {
+		s.log.Warn("Failed to encode query response for the query cache", "error", err)
+		queryCacheErrors.WithLabelValues("encode").Inc()
+		return
+	}
+	if max := s.cfg.QueryCache.MaxValueSize; max > 0 && len(raw) > max {
+		s.log.Debug("Query response is too large to be cached", "size", len(raw), "max", max)
+		return
+	}
+	if err := s.cacheStorage.Set(ctx, key, raw, ttl); err != nil {
+		s.log.Warn("Failed to cache query response", "error", err)
+		queryCacheErrors.WithLabelValues("set").Inc()
+	}
+}
+
+// setCacheStatus adds the cache status to the response headers of the HTTP request of ctx, if any. Queries of mixed
+// data source requests run concurrently, so writing the headers is synchronized.
+func (s *Service) setCacheStatus(ctx context.Context, status string) {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Resp == nil {
+		return
+	}
+	s.headerMu.Lock()
+	defer s.headerMu.Unlock()
+	reqCtx.Resp.Header().Add(HeaderCache, status)
+}
+
+func requestSkipsCache(ctx context.Context) bool {
+	reqCtx := contexthandler.FromContext(ctx)
+	return reqCtx != nil && reqCtx.Req != nil && reqCtx.Req.Header.Get(HeaderCacheSkip) != ""
+}
+
+type queryCacheKeyQuery struct {
+	RefID string          `json:"refId"`
+	From  int64           `json:"from"`
+	To    int64           `json:"to"`
+	Model json.RawMessage `json:"model"`
+}
+
+// queryCacheKeyUser identifies the user a response is cached for.
+type queryCacheKeyUser struct {
+	ID           int64   `json:"id"`
+	Login        string  `json:"login"`
+	APIKeyID     int64   `json:"apiKeyId,omitempty"`
+	OrgRole      string  `json:"orgRole"`
+	GrafanaAdmin bool    `json:"grafanaAdmin,omitempty"`
+	Teams        []int64 `json:"teams,omitempty"`
+}
+
+// queryCacheKey returns the cache key of the queries of a data source. Keys are built from the normalized query
+// models and the time ranges rounded to the query intervals, so that the same panel loaded at about the same time
+// shares the cached response. The user is always part of the key, as users may be allowed to see different data,
+// and data sources may receive the identity of the user.
+func queryCacheKey(user *user.SignedInUser, ds *datasources.DataSource, queries []backend.DataQuery) (string, error) {
+	key := struct {
+		OrgID      int64                `json:"orgId"`
+		Datasource string               `json:"datasource"`
+		Updated    int64                `json:"updated"`
+		User       *queryCacheKeyUser   `json:"user,omitempty"`
+		Queries    []queryCacheKeyQuery `json:"queries"`
+	}{
+		OrgID:      ds.OrgId,
+		Datasource: ds.Uid,
+		Updated:    ds.Updated.UnixNano(),
+		Queries:    make([]queryCacheKeyQuery, 0, len(queries)),
+	}
+	if user != nil {
+		teams := append([]int64(nil), user.Teams...)
+		sort.Slice(teams, func(i, j int) bool { return teams[i] < teams[j] })
+		key.User = &queryCacheKeyUser{
+			ID:           user.UserID,
+			Login:        user.Login,
+			APIKeyID:     user.ApiKeyID,
+			OrgRole:      string(user.OrgRole),
+			GrafanaAdmin: user.IsGrafanaAdmin,
+			Teams:        teams,
+		}
+	}
+
+	for _, q := range queries {
+		model, err := normalizeQueryModel(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		key.Queries = append(key.Queries, queryCacheKeyQuery{
+			RefID: q.RefID,
+			From:  roundToInterval(q.TimeRange.From, q.Interval),
+			To:    roundToInterval(q.TimeRange.To, q.Interval),
+			Model: model,
+		})
+	}
+
+	raw, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return "query-cache-" + hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeQueryModel removes the volatile properties of a query model. Maps are marshaled with sorted keys, so the
+// result doesn't depend on the order of the properties either.
+func normalizeQueryModel(raw json.RawMessage) (json.RawMessage, error) {
+	model := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &model); err != nil {
+		return nil, err
+	}
+	for _, k := range volatileQueryKeys {
+		delete(model, k)
+	}
+	return json.Marshal(model)
+}
+
+func roundToInterval(t time.Time, interval time.Duration) int64 {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return t.Truncate(interval).UnixMilli()
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestQueryDataCache(t *testing.T) {
+	setupCache := func(t *testing.T, jsonData map[string]interface{}) *testContext {
+		t.Helper()
+		tc := setup(t)
+		tc.queryService.cacheStorage = remotecache.NewFakeStore(t)
+		tc.queryService.cfg.QueryCache = setting.QueryCacheSettings{Enabled: true, TTL: time.Minute}
+		tc.dataSourceCache.ds = &datasources.DataSource{Id: 1, Uid: "ds1", Type: "prometheus", JsonData: simplejson.NewFromAny(jsonData)}
+		tc.pluginContext.responses = backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("a", data.NewField("Value", nil, []float64{1}))}},
+		}
+		return tc
+	}
+	query := func(t *testing.T, tc *testContext, skipCache bool, rawQuery string) *backend.QueryDataResponse {
+		t.Helper()
+		tc.pluginContext.req = nil
+		res, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, skipCache, metricRequestWithQueries(t, rawQuery))
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("identical queries are served from the cache", func(t *testing.T) {
+		tc := setupCache(t, map[string]interface{}{"queryCachingEnabled": true})
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000, "expr": "up", "requestId": "1"}`)
+		require.NotNil(t, tc.pluginContext.req)
+
+		res := query(t, tc, false, `{"requestId": "2", "expr": "up", "datasourceId": 1, "intervalMs": 60000, "refId": "A"}`)
+		require.Nil(t, tc.pluginContext.req)
+		require.Len(t, res.Responses["A"].Frames, 1)
+		require.Equal(t, 1.0, res.Responses["A"].Frames[0].Fields[0].At(0))
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000, "expr": "down"}`)
+		require.NotNil(t, tc.pluginContext.req)
+	})
+
+	t.Run("skipping the cache queries the data source", func(t *testing.T) {
+		tc := setupCache(t, map[string]interface{}{"queryCachingEnabled": true})
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		query(t, tc, true, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		require.NotNil(t, tc.pluginContext.req)
+	})
+
+	t.Run("responses are not cached unless the data source enabled caching", func(t *testing.T) {
+		tc := setupCache(t, map[string]interface{}{})
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		require.NotNil(t, tc.pluginContext.req)
+	})
+
+	t.Run("users with different permissions get separate cache entries", func(t *testing.T) {
+		tc := setupCache(t, map[string]interface{}{"queryCachingEnabled": true})
+		tc.signedInUser = &user.SignedInUser{UserID: 1, Login: "admin", OrgID: 1, OrgRole: org.RoleAdmin, Teams: []int64{1}}
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		require.Nil(t, tc.pluginContext.req)
+
+		tc.signedInUser = &user.SignedInUser{UserID: 2, Login: "viewer", OrgID: 1, OrgRole: org.RoleViewer}
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		require.NotNil(t, tc.pluginContext.req)
+	})
+
+	t.Run("responses with errors are not cached", func(t *testing.T) {
+		tc := setupCache(t, map[string]interface{}{"queryCachingEnabled": true})
+		tc.pluginContext.responses = backend.Responses{"A": {Error: context.DeadlineExceeded}}
+
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		query(t, tc, false, `{"refId": "A", "datasourceId": 1, "intervalMs": 60000}`)
+		require.NotNil(t, tc.pluginContext.req)
+	})
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	ds := &datasources.DataSource{OrgId: 1, Uid: "ds1", JsonData: simplejson.New()}
+	viewer := &user.SignedInUser{UserID: 1, Login: "viewer", OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{2, 1}}
+	from := time.Date(2022, 1, 1, 10, 0, 12, 0, time.UTC)
+	dataQuery := func(from time.Time, model string) backend.DataQuery {
+		return backend.DataQuery{
+			RefID:     "A",
+			Interval:  time.Minute,
+			TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+			JSON:      json.RawMessage(model),
+		}
+	}
+	key := func(t *testing.T, user *user.SignedInUser, q backend.DataQuery) string {
+		t.Helper()
+		k, err := queryCacheKey(user, ds, []backend.DataQuery{q})
+		require.NoError(t, err)
+		return k
+	}
+
+	base := key(t, viewer, dataQuery(from, `{"expr": "up", "key": "Q-1"}`))
+	require.Equal(t, base, key(t, viewer, dataQuery(from.Add(30*time.Second), `{"key": "Q-2", "expr": "up"}`)))
+	require.Equal(t, base, key(t, &user.SignedInUser{UserID: 1, Login: "viewer", OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{1, 2}}, dataQuery(from, `{"expr": "up"}`)))
+	require.NotEqual(t, base, key(t, viewer, dataQuery(from.Add(time.Minute), `{"expr": "up"}`)))
+	require.NotEqual(t, base, key(t, viewer, dataQuery(from, `{"expr": "down"}`)))
+
+	for _, other := range []*user.SignedInUser{
+		{UserID: 2, Login: "editor", OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{1, 2}},
+		{UserID: 1, Login: "viewer", OrgID: 1, OrgRole: org.RoleEditor, Teams: []int64{1, 2}},
+		{UserID: 1, Login: "viewer", OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{1}},
+		{UserID: 1, Login: "viewer", OrgID: 1, OrgRole: org.RoleViewer, Teams: []int64{1, 2}, IsGrafanaAdmin: true},
+		{ApiKeyID: 1, OrgID: 1, OrgRole: org.RoleViewer},
+	} {
+		require.NotEqual(t, base, key(t, other, dataQuery(from, `{"expr": "up"}`)))
+	}
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginuser"
+)
+
+// DataSourceQuerier lets core data sources query other data sources through the query service. Core data sources
+// are dependencies of the query service, so the querier is created first and the query service registers itself
+// with it when it's created.
+type DataSourceQuerier struct {
+	queryService  *Service
+	users         pluginuser.Resolver
+	accessControl accesscontrol.AccessControl
+}
+
+func ProvideDataSourceQuerier(users pluginuser.Resolver, accessControl accesscontrol.AccessControl) *DataSourceQuerier {
+	return &DataSourceQuerier{
+		users:         users,
+		accessControl: accessControl,
+	}
+}
+
+// QueryDataSource runs the queries against the data source with the given uid, as the user of the plugin context.
+// The user must be allowed to query the data source.
+func (q *DataSourceQuerier) QueryDataSource(ctx context.Context, pCtx backend.PluginContext, datasourceUID string, timeRange backend.TimeRange, queries []*simplejson.Json) (*backend.QueryDataResponse, error) {
+	if q.queryService == nil {
+		return nil, errors.New("querying other data sources is not available")
+	}
+
+	signedInUser, err := q.users.SignedInUser(ctx, pCtx.OrgID, pCtx.User)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(datasourceUID))
+	allowed, err := q.accessControl.Evaluate(ctx, signedInUser, evaluator)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, datasources.ErrDataSourceAccessDenied
+	}
+
+	for _, query := range queries {
+		query.Set("datasource", map[string]interface{}{"uid": datasourceUID})
+	}
+
+	return q.queryService.QueryData(ctx, signedInUser, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(timeRange.From.UnixMilli(), 10),
+		To:      strconv.FormatInt(timeRange.To.UnixMilli(), 10),
+		Queries: queries,
+	})
+}
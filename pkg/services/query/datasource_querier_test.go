@@ -0,0 +1,59 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeUserResolver struct {
+	user *user.SignedInUser
+}
+
+func (r *fakeUserResolver) SignedInUser(_ context.Context, _ int64, _ *backend.User) (*user.SignedInUser, error) {
+	return r.user, nil
+}
+
+func TestDataSourceQuerier(t *testing.T) {
+	pCtx := backend.PluginContext{OrgID: 1, User: &backend.User{Login: "alice"}}
+	queries := func() []*simplejson.Json {
+		return []*simplejson.Json{simplejson.NewFromAny(map[string]interface{}{"refId": "A", "expr": "up"})}
+	}
+
+	setupQuerier := func(t *testing.T, permissions map[string][]string) (*DataSourceQuerier, *testContext) {
+		tc := setup(t)
+		querier := ProvideDataSourceQuerier(&fakeUserResolver{user: &user.SignedInUser{
+			UserID:      2,
+			OrgID:       1,
+			Login:       "alice",
+			Permissions: map[int64]map[string][]string{1: permissions},
+		}}, acimpl.ProvideAccessControl(setting.NewCfg()))
+		querier.queryService = tc.queryService
+		return querier, tc
+	}
+
+	t.Run("queries the data source as the user of the plugin context", func(t *testing.T) {
+		querier, tc := setupQuerier(t, map[string][]string{datasources.ActionQuery: {"datasources:uid:prom"}})
+
+		_, err := querier.QueryDataSource(context.Background(), pCtx, "prom", backend.TimeRange{}, queries())
+		require.NoError(t, err)
+		require.NotNil(t, tc.pluginContext.req)
+		require.Equal(t, "alice", tc.pluginContext.req.PluginContext.User.Login)
+	})
+
+	t.Run("fails if the user can't query the data source", func(t *testing.T) {
+		querier, tc := setupQuerier(t, map[string][]string{datasources.ActionQuery: {"datasources:uid:other"}})
+
+		_, err := querier.QueryDataSource(context.Background(), pCtx, "prom", backend.TimeRange{}, queries())
+		require.ErrorIs(t, err, datasources.ErrDataSourceAccessDenied)
+		require.Nil(t, tc.pluginContext.req)
+	})
+}
@@ -20,6 +20,7 @@ type parsedRequest struct {
 	hasExpression bool
 	parsedQueries map[string][]parsedQuery
 	dsTypes       map[string]bool
+	skipCache     bool
 }
 
 func (pr parsedRequest) getFlattenedQueries() []parsedQuery {
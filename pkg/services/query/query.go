@@ -3,6 +3,7 @@ package query
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -10,6 +11,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/plugins/adapters"
@@ -36,6 +38,8 @@ func ProvideService(
 	pluginRequestValidator models.PluginRequestValidator,
 	dataSourceService datasources.DataSourceService,
 	pluginClient plugins.Client,
+	cacheStorage remotecache.CacheStorage,
+	dataSourceQuerier *DataSourceQuerier,
 ) *Service {
 	g := &Service{
 		cfg:                    cfg,
@@ -44,8 +48,12 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		dataSourceService:      dataSourceService,
 		pluginClient:           pluginClient,
+		cacheStorage:           cacheStorage,
 		log:                    log.New("query_data"),
 	}
+	if dataSourceQuerier != nil {
+		dataSourceQuerier.queryService = g
+	}
 	g.log.Info("Query Service initialization")
 	return g
 }
@@ -57,7 +65,9 @@ type Service struct {
 	pluginRequestValidator models.PluginRequestValidator
 	dataSourceService      datasources.DataSourceService
 	pluginClient           plugins.Client
+	cacheStorage           remotecache.CacheStorage
 	log                    log.Logger
+	headerMu               sync.Mutex
 }
 
 // Run Service.
@@ -67,7 +77,26 @@ func (s *Service) Run(ctx context.Context) error {
 }
 
 // QueryData processes queries and returns query responses. It handles queries to single or mixed datasources, as well as expressions.
+// Transformations of the request are applied to the frames of all queries. Alert rules don't query through this
+// service and their queries are never transformed.
 func (s *Service) QueryData(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	if len(reqDTO.Transformations) == 0 {
+		return s.queryData(ctx, user, skipCache, reqDTO)
+	}
+
+	transformations, err := parseTransformations(reqDTO.Transformations)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.queryData(ctx, user, skipCache, reqDTO)
+	if err != nil {
+		return nil, err
+	}
+	return transformResponse(resp, reqDTO.Queries, transformations)
+}
+
+// queryData queries the data sources of the request without applying transformations.
+func (s *Service) queryData(ctx context.Context, user *user.SignedInUser, skipCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
 	// Parse the request into parsed queries grouped by datasource uid
 	parsedReq, err := s.parseMetricRequest(ctx, user, skipCache, reqDTO)
 	if err != nil {
@@ -232,7 +261,7 @@ func (s *Service) handleQuerySingleDatasource(ctx context.Context, user *user.Si
 		req.Queries = append(req.Queries, q.query)
 	}
 
-	return s.pluginClient.QueryData(ctx, req)
+	return s.queryDataCached(ctx, user, ds, parsedReq.skipCache, req)
 }
 
 // parseRequest parses a request into parsed queries grouped by datasource uid
@@ -246,6 +275,7 @@ func (s *Service) parseMetricRequest(ctx context.Context, user *user.SignedInUse
 		hasExpression: false,
 		parsedQueries: make(map[string][]parsedQuery),
 		dsTypes:       make(map[string]bool),
+		skipCache:     skipCache,
 	}
 
 	// Parse the queries and store them by datasource
@@ -400,7 +400,7 @@ func setup(t *testing.T) *testContext {
 		SimulatePluginFailure: false,
 	}
 	exprService := expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, pc, fakeDatasourceService)
-	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, ds, pc) // provider belonging to this package
+	queryService := ProvideService(setting.NewCfg(), dc, exprService, rv, ds, pc, nil, nil) // provider belonging to this package
 	return &testContext{
 		pluginContext:          pc,
 		secretStore:            ss,
@@ -460,7 +460,8 @@ func (c *fakeDataSourceCache) GetDatasourceByUID(ctx context.Context, datasource
 
 type fakePluginClient struct {
 	plugins.Client
-	req *backend.QueryDataRequest
+	req       *backend.QueryDataRequest
+	responses backend.Responses
 }
 
 func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
@@ -475,5 +476,8 @@ func (c *fakePluginClient) QueryData(ctx context.Context, req *backend.QueryData
 		return nil, errors.New("plugin client failed")
 	}
 
+	if c.responses != nil {
+		return &backend.QueryDataResponse{Responses: c.responses}, nil
+	}
 	return &backend.QueryDataResponse{Responses: make(backend.Responses)}, nil
 }
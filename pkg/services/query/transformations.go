@@ -0,0 +1,717 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// A transformer applies a dashboard panel transformation with the given options to the frames of all queries.
+type transformer func(options *simplejson.Json, frames data.Frames) (data.Frames, error)
+
+// transformers are the panel transformations which can be applied on the server. They are ported from
+// @grafana/data and should return the same frames as in the browser.
+var transformers = map[string]transformer{
+	"joinByField":     joinByField,
+	"seriesToColumns": joinByField,
+	"groupBy":         groupBy,
+	"organize":        organizeFields,
+	"filterByValue":   filterByValue,
+}
+
+type transformation struct {
+	id      string
+	options *simplejson.Json
+}
+
+// parseTransformations reads the transformations of a panel, skipping disabled ones. All transformations must be
+// supported, as returning frames which would be transformed differently in the browser is worse than failing.
+func parseTransformations(raw []*simplejson.Json) ([]transformation, error) {
+	transformations := make([]transformation, 0, len(raw))
+	for _, t := range raw {
+		if t.Get("disabled").MustBool(false) {
+			continue
+		}
+		id := t.Get("id").MustString()
+		if _, ok := transformers[id]; !ok {
+			return nil, ErrUnsupportedTransformation.Errorf("unsupported transformation: %q", id)
+		}
+		transformations = append(transformations, transformation{id: id, options: t.Get("options")})
+	}
+	return transformations, nil
+}
+
+// transformResponse applies the transformations to the frames of all responses, in the order of the queries.
+// Transformed frames are returned in the response of the query they came from, or in the response of the first
+// query for frames combining several queries.
+func transformResponse(resp *backend.QueryDataResponse, queries []*simplejson.Json, transformations []transformation) (*backend.QueryDataResponse, error) {
+	refIDs := make([]string, 0, len(queries))
+	var frames data.Frames
+	for _, query := range queries {
+		refID := query.Get("refId").MustString("A")
+		refIDs = append(refIDs, refID)
+		for _, frame := range resp.Responses[refID].Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			frames = append(frames, frame)
+		}
+	}
+
+	for _, t := range transformations {
+		var err error
+		frames, err = transformers[t.id](t.options, frames)
+		if err != nil {
+			return nil, fmt.Errorf("%s transformation failed: %w", t.id, err)
+		}
+	}
+
+	transformed := backend.NewQueryDataResponse()
+	for refID, res := range resp.Responses {
+		res.Frames = nil
+		transformed.Responses[refID] = res
+	}
+	for _, frame := range frames {
+		refID := frame.RefID
+		if _, ok := transformed.Responses[refID]; !ok {
+			refID = refIDs[0]
+		}
+		res := transformed.Responses[refID]
+		res.Frames = append(res.Frames, frame)
+		transformed.Responses[refID] = res
+	}
+	return transformed, nil
+}
+
+// fieldDisplayName returns the name of a field as shown in panels, which is what transformation options refer to.
+// frames are all frames the field is displayed with.
+func fieldDisplayName(field *data.Field, frame *data.Frame, frames data.Frames) string {
+	if field.Config != nil && field.Config.DisplayName != "" {
+		return field.Config.DisplayName
+	}
+	if field.Config != nil && field.Config.DisplayNameFromDS != "" {
+		return field.Config.DisplayNameFromDS
+	}
+	if field.Type().Time() && len(field.Labels) == 0 {
+		if field.Name == "" {
+			return "Time"
+		}
+		return field.Name
+	}
+
+	var parts []string
+	frameNamesDiffer := false
+	for i := 1; i < len(frames); i++ {
+		if frames[i].Name != frames[i-1].Name {
+			frameNamesDiffer = true
+			break
+		}
+	}
+
+	frameNameAdded, labelsAdded := false, false
+	if frameNamesDiffer && frame.Name != "" {
+		parts = append(parts, frame.Name)
+		frameNameAdded = true
+	}
+	if field.Name != "" && field.Name != data.TimeSeriesValueFieldName {
+		parts = append(parts, field.Name)
+	}
+	if len(field.Labels) > 0 {
+		if name := singleLabelName(frames); name == "" {
+			parts = append(parts, formatLabels(field.Labels))
+			labelsAdded = true
+		} else if v := field.Labels[name]; v != "" {
+			parts = append(parts, v)
+			labelsAdded = true
+		}
+	}
+	if !frameNameAdded && !labelsAdded && field.Name == data.TimeSeriesValueFieldName && frame.Name != "" {
+		parts = append(parts, frame.Name)
+	}
+
+	switch {
+	case len(parts) > 0:
+		return strings.Join(parts, " ")
+	case field.Name != "":
+		return field.Name
+	default:
+		return data.TimeSeriesValueFieldName
+	}
+}
+
+// singleLabelName returns the label name if all fields of the frames have the same single label.
+func singleLabelName(frames data.Frames) string {
+	name := ""
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			for k := range field.Labels {
+				if name == "" {
+					name = k
+				} else if k != name {
+					return ""
+				}
+			}
+		}
+	}
+	return name
+}
+
+func formatLabels(labels data.Labels) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, labels[k]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// organizeFields excludes, orders and renames fields by their display names. Display names are those of the
+// fields in the frames passed to the transformation, so excluding fields doesn't change the names of others.
+func organizeFields(options *simplejson.Json, frames data.Frames) (data.Frames, error) {
+	exclude := options.Get("excludeByName").MustMap()
+	index := options.Get("indexByName").MustMap()
+	rename := options.Get("renameByName").MustMap()
+
+	names := make(map[*data.Field]string)
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			names[field] = fieldDisplayName(field, frame, frames)
+		}
+	}
+
+	filtered := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		fields := make([]*data.Field, 0, len(frame.Fields))
+		for _, field := range frame.Fields {
+			if excluded, _ := exclude[names[field]].(bool); !excluded {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		copied := *frame
+		copied.Fields = fields
+		filtered = append(filtered, &copied)
+	}
+
+	indexOf := func(name string) int64 {
+		i, err := simplejson.NewFromAny(index[name]).Int64()
+		if err != nil {
+			return math.MaxInt64
+		}
+		return i
+	}
+	for _, frame := range filtered {
+		if len(index) > 0 {
+			sort.SliceStable(frame.Fields, func(i, j int) bool {
+				return indexOf(names[frame.Fields[i]]) < indexOf(names[frame.Fields[j]])
+			})
+		}
+
+		for i, field := range frame.Fields {
+			renameTo, _ := rename[names[field]].(string)
+			if renameTo == "" {
+				continue
+			}
+			renamed := *field
+			config := data.FieldConfig{}
+			if field.Config != nil {
+				config = *field.Config
+			}
+			config.DisplayName = renameTo
+			renamed.Config = &config
+			frame.Fields[i] = &renamed
+		}
+	}
+	return filtered, nil
+}
+
+type valueMatcher func(v interface{}) bool
+
+// filterByValue keeps or removes the rows of each frame matching any or all of the filters.
+func filterByValue(options *simplejson.Json, frames data.Frames) (data.Frames, error) {
+	filters := options.Get("filters").MustArray()
+	if len(filters) == 0 {
+		return frames, nil
+	}
+	include := options.Get("type").MustString("include") == "include"
+	matchAll := options.Get("match").MustString("any") == "all"
+
+	type rowFilter struct {
+		fieldName string
+		match     valueMatcher
+	}
+	rowFilters := make([]rowFilter, 0, len(filters))
+	for _, f := range filters {
+		filter := simplejson.NewFromAny(f)
+		match, err := newValueMatcher(filter.Get("config"))
+		if err != nil {
+			return nil, err
+		}
+		rowFilters = append(rowFilters, rowFilter{fieldName: filter.Get("fieldName").MustString(), match: match})
+	}
+
+	filtered := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		fieldIndex := map[string]int{}
+		for i, field := range frame.Fields {
+			fieldIndex[fieldDisplayName(field, frame, frames)] = i
+		}
+
+		kept := frame.EmptyCopy()
+		for row := 0; row < frame.Rows(); row++ {
+			matching := false
+			for _, f := range rowFilters {
+				i, ok := fieldIndex[f.fieldName]
+				matching = false
+				if ok {
+					v := valueAt(frame.Fields[i], row)
+					matching = f.match(v)
+				}
+				if matching != matchAll {
+					break
+				}
+			}
+			if matching == include {
+				kept.AppendRow(frame.RowCopy(row)...)
+			}
+		}
+		// EmptyCopy doesn't copy the frame meta and field configs
+		kept.Meta = frame.Meta
+		for i, field := range kept.Fields {
+			field.Config = frame.Fields[i].Config
+		}
+		filtered = append(filtered, kept)
+	}
+	return filtered, nil
+}
+
+// newValueMatcher returns the value matcher of a filter config, with the loose comparisons of JavaScript.
+func newValueMatcher(config *simplejson.Json) (valueMatcher, error) {
+	options := config.Get("options")
+	switch id := config.Get("id").MustString(); id {
+	case "isNull":
+		return func(v interface{}) bool { return v == nil }, nil
+	case "isNotNull":
+		return func(v interface{}) bool { return v != nil }, nil
+	case "greater", "greaterOrEqual", "lower", "lowerOrEqual":
+		limit, ok := toNumber(options.Get("value").Interface())
+		return func(v interface{}) bool {
+			n, isNumber := toNumber(v)
+			if !ok || !isNumber {
+				return false
+			}
+			switch id {
+			case "greater":
+				return n > limit
+			case "greaterOrEqual":
+				return n >= limit
+			case "lower":
+				return n < limit
+			default:
+				return n <= limit
+			}
+		}, nil
+	case "between":
+		from, fromOK := toNumber(options.Get("from").Interface())
+		to, toOK := toNumber(options.Get("to").Interface())
+		return func(v interface{}) bool {
+			n, isNumber := toNumber(v)
+			return fromOK && toOK && isNumber && n > from && n < to
+		}, nil
+	case "equal", "notEqual":
+		expected := options.Get("value").Interface()
+		return func(v interface{}) bool {
+			return looseEqual(v, expected) == (id == "equal")
+		}, nil
+	case "regex":
+		re, err := regexp.Compile(options.Get("value").MustString())
+		if err != nil {
+			return nil, err
+		}
+		return func(v interface{}) bool {
+			return v != nil && re.MatchString(toString(v))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value matcher: %q", id)
+	}
+}
+
+// toNumber converts numbers, times in epoch milliseconds, and numeric strings to float64.
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case nil:
+		return 0, false
+	case time.Time:
+		return float64(n.UnixMilli()), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		f, err := simplejson.NewFromAny(v).Float64()
+		if err != nil || math.IsNaN(f) {
+			return 0, false
+		}
+		return f, true
+	}
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case time.Time:
+		return strconv.FormatInt(s.UnixMilli(), 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func looseEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return x == y
+		}
+	}
+	return toString(a) == toString(b)
+}
+
+// joinByField joins the frames into a single frame by the values of a field, the first time field by default.
+// Other fields become nullable, and are null in rows with join values missing from their frame. Join fields of
+// different types can't be joined, so they fail the transformation.
+func joinByField(options *simplejson.Json, frames data.Frames) (data.Frames, error) {
+	if len(frames) < 2 {
+		return frames, nil
+	}
+	byField := options.Get("byField").MustString()
+	inner := options.Get("mode").MustString("outer") == "inner"
+
+	joinFieldIndex := func(frame *data.Frame) int {
+		for i, field := range frame.Fields {
+			if byField != "" && fieldDisplayName(field, frame, frames) == byField {
+				return i
+			}
+			if byField == "" && field.Type().Time() {
+				return i
+			}
+		}
+		return -1
+	}
+
+	type joinedFrame struct {
+		frame     *data.Frame
+		joinIndex int
+		rows      map[interface{}]int
+	}
+	var (
+		joined    []joinedFrame
+		joinField *data.Field
+		keys      []interface{}
+		keyValues = map[interface{}]interface{}{}
+		keyCounts = map[interface{}]int{}
+	)
+	for _, frame := range frames {
+		i := joinFieldIndex(frame)
+		if i < 0 {
+			continue
+		}
+		field := frame.Fields[i]
+		if joinField == nil {
+			joinField = field
+		} else if field.Type().NonNullableType() != joinField.Type().NonNullableType() {
+			return nil, fmt.Errorf("join field of frame %q is of type %s, expected %s", frame.Name, field.Type().NonNullableType().ItemTypeString(), joinField.Type().NonNullableType().ItemTypeString())
+		}
+
+		jf := joinedFrame{frame: frame, joinIndex: i, rows: map[interface{}]int{}}
+		for row := 0; row < field.Len(); row++ {
+			v, ok := field.ConcreteAt(row)
+			if !ok {
+				continue
+			}
+			key := joinKey(v)
+			if _, ok := jf.rows[key]; ok {
+				continue
+			}
+			jf.rows[key] = row
+			if _, ok := keyValues[key]; !ok {
+				keyValues[key] = v
+				keys = append(keys, key)
+			}
+			keyCounts[key]++
+		}
+		joined = append(joined, jf)
+	}
+	if len(joined) == 0 {
+		return frames, nil
+	}
+
+	if inner {
+		common := keys[:0]
+		for _, key := range keys {
+			if keyCounts[key] == len(joined) {
+				common = append(common, key)
+			}
+		}
+		keys = common
+	}
+	if t := joinField.Type(); t.Time() || t.Numeric() {
+		sort.SliceStable(keys, func(i, j int) bool {
+			a, _ := toNumber(keyValues[keys[i]])
+			b, _ := toNumber(keyValues[keys[j]])
+			return a < b
+		})
+	}
+
+	out := data.NewFieldFromFieldType(joinField.Type().NonNullableType(), len(keys))
+	out.Name = joinField.Name
+	out.Labels = joinField.Labels
+	out.Config = joinField.Config
+	for row, key := range keys {
+		out.SetConcrete(row, keyValues[key])
+	}
+	result := data.NewFrame("", out)
+
+	for _, jf := range joined {
+		for i, field := range jf.frame.Fields {
+			if i == jf.joinIndex {
+				continue
+			}
+			values := data.NewFieldFromFieldType(field.Type().NullableType(), len(keys))
+			values.Name = field.Name
+			values.Config = field.Config
+			values.Labels = field.Labels.Copy()
+			if jf.frame.Name != "" {
+				if values.Labels == nil {
+					values.Labels = data.Labels{}
+				}
+				values.Labels["name"] = jf.frame.Name
+			}
+			for row, key := range keys {
+				if srcRow, ok := jf.rows[key]; ok {
+					if v, ok := field.ConcreteAt(srcRow); ok {
+						values.SetConcrete(row, v)
+					}
+				}
+			}
+			result.Fields = append(result.Fields, values)
+		}
+	}
+	return data.Frames{result}, nil
+}
+
+// joinKey returns a comparable key for a join field value.
+func joinKey(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UnixNano()
+	}
+	return v
+}
+
+// groupBy groups the rows of each frame by the values of the group by fields, and calculates the aggregations of
+// the other fields for each group. Frames without a group by field are removed.
+func groupBy(options *simplejson.Json, frames data.Frames) (data.Frames, error) {
+	fieldOptions := options.Get("fields")
+	operation := func(name string) string {
+		return fieldOptions.Get(name).Get("operation").MustString()
+	}
+
+	hasGroupBy := false
+	for name := range fieldOptions.MustMap() {
+		if operation(name) == "groupby" {
+			hasGroupBy = true
+		}
+	}
+	if !hasGroupBy {
+		return frames, nil
+	}
+
+	grouped := make(data.Frames, 0, len(frames))
+	for _, frame := range frames {
+		names := make([]string, len(frame.Fields))
+		var groupFields []int
+		for i, field := range frame.Fields {
+			names[i] = fieldDisplayName(field, frame, frames)
+			if operation(names[i]) == "groupby" {
+				groupFields = append(groupFields, i)
+			}
+		}
+		if len(groupFields) == 0 {
+			continue
+		}
+
+		var (
+			groupKeys []string
+			groupRows = map[string][]int{}
+		)
+		for row := 0; row < frame.Rows(); row++ {
+			keyParts := make([]string, 0, len(groupFields))
+			for _, i := range groupFields {
+				v := valueAt(frame.Fields[i], row)
+				if v == nil {
+					keyParts = append(keyParts, "")
+				} else {
+					keyParts = append(keyParts, toString(v))
+				}
+			}
+			key := strings.Join(keyParts, ",")
+			if _, ok := groupRows[key]; !ok {
+				groupKeys = append(groupKeys, key)
+			}
+			groupRows[key] = append(groupRows[key], row)
+		}
+
+		result := data.NewFrame(frame.Name)
+		result.RefID = frame.RefID
+		for _, i := range groupFields {
+			field := frame.Fields[i]
+			values := data.NewFieldFromFieldType(field.Type(), len(groupKeys))
+			values.Name = field.Name
+			values.Labels = field.Labels
+			values.Config = field.Config
+			for g, key := range groupKeys {
+				values.Set(g, field.CopyAt(groupRows[key][0]))
+			}
+			result.Fields = append(result.Fields, values)
+		}
+
+		for i, field := range frame.Fields {
+			if operation(names[i]) != "aggregate" {
+				continue
+			}
+			for _, aggregation := range fieldOptions.Get(names[i]).Get("aggregations").MustStringArray() {
+				values, err := aggregate(field, aggregation, groupKeys, groupRows)
+				if err != nil {
+					return nil, err
+				}
+				values.Name = fmt.Sprintf("%s (%s)", names[i], aggregation)
+				result.Fields = append(result.Fields, values)
+			}
+		}
+		grouped = append(grouped, result)
+	}
+	return grouped, nil
+}
+
+// aggregate calculates a reducer of the panel editor for the rows of each group.
+func aggregate(field *data.Field, reducer string, groupKeys []string, groupRows map[string][]int) (*data.Field, error) {
+	switch reducer {
+	case "first", "last", "firstNotNull", "lastNotNull":
+		values := data.NewFieldFromFieldType(field.Type().NullableType(), len(groupKeys))
+		for g, key := range groupKeys {
+			rows := groupRows[key]
+			if reducer == "last" || reducer == "lastNotNull" {
+				rows = make([]int, len(groupRows[key]))
+				for i, row := range groupRows[key] {
+					rows[len(rows)-1-i] = row
+				}
+			}
+			for _, row := range rows {
+				v, ok := field.ConcreteAt(row)
+				if ok {
+					values.SetConcrete(g, v)
+				}
+				if ok || reducer == "first" || reducer == "last" {
+					break
+				}
+			}
+		}
+		return values, nil
+	case "allIsNull":
+		values := data.NewFieldFromFieldType(data.FieldTypeBool, len(groupKeys))
+		for g, key := range groupKeys {
+			allIsNull := true
+			for _, row := range groupRows[key] {
+				v := valueAt(field, row)
+				if _, ok := toNumber(v); ok {
+					allIsNull = false
+				}
+			}
+			values.Set(g, allIsNull)
+		}
+		return values, nil
+	case "count", "sum", "mean", "min", "max", "range":
+	default:
+		return nil, fmt.Errorf("unsupported aggregation: %q", reducer)
+	}
+
+	values := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, len(groupKeys))
+	for g, key := range groupKeys {
+		rows := groupRows[key]
+		if reducer == "count" {
+			count := float64(len(rows))
+			values.Set(g, &count)
+			continue
+		}
+
+		var sum, min, max float64
+		count := 0
+		for _, row := range rows {
+			v := valueAt(field, row)
+			n, ok := toNumber(v)
+			if !ok {
+				continue
+			}
+			if count == 0 || n < min {
+				min = n
+			}
+			if count == 0 || n > max {
+				max = n
+			}
+			sum += n
+			count++
+		}
+
+		var result float64
+		switch reducer {
+		case "sum":
+			result = sum
+		case "mean", "min", "max", "range":
+			if count == 0 {
+				continue
+			}
+			switch reducer {
+			case "mean":
+				result = sum / float64(count)
+			case "min":
+				result = min
+			case "max":
+				result = max
+			default:
+				result = max - min
+			}
+		}
+		values.Set(g, &result)
+	}
+	return values, nil
+}
+
+// valueAt returns the value of a field at a row, or nil if it is null.
+func valueAt(field *data.Field, row int) interface{} {
+	v, ok := field.ConcreteAt(row)
+	if !ok {
+		return nil
+	}
+	return v
+}
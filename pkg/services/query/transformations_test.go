@@ -0,0 +1,250 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestQueryDataTransformations(t *testing.T) {
+	t.Run("transformations are applied to the frames of all queries", func(t *testing.T) {
+		tc := setup(t)
+		tc.pluginContext.responses = backend.Responses{
+			"A": {Frames: data.Frames{data.NewFrame("a",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+				data.NewField("Value", nil, []float64{1, 2}),
+			)}},
+			"B": {Frames: data.Frames{data.NewFrame("b",
+				data.NewField("time", nil, []time.Time{time.Unix(2, 0), time.Unix(3, 0)}),
+				data.NewField("Value", nil, []float64{20, 30}),
+			)}},
+		}
+		reqDTO := metricRequestWithQueries(t, `{"refId": "A", "datasource": {"uid": "ds1"}}`, `{"refId": "B", "datasource": {"uid": "ds1"}}`)
+		reqDTO.Transformations = transformationsJSON(t, `[
+			{"id": "joinByField", "options": {}},
+			{"id": "organize", "options": {"excludeByName": {"a": true}}, "disabled": true},
+			{"id": "organize", "options": {"renameByName": {"b": "B"}}}
+		]`)
+
+		res, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+		require.NoError(t, err)
+		require.Len(t, res.Responses["A"].Frames, 1)
+		require.Empty(t, res.Responses["B"].Frames)
+
+		frame := res.Responses["A"].Frames[0]
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, 3, frame.Rows())
+		require.Equal(t, "B", frame.Fields[2].Config.DisplayName)
+	})
+
+	t.Run("unsupported transformations fail before querying", func(t *testing.T) {
+		tc := setup(t)
+		reqDTO := metricRequestWithQueries(t, `{"refId": "A", "datasource": {"uid": "ds1"}}`)
+		reqDTO.Transformations = transformationsJSON(t, `[{"id": "calculateField"}]`)
+
+		_, err := tc.queryService.QueryData(context.Background(), tc.signedInUser, true, reqDTO)
+		require.True(t, errors.Is(err, ErrUnsupportedTransformation))
+		require.Nil(t, tc.pluginContext.req)
+	})
+}
+
+func TestJoinByField(t *testing.T) {
+	frames := func() data.Frames {
+		return data.Frames{
+			data.NewFrame("a",
+				data.NewField("time", nil, []time.Time{time.Unix(3, 0), time.Unix(1, 0)}),
+				data.NewField("value", data.Labels{"host": "a"}, []float64{3, 1}),
+			),
+			data.NewFrame("b",
+				data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+				data.NewField("value", data.Labels{"host": "b"}, []int64{10, 20}),
+			),
+		}
+	}
+
+	t.Run("outer join on the time field", func(t *testing.T) {
+		res, err := joinByField(simplejson.New(), frames())
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Len(t, res[0].Fields, 3)
+		require.Equal(t, 3, res[0].Rows())
+		require.Equal(t, time.Unix(1, 0), res[0].Fields[0].At(0))
+		require.Equal(t, time.Unix(3, 0), res[0].Fields[0].At(2))
+		require.Equal(t, 1.0, *res[0].Fields[1].At(0).(*float64))
+		require.Nil(t, res[0].Fields[1].At(1))
+		require.Equal(t, int64(20), *res[0].Fields[2].At(1).(*int64))
+		require.Nil(t, res[0].Fields[2].At(2))
+		require.Equal(t, data.Labels{"host": "b", "name": "b"}, res[0].Fields[2].Labels)
+	})
+
+	t.Run("inner join keeps common values only", func(t *testing.T) {
+		res, err := joinByField(simplejson.NewFromAny(map[string]interface{}{"mode": "inner"}), frames())
+		require.NoError(t, err)
+		require.Equal(t, 1, res[0].Rows())
+		require.Equal(t, time.Unix(1, 0), res[0].Fields[0].At(0))
+	})
+
+	t.Run("join by a field name", func(t *testing.T) {
+		res, err := joinByField(simplejson.NewFromAny(map[string]interface{}{"byField": "missing"}), frames())
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+	})
+
+	t.Run("join fields of different types fail", func(t *testing.T) {
+		mixed := frames()
+		mixed[1].Fields[0] = data.NewField("time", nil, []int64{1000, 2000}).SetConfig(&data.FieldConfig{DisplayName: "time"})
+
+		_, err := joinByField(simplejson.NewFromAny(map[string]interface{}{"byField": "time"}), mixed)
+		require.EqualError(t, err, `join field of frame "b" is of type int64, expected time.Time`)
+	})
+}
+
+func TestGroupBy(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("server", nil, []string{"a", "b", "a"}),
+		data.NewField("status", nil, []*string{strPtr("ok"), nil, strPtr("error")}),
+		data.NewField("latency", nil, []*float64{floatPtr(1), floatPtr(5), floatPtr(3)}),
+	)
+	options := simplejson.NewFromAny(map[string]interface{}{
+		"fields": map[string]interface{}{
+			"server":  map[string]interface{}{"operation": "groupby"},
+			"status":  map[string]interface{}{"operation": "aggregate", "aggregations": []interface{}{"lastNotNull"}},
+			"latency": map[string]interface{}{"operation": "aggregate", "aggregations": []interface{}{"mean", "max", "count"}},
+		},
+	})
+
+	res, err := groupBy(options, data.Frames{frame})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, 2, res[0].Rows())
+
+	names := make([]string, 0, len(res[0].Fields))
+	for _, field := range res[0].Fields {
+		names = append(names, field.Name)
+	}
+	require.Equal(t, []string{"server", "status (lastNotNull)", "latency (mean)", "latency (max)", "latency (count)"}, names)
+	require.Equal(t, []interface{}{"a", strPtr("error"), floatPtr(2), floatPtr(3), floatPtr(2)}, res[0].RowCopy(0))
+	require.Equal(t, []interface{}{"b", (*string)(nil), floatPtr(5), floatPtr(5), floatPtr(1)}, res[0].RowCopy(1))
+
+	t.Run("unsupported aggregations fail", func(t *testing.T) {
+		options.SetPath([]string{"fields", "latency", "aggregations"}, []interface{}{"p99"})
+		_, err := groupBy(options, data.Frames{frame})
+		require.EqualError(t, err, `unsupported aggregation: "p99"`)
+	})
+}
+
+func TestOrganizeFields(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+		data.NewField("Value", data.Labels{"host": "a"}, []float64{1}),
+		data.NewField("other", nil, []float64{2}),
+	)
+	options := simplejson.NewFromAny(map[string]interface{}{
+		"excludeByName": map[string]interface{}{"other": true},
+		"indexByName":   map[string]interface{}{"a": 0, "time": 1},
+		"renameByName":  map[string]interface{}{"a": "Host A"},
+	})
+
+	res, err := organizeFields(options, data.Frames{frame})
+	require.NoError(t, err)
+	require.Len(t, res[0].Fields, 2)
+	require.Equal(t, "Value", res[0].Fields[0].Name)
+	require.Equal(t, "Host A", res[0].Fields[0].Config.DisplayName)
+	require.Equal(t, "time", res[0].Fields[1].Name)
+	require.Len(t, frame.Fields, 3)
+	require.Nil(t, frame.Fields[1].Config)
+}
+
+func TestOrganizeFieldsDisplayNames(t *testing.T) {
+	frames := data.Frames{
+		data.NewFrame("", data.NewField("Value", data.Labels{"host": "a"}, []float64{1})),
+		data.NewFrame("", data.NewField("Value", data.Labels{"host": "b", "env": "dev"}, []float64{2})),
+	}
+	// Without the excluded frame the first field would be displayed as "a", but options refer to the names of
+	// the fields as they were before the transformation.
+	options := simplejson.NewFromAny(map[string]interface{}{
+		"excludeByName": map[string]interface{}{`{env="dev", host="b"}`: true},
+		"renameByName":  map[string]interface{}{`{host="a"}`: "Host A", "a": "wrong"},
+	})
+
+	res, err := organizeFields(options, frames)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Equal(t, "Host A", res[0].Fields[0].Config.DisplayName)
+}
+
+func TestFilterByValue(t *testing.T) {
+	frame := data.NewFrame("",
+		data.NewField("host", nil, []string{"web-1", "web-2", "db-1"}),
+		data.NewField("cpu", nil, []*float64{floatPtr(90), nil, floatPtr(10)}),
+	)
+	frame.Fields[1].Config = &data.FieldConfig{Unit: "percent"}
+
+	filter := func(t *testing.T, typ, match string, filters ...map[string]interface{}) *data.Frame {
+		t.Helper()
+		raw := make([]interface{}, 0, len(filters))
+		for _, f := range filters {
+			raw = append(raw, f)
+		}
+		res, err := filterByValue(simplejson.NewFromAny(map[string]interface{}{"type": typ, "match": match, "filters": raw}), data.Frames{frame})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, "percent", res[0].Fields[1].Config.Unit)
+		return res[0]
+	}
+	greater := map[string]interface{}{"fieldName": "cpu", "config": map[string]interface{}{"id": "greater", "options": map[string]interface{}{"value": 50}}}
+	web := map[string]interface{}{"fieldName": "host", "config": map[string]interface{}{"id": "regex", "options": map[string]interface{}{"value": "^web"}}}
+	isNull := map[string]interface{}{"fieldName": "cpu", "config": map[string]interface{}{"id": "isNull"}}
+
+	res := filter(t, "include", "any", greater, isNull)
+	require.Equal(t, 2, res.Rows())
+	require.Equal(t, "web-1", res.Fields[0].At(0))
+	require.Equal(t, "web-2", res.Fields[0].At(1))
+
+	res = filter(t, "include", "all", greater, web)
+	require.Equal(t, 1, res.Rows())
+	require.Equal(t, "web-1", res.Fields[0].At(0))
+
+	res = filter(t, "exclude", "any", web)
+	require.Equal(t, 1, res.Rows())
+	require.Equal(t, "db-1", res.Fields[0].At(0))
+}
+
+func TestFieldDisplayName(t *testing.T) {
+	a := data.NewFrame("a", data.NewField("Value", data.Labels{"host": "a"}, []float64{}))
+	b := data.NewFrame("b", data.NewField("Value", data.Labels{"host": "b", "dc": "eu"}, []float64{}))
+	c := data.NewFrame("c", data.NewField("Value", nil, []float64{}))
+
+	require.Equal(t, "a", fieldDisplayName(a.Fields[0], a, data.Frames{a}))
+	require.Equal(t, `b {dc="eu", host="b"}`, fieldDisplayName(b.Fields[0], b, data.Frames{a, b}))
+	require.Equal(t, "c", fieldDisplayName(c.Fields[0], c, data.Frames{c}))
+
+	c.Fields[0].Config = &data.FieldConfig{DisplayNameFromDS: "from ds"}
+	require.Equal(t, "from ds", fieldDisplayName(c.Fields[0], c, data.Frames{c}))
+}
+
+func transformationsJSON(t *testing.T, raw string) []*simplejson.Json {
+	t.Helper()
+	j, err := simplejson.NewJson([]byte(raw))
+	require.NoError(t, err)
+	transformations := make([]*simplejson.Json, 0)
+	for _, v := range j.MustArray() {
+		transformations = append(transformations, simplejson.NewFromAny(v))
+	}
+	return transformations
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
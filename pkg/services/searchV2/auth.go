@@ -6,6 +6,8 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/sqlstore/permissions"
 	"github.com/grafana/grafana/pkg/services/sqlstore/searchstore"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -17,6 +19,18 @@ type ResourceFilter func(uid string) bool
 // FutureAuthService eventually implemented by the security service
 type FutureAuthService interface {
 	GetDashboardReadFilter(user *user.SignedInUser) (ResourceFilter, error)
+	GetFolderReadFilter(user *user.SignedInUser) (ResourceFilter, error)
+	GetAlertRuleReadFilter(user *user.SignedInUser) (ResourceFilter, error)
+	GetDatasourceReadFilter(user *user.SignedInUser) (ResourceFilter, error)
+	GetDatasourceQueryFilter(user *user.SignedInUser) (ResourceFilter, error)
+}
+
+// entityFilters checks the access to the entities indexed from other tables than the dashboard table
+type entityFilters struct {
+	folder          ResourceFilter // folders the user can read, "" being the General folder
+	alertRule       ResourceFilter // folders the user can read the alert rules of
+	datasource      ResourceFilter // data sources the user can read
+	datasourceQuery ResourceFilter // data sources the user can query
 }
 
 var _ FutureAuthService = (*simpleSQLAuthService)(nil)
@@ -30,7 +44,7 @@ type dashIdQueryResult struct {
 	UID string `xorm:"uid"`
 }
 
-func (a *simpleSQLAuthService) getDashboardTableAuthFilter(user *user.SignedInUser) searchstore.FilterWhere {
+func (a *simpleSQLAuthService) getDashboardTableAuthFilter(user *user.SignedInUser, filterType string) searchstore.FilterWhere {
 	if a.ac.IsDisabled() {
 		return permissions.DashboardPermissionFilter{
 			OrgRole:         user.OrgRole,
@@ -41,11 +55,12 @@ func (a *simpleSQLAuthService) getDashboardTableAuthFilter(user *user.SignedInUs
 		}
 	}
 
-	return permissions.NewAccessControlDashboardPermissionFilter(user, models.PERMISSION_VIEW, searchstore.TypeDashboard)
+	return permissions.NewAccessControlDashboardPermissionFilter(user, models.PERMISSION_VIEW, filterType)
 }
 
-func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
-	filter := a.getDashboardTableAuthFilter(user)
+// getReadableUIDs returns the uids of the dashboard table rows the user can read
+func (a *simpleSQLAuthService) getReadableUIDs(user *user.SignedInUser, filterType string) (map[string]bool, error) {
+	filter := a.getDashboardTableAuthFilter(user, filterType)
 	rows := make([]*dashIdQueryResult, 0)
 
 	err := a.sql.WithDbSession(context.Background(), func(sess *db.Session) error {
@@ -54,6 +69,9 @@ func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (
 			Where(sql, params...).
 			Where("org_id = ?", user.OrgID).
 			Cols("uid")
+		if filterType == searchstore.TypeFolder || filterType == searchstore.TypeAlertFolder {
+			sess.Where("is_folder = ?", a.sql.GetDialect().BooleanStr(true))
+		}
 
 		err := sess.Find(&rows)
 		if err != nil {
@@ -71,8 +89,76 @@ func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (
 	for i := 0; i < len(rows); i++ {
 		uids[rows[i].UID] = true
 	}
+	return uids, nil
+}
+
+func (a *simpleSQLAuthService) GetDashboardReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
+	uids, err := a.getReadableUIDs(user, searchstore.TypeDashboard)
+	if err != nil {
+		return nil, err
+	}
 
 	return func(uid string) bool {
 		return uids[uid]
 	}, err
 }
+
+// GetFolderReadFilter returns the folders the user can read. The General folder, with an empty uid,
+// is readable by the org viewers like for the library panels.
+func (a *simpleSQLAuthService) GetFolderReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
+	uids, err := a.getReadableUIDs(user, searchstore.TypeFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	canReadGeneral := user.HasRole(org.RoleViewer)
+	return func(uid string) bool {
+		if uid == "" || uid == accesscontrol.GeneralFolderUID {
+			return canReadGeneral
+		}
+		return uids[uid]
+	}, nil
+}
+
+// GetAlertRuleReadFilter returns the folders the user can read the alert rules of.
+// Alert rules are never stored in the General folder.
+func (a *simpleSQLAuthService) GetAlertRuleReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
+	uids, err := a.getReadableUIDs(user, searchstore.TypeAlertFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	// without access control, reading the alert rules requires the viewer role
+	canRead := !a.ac.IsDisabled() || user.HasRole(org.RoleViewer)
+	return func(uid string) bool {
+		return canRead && uids[uid]
+	}, nil
+}
+
+func (a *simpleSQLAuthService) GetDatasourceReadFilter(user *user.SignedInUser) (ResourceFilter, error) {
+	if a.ac.IsDisabled() {
+		isAdmin := user.HasRole(org.RoleAdmin)
+		return func(uid string) bool {
+			return isAdmin
+		}, nil
+	}
+
+	permissions := user.Permissions[user.OrgID]
+	return func(uid string) bool {
+		return accesscontrol.EvalPermission(datasources.ActionRead, datasources.ScopeProvider.GetResourceScopeUID(uid)).Evaluate(permissions)
+	}, nil
+}
+
+func (a *simpleSQLAuthService) GetDatasourceQueryFilter(user *user.SignedInUser) (ResourceFilter, error) {
+	if a.ac.IsDisabled() {
+		isViewer := user.HasRole(org.RoleViewer)
+		return func(uid string) bool {
+			return isViewer
+		}, nil
+	}
+
+	permissions := user.Permissions[user.OrgID]
+	return func(uid string) bool {
+		return accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid)).Evaluate(permissions)
+	}, nil
+}
@@ -30,11 +30,15 @@ const (
 	documentFieldTransformer = "transformer"
 	documentFieldDSUID       = "ds_uid"
 	documentFieldDSType      = "ds_type"
+	documentFieldDescription = "description"
+	documentFieldQuery       = "query"
 	DocumentFieldCreatedAt   = "created_at"
 	DocumentFieldUpdatedAt   = "updated_at"
+
+	documentFieldTransformerConfig = "transformer_config"
 )
 
-func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
+func initOrgIndex(dashboards []dashboard, entities []entity, logger log.Logger, extendDoc ExtendDashboardFunc) (*orgIndex, error) {
 	dashboardWriter, err := bluge.OpenWriter(bluge.InMemoryOnlyConfig())
 	if err != nil {
 		return nil, fmt.Errorf("error opening writer: %v", err)
@@ -119,6 +123,14 @@ func initOrgIndex(dashboards []dashboard, logger log.Logger, extendDoc ExtendDas
 		}
 	}
 
+	// Then the other entities (alert rules, data sources etc).
+	for _, e := range entities {
+		batch.Insert(getEntityDoc(e))
+		if err := flushIfRequired(false); err != nil {
+			return nil, err
+		}
+	}
+
 	// Flush docs in batch with force as we are in the end.
 	if err := flushIfRequired(true); err != nil {
 		return nil, err
@@ -159,6 +171,10 @@ func getNonFolderDashboardDoc(dash dashboard, location string) *bluge.Document {
 		AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, dash.created).Sortable().StoreValue()).
 		AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, dash.updated).Sortable().StoreValue())
 
+	if dash.summary.Description != "" {
+		doc.AddField(newFullTextField(documentFieldDescription, dash.summary.Description))
+	}
+
 	// dashboards only use the key part of labels
 	for k := range dash.summary.Labels {
 		doc.AddField(bluge.NewKeywordField(documentFieldTag, k).
@@ -198,6 +214,16 @@ func getDashboardPanelDocs(dash dashboard, location string) []*bluge.Document {
 			AddField(bluge.NewKeywordField(documentFieldLocation, location).Aggregatable().StoreValue()).
 			AddField(bluge.NewKeywordField(documentFieldKind, string(entityKindPanel)).Aggregatable().StoreValue()) // likely want independent index for this
 
+		if panel.Description != "" {
+			doc.AddField(newFullTextField(documentFieldDescription, panel.Description))
+		}
+		if queries, ok := panel.Fields["queries"].([]string); ok && len(queries) > 0 {
+			doc.AddField(newFullTextField(documentFieldQuery, strings.Join(queries, "\n")))
+		}
+		if config, ok := panel.Fields["transformerConfig"].([]string); ok && len(config) > 0 {
+			doc.AddField(newFullTextField(documentFieldTransformerConfig, strings.Join(config, " ")))
+		}
+
 		for _, ref := range dash.summary.References {
 			switch ref.Kind {
 			case models.StandardKindDashboard:
@@ -362,6 +388,7 @@ func doSearchQuery(
 	logger log.Logger,
 	index *orgIndex,
 	filter ResourceFilter,
+	entityFilters entityFilters,
 	q DashboardQuery,
 	extender QueryExtender,
 	appSubUrl string,
@@ -379,7 +406,7 @@ func doSearchQuery(
 
 	hasConstraints := false
 	fullQuery := bluge.NewBooleanQuery()
-	fullQuery.AddMust(newPermissionFilter(filter, logger))
+	fullQuery.AddMust(newPermissionFilter(filter, entityFilters, logger))
 
 	// Only show dashboard / folders / panels.
 	if len(q.Kind) > 0 {
@@ -426,6 +453,12 @@ func doSearchQuery(
 		hasConstraints = true
 	}
 
+	// Datasource type
+	if q.DatasourceType != "" {
+		fullQuery.AddMust(bluge.NewTermQuery(q.DatasourceType).SetField(documentFieldDSType))
+		hasConstraints = true
+	}
+
 	// Folder
 	if q.Location != "" {
 		fullQuery.AddMust(bluge.NewTermQuery(q.Location).SetField(documentFieldLocation))
@@ -451,6 +484,10 @@ func doSearchQuery(
 				SetAnalyzer(ngramQueryAnalyzer).SetBoost(1))
 		}
 
+		if q.FullText {
+			bq.AddShould(newFullTextQuery(q.Query))
+		}
+
 		fullQuery.AddMust(bq)
 	}
 
@@ -466,6 +503,9 @@ func doSearchQuery(
 	if q.Explain {
 		req.ExplainScores()
 	}
+	if q.FullText {
+		req.IncludeLocations()
+	}
 	req.WithStandardAggregations()
 
 	if q.Sort != "" {
@@ -499,6 +539,7 @@ func doSearchQuery(
 	fTags := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
 	fDSUIDs := data.NewFieldFromFieldType(data.FieldTypeJSON, 0)
 	fExplain := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
+	fHighlight := data.NewFieldFromFieldType(data.FieldTypeNullableJSON, 0)
 
 	fScore.Name = "score"
 	fUID.Name = "uid"
@@ -515,11 +556,29 @@ func doSearchQuery(
 	fDSUIDs.Name = "ds_uid"
 	fTags.Name = "tags"
 	fExplain.Name = "explain"
+	fHighlight.Name = "highlight"
 
 	frame := data.NewFrame("Query results", fKind, fUID, fName, fPType, fURL, fTags, fDSUIDs, fLocation)
 	if q.Explain {
 		frame.Fields = append(frame.Fields, fScore, fExplain)
 	}
+	if q.FullText {
+		frame.Fields = append(frame.Fields, fHighlight)
+	}
+
+	// the usage is returned when sorting by it
+	var fUsage *data.Field
+	switch header.SortBy {
+	case DocumentFieldViewsLast30Days, DocumentFieldQueriesLast30Days:
+		fUsage = data.NewFieldFromFieldType(data.FieldTypeInt64, 0)
+	case DocumentFieldLastViewedAt:
+		fUsage = data.NewFieldFromFieldType(data.FieldTypeNullableTime, 0)
+	}
+	if fUsage != nil {
+		fUsage.Name = header.SortBy
+		frame.Fields = append(frame.Fields, fUsage)
+	}
+
 	frame.SetMeta(&data.FrameMeta{
 		Type:   "search-results",
 		Custom: header,
@@ -541,6 +600,8 @@ func doSearchQuery(
 		loc := ""
 		var dsUIDs []string
 		var tags []string
+		var fullText map[string]string
+		var usage interface{}
 
 		err = match.VisitStoredFields(func(field string, value []byte) bool {
 			switch field {
@@ -560,6 +621,25 @@ func doSearchQuery(
 				dsUIDs = append(dsUIDs, string(value))
 			case documentFieldTag:
 				tags = append(tags, string(value))
+			case DocumentFieldViewsLast30Days, DocumentFieldQueriesLast30Days:
+				if field == header.SortBy {
+					if v, err := bluge.DecodeNumericFloat64(value); err == nil {
+						usage = int64(v)
+					}
+				}
+			case DocumentFieldLastViewedAt:
+				if field == header.SortBy {
+					if v, err := bluge.DecodeDateTime(value); err == nil {
+						usage = &v
+					}
+				}
+			case documentFieldDescription, documentFieldQuery, documentFieldTransformerConfig:
+				if q.FullText {
+					if fullText == nil {
+						fullText = make(map[string]string)
+					}
+					fullText[field] = string(value)
+				}
 			default:
 				ext(field, value)
 			}
@@ -571,6 +651,10 @@ func doSearchQuery(
 			return response
 		}
 
+		if entityKind(kind).isEntity() {
+			uid = entityUIDFromDocumentID(entityKind(kind), uid)
+		}
+
 		fKind.Append(kind)
 		fUID.Append(uid)
 		fPType.Append(ptype)
@@ -601,6 +685,24 @@ func doSearchQuery(
 		jsb := json.RawMessage(js)
 		fDSUIDs.Append(jsb)
 
+		if fUsage != nil {
+			if usage != nil {
+				fUsage.Append(usage)
+			} else {
+				fUsage.Extend(1)
+			}
+		}
+
+		if q.FullText {
+			if highlights := getFullTextHighlights(match.Locations, fullText); len(highlights) > 0 {
+				js, _ := json.Marshal(highlights)
+				jsb := json.RawMessage(js)
+				fHighlight.Append(&jsb)
+			} else {
+				fHighlight.Append(nil)
+			}
+		}
+
 		if q.Explain {
 			if isMatchAllQuery {
 				fScore.Append(float64(fieldLen + q.From))
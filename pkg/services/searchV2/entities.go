@@ -0,0 +1,456 @@
+package searchV2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blugelabs/bluge"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/store"
+	kdash "github.com/grafana/grafana/pkg/services/store/kind/dashboard"
+)
+
+// entity is a non-dashboard object stored in the search index. Entities are
+// updated from the entity events of their stores, see entityKindByEntityType.
+type entity struct {
+	kind        entityKind
+	uid         string
+	name        string
+	description string
+	url         string
+	location    string // folder uid for entities stored in folders
+	panelType   string
+	tags        []string
+	dsUIDs      []string
+	dsTypes     []string
+	created     time.Time
+	updated     time.Time
+}
+
+type entityLoader interface {
+	// LoadEntities returns all the indexed non-dashboard entities of an organization.
+	LoadEntities(ctx context.Context, orgID int64) ([]entity, error)
+	// LoadEntity returns a single entity, or nil when it does not exist anymore.
+	LoadEntity(ctx context.Context, orgID int64, kind entityKind, uid string) (*entity, error)
+}
+
+// entityKindByEntityType maps the types of the entity events to the indexed entity kinds.
+var entityKindByEntityType = map[store.EntityType]entityKind{
+	store.EntityTypeAlertRule:    entityKindAlertRule,
+	store.EntityTypeDatasource:   entityKindDatasource,
+	store.EntityTypeLibraryPanel: entityKindLibraryPanel,
+	store.EntityTypePlaylist:     entityKindPlaylist,
+	store.EntityTypeCorrelation:  entityKindCorrelation,
+}
+
+// entityDocumentID prefixes the uid with the kind so that entities never collide
+// with dashboards, folders or panels in the index.
+func entityDocumentID(kind entityKind, uid string) string {
+	return string(kind) + ":" + uid
+}
+
+// entityUIDFromDocumentID reverses entityDocumentID.
+func entityUIDFromDocumentID(kind entityKind, id string) string {
+	return strings.TrimPrefix(id, string(kind)+":")
+}
+
+func getEntityDoc(e entity) *bluge.Document {
+	doc := newSearchDocument(entityDocumentID(e.kind, e.uid), e.name, e.description, e.url).
+		AddField(bluge.NewKeywordField(documentFieldKind, string(e.kind)).Aggregatable().StoreValue())
+
+	if e.location != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldLocation, e.location).Aggregatable().StoreValue())
+	}
+	if e.panelType != "" {
+		doc.AddField(bluge.NewKeywordField(documentFieldPanelType, e.panelType).Aggregatable().StoreValue())
+	}
+	if !e.created.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldCreatedAt, e.created).Sortable().StoreValue())
+	}
+	if !e.updated.IsZero() {
+		doc.AddField(bluge.NewDateTimeField(DocumentFieldUpdatedAt, e.updated).Sortable().StoreValue())
+	}
+	for _, tag := range e.tags {
+		doc.AddField(bluge.NewKeywordField(documentFieldTag, tag).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	for _, uid := range e.dsUIDs {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSUID, uid).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	for _, t := range e.dsTypes {
+		doc.AddField(bluge.NewKeywordField(documentFieldDSType, t).
+			StoreValue().
+			Aggregatable().
+			SearchTermPositions())
+	}
+	return doc
+}
+
+type sqlEntityLoader struct {
+	sql    db.DB
+	logger log.Logger
+	tracer tracing.Tracer
+}
+
+func newSQLEntityLoader(sql db.DB, tracer tracing.Tracer) *sqlEntityLoader {
+	return &sqlEntityLoader{sql: sql, logger: log.New("sqlEntityLoader"), tracer: tracer}
+}
+
+func (l sqlEntityLoader) LoadEntities(ctx context.Context, orgID int64) ([]entity, error) {
+	ctx, span := l.tracer.Start(ctx, "sqlEntityLoader LoadEntities")
+	span.SetAttributes("orgID", orgID, attribute.Key("orgID").Int64(orgID))
+	defer span.End()
+
+	lookup, err := kdash.LoadDatasourceLookup(ctx, orgID, l.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity, 0)
+	for _, loader := range l.loaders() {
+		res, err := loader.load(ctx, orgID, "", lookup)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %s: %w", loader.kind, err)
+		}
+		entities = append(entities, res...)
+	}
+	return entities, nil
+}
+
+func (l sqlEntityLoader) LoadEntity(ctx context.Context, orgID int64, kind entityKind, uid string) (*entity, error) {
+	ctx, span := l.tracer.Start(ctx, "sqlEntityLoader LoadEntity")
+	span.SetAttributes("orgID", orgID, attribute.Key("orgID").Int64(orgID))
+	defer span.End()
+
+	var load entityLoadFunc
+	for _, loader := range l.loaders() {
+		if loader.kind == kind {
+			load = loader.load
+		}
+	}
+	if load == nil {
+		return nil, fmt.Errorf("unknown entity kind: %s", kind)
+	}
+
+	lookup, err := kdash.LoadDatasourceLookup(ctx, orgID, l.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := load(ctx, orgID, uid, lookup)
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+// entityLoadFunc loads the entities of an organization, or a single one when uid is set.
+type entityLoadFunc func(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]entity, error)
+
+type entityKindLoader struct {
+	kind entityKind
+	load entityLoadFunc
+}
+
+func (l sqlEntityLoader) loaders() []entityKindLoader {
+	return []entityKindLoader{
+		{kind: entityKindDatasource, load: l.loadDatasources},
+		{kind: entityKindAlertRule, load: l.loadAlertRules},
+		{kind: entityKindLibraryPanel, load: l.loadLibraryPanels},
+		{kind: entityKindPlaylist, load: l.loadPlaylists},
+		{kind: entityKindCorrelation, load: l.loadCorrelations},
+	}
+}
+
+type datasourceEntityQueryResult struct {
+	UID     string    `xorm:"uid"`
+	Name    string    `xorm:"name"`
+	Type    string    `xorm:"type"`
+	Created time.Time `xorm:"created"`
+	Updated time.Time `xorm:"updated"`
+}
+
+func (l sqlEntityLoader) loadDatasources(ctx context.Context, orgID int64, uid string, _ kdash.DatasourceLookup) ([]entity, error) {
+	rows := make([]*datasourceEntityQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("data_source").
+			Where("org_id = ?", orgID).
+			Cols("uid", "name", "type", "created", "updated")
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity, 0, len(rows))
+	for _, row := range rows {
+		// a data source references itself, so that searching by data source
+		// returns it along with everything using it
+		entities = append(entities, entity{
+			kind:    entityKindDatasource,
+			uid:     row.UID,
+			name:    row.Name,
+			url:     "/datasources/edit/" + row.UID,
+			dsUIDs:  []string{row.UID},
+			dsTypes: []string{row.Type},
+			created: row.Created,
+			updated: row.Updated,
+		})
+	}
+	return entities, nil
+}
+
+type alertRuleEntityQueryResult struct {
+	UID          string    `xorm:"uid"`
+	Title        string    `xorm:"title"`
+	NamespaceUID string    `xorm:"namespace_uid"`
+	Labels       string    `xorm:"labels"`
+	Data         string    `xorm:"data"`
+	Updated      time.Time `xorm:"updated"`
+}
+
+type alertRuleQuery struct {
+	DatasourceUID string `json:"datasourceUid"`
+}
+
+func (l sqlEntityLoader) loadAlertRules(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]entity, error) {
+	rows := make([]*alertRuleEntityQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("alert_rule").
+			Where("org_id = ?", orgID).
+			Cols("uid", "title", "namespace_uid", "labels", "data", "updated")
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity, 0, len(rows))
+	for _, row := range rows {
+		e := entity{
+			kind:     entityKindAlertRule,
+			uid:      row.UID,
+			name:     row.Title,
+			url:      "/alerting/grafana/" + row.UID + "/view",
+			location: row.NamespaceUID,
+			updated:  row.Updated,
+		}
+
+		if row.Labels != "" {
+			labels := map[string]string{}
+			if err := json.Unmarshal([]byte(row.Labels), &labels); err != nil {
+				l.logger.Warn("Error reading alert rule labels", "error", err, "ruleUid", row.UID)
+			}
+			for k, v := range labels {
+				e.tags = append(e.tags, k+"="+v)
+			}
+		}
+
+		var queries []alertRuleQuery
+		if err := json.Unmarshal([]byte(row.Data), &queries); err != nil {
+			l.logger.Warn("Error reading alert rule queries", "error", err, "ruleUid", row.UID)
+		}
+		for _, q := range queries {
+			// skip server side expressions
+			if q.DatasourceUID == "" || q.DatasourceUID == "__expr__" || q.DatasourceUID == "-100" {
+				continue
+			}
+			e.addDatasource(q.DatasourceUID, lookup)
+		}
+
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type libraryPanelEntityQueryResult struct {
+	UID         string    `xorm:"uid"`
+	Name        string    `xorm:"name"`
+	Description string    `xorm:"description"`
+	FolderUID   string    `xorm:"folder_uid"`
+	Model       []byte    `xorm:"model"`
+	Created     time.Time `xorm:"created"`
+	Updated     time.Time `xorm:"updated"`
+}
+
+func (l sqlEntityLoader) loadLibraryPanels(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]entity, error) {
+	rows := make([]*libraryPanelEntityQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := `SELECT le.uid, le.name, le.description, COALESCE(d.uid, '') AS folder_uid, le.model, le.created, le.updated
+			FROM library_element AS le
+			LEFT JOIN dashboard AS d ON d.id = le.folder_id AND d.org_id = le.org_id
+			WHERE le.org_id = ? AND le.kind = ?`
+		params := []interface{}{orgID, 1}
+		if uid != "" {
+			sql += " AND le.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	builder := kdash.NewStaticDashboardSummaryBuilder(lookup, false)
+	entities := make([]entity, 0, len(rows))
+	for _, row := range rows {
+		e := entity{
+			kind:        entityKindLibraryPanel,
+			uid:         row.UID,
+			name:        row.Name,
+			description: row.Description,
+			url:         "/library-panels",
+			location:    row.FolderUID,
+			created:     row.Created,
+			updated:     row.Updated,
+		}
+
+		// read the panel model as a single panel dashboard to reuse the dashboard reference extraction
+		body, err := json.Marshal(map[string]interface{}{"panels": []json.RawMessage{row.Model}})
+		if err == nil {
+			var summary *models.EntitySummary
+			summary, _, err = builder(ctx, row.UID, body)
+			if summary != nil {
+				for _, panel := range summary.Nested {
+					for _, ref := range panel.References {
+						switch ref.Kind {
+						case models.StandardKindDataSource:
+							if ref.UID != "" {
+								e.addDatasource(ref.UID, lookup)
+							}
+						case models.ExternalEntityReferencePlugin:
+							if ref.Type == models.StandardKindPanel {
+								e.panelType = ref.UID
+							}
+						}
+					}
+				}
+			}
+		}
+		if err != nil {
+			l.logger.Warn("Error reading library panel model", "error", err, "libraryPanelUid", row.UID)
+		}
+
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+type playlistEntityQueryResult struct {
+	UID  string `xorm:"uid"`
+	Name string `xorm:"name"`
+}
+
+func (l sqlEntityLoader) loadPlaylists(ctx context.Context, orgID int64, uid string, _ kdash.DatasourceLookup) ([]entity, error) {
+	rows := make([]*playlistEntityQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Table("playlist").
+			Where("org_id = ?", orgID).
+			Cols("uid", "name")
+		if uid != "" {
+			sess.Where("uid = ?", uid)
+		}
+		return sess.Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity, 0, len(rows))
+	for _, row := range rows {
+		entities = append(entities, entity{
+			kind: entityKindPlaylist,
+			uid:  row.UID,
+			name: row.Name,
+			url:  "/playlists/play/" + row.UID,
+		})
+	}
+	return entities, nil
+}
+
+type correlationEntityQueryResult struct {
+	UID         string `xorm:"uid"`
+	SourceUID   string `xorm:"source_uid"`
+	TargetUID   string `xorm:"target_uid"`
+	Label       string `xorm:"label"`
+	Description string `xorm:"description"`
+}
+
+func (l sqlEntityLoader) loadCorrelations(ctx context.Context, orgID int64, uid string, lookup kdash.DatasourceLookup) ([]entity, error) {
+	rows := make([]*correlationEntityQueryResult, 0)
+	err := l.sql.WithDbSession(ctx, func(sess *db.Session) error {
+		sql := `SELECT c.uid, c.source_uid, COALESCE(c.target_uid, '') AS target_uid, c.label, c.description
+			FROM correlation AS c
+			INNER JOIN data_source AS ds ON ds.uid = c.source_uid
+			WHERE ds.org_id = ?`
+		params := []interface{}{orgID}
+		if uid != "" {
+			sql += " AND c.uid = ?"
+			params = append(params, uid)
+		}
+		return sess.SQL(sql, params...).Find(&rows)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entities := make([]entity, 0, len(rows))
+	for _, row := range rows {
+		e := entity{
+			kind:        entityKindCorrelation,
+			uid:         row.UID,
+			name:        row.Label,
+			description: row.Description,
+			url:         "/datasources/correlations",
+		}
+		e.addDatasource(row.SourceUID, lookup)
+		if row.TargetUID != "" && row.TargetUID != row.SourceUID {
+			e.addDatasource(row.TargetUID, lookup)
+		}
+		entities = append(entities, e)
+	}
+	return entities, nil
+}
+
+// addDatasource references a data source once, along with its type when known.
+func (e *entity) addDatasource(uid string, lookup kdash.DatasourceLookup) {
+	for _, existing := range e.dsUIDs {
+		if existing == uid {
+			return
+		}
+	}
+	e.dsUIDs = append(e.dsUIDs, uid)
+
+	if lookup == nil {
+		return
+	}
+	ref := lookup.ByRef(&kdash.DataSourceRef{UID: uid})
+	if ref == nil || ref.Type == "" {
+		return
+	}
+	for _, t := range e.dsTypes {
+		if t == ref.Type {
+			return
+		}
+	}
+	e.dsTypes = append(e.dsTypes, ref.Type)
+}
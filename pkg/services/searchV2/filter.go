@@ -12,8 +12,9 @@ import (
 )
 
 type PermissionFilter struct {
-	log    log.Logger
-	filter ResourceFilter
+	log           log.Logger
+	filter        ResourceFilter
+	entityFilters entityFilters
 }
 
 type entityKind string
@@ -24,18 +25,29 @@ const (
 	entityKindFolder     entityKind = models.StandardKindFolder
 	entityKindDatasource entityKind = models.StandardKindDataSource
 	entityKindQuery      entityKind = models.StandardKindQuery
+
+	entityKindAlertRule    entityKind = "alert-rule"
+	entityKindLibraryPanel entityKind = "library-panel"
+	entityKindPlaylist     entityKind = models.StandardKindPlaylist
+	entityKindCorrelation  entityKind = "correlation"
 )
 
 func (r entityKind) IsValid() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isEntity()
 }
 
 func (r entityKind) supportsAuthzCheck() bool {
-	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder
+	return r == entityKindPanel || r == entityKindDashboard || r == entityKindFolder || r.isEntity()
+}
+
+// isEntity returns true for the kinds indexed from other tables than the dashboard table
+func (r entityKind) isEntity() bool {
+	return r == entityKindAlertRule || r == entityKindDatasource || r == entityKindLibraryPanel ||
+		r == entityKindPlaylist || r == entityKindCorrelation
 }
 
 var (
-	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind}
+	permissionFilterFields                 = []string{documentFieldUID, documentFieldKind, documentFieldLocation, documentFieldDSUID}
 	panelIdFieldRegex                      = regexp.MustCompile(`^(.*)#([0-9]{1,4})$`)
 	panelIdFieldDashboardUidSubmatchIndex  = 1
 	panelIdFieldPanelIdSubmatchIndex       = 2
@@ -44,10 +56,11 @@ var (
 	_ bluge.Query = (*PermissionFilter)(nil)
 )
 
-func newPermissionFilter(resourceFilter ResourceFilter, log log.Logger) *PermissionFilter {
+func newPermissionFilter(resourceFilter ResourceFilter, entityFilters entityFilters, log log.Logger) *PermissionFilter {
 	return &PermissionFilter{
-		filter: resourceFilter,
-		log:    log,
+		filter:        resourceFilter,
+		entityFilters: entityFilters,
+		log:           log,
 	}
 }
 
@@ -64,7 +77,7 @@ func (q *PermissionFilter) logAccessDecision(decision bool, kind interface{}, id
 	}
 }
 
-func (q *PermissionFilter) canAccess(kind entityKind, id string) bool {
+func (q *PermissionFilter) canAccess(kind entityKind, id string, location string, dsUIDs []string) bool {
 	if !kind.supportsAuthzCheck() {
 		q.logAccessDecision(false, kind, id, "entityDoesNotSupportAuthz")
 		return false
@@ -97,6 +110,39 @@ func (q *PermissionFilter) canAccess(kind entityKind, id string) bool {
 
 		q.logAccessDecision(decision, kind, id, "resourceFilter", "dashboardUid", dashboardUid, "panelId", matches[panelIdFieldPanelIdSubmatchIndex])
 		return decision
+	case entityKindAlertRule:
+		// alert rules are read in their folder, and need all the data sources they query
+		if !q.entityFilters.alertRule(location) {
+			q.logAccessDecision(false, kind, id, "alertRuleFilter", "folderUid", location)
+			return false
+		}
+		for _, dsUID := range dsUIDs {
+			if !q.entityFilters.datasourceQuery(dsUID) {
+				q.logAccessDecision(false, kind, id, "datasourceQueryFilter", "dsUid", dsUID)
+				return false
+			}
+		}
+		q.logAccessDecision(true, kind, id, "alertRuleFilter", "folderUid", location)
+		return true
+	case entityKindLibraryPanel:
+		// library panels are read with their folder
+		decision := q.entityFilters.folder(location)
+		q.logAccessDecision(decision, kind, id, "folderFilter", "folderUid", location)
+		return decision
+	case entityKindDatasource, entityKindCorrelation:
+		// data sources reference themselves, and correlations need all their data sources
+		for _, dsUID := range dsUIDs {
+			if !q.entityFilters.datasource(dsUID) {
+				q.logAccessDecision(false, kind, id, "datasourceFilter", "dsUid", dsUID)
+				return false
+			}
+		}
+		decision := len(dsUIDs) > 0
+		q.logAccessDecision(decision, kind, id, "datasourceFilter")
+		return decision
+	case entityKindPlaylist:
+		q.logAccessDecision(true, kind, id, "orgEntity")
+		return true
 	default:
 		q.logAccessDecision(false, kind, id, "reason", "unknownKind")
 		return false
@@ -111,12 +157,18 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 
 	s, err := searcher.NewMatchAllSearcher(i, 1, similarity.ConstantScorer(1), options)
 	return searcher.NewFilteringSearcher(s, func(d *search.DocumentMatch) bool {
-		var kind, id string
+		var kind, id, location string
+		var dsUIDs []string
 		err := dvReader.VisitDocumentValues(d.Number, func(field string, term []byte) {
-			if field == documentFieldKind {
+			switch field {
+			case documentFieldKind:
 				kind = string(term)
-			} else if field == documentFieldUID {
+			case documentFieldUID:
 				id = string(term)
+			case documentFieldLocation:
+				location = string(term)
+			case documentFieldDSUID:
+				dsUIDs = append(dsUIDs, string(term))
 			}
 		})
 		if err != nil {
@@ -130,6 +182,6 @@ func (q *PermissionFilter) Searcher(i search.Reader, options search.SearcherOpti
 			return false
 		}
 
-		return q.canAccess(e, id)
+		return q.canAccess(e, id, location, dsUIDs)
 	}), err
 }
@@ -0,0 +1,70 @@
+package searchV2
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/blugelabs/bluge"
+	"github.com/blugelabs/bluge/analysis"
+	"github.com/blugelabs/bluge/analysis/token"
+	"github.com/blugelabs/bluge/analysis/tokenizer"
+	"github.com/blugelabs/bluge/search"
+	"github.com/blugelabs/bluge/search/highlight"
+)
+
+// fullTextFields are the searchable text fields which are only matched when
+// the query asks for full-text search
+var fullTextFields = []string{documentFieldDescription, documentFieldQuery, documentFieldTransformerConfig}
+
+// fullTextAnalyzer splits text on anything which can not be part of an
+// identifier, so that metric, table and column names found in query expressions
+// (`rate(http_requests_total[5m])`, `SELECT * FROM db.users`) are indexed as terms.
+var fullTextAnalyzer = &analysis.Analyzer{
+	Tokenizer: tokenizer.NewCharacterTokenizer(func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	}),
+	TokenFilters: []analysis.TokenFilter{
+		token.NewLowerCaseFilter(),
+	},
+}
+
+var fullTextHighlighter = highlight.NewHTMLHighlighter()
+
+func newFullTextField(name string, value string) *bluge.TermField {
+	return bluge.NewTextField(name, value).
+		WithAnalyzer(fullTextAnalyzer).
+		StoreValue().
+		SearchTermPositions()
+}
+
+func newFullTextQuery(q string) bluge.Query {
+	bq := bluge.NewBooleanQuery()
+	for _, field := range fullTextFields {
+		bq.AddShould(bluge.NewMatchQuery(q).
+			SetField(field).
+			SetOperator(bluge.MatchQueryOperatorAnd). // all terms must match
+			SetAnalyzer(fullTextAnalyzer))
+	}
+	return bq
+}
+
+// getFullTextHighlights returns the best matching snippet of each full-text field,
+// with the matched terms wrapped in <mark> tags
+func getFullTextHighlights(locations search.FieldTermLocationMap, values map[string]string) map[string]string {
+	highlights := make(map[string]string)
+	for _, field := range fullTextFields {
+		tlm, ok := locations[field]
+		if !ok || len(tlm) == 0 {
+			continue
+		}
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+		fragment := strings.TrimSpace(fullTextHighlighter.BestFragment(tlm, []byte(value)))
+		if fragment != "" {
+			highlights[field] = fragment
+		}
+	}
+	return highlights
+}
@@ -89,6 +89,7 @@ func (i *orgIndex) readerForIndex(idxType indexType) (*bluge.Reader, func(), err
 type searchIndex struct {
 	mu                      sync.RWMutex
 	loader                  dashboardLoader
+	entityLoader            entityLoader
 	perOrgIndex             map[int64]*orgIndex
 	initializedOrgs         map[int64]bool
 	initialIndexingComplete bool
@@ -104,9 +105,10 @@ type searchIndex struct {
 	settings                setting.SearchSettings
 }
 
-func newSearchIndex(dashLoader dashboardLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
+func newSearchIndex(dashLoader dashboardLoader, entLoader entityLoader, evStore eventStore, extender DocumentExtender, folderIDs folderUIDLookup, tracer tracing.Tracer, features featuremgmt.FeatureToggles, settings setting.SearchSettings) *searchIndex {
 	return &searchIndex{
 		loader:          dashLoader,
+		entityLoader:    entLoader,
 		eventStore:      evStore,
 		perOrgIndex:     map[int64]*orgIndex{},
 		initializedOrgs: map[int64]bool{},
@@ -486,13 +488,22 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 	}
 	i.logger.Info("Finish loading org dashboards", "elapsed", orgSearchIndexLoadTime, "orgId", orgID)
 
+	var entities []entity
+	if i.entityLoader != nil {
+		entities, err = i.entityLoader.LoadEntities(ctx, orgID)
+		if err != nil {
+			return 0, fmt.Errorf("error loading entities: %w", err)
+		}
+		orgSearchIndexLoadTime = time.Since(started)
+	}
+
 	dashboardExtender := i.extender.GetDashboardExtender(orgID)
 
 	_, initOrgIndexSpan := i.tracer.Start(ctx, "searchV2 buildOrgIndex init org index")
 	initOrgIndexSpan.SetAttributes("org_id", orgID, attribute.Key("org_id").Int64(orgID))
 	initOrgIndexSpan.SetAttributes("dashboardCount", len(dashboards), attribute.Key("dashboardCount").Int(len(dashboards)))
 
-	index, err := initOrgIndex(dashboards, i.logger, dashboardExtender)
+	index, err := initOrgIndex(dashboards, entities, i.logger, dashboardExtender)
 
 	initOrgIndexSpan.End()
 
@@ -507,7 +518,8 @@ func (i *searchIndex) buildOrgIndex(ctx context.Context, orgID int64) (int, erro
 			"orgSearchIndexLoadTime", orgSearchIndexLoadTime,
 			"orgSearchIndexBuildTime", orgSearchIndexBuildTime,
 			"orgSearchIndexTotalTime", orgSearchIndexTotalTime,
-			"orgSearchDashboardCount", len(dashboards))...)
+			"orgSearchDashboardCount", len(dashboards),
+			"orgSearchEntityCount", len(entities))...)
 
 	i.mu.Lock()
 	if oldIndex, ok := i.perOrgIndex[orgID]; ok {
@@ -654,6 +666,10 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	}
 	i.mu.Unlock()
 
+	if entKind, ok := entityKindByEntityType[kind]; ok {
+		return i.applyEntityEvent(ctx, orgID, entKind, uid)
+	}
+
 	// Both dashboard and folder share same DB table.
 	dbDashboards, err := i.loader.LoadDashboards(ctx, orgID, uid)
 	if err != nil {
@@ -688,6 +704,36 @@ func (i *searchIndex) applyEvent(ctx context.Context, orgID int64, kind store.En
 	return nil
 }
 
+func (i *searchIndex) applyEntityEvent(ctx context.Context, orgID int64, kind entityKind, uid string) error {
+	if i.entityLoader == nil {
+		return nil
+	}
+
+	e, err := i.entityLoader.LoadEntity(ctx, orgID, kind, uid)
+	if err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	index, ok := i.perOrgIndex[orgID]
+	if !ok {
+		// Skip event for org not yet fully indexed.
+		return nil
+	}
+
+	writer := index.writerForIndex(indexTypeDashboard)
+	batch := bluge.NewBatch()
+	if e == nil {
+		batch.Delete(bluge.NewDocument(entityDocumentID(kind, uid)).ID())
+	} else {
+		doc := getEntityDoc(*e)
+		batch.Update(doc.ID(), doc)
+	}
+	return writer.Batch(batch)
+}
+
 func (i *searchIndex) removeDashboard(_ context.Context, index *orgIndex, dashboardUID string) error {
 	dashboardLocation, ok, err := getDashboardLocation(index, dashboardUID)
 	if err != nil {
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
+	"github.com/grafana/grafana/pkg/services/dashboardusage/dashboardusagetest"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
 
@@ -29,6 +32,23 @@ func (t *testDashboardLoader) LoadDashboards(_ context.Context, _ int64, _ strin
 	return t.dashboards, nil
 }
 
+type testEntityLoader struct {
+	entities []entity
+}
+
+func (t *testEntityLoader) LoadEntities(_ context.Context, _ int64) ([]entity, error) {
+	return t.entities, nil
+}
+
+func (t *testEntityLoader) LoadEntity(_ context.Context, _ int64, kind entityKind, uid string) (*entity, error) {
+	for _, e := range t.entities {
+		if e.kind == kind && e.uid == uid {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
 var testLogger = log.New("index-test-logger")
 
 var testAllowAllFilter = func(uid string) bool {
@@ -39,6 +59,11 @@ var testDisallowAllFilter = func(uid string) bool {
 	return false
 }
 
+// testEntityFilters uses the same filter for all the entities
+func testEntityFilters(filter ResourceFilter) entityFilters {
+	return entityFilters{folder: filter, alertRule: filter, datasource: filter, datasourceQuery: filter}
+}
+
 var testOrgID int64 = 1
 
 func initTestOrgIndexFromDashes(t *testing.T, dashboards []dashboard) *orgIndex {
@@ -59,11 +84,22 @@ func initTestIndexFromDashes(t *testing.T, dashboards []dashboard) *searchIndex
 }
 
 func initTestIndexFromDashesExtended(t *testing.T, dashboards []dashboard, extender DocumentExtender) *searchIndex {
+	t.Helper()
+	return initTestIndexFromDashesAndEntities(t, dashboards, nil, extender)
+}
+
+func initTestOrgIndexFromDashesAndEntities(t *testing.T, dashboards []dashboard, entities []entity) *orgIndex {
+	t.Helper()
+	searchIdx := initTestIndexFromDashesAndEntities(t, dashboards, &testEntityLoader{entities: entities}, &NoopDocumentExtender{})
+	return searchIdx.perOrgIndex[testOrgID]
+}
+
+func initTestIndexFromDashesAndEntities(t *testing.T, dashboards []dashboard, entLoader entityLoader, extender DocumentExtender) *searchIndex {
 	t.Helper()
 	dashboardLoader := &testDashboardLoader{
 		dashboards: dashboards,
 	}
-	index := newSearchIndex(dashboardLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
+	index := newSearchIndex(dashboardLoader, entLoader, &store.MockEntityEventsService{}, extender, func(ctx context.Context, folderId int64) (string, error) { return "x", nil }, tracing.InitializeTracerForTest(), featuremgmt.WithFeatures(), setting.SearchSettings{})
 	require.NotNil(t, index)
 	numDashboards, err := index.buildOrgIndex(context.Background(), testOrgID)
 	require.NoError(t, err)
@@ -78,7 +114,7 @@ func checkSearchResponse(t *testing.T, fileName string, index *orgIndex, filter
 
 func checkSearchResponseExtended(t *testing.T, fileName string, index *orgIndex, filter ResourceFilter, query DashboardQuery, extender QueryExtender) {
 	t.Helper()
-	resp := doSearchQuery(context.Background(), testLogger, index, filter, query, extender, "/pfix")
+	resp := doSearchQuery(context.Background(), testLogger, index, filter, testEntityFilters(filter), query, extender, "/pfix")
 	experimental.CheckGoldenJSONResponse(t, "testdata", fileName, resp, true)
 }
 
@@ -105,7 +141,7 @@ func checkSearchResponseOrdering(t *testing.T, fileName string, index *orgIndex,
 func checkSearchResponseOrderingExtended(t *testing.T, fileName string, index *orgIndex, filter ResourceFilter, query DashboardQuery, extender QueryExtender) {
 	t.Helper()
 	query.Explain = true
-	resp := doSearchQuery(context.Background(), testLogger, index, filter, query, extender, "/pfix")
+	resp := doSearchQuery(context.Background(), testLogger, index, filter, testEntityFilters(filter), query, extender, "/pfix")
 	experimental.CheckGoldenJSONFrame(t, "testdata", fileName, getFrameWithNames(resp), true)
 }
 
@@ -284,6 +320,37 @@ func TestDashboardIndexSort(t *testing.T) {
 	})
 }
 
+func TestDashboardIndexSort_Usage(t *testing.T) {
+	lastViewed := time.Date(2022, time.November, 1, 12, 0, 0, 0, time.UTC)
+	usage := dashboardusagetest.NewDashboardUsageServiceFake()
+	usage.ExpectedSummaries = map[string]*dashboardusage.UsageSummary{
+		"1": {DashboardUID: "1", Views: 3, Queries: 30},
+		"2": {DashboardUID: "2", Views: 7, Queries: 10, LastViewed: &lastViewed},
+	}
+	extender := newUsageDocumentExtender(usage, &NoopDocumentExtender{})
+
+	t.Run("sort-views-desc", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesExtended(t, testSortDashboards, extender)
+		checkSearchResponseExtended(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Query: "*", Sort: "-" + DocumentFieldViewsLast30Days}, &NoopQueryExtender{},
+		)
+	})
+
+	t.Run("sort-queries-desc", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesExtended(t, testSortDashboards, extender)
+		checkSearchResponseExtended(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Query: "*", Sort: "-" + DocumentFieldQueriesLast30Days}, &NoopQueryExtender{},
+		)
+	})
+
+	t.Run("sort-last-viewed-desc", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesExtended(t, testSortDashboards, extender)
+		checkSearchResponseExtended(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Query: "*", Sort: "-" + DocumentFieldLastViewedAt}, &NoopQueryExtender{},
+		)
+	})
+}
+
 var testPrefixDashboards = []dashboard{
 	{
 		id:  1,
@@ -467,7 +534,7 @@ func TestDashboardIndex_Folders(t *testing.T) {
 	t.Run("folders-dashboard-has-folder", func(t *testing.T) {
 		index := initTestOrgIndexFromDashes(t, dashboardsWithFolders)
 		// TODO: golden file compare does not work here.
-		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter,
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, testEntityFilters(testAllowAllFilter),
 			DashboardQuery{Query: "Dashboard in folder", Kind: []string{string(entityKindDashboard)}},
 			&NoopQueryExtender{}, "")
 		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
@@ -492,7 +559,7 @@ func TestDashboardIndex_Folders(t *testing.T) {
 		require.True(t, ok)
 		err := index.removeFolder(context.Background(), orgIdx, "1")
 		require.NoError(t, err)
-		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter,
+		resp := doSearchQuery(context.Background(), testLogger, orgIdx, testAllowAllFilter, testEntityFilters(testAllowAllFilter),
 			DashboardQuery{Query: "Panel", Kind: []string{string(entityKindPanel)}},
 			&NoopQueryExtender{}, "")
 		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
@@ -529,7 +596,7 @@ func TestDashboardIndex_Panels(t *testing.T) {
 		index := initTestOrgIndexFromDashes(t, dashboardsWithPanels)
 		// TODO: golden file compare does not work here.
 		resp := doSearchQuery(
-			context.Background(), testLogger, index, testAllowAllFilter,
+			context.Background(), testLogger, index, testAllowAllFilter, testEntityFilters(testAllowAllFilter),
 			DashboardQuery{Query: "Panel", Kind: []string{string(entityKindPanel)}},
 			&NoopQueryExtender{}, "")
 		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
@@ -732,3 +799,281 @@ func TestDashboardIndex_MultiTermPrefixMatch(t *testing.T) {
 		})
 	}
 }
+
+var dashboardsWithDatasources = []dashboard{
+	{
+		id:       1,
+		uid:      "1",
+		isFolder: true,
+		summary: &models.EntitySummary{
+			Name: "My folder",
+		},
+	},
+	{
+		id:  2,
+		uid: "2",
+		summary: &models.EntitySummary{
+			Name: "Prometheus dashboard",
+			References: []*models.EntityExternalReference{
+				{Kind: models.StandardKindDataSource, Type: "prometheus", UID: "prom"},
+			},
+		},
+	},
+}
+
+var testEntities = []entity{
+	{
+		kind:    entityKindDatasource,
+		uid:     "prom",
+		name:    "Prometheus",
+		url:     "/datasources/edit/prom",
+		dsUIDs:  []string{"prom"},
+		dsTypes: []string{"prometheus"},
+	},
+	{
+		kind:    entityKindDatasource,
+		uid:     "loki",
+		name:    "Loki",
+		url:     "/datasources/edit/loki",
+		dsUIDs:  []string{"loki"},
+		dsTypes: []string{"loki"},
+	},
+	{
+		kind:     entityKindAlertRule,
+		uid:      "rule",
+		name:     "High CPU usage",
+		url:      "/alerting/grafana/rule/view",
+		location: "1",
+		tags:     []string{"severity=critical"},
+		dsUIDs:   []string{"prom"},
+		dsTypes:  []string{"prometheus"},
+	},
+	{
+		kind:      entityKindLibraryPanel,
+		uid:       "libpanel",
+		name:      "Prometheus library panel",
+		url:       "/library-panels",
+		panelType: "timeseries",
+		dsUIDs:    []string{"prom"},
+		dsTypes:   []string{"prometheus"},
+	},
+	{
+		kind: entityKindPlaylist,
+		uid:  "playlist",
+		name: "Morning playlist",
+		url:  "/playlists/play/playlist",
+	},
+	{
+		kind:    entityKindCorrelation,
+		uid:     "correlation",
+		name:    "Prometheus to Loki",
+		url:     "/datasources/correlations",
+		dsUIDs:  []string{"prom", "loki"},
+		dsTypes: []string{"prometheus", "loki"},
+	},
+}
+
+func TestDashboardIndex_Entities(t *testing.T) {
+	t.Run("entities-by-datasource", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		checkSearchResponse(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Datasource: "prom", Sort: "name_sort"},
+		)
+	})
+	t.Run("entities-by-kind", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		checkSearchResponse(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Kind: []string{string(entityKindAlertRule), string(entityKindPlaylist)}, Sort: "name_sort"},
+		)
+	})
+	t.Run("entities-by-datasource-type", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, testEntityFilters(testAllowAllFilter),
+			DashboardQuery{DatasourceType: "loki"},
+			&NoopQueryExtender{}, "")
+		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
+		require.True(t, ok)
+		require.Equal(t, uint64(2), custom.Count) // the loki data source and the correlation
+	})
+	t.Run("entities-filtered-by-permissions", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		promOnly := func(uid string) bool {
+			return uid == "prom"
+		}
+		found := searchEntityNames(t, index, testDisallowAllFilter, entityFilters{
+			folder:          testDisallowAllFilter,
+			alertRule:       testDisallowAllFilter,
+			datasource:      promOnly,
+			datasourceQuery: testAllowAllFilter,
+		}, DashboardQuery{Sort: "name_sort"})
+		// The alert rule and the library panel are in folders the user cannot read, the correlation needs loki.
+		require.Equal(t, []string{"Morning playlist", "Prometheus"}, found)
+	})
+	t.Run("alert-rules-need-folder-and-datasource-query-access", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		folderOne := func(uid string) bool {
+			return uid == "1"
+		}
+		query := DashboardQuery{Kind: []string{string(entityKindAlertRule)}}
+
+		found := searchEntityNames(t, index, testAllowAllFilter, entityFilters{
+			folder:          testAllowAllFilter,
+			alertRule:       folderOne,
+			datasource:      testAllowAllFilter,
+			datasourceQuery: testAllowAllFilter,
+		}, query)
+		require.Equal(t, []string{"High CPU usage"}, found)
+
+		// reading the folder is not enough to read its alert rules
+		found = searchEntityNames(t, index, testAllowAllFilter, entityFilters{
+			folder:          testAllowAllFilter,
+			alertRule:       testDisallowAllFilter,
+			datasource:      testAllowAllFilter,
+			datasourceQuery: testAllowAllFilter,
+		}, query)
+		require.Empty(t, found)
+
+		// the rule queries prometheus
+		found = searchEntityNames(t, index, testAllowAllFilter, entityFilters{
+			folder:    testAllowAllFilter,
+			alertRule: folderOne,
+			// reading a data source is not enough to query it
+			datasource: testAllowAllFilter,
+			datasourceQuery: func(uid string) bool {
+				return uid == "loki"
+			},
+		}, query)
+		require.Empty(t, found)
+	})
+	t.Run("library-panels-use-the-folder-filter", func(t *testing.T) {
+		index := initTestOrgIndexFromDashesAndEntities(t, dashboardsWithDatasources, testEntities)
+		query := DashboardQuery{Kind: []string{string(entityKindLibraryPanel)}}
+		generalFolder := func(uid string) bool {
+			return uid == ""
+		}
+
+		// the dashboard filter does not give access to the library panels
+		found := searchEntityNames(t, index, testAllowAllFilter, entityFilters{
+			folder:          testDisallowAllFilter,
+			alertRule:       testAllowAllFilter,
+			datasource:      testAllowAllFilter,
+			datasourceQuery: testAllowAllFilter,
+		}, query)
+		require.Empty(t, found)
+
+		found = searchEntityNames(t, index, testDisallowAllFilter, entityFilters{
+			folder:          generalFolder,
+			alertRule:       testDisallowAllFilter,
+			datasource:      testDisallowAllFilter,
+			datasourceQuery: testDisallowAllFilter,
+		}, query)
+		require.Equal(t, []string{"Prometheus library panel"}, found)
+	})
+}
+
+func TestDashboardIndexEntityUpdates(t *testing.T) {
+	setup := func(t *testing.T) (*searchIndex, *testEntityLoader) {
+		loader := &testEntityLoader{entities: append([]entity{}, testEntities...)}
+		index := initTestIndexFromDashesAndEntities(t, dashboardsWithDatasources, loader, &NoopDocumentExtender{})
+		return index, loader
+	}
+	search := func(t *testing.T, index *searchIndex, kind entityKind) []string {
+		orgIdx, ok := index.getOrgIndex(testOrgID)
+		require.True(t, ok)
+		return searchEntityNames(t, orgIdx, testAllowAllFilter, testEntityFilters(testAllowAllFilter), DashboardQuery{Kind: []string{string(kind)}, Sort: "name_sort"})
+	}
+
+	t.Run("entity-update", func(t *testing.T) {
+		index, loader := setup(t)
+		loader.entities[2].name = "Low CPU usage"
+
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypeAlertRule, "rule", store.EntityEventTypeUpdate)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Low CPU usage"}, search(t, index, entityKindAlertRule))
+	})
+
+	t.Run("entity-create", func(t *testing.T) {
+		index, loader := setup(t)
+		loader.entities = append(loader.entities, entity{kind: entityKindPlaylist, uid: "evening", name: "Evening playlist"})
+
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypePlaylist, "evening", store.EntityEventTypeCreate)
+		require.NoError(t, err)
+		require.Equal(t, []string{"Evening playlist", "Morning playlist"}, search(t, index, entityKindPlaylist))
+	})
+
+	t.Run("entity-delete", func(t *testing.T) {
+		index, loader := setup(t)
+		loader.entities = loader.entities[:5] // without the correlation
+
+		err := index.applyEvent(context.Background(), testOrgID, store.EntityTypeCorrelation, "correlation", store.EntityEventTypeDelete)
+		require.NoError(t, err)
+		require.Empty(t, search(t, index, entityKindCorrelation))
+		require.Equal(t, []string{"Loki", "Prometheus"}, search(t, index, entityKindDatasource))
+	})
+}
+
+func searchEntityNames(t *testing.T, index *orgIndex, filter ResourceFilter, filters entityFilters, query DashboardQuery) []string {
+	t.Helper()
+	resp := doSearchQuery(context.Background(), testLogger, index, filter, filters, query, &NoopQueryExtender{}, "")
+	require.NoError(t, resp.Error)
+	names, _ := resp.Frames[0].FieldByName(documentFieldName)
+	var found []string
+	for i := 0; i < names.Len(); i++ {
+		found = append(found, names.At(i).(string))
+	}
+	return found
+}
+
+var dashboardsWithPanelQueries = []dashboard{
+	{
+		id:  1,
+		uid: "1",
+		summary: &models.EntitySummary{
+			Name: "Requests",
+			Nested: []*models.EntitySummary{
+				{
+					Kind:        "panel",
+					UID:         "1#1",
+					Name:        "Request rate",
+					Description: "Rate of the http requests, per handler",
+					Fields: map[string]interface{}{
+						"queries": []string{"sum by (handler) (rate(http_requests_total[5m]))"},
+					},
+				},
+				{
+					Kind: "panel",
+					UID:  "1#2",
+					Name: "Users",
+					Fields: map[string]interface{}{
+						"queries":           []string{"SELECT count(*) FROM app.users WHERE active"},
+						"transformerConfig": []string{"renameByName", "count", "Active users"},
+					},
+				},
+			},
+		},
+	},
+}
+
+func TestDashboardIndex_FullText(t *testing.T) {
+	t.Run("fulltext-query-metric", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithPanelQueries)
+		checkSearchResponse(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Query: "http_requests_total", FullText: true},
+		)
+	})
+	t.Run("fulltext-query-table", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithPanelQueries)
+		checkSearchResponse(t, filepath.Base(t.Name()), index, testAllowAllFilter,
+			DashboardQuery{Query: "users", FullText: true},
+		)
+	})
+	t.Run("fulltext-disabled", func(t *testing.T) {
+		index := initTestOrgIndexFromDashes(t, dashboardsWithPanelQueries)
+		resp := doSearchQuery(context.Background(), testLogger, index, testAllowAllFilter, testEntityFilters(testAllowAllFilter),
+			DashboardQuery{Query: "http_requests_total"},
+			&NoopQueryExtender{}, "")
+		custom, ok := resp.Frames[0].Meta.Custom.(*customMeta)
+		require.True(t, ok)
+		require.Equal(t, uint64(0), custom.Count)
+	})
+}
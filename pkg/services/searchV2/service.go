@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/store"
@@ -77,6 +78,7 @@ type StandardSearchService struct {
 	reIndexCh      chan struct{}
 	queries        querylibrary.Service
 	features       featuremgmt.FeatureToggles
+	usage          dashboardusage.Service
 }
 
 func (s *StandardSearchService) IsReady(ctx context.Context, orgId int64) IsSearchReadyResponse {
@@ -85,7 +87,7 @@ func (s *StandardSearchService) IsReady(ctx context.Context, orgId int64) IsSear
 
 func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEventsService,
 	ac accesscontrol.Service, tracer tracing.Tracer, features featuremgmt.FeatureToggles, orgService org.Service,
-	userService user.Service, queries querylibrary.Service) SearchService {
+	userService user.Service, queries querylibrary.Service, dashboardUsage dashboardusage.Service) SearchService {
 	extender := &NoopExtender{}
 	s := &StandardSearchService{
 		cfg: cfg,
@@ -97,8 +99,9 @@ func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEv
 		},
 		dashboardIndex: newSearchIndex(
 			newSQLDashboardLoader(sql, tracer, cfg.Search),
+			newSQLEntityLoader(sql, tracer),
 			entityEventStore,
-			extender.GetDocumentExtender(),
+			newUsageDocumentExtender(dashboardUsage, extender.GetDocumentExtender()),
 			newFolderIDLookup(sql),
 			tracer,
 			features,
@@ -111,6 +114,7 @@ func ProvideService(cfg *setting.Cfg, sql db.DB, entityEventStore store.EntityEv
 		userService: userService,
 		queries:     queries,
 		features:    features,
+		usage:       dashboardUsage,
 	}
 	return s
 }
@@ -145,7 +149,7 @@ func (s *StandardSearchService) TriggerReIndex() {
 
 func (s *StandardSearchService) RegisterDashboardIndexExtender(ext DashboardIndexExtender) {
 	s.extender = ext
-	s.dashboardIndex.extender = ext.GetDocumentExtender()
+	s.dashboardIndex.extender = newUsageDocumentExtender(s.usage, ext.GetDocumentExtender())
 }
 
 func (s *StandardSearchService) getUser(ctx context.Context, backendUser *backend.User, orgId int64) (*user.SignedInUser, error) {
@@ -254,6 +258,15 @@ func (s *StandardSearchService) doDashboardQuery(ctx context.Context, signedInUs
 		return rsp
 	}
 
+	entityFilters, err := s.getEntityFilters(signedInUser)
+	if err != nil {
+		dashboardSearchFailureRequestsCounter.With(prometheus.Labels{
+			"reason": "get_entity_filter_error",
+		}).Inc()
+		rsp.Error = err
+		return rsp
+	}
+
 	index, err := s.dashboardIndex.getOrCreateOrgIndex(ctx, orgID)
 	if err != nil {
 		dashboardSearchFailureRequestsCounter.With(prometheus.Labels{
@@ -272,7 +285,7 @@ func (s *StandardSearchService) doDashboardQuery(ctx context.Context, signedInUs
 		return rsp
 	}
 
-	response := doSearchQuery(ctx, s.logger, index, filter, q, s.extender.GetQueryExtender(q), s.cfg.AppSubURL)
+	response := doSearchQuery(ctx, s.logger, index, filter, entityFilters, q, s.extender.GetQueryExtender(q), s.cfg.AppSubURL)
 
 	if q.WithAllowedActions {
 		if err := s.addAllowedActionsField(ctx, orgID, signedInUser, response); err != nil {
@@ -288,3 +301,21 @@ func (s *StandardSearchService) doDashboardQuery(ctx context.Context, signedInUs
 
 	return response
 }
+
+func (s *StandardSearchService) getEntityFilters(signedInUser *user.SignedInUser) (entityFilters, error) {
+	var filters entityFilters
+	var err error
+	if filters.folder, err = s.auth.GetFolderReadFilter(signedInUser); err != nil {
+		return filters, err
+	}
+	if filters.alertRule, err = s.auth.GetAlertRuleReadFilter(signedInUser); err != nil {
+		return filters, err
+	}
+	if filters.datasource, err = s.auth.GetDatasourceReadFilter(signedInUser); err != nil {
+		return filters, err
+	}
+	if filters.datasourceQuery, err = s.auth.GetDatasourceQueryFilter(signedInUser); err != nil {
+		return filters, err
+	}
+	return filters, nil
+}
@@ -25,6 +25,7 @@ type DashboardQuery struct {
 	PanelType          string       `json:"panel_type,omitempty"`
 	UIDs               []string     `json:"uid,omitempty"`
 	Explain            bool         `json:"explain,omitempty"`            // adds details on why document matched
+	FullText           bool         `json:"fullText,omitempty"`           // also match descriptions, query expressions and transformations
 	WithAllowedActions bool         `json:"withAllowedActions,omitempty"` // adds allowed actions per entity
 	Facet              []FacetField `json:"facet,omitempty"`
 	SkipLocation       bool         `json:"skipLocation,omitempty"`
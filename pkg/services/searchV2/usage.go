@@ -2,16 +2,25 @@ package searchV2
 
 import (
 	"context"
+	"time"
 
 	"github.com/blugelabs/bluge"
 	"github.com/blugelabs/bluge/search"
 	"github.com/blugelabs/bluge/search/aggregations"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboardusage"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
+// Sortable dashboard usage fields
+const (
+	DocumentFieldViewsLast30Days   = "usage_views_last_30_days"
+	DocumentFieldQueriesLast30Days = "usage_queries_last_30_days"
+	DocumentFieldLastViewedAt      = "usage_last_viewed"
+)
+
 type usageGauge struct {
 	field string
 	gauge *prometheus.GaugeVec
@@ -75,3 +84,45 @@ func updateUsageStats(ctx context.Context, reader *bluge.Reader, logger log.Logg
 		}
 	}
 }
+
+// usageDocumentExtender adds the dashboard usage to the dashboard documents, so
+// that search results can be sorted by views or last view.
+type usageDocumentExtender struct {
+	usage  dashboardusage.Service
+	next   DocumentExtender
+	logger log.Logger
+}
+
+func newUsageDocumentExtender(usage dashboardusage.Service, next DocumentExtender) DocumentExtender {
+	if usage == nil {
+		return next
+	}
+	return &usageDocumentExtender{usage: usage, next: next, logger: log.New("searchV2.usage")}
+}
+
+func (e *usageDocumentExtender) GetDashboardExtender(orgID int64, uids ...string) ExtendDashboardFunc {
+	next := e.next.GetDashboardExtender(orgID, uids...)
+
+	summaries, err := e.usage.GetUsageSummaries(context.Background(), &dashboardusage.GetUsageSummariesQuery{
+		OrgID: orgID,
+		UIDs:  uids,
+		Since: time.Now().AddDate(0, 0, -30),
+	})
+	if err != nil {
+		// index the dashboards anyway, without their usage
+		e.logger.Error("Failed to load dashboard usage", "error", err, "orgId", orgID)
+	}
+
+	return func(uid string, doc *bluge.Document) error {
+		summary := summaries[uid]
+		if summary == nil {
+			summary = &dashboardusage.UsageSummary{}
+		}
+		doc.AddField(bluge.NewNumericField(DocumentFieldViewsLast30Days, float64(summary.Views)).Sortable().StoreValue())
+		doc.AddField(bluge.NewNumericField(DocumentFieldQueriesLast30Days, float64(summary.Queries)).Sortable().StoreValue())
+		if summary.LastViewed != nil {
+			doc.AddField(bluge.NewDateTimeField(DocumentFieldLastViewedAt, *summary.LastViewed).Sortable().StoreValue())
+		}
+		return next(uid, doc)
+	}
+}
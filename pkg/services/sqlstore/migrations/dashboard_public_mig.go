@@ -84,4 +84,122 @@ func addPublicDashboardMigration(mg *Migrator) {
 
 	mg.AddMigration("delete orphaned public dashboards", NewRawSQLMigration(
 		"DELETE FROM dashboard_public WHERE dashboard_uid NOT IN (SELECT uid FROM dashboard)"))
+
+	mg.AddMigration("add share column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "share",
+		Type:     DB_NVarchar,
+		Length:   64,
+		Nullable: false,
+		Default:  "'public'",
+	}))
+
+	mg.AddMigration("add expires_at column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "expires_at",
+		Type:     DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add rate_limit column", NewAddColumnMigration(dashboardPublicCfgV2, &Column{
+		Name:     "rate_limit",
+		Type:     DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	addPublicDashboardViewerMigrations(mg)
+	addPublicDashboardAccessLogMigrations(mg)
+}
+
+// addPublicDashboardViewerMigrations adds the tables of the viewers invited by email to public dashboards, their magic
+// link and session tokens, and the log of their usage.
+func addPublicDashboardViewerMigrations(mg *Migrator) {
+	viewerV1 := Table{
+		Name: "dashboard_public_viewer",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "email", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+			{Name: "last_viewed_at", Type: DB_DateTime, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"uid"}, Type: UniqueIndex},
+			{Cols: []string{"public_dashboard_uid", "email"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create dashboard public viewer table v1", NewAddTableMigration(viewerV1))
+	addTableIndicesMigrations(mg, "v1", viewerV1)
+
+	viewerTokenV1 := Table{
+		Name: "dashboard_public_viewer_token",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "viewer_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "token_hash", Type: DB_NVarchar, Length: 100, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+			{Name: "expires_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"token_hash"}, Type: UniqueIndex},
+			{Cols: []string{"viewer_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public viewer token table v1", NewAddTableMigration(viewerTokenV1))
+	addTableIndicesMigrations(mg, "v1", viewerTokenV1)
+
+	viewerEventV1 := Table{
+		Name: "dashboard_public_viewer_event",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "email", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "event", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "created_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"public_dashboard_uid", "created_at"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public viewer event table v1", NewAddTableMigration(viewerEventV1))
+	addTableIndicesMigrations(mg, "v1", viewerEventV1)
+
+	// the retention of the usage log deletes events by time
+	mg.AddMigration("add index dashboard_public_viewer_event.created_at", NewAddIndexMigration(viewerEventV1, &Index{
+		Cols: []string{"created_at"},
+	}))
+}
+
+// addPublicDashboardAccessLogMigrations adds the table of the access log of public dashboards
+func addPublicDashboardAccessLogMigrations(mg *Migrator) {
+	accessLogV1 := Table{
+		Name: "dashboard_public_access_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "status", Type: DB_Int, Nullable: false},
+			{Name: "duration_ms", Type: DB_BigInt, Nullable: false},
+			{Name: "client_ip_hash", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "referer", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "created_at", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "created_at"}},
+			{Cols: []string{"public_dashboard_uid", "created_at"}},
+			{Cols: []string{"created_at"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public access log table v1", NewAddTableMigration(accessLogV1))
+	addTableIndicesMigrations(mg, "v1", accessLogV1)
 }
@@ -71,4 +71,28 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add payload_path column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "payload_path", Type: DB_NVarchar, Length: 255, Nullable: true,
+	}))
+
+	mg.AddMigration("Add access column to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "access", Type: DB_NVarchar, Length: 20, Nullable: false, Default: "'public'",
+	}))
+
+	snapshotTeamV1 := Table{
+		Name: "dashboard_snapshot_team",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "snapshot_id", Type: DB_BigInt, Nullable: false},
+			{Name: "team_id", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"snapshot_id", "team_id"}, Type: UniqueIndex},
+			{Cols: []string{"team_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_snapshot_team table", NewAddTableMigration(snapshotTeamV1))
+	addTableIndicesMigrations(mg, "v1", snapshotTeamV1)
 }
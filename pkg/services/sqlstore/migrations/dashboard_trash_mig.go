@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardTrashMigrations(mg *Migrator) {
+	dashboardTrashV1 := Table{
+		Name: "dashboard_trash",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "is_folder", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "folder_uid", Type: DB_NVarchar, Length: 40, Nullable: true},
+			// the row of the deleted folder the dashboard was deleted with
+			{Name: "parent_id", Type: DB_BigInt, Nullable: false, Default: "0"},
+			// the id the dashboard had, its versions are kept under it
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "data", Type: DB_MediumText, Nullable: false},
+			{Name: "acl", Type: DB_Text, Nullable: true},
+			{Name: "permissions", Type: DB_Text, Nullable: true},
+			{Name: "deleted", Type: DB_DateTime, Nullable: false},
+			{Name: "deleted_by", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"parent_id"}},
+			{Cols: []string{"deleted"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_trash table v1", NewAddTableMigration(dashboardTrashV1))
+	addTableIndicesMigrations(mg, "v1", dashboardTrashV1)
+
+	mg.AddMigration("add deleted column to dashboard_version", NewAddColumnMigration(Table{Name: "dashboard_version"}, &Column{
+		Name: "deleted", Type: DB_Bool, Nullable: false, Default: "0",
+	}))
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addDashboardUsageMigrations(mg *Migrator) {
+	dashboardUsageDailyV1 := Table{
+		Name: "dashboard_usage_daily",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "day", Type: DB_BigInt, Nullable: false},
+			{Name: "views", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "queries", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "last_viewed", Type: DB_BigInt, Nullable: false, Default: "0"},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "dashboard_uid", "user_id", "day"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "day"}},
+			{Cols: []string{"day"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_usage_daily table v1", NewAddTableMigration(dashboardUsageDailyV1))
+	addTableIndicesMigrations(mg, "v1", dashboardUsageDailyV1)
+}
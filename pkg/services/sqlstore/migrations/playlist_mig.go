@@ -52,6 +52,24 @@ func addPlaylistUIDMigration(mg *Migrator) {
 	}))
 }
 
+func addPlaylistItemOverridesMigration(mg *Migrator) {
+	// Per item overrides of the playlist interval, time range and variables
+	playlistItem := Table{Name: "playlist_item"}
+
+	mg.AddMigration("Add interval column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "interval", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("Add time_from column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_from", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("Add time_to column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "time_to", Type: DB_NVarchar, Length: 255, Nullable: false, Default: "''",
+	}))
+	mg.AddMigration("Add variables column to playlist_item", NewAddColumnMigration(playlistItem, &Column{
+		Name: "variables", Type: DB_Text, Nullable: true,
+	}))
+}
+
 func playlistV2() Table {
 	return Table{
 		Name: "playlist",
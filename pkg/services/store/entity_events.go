@@ -27,6 +27,12 @@ const (
 	EntityTypeFolder    EntityType = "folder"
 	EntityTypeImage     EntityType = "image"
 	EntityTypeJSON      EntityType = "json"
+
+	EntityTypeAlertRule    EntityType = "alert-rule"
+	EntityTypeDatasource   EntityType = "datasource"
+	EntityTypeLibraryPanel EntityType = "library-panel"
+	EntityTypePlaylist     EntityType = "playlist"
+	EntityTypeCorrelation  EntityType = "correlation"
 )
 
 // CreateDatabaseEntityId creates entityId for entities stored in the existing SQL tables
@@ -42,6 +48,20 @@ func CreateDatabaseEntityId(internalId interface{}, orgId int64, entityType Enti
 	return fmt.Sprintf("database/%d/%s/%s", orgId, entityType, internalIdAsString)
 }
 
+// NewDatabaseEntityEvent creates the event of a change to an entity stored in the existing SQL tables
+func NewDatabaseEntityEvent(internalId interface{}, orgId int64, entityType EntityType, eventType EntityEventType) *EntityEvent {
+	return &EntityEvent{
+		EventType: eventType,
+		EntityId:  CreateDatabaseEntityId(internalId, orgId, entityType),
+		Created:   time.Now().Unix(),
+	}
+}
+
+// EntityEventsEnabled returns true when the entity events are consumed, and cleaned up, by the search index
+func EntityEventsEnabled(features featuremgmt.FeatureToggles) bool {
+	return features != nil && features.IsEnabled(featuremgmt.FlagPanelTitleSearch)
+}
+
 type EntityEvent struct {
 	Id        int64
 	EventType EntityEventType
@@ -70,7 +90,7 @@ type EntityEventsService interface {
 }
 
 func ProvideEntityEventsService(cfg *setting.Cfg, sqlStore db.DB, features featuremgmt.FeatureToggles) EntityEventsService {
-	if !features.IsEnabled(featuremgmt.FlagPanelTitleSearch) {
+	if !EntityEventsEnabled(features) {
 		return &dummyEntityEventsService{}
 	}
 
@@ -2,6 +2,7 @@ package dashboard
 
 import (
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -415,9 +416,12 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) panelInfo {
 		case "transformations":
 			for iter.ReadArray() {
 				for sub := iter.ReadObject(); sub != ""; sub = iter.ReadObject() {
-					if sub == "id" {
+					switch sub {
+					case "id":
 						panel.Transformer = append(panel.Transformer, iter.ReadString())
-					} else {
+					case "options":
+						panel.Config = appendConfigText(panel.Config, iter.Read())
+					default:
 						iter.Skip()
 					}
 				}
@@ -445,6 +449,33 @@ func readpanelInfo(iter *jsoniter.Iterator, lookup DatasourceLookup) panelInfo {
 	}
 
 	panel.Datasource = targets.GetDatasourceInfo()
+	panel.Queries = targets.GetQueries()
 
 	return panel
 }
+
+// appendConfigText collects the string values and object keys (often field names)
+// nested in a transformation config
+func appendConfigText(text []string, v interface{}) []string {
+	switch val := v.(type) {
+	case string:
+		if val != "" {
+			text = append(text, val)
+		}
+	case []interface{}:
+		for _, item := range val {
+			text = appendConfigText(text, item)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			text = append(text, k)
+			text = appendConfigText(text, val[k])
+		}
+	}
+	return text
+}
@@ -81,6 +81,12 @@ func NewStaticDashboardSummaryBuilder(lookup DatasourceLookup, sanitize bool) mo
 			p.URL = fmt.Sprintf("%s?viewPanel=%d", url, panel.ID)
 			p.Fields = make(map[string]interface{}, 0)
 			p.Fields["type"] = panel.Type
+			if len(panel.Queries) > 0 {
+				p.Fields["queries"] = panel.Queries
+			}
+			if len(panel.Config) > 0 {
+				p.Fields["transformerConfig"] = panel.Config
+			}
 
 			if panel.Type != "row" {
 				panelRefs.Add(models.ExternalEntityReferencePlugin, string(plugins.Panel), panel.Type)
@@ -4,9 +4,20 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
+// queryTextFields are the target properties holding a query expression in
+// the core data sources
+var queryTextFields = map[string]bool{
+	"expr":      true, // prometheus, loki
+	"rawSql":    true, // sql data sources
+	"query":     true, // influxdb (flux, influxql), elasticsearch, tempo
+	"queryText": true, // testdata, postgres legacy
+	"target":    true, // graphite
+}
+
 type targetInfo struct {
-	lookup DatasourceLookup
-	uids   map[string]*DataSourceRef
+	lookup  DatasourceLookup
+	uids    map[string]*DataSourceRef
+	queries []string
 }
 
 func newTargetInfo(lookup DatasourceLookup) targetInfo {
@@ -60,6 +71,11 @@ func (s *targetInfo) addDatasource(iter *jsoniter.Iterator) {
 	}
 }
 
+// GetQueries returns the query expressions of the targets, in order
+func (s *targetInfo) GetQueries() []string {
+	return s.queries
+}
+
 func (s *targetInfo) addRef(ref *DataSourceRef) {
 	if ref != nil && ref.UID != "" {
 		s.uids[ref.UID] = ref
@@ -76,6 +92,13 @@ func (s *targetInfo) addTarget(iter *jsoniter.Iterator) {
 			iter.Skip()
 
 		default:
+			if queryTextFields[l1Field] && iter.WhatIsNext() == jsoniter.StringValue {
+				if q := iter.ReadString(); q != "" {
+					s.queries = append(s.queries, q)
+				}
+				continue
+			}
+
 			v := iter.Read()
 			logf("[Panel.TARGET] %s=%v\n", l1Field, v)
 		}
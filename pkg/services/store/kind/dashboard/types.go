@@ -8,6 +8,8 @@ type panelInfo struct {
 	PluginVersion string          `json:"pluginVersion,omitempty"`
 	Datasource    []DataSourceRef `json:"datasource,omitempty"`  // UIDs
 	Transformer   []string        `json:"transformer,omitempty"` // ids of the transformation steps
+	Queries       []string        `json:"queries,omitempty"`     // query expressions of the targets (PromQL, SQL...)
+	Config        []string        `json:"config,omitempty"`      // text values from the transformation options
 
 	// Rows define panels as sub objects
 	Collapsed []panelInfo `json:"collapsed,omitempty"`
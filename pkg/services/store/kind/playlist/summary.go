@@ -9,6 +9,13 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 )
 
+// searchQuery holds the parts of a dashboard_by_search item that are referenced
+// by the summary
+type searchQuery struct {
+	Location string   `json:"location,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
 func GetEntityKindInfo() models.EntityKindInfo {
 	return models.EntityKindInfo{
 		ID:          models.StandardKindPlaylist,
@@ -55,6 +62,27 @@ func summaryBuilder(ctx context.Context, uid string, body []byte) (*models.Entit
 			}
 			summary.Labels[item.Value] = ""
 
+		case playlist.PlaylistItemTypeDashboardBySearch:
+			query := searchQuery{}
+			if err := json.Unmarshal([]byte(item.Value), &query); err != nil {
+				summary.Error = &models.EntityErrorInfo{
+					Message: "Playlist has an invalid search query",
+				}
+				continue
+			}
+			if query.Location != "" {
+				summary.References = append(summary.References, &models.EntityExternalReference{
+					Kind: models.StandardKindFolder,
+					UID:  query.Location,
+				})
+			}
+			for _, tag := range query.Tags {
+				if summary.Labels == nil {
+					summary.Labels = make(map[string]string, 0)
+				}
+				summary.Labels[tag] = ""
+			}
+
 		case playlist.PlaylistItemTypeDashboardById:
 			// obviously insufficient long term... but good to have an example :)
 			summary.Error = &models.EntityErrorInfo{
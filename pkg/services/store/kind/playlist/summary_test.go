@@ -23,6 +23,7 @@ func TestPlaylistSummary(t *testing.T) {
 			{Type: playlist.PlaylistItemTypeDashboardByUid, Value: "D1"},
 			{Type: playlist.PlaylistItemTypeDashboardByTag, Value: "tagA"},
 			{Type: playlist.PlaylistItemTypeDashboardByUid, Value: "D3"},
+			{Type: playlist.PlaylistItemTypeDashboardBySearch, Value: `{"location":"F1","tags":["tagB"]}`},
 		},
 	}
 	out, err := json.Marshal(playlist)
@@ -33,7 +34,9 @@ func TestPlaylistSummary(t *testing.T) {
 	summary, body, err := builder(context.Background(), "abc", out)
 	require.NoError(t, err)
 	require.Equal(t, "test", summary.Name)
-	require.Equal(t, 2, len(summary.References))
-	require.Equal(t, map[string]string{"tagA": ""}, summary.Labels)
+	require.Equal(t, 3, len(summary.References))
+	require.Equal(t, "folder", summary.References[2].Kind)
+	require.Equal(t, "F1", summary.References[2].UID)
+	require.Equal(t, map[string]string{"tagA": "", "tagB": ""}, summary.Labels)
 	require.True(t, json.Valid(body))
 }
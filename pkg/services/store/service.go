@@ -68,6 +68,9 @@ type StorageService interface {
 	// Read raw file contents out of the store
 	Read(ctx context.Context, user *user.SignedInUser, path string) (*filestorage.File, error)
 
+	// Read file metadata, without the contents
+	ReadMetadata(ctx context.Context, user *user.SignedInUser, path string) (*filestorage.FileMetadata, error)
+
 	Upload(ctx context.Context, user *user.SignedInUser, req *UploadRequest) error
 
 	Delete(ctx context.Context, user *user.SignedInUser, path string) error
@@ -348,6 +351,14 @@ func (s *standardStorageService) Read(ctx context.Context, user *user.SignedInUs
 	return s.tree.GetFile(ctx, getOrgId(user), path)
 }
 
+func (s *standardStorageService) ReadMetadata(ctx context.Context, user *user.SignedInUser, path string) (*filestorage.FileMetadata, error) {
+	guardian := s.authService.newGuardian(ctx, user, getFirstSegment(path))
+	if !guardian.canView(path) {
+		return nil, ErrAccessDenied
+	}
+	return s.tree.GetFileMetadata(ctx, getOrgId(user), path)
+}
+
 func (s *standardStorageService) Usage(ctx context.Context, ScopeParameters *quota.ScopeParameters) (*quota.Map, error) {
 	u := &quota.Map{}
 
@@ -128,6 +128,25 @@ func (t *nestedTree) GetFile(ctx context.Context, orgId int64, path string) (*fi
 	return file, err
 }
 
+func (t *nestedTree) GetFileMetadata(ctx context.Context, orgId int64, path string) (*filestorage.FileMetadata, error) {
+	if path == "" {
+		return nil, nil // not found
+	}
+	root, path := t.getRoot(orgId, path)
+	if root == nil {
+		return nil, nil // not found (or not ready)
+	}
+	store := root.Store()
+	if store == nil {
+		return nil, fmt.Errorf("store not ready")
+	}
+	file, found, err := store.Get(ctx, path, &filestorage.GetFileOptions{WithContents: false})
+	if err != nil || !found || file == nil {
+		return nil, err
+	}
+	return &file.FileMetadata, nil
+}
+
 func filterStoragesUnderContentRoot(storages []storageRuntime) []storageRuntime {
 	out := make([]storageRuntime, 0)
 	for _, s := range storages {
@@ -39,6 +39,7 @@ type WriteValueResponse struct {
 
 type storageTree interface {
 	GetFile(ctx context.Context, orgId int64, path string) (*filestorage.File, error)
+	GetFileMetadata(ctx context.Context, orgId int64, path string) (*filestorage.FileMetadata, error)
 	ListFolder(ctx context.Context, orgId int64, path string, accessFilter filestorage.PathFilter) (*StorageListFrame, error)
 }
 
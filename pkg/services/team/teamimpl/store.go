@@ -157,6 +157,10 @@ func (ss *xormStore) Delete(ctx context.Context, cmd *models.DeleteTeamCommand)
 			}
 		}
 
+		if _, err := sess.Exec("DELETE FROM dashboard_snapshot_team WHERE team_id = ?", cmd.Id); err != nil {
+			return err
+		}
+
 		_, err := sess.Exec("DELETE FROM permission WHERE scope=?", ac.Scope("teams", "id", fmt.Sprint(cmd.Id)))
 
 		return err
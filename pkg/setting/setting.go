@@ -294,6 +294,7 @@ type Cfg struct {
 
 	// Dashboards
 	DefaultHomeDashboardPath string
+	DashboardTrashRetention  time.Duration
 
 	// Auth
 	LoginCookieName              string
@@ -403,9 +404,19 @@ type Cfg struct {
 
 	// Data sources
 	DataSourceLimit int
+	// SQLiteDataSourceAllowedPaths are directories or files SQLite data sources can read from.
+	SQLiteDataSourceAllowedPaths []string
+	// PrometheusQueryCache configures incremental caching of Prometheus range query results.
+	PrometheusQueryCache PrometheusQueryCacheSettings
+	// QueryCache configures caching of query results of data sources which opted in.
+	QueryCache QueryCacheSettings
 
 	// Snapshots
 	SnapshotPublicMode bool
+	// SnapshotStorageType is where snapshot payloads are stored, one of database, disk or blob.
+	SnapshotStorageType      string
+	SnapshotStoragePath      string
+	SnapshotStorageBucketURL string
 
 	ErrTemplateName string
 
@@ -455,6 +466,12 @@ type Cfg struct {
 	// LiveAllowedOrigins is a set of origins accepted by Live. If not provided
 	// then Live uses AppURL as the only allowed origin.
 	LiveAllowedOrigins []string
+	// LiveHistoryWindow is a time window of managed stream channel history
+	// available to subscribers on join. Zero value disables history.
+	LiveHistoryWindow time.Duration
+	// LiveHistoryMaxFrames is a maximum number of frames kept in history per
+	// managed stream channel.
+	LiveHistoryMaxFrames int
 
 	// Grafana.com URL, used for OAuth redirect.
 	GrafanaComURL string
@@ -473,6 +490,14 @@ type Cfg struct {
 	// Query history
 	QueryHistoryEnabled bool
 
+	// Public dashboards
+	PublicDashboardsAccessLogRetention   time.Duration
+	PublicDashboardsViewerEventRetention time.Duration
+
+	// Dashboard usage
+	DashboardUsageEnabled   bool
+	DashboardUsageRetention time.Duration
+
 	DashboardPreviews DashboardPreviewsSettings
 
 	Storage StorageSettings
@@ -987,6 +1012,10 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	MinRefreshInterval = valueAsString(dashboards, "min_refresh_interval", "5s")
 
 	cfg.DefaultHomeDashboardPath = dashboards.Key("default_home_dashboard_path").MustString("")
+	cfg.DashboardTrashRetention, err = gtime.ParseDuration(valueAsString(dashboards, "trash_retention", "30d"))
+	if err != nil {
+		return err
+	}
 
 	if err := readUserSettings(iniFile, cfg); err != nil {
 		return err
@@ -1048,6 +1077,23 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	queryHistory := iniFile.Section("query_history")
 	cfg.QueryHistoryEnabled = queryHistory.Key("enabled").MustBool(true)
 
+	publicDashboards := iniFile.Section("public_dashboards")
+	cfg.PublicDashboardsAccessLogRetention, err = gtime.ParseDuration(valueAsString(publicDashboards, "access_log_retention", "30d"))
+	if err != nil {
+		return err
+	}
+	cfg.PublicDashboardsViewerEventRetention, err = gtime.ParseDuration(valueAsString(publicDashboards, "viewer_event_retention", "90d"))
+	if err != nil {
+		return err
+	}
+
+	dashboardUsage := iniFile.Section("dashboard_usage")
+	cfg.DashboardUsageEnabled = dashboardUsage.Key("enabled").MustBool(true)
+	cfg.DashboardUsageRetention, err = gtime.ParseDuration(valueAsString(dashboardUsage, "retention", "90d"))
+	if err != nil {
+		return err
+	}
+
 	panelsSection := iniFile.Section("panels")
 	cfg.DisableSanitizeHtml = panelsSection.Key("disable_sanitize_html").MustBool(false)
 
@@ -1648,6 +1694,18 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 	SnapShotRemoveExpired = snapshots.Key("snapshot_remove_expired").MustBool(true)
 	cfg.SnapshotPublicMode = snapshots.Key("public_mode").MustBool(false)
 
+	cfg.SnapshotStorageType = valueAsString(snapshots, "storage_type", "database")
+	switch cfg.SnapshotStorageType {
+	case "database", "disk", "blob":
+	default:
+		return fmt.Errorf("invalid snapshot storage type %q, must be one of database, disk or blob", cfg.SnapshotStorageType)
+	}
+	cfg.SnapshotStoragePath = makeAbsolute(valueAsString(snapshots, "storage_path", "snapshots"), cfg.DataPath)
+	cfg.SnapshotStorageBucketURL = valueAsString(snapshots, "storage_bucket_url", "")
+	if cfg.SnapshotStorageType == "blob" && cfg.SnapshotStorageBucketURL == "" {
+		return errors.New("snapshot storage type blob requires storage_bucket_url to be set")
+	}
+
 	return nil
 }
 
@@ -1736,6 +1794,41 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) string {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+	cfg.SQLiteDataSourceAllowedPaths = util.SplitString(datasources.Key("sqlite_allowed_paths").MustString(""))
+	cfg.PrometheusQueryCache = PrometheusQueryCacheSettings{
+		Enabled:      datasources.Key("prometheus_query_cache_enabled").MustBool(false),
+		Backend:      datasources.Key("prometheus_query_cache_backend").In("memory", []string{"memory", "remote"}),
+		MaxStaleness: datasources.Key("prometheus_query_cache_max_staleness").MustDuration(10 * time.Minute),
+		TTL:          datasources.Key("prometheus_query_cache_ttl").MustDuration(time.Hour),
+	}
+	cfg.QueryCache = QueryCacheSettings{
+		Enabled:      datasources.Key("query_cache_enabled").MustBool(true),
+		TTL:          datasources.Key("query_cache_ttl").MustDuration(time.Minute),
+		MaxValueSize: datasources.Key("query_cache_max_value_size").MustInt(1024 * 1024),
+	}
+}
+
+// PrometheusQueryCacheSettings configures caching of Prometheus range query results, so that dashboard refreshes
+// only query the part of the time range that isn't cached yet.
+type PrometheusQueryCacheSettings struct {
+	Enabled bool
+	// Backend is either "memory", to keep results per Grafana instance, or "remote" to use the remote cache.
+	Backend string
+	// MaxStaleness is how far back from the end of a cached result data is queried again, since recent
+	// samples may still change.
+	MaxStaleness time.Duration
+	// TTL is how long cached results are kept.
+	TTL time.Duration
+}
+
+// QueryCacheSettings configures caching of query results in the remote cache. Results are only cached for data
+// sources which enabled caching in their settings.
+type QueryCacheSettings struct {
+	Enabled bool
+	// TTL is how long cached results are kept, unless a data source configures its own TTL.
+	TTL time.Duration
+	// MaxValueSize is the maximum size in bytes of a cached response. Larger responses aren't cached.
+	MaxValueSize int
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
@@ -1764,6 +1857,14 @@ func (cfg *Cfg) readLiveSettings(iniFile *ini.File) error {
 		return fmt.Errorf("unsupported live HA engine type: %s", cfg.LiveHAEngine)
 	}
 	cfg.LiveHAEngineAddress = section.Key("ha_engine_address").MustString("127.0.0.1:6379")
+	cfg.LiveHistoryWindow = section.Key("history_window").MustDuration(0)
+	if cfg.LiveHistoryWindow < 0 {
+		return fmt.Errorf("unexpected value %s for [live] history_window", cfg.LiveHistoryWindow)
+	}
+	cfg.LiveHistoryMaxFrames = section.Key("history_max_frames").MustInt(1000)
+	if cfg.LiveHistoryMaxFrames <= 0 {
+		return fmt.Errorf("unexpected value %d for [live] history_max_frames", cfg.LiveHistoryMaxFrames)
+	}
 
 	var originPatterns []string
 	allowedOrigins := section.Key("allowed_origins").MustString("")
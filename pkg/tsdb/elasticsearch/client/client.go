@@ -31,6 +31,14 @@ type DatasourceInfo struct {
 	MaxConcurrentShardRequests int64
 	IncludeFrozen              bool
 	XPack                      bool
+	ConfiguredFields           ConfiguredFields
+}
+
+// ConfiguredFields holds the document fields configured in the datasource settings
+type ConfiguredFields struct {
+	TimeField       string
+	LogMessageField string
+	LogLevelField   string
 }
 
 const loggerName = "tsdb.elasticsearch.client"
@@ -38,6 +46,7 @@ const loggerName = "tsdb.elasticsearch.client"
 // Client represents a client which can interact with elasticsearch api
 type Client interface {
 	GetTimeField() string
+	GetConfiguredFields() ConfiguredFields
 	GetMinInterval(queryInterval string) (time.Duration, error)
 	ExecuteMultisearch(r *MultiSearchRequest) (*MultiSearchResponse, error)
 	MultiSearch() *MultiSearchRequestBuilder
@@ -81,6 +90,10 @@ func (c *baseClientImpl) GetTimeField() string {
 	return c.timeField
 }
 
+func (c *baseClientImpl) GetConfiguredFields() ConfiguredFields {
+	return c.ds.ConfiguredFields
+}
+
 func (c *baseClientImpl) GetMinInterval(queryInterval string) (time.Duration, error) {
 	timeInterval := c.ds.TimeInterval
 	return intervalv2.GetIntervalFrom(queryInterval, timeInterval, 0, 5*time.Second)
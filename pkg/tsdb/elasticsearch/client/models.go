@@ -11,7 +11,7 @@ type SearchRequest struct {
 	Index       string
 	Interval    intervalv2.Interval
 	Size        int
-	Sort        map[string]interface{}
+	Sort        []map[string]interface{}
 	Query       *Query
 	Aggs        AggArray
 	CustomProps map[string]interface{}
@@ -39,6 +39,14 @@ func (r *SearchRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(root)
 }
 
+// SortOrder represents the order of a sort
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
 // SearchResponseHits represents search response hits
 type SearchResponseHits struct {
 	Hits []map[string]interface{}
@@ -11,8 +11,8 @@ type SearchRequestBuilder struct {
 	interval intervalv2.Interval
 	index    string
 	size     int
-	// Currently sort is map, but based in examples it should be an array https://www.elastic.co/guide/en/elasticsearch/reference/current/sort-search-results.html
-	sort         map[string]interface{}
+	// sort is an array to keep the order of sort fields, which search_after values depend on
+	sort         []map[string]interface{}
 	queryBuilder *QueryBuilder
 	aggBuilders  []AggBuilder
 	customProps  map[string]interface{}
@@ -22,7 +22,7 @@ type SearchRequestBuilder struct {
 func NewSearchRequestBuilder(interval intervalv2.Interval) *SearchRequestBuilder {
 	builder := &SearchRequestBuilder{
 		interval:    interval,
-		sort:        make(map[string]interface{}),
+		sort:        make([]map[string]interface{}, 0),
 		customProps: make(map[string]interface{}),
 		aggBuilders: make([]AggBuilder, 0),
 	}
@@ -68,18 +68,32 @@ func (b *SearchRequestBuilder) Size(size int) *SearchRequestBuilder {
 	return b
 }
 
-// SortDesc adds a sort to the search request
+// SortDesc adds a descending sort to the search request
 func (b *SearchRequestBuilder) SortDesc(field, unmappedType string) *SearchRequestBuilder {
+	return b.Sort(SortOrderDesc, field, unmappedType)
+}
+
+// Sort adds a sort to the search request
+func (b *SearchRequestBuilder) Sort(order SortOrder, field, unmappedType string) *SearchRequestBuilder {
 	props := map[string]string{
-		"order": "desc",
+		"order": string(order),
 	}
 
 	if unmappedType != "" {
 		props["unmapped_type"] = unmappedType
 	}
 
-	b.sort[field] = props
+	b.sort = append(b.sort, map[string]interface{}{field: props})
+
+	return b
+}
 
+// SearchAfter sets the sort values of the last document of the previous page, the search
+// request then returns the documents following it
+func (b *SearchRequestBuilder) SearchAfter(values []interface{}) *SearchRequestBuilder {
+	if len(values) > 0 {
+		b.customProps["search_after"] = values
+	}
 	return b
 }
 
@@ -92,14 +106,20 @@ func (b *SearchRequestBuilder) AddDocValueField(field string) *SearchRequestBuil
 	return b
 }
 
+// Tags wrapping highlighted phrases in documents returned by log queries
+const (
+	HighlightPreTagsString  = "@HIGHLIGHT@"
+	HighlightPostTagsString = "@/HIGHLIGHT@"
+)
+
 // Add highlights to the search request for log queries
 func (b *SearchRequestBuilder) AddHighlight() *SearchRequestBuilder {
 	b.customProps["highlight"] = map[string]interface{}{
 		"fields": map[string]interface{}{
 			"*": map[string]interface{}{},
 		},
-		"pre_tags":      []string{"@HIGHLIGHT@"},
-		"post_tags":     []string{"@/HIGHLIGHT@"},
+		"pre_tags":      []string{HighlightPreTagsString},
+		"post_tags":     []string{HighlightPostTagsString},
 		"fragment_size": 2147483647,
 	}
 	return b
@@ -41,10 +41,15 @@ func newFlowTestDsInfo(body []byte, reuestCallback func(req *http.Request) error
 		Transport: &queryDataTestRoundTripper{body: body, requestCallback: reuestCallback},
 	}
 	return &es.DatasourceInfo{
-		ESVersion:                  semver.MustParse("8.5.0"),
-		Interval:                   "Daily",
-		Database:                   "[testdb-]YYYY.MM.DD",
-		TimeField:                  "testtime",
+		ESVersion: semver.MustParse("8.5.0"),
+		Interval:  "Daily",
+		Database:  "[testdb-]YYYY.MM.DD",
+		TimeField: "testtime",
+		ConfiguredFields: es.ConfiguredFields{
+			TimeField:       "testtime",
+			LogMessageField: "line",
+			LogLevelField:   "lvl",
+		},
 		TimeInterval:               "1s",
 		URL:                        "http://localhost:9200",
 		HTTPClient:                 &client,
@@ -1,6 +1,7 @@
 package elasticsearch
 
 import (
+	"encoding/json"
 	"errors"
 	"regexp"
 	"sort"
@@ -20,6 +21,9 @@ const (
 	percentilesType   = "percentiles"
 	extendedStatsType = "extended_stats"
 	topMetricsType    = "top_metrics"
+	logsType          = "logs"
+	rawDataType       = "raw_data"
+	rawDocumentType   = "raw_document"
 	// Bucket types
 	dateHistType    = "date_histogram"
 	histogramType   = "histogram"
@@ -28,7 +32,7 @@ const (
 	geohashGridType = "geohash_grid"
 )
 
-func parseResponse(responses []*es.SearchResponse, targets []*Query) (*backend.QueryDataResponse, error) {
+func parseResponse(responses []*es.SearchResponse, targets []*Query, configuredFields es.ConfiguredFields) (*backend.QueryDataResponse, error) {
 	result := backend.QueryDataResponse{
 		Responses: backend.Responses{},
 	}
@@ -49,6 +53,15 @@ func parseResponse(responses []*es.SearchResponse, targets []*Query) (*backend.Q
 
 		queryRes := backend.DataResponse{}
 
+		if isDocumentQuery(target) {
+			err := processDocumentResponse(res, target, configuredFields, &queryRes)
+			if err != nil {
+				return &backend.QueryDataResponse{}, err
+			}
+			result.Responses[target.RefID] = queryRes
+			continue
+		}
+
 		props := make(map[string]string)
 		err := processBuckets(res.Aggregations, target, &queryRes, props, 0)
 		if err != nil {
@@ -716,3 +729,301 @@ func getErrorFromElasticResponse(response *es.SearchResponse) string {
 
 	return errorString
 }
+
+func isDocumentQuery(target *Query) bool {
+	if len(target.Metrics) == 0 {
+		return false
+	}
+	switch target.Metrics[0].Type {
+	case logsType, rawDataType, rawDocumentType:
+		return true
+	}
+	return false
+}
+
+// processDocumentResponse creates a single frame from the documents returned by logs, raw data
+// and raw document queries, matching the frames created by the frontend for these queries.
+func processDocumentResponse(res *es.SearchResponse, target *Query, configuredFields es.ConfiguredFields, queryRes *backend.DataResponse) error {
+	var hits []map[string]interface{}
+	if res.Hits != nil {
+		hits = res.Hits.Hits
+	}
+
+	metricType := target.Metrics[0].Type
+	if metricType == rawDocumentType {
+		return processRawDocumentResponse(hits, target, queryRes)
+	}
+	isLogsQuery := metricType == logsType
+
+	docs := make([]map[string]interface{}, 0, len(hits))
+	propNames := make(map[string]bool)
+	var searchWords []string
+	seenWords := make(map[string]bool)
+	for _, hit := range hits {
+		source := map[string]interface{}{}
+		if s, ok := hit["_source"].(map[string]interface{}); ok {
+			source = flattenSource(s, "")
+		}
+
+		doc := map[string]interface{}{
+			"_id":       hit["_id"],
+			"_type":     hit["_type"],
+			"_index":    hit["_index"],
+			"sort":      hit["sort"],
+			"highlight": hit["highlight"],
+		}
+		if isLogsQuery {
+			doc["_source"] = source
+		}
+		for k, v := range source {
+			doc[k] = v
+		}
+		if configuredFields.LogLevelField != "" {
+			doc["level"] = doc[configuredFields.LogLevelField]
+		}
+		if fields, ok := hit["fields"].(map[string]interface{}); ok {
+			if values, ok := fields[configuredFields.TimeField].([]interface{}); ok && len(values) > 0 {
+				doc[configuredFields.TimeField] = values[0]
+			}
+		}
+
+		for k, v := range doc {
+			if v != nil {
+				propNames[k] = true
+			}
+		}
+
+		if highlight, ok := doc["highlight"].(map[string]interface{}); ok {
+			for _, word := range highlightedWords(highlight) {
+				if !seenWords[word] {
+					seenWords[word] = true
+					searchWords = append(searchWords, word)
+				}
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	fields := make([]*data.Field, 0, len(propNames))
+	if len(docs) > 0 {
+		fieldNames := make(map[string]bool)
+		if configuredFields.TimeField != "" {
+			fields = append(fields, createTimeField(docs, configuredFields.TimeField))
+			fieldNames[configuredFields.TimeField] = true
+		}
+		if isLogsQuery {
+			if configuredFields.LogMessageField != "" {
+				fields = append(fields, createStringField(docs, configuredFields.LogMessageField))
+				fieldNames[configuredFields.LogMessageField] = true
+			}
+			if configuredFields.LogLevelField != "" {
+				fields = append(fields, createStringField(docs, "level"))
+				fieldNames["level"] = true
+			}
+		}
+
+		names := make([]string, 0, len(propNames))
+		for name := range propNames {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if fieldNames[name] {
+				continue
+			}
+			fields = append(fields, createDocumentField(docs, name))
+		}
+	}
+
+	frame := data.NewFrame("", fields...)
+	frame.RefID = target.RefID
+	if isLogsQuery {
+		frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeLogs}
+		if len(searchWords) > 0 {
+			frame.Meta.Custom = map[string]interface{}{
+				"searchWords": searchWords,
+			}
+		}
+	}
+	queryRes.Frames = data.Frames{frame}
+	return nil
+}
+
+// processRawDocumentResponse creates a frame with a single field holding each document as JSON.
+func processRawDocumentResponse(hits []map[string]interface{}, target *Query, queryRes *backend.DataResponse) error {
+	values := make([]*json.RawMessage, 0, len(hits))
+	for _, hit := range hits {
+		doc := map[string]interface{}{
+			"_id":    hit["_id"],
+			"_type":  hit["_type"],
+			"_index": hit["_index"],
+		}
+		if source, ok := hit["_source"].(map[string]interface{}); ok {
+			for k, v := range source {
+				doc[k] = v
+			}
+		}
+		if fields, ok := hit["fields"].(map[string]interface{}); ok {
+			for k, v := range fields {
+				doc[k] = v
+			}
+		}
+
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		value := json.RawMessage(b)
+		values = append(values, &value)
+	}
+
+	frame := data.NewFrame("", data.NewField(target.RefID, nil, values))
+	frame.RefID = target.RefID
+	queryRes.Frames = data.Frames{frame}
+	return nil
+}
+
+// flattenSource flattens nested objects of a document source into dot separated field names.
+func flattenSource(source map[string]interface{}, prefix string) map[string]interface{} {
+	flattened := make(map[string]interface{})
+	for k, v := range source {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok && len(nested) > 0 {
+			for nk, nv := range flattenSource(nested, key) {
+				flattened[nk] = nv
+			}
+			continue
+		}
+		flattened[key] = v
+	}
+	return flattened
+}
+
+var highlightWordRegex = regexp.MustCompile(regexp.QuoteMeta(es.HighlightPreTagsString) + `(.*?)` + regexp.QuoteMeta(es.HighlightPostTagsString))
+
+// highlightedWords returns the phrases wrapped in highlight tags by Elasticsearch.
+func highlightedWords(highlight map[string]interface{}) []string {
+	keys := make([]string, 0, len(highlight))
+	for k := range highlight {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var words []string
+	for _, k := range keys {
+		lines, ok := highlight[k].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, line := range lines {
+			s, ok := line.(string)
+			if !ok {
+				continue
+			}
+			for _, match := range highlightWordRegex.FindAllStringSubmatch(s, -1) {
+				if match[1] != "" {
+					words = append(words, match[1])
+				}
+			}
+		}
+	}
+	return words
+}
+
+func createTimeField(docs []map[string]interface{}, name string) *data.Field {
+	values := make([]*time.Time, len(docs))
+	for i, doc := range docs {
+		values[i] = parseDocumentTime(doc[name])
+	}
+	field := data.NewField(name, nil, values)
+	field.Config = (&data.FieldConfig{}).SetFilterable(true)
+	return field
+}
+
+func parseDocumentTime(value interface{}) *time.Time {
+	switch v := value.(type) {
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil
+		}
+		return &t
+	case float64:
+		t := time.UnixMilli(int64(v)).UTC()
+		return &t
+	}
+	return nil
+}
+
+func createStringField(docs []map[string]interface{}, name string) *data.Field {
+	values := make([]*string, len(docs))
+	for i, doc := range docs {
+		if s, ok := doc[name].(string); ok {
+			values[i] = &s
+		}
+	}
+	return data.NewField(name, nil, values)
+}
+
+// createDocumentField creates a field with the type guessed from the first value of the
+// property. Properties with values of different types are returned as JSON.
+func createDocumentField(docs []map[string]interface{}, name string) *data.Field {
+	var fieldType data.FieldType
+	for _, doc := range docs {
+		if v := doc[name]; v != nil {
+			fieldType = documentFieldType(v)
+			break
+		}
+	}
+	for _, doc := range docs {
+		if v := doc[name]; v != nil && documentFieldType(v) != fieldType {
+			fieldType = data.FieldTypeNullableJSON
+			break
+		}
+	}
+
+	field := data.NewFieldFromFieldType(fieldType, len(docs))
+	field.Name = name
+	field.Config = (&data.FieldConfig{}).SetFilterable(true)
+	for i, doc := range docs {
+		v := doc[name]
+		if v == nil {
+			continue
+		}
+		switch fieldType {
+		case data.FieldTypeNullableFloat64:
+			f := v.(float64)
+			field.Set(i, &f)
+		case data.FieldTypeNullableString:
+			s := v.(string)
+			field.Set(i, &s)
+		case data.FieldTypeNullableBool:
+			b := v.(bool)
+			field.Set(i, &b)
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			raw := json.RawMessage(b)
+			field.Set(i, &raw)
+		}
+	}
+	return field
+}
+
+func documentFieldType(value interface{}) data.FieldType {
+	switch value.(type) {
+	case float64:
+		return data.FieldTypeNullableFloat64
+	case string:
+		return data.FieldTypeNullableString
+	case bool:
+		return data.FieldTypeNullableBool
+	}
+	return data.FieldTypeNullableJSON
+}
@@ -1181,5 +1181,5 @@ func parseTestResponse(tsdbQueries map[string]string, responseBody string) (*bac
 		return nil, err
 	}
 
-	return parseResponse(response.Responses, queries)
+	return parseResponse(response.Responses, queries, es.ConfiguredFields{TimeField: "@timestamp"})
 }
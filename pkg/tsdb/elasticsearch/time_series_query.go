@@ -56,7 +56,7 @@ func (e *timeSeriesQuery) execute() (*backend.QueryDataResponse, error) {
 		return &backend.QueryDataResponse{}, err
 	}
 
-	return parseResponse(res.Responses, queries)
+	return parseResponse(res.Responses, queries, e.client.GetConfiguredFields())
 }
 
 func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilder, from, to int64,
@@ -76,7 +76,7 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 
 	if len(q.BucketAggs) == 0 {
 		// If no aggregations, only document and logs queries are valid
-		if len(q.Metrics) == 0 || !(q.Metrics[0].Type == "raw_data" || q.Metrics[0].Type == "raw_document" || q.Metrics[0].Type == "logs") {
+		if len(q.Metrics) == 0 || !isDocumentQuery(q) {
 			result.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("invalid query, missing metrics and aggregations"),
 			}
@@ -85,12 +85,17 @@ func (e *timeSeriesQuery) processQuery(q *Query, ms *es.MultiSearchRequestBuilde
 
 		// Defaults for log and document queries
 		metric := q.Metrics[0]
-		b.SortDesc(e.client.GetTimeField(), "boolean")
-		b.SortDesc("_doc", "")
+		order := es.SortOrderDesc
+		if metric.Settings.Get("sortDirection").MustString() == string(es.SortOrderAsc) {
+			order = es.SortOrderAsc
+		}
+		b.Sort(order, e.client.GetTimeField(), "boolean")
+		b.Sort(order, "_doc", "")
+		b.SearchAfter(metric.Settings.Get("searchAfter").MustArray())
 		b.AddDocValueField(e.client.GetTimeField())
 		b.Size(metric.Settings.Get("size").MustInt(500))
 
-		if metric.Type == "logs" {
+		if metric.Type == logsType {
 			// Add additional defaults for log query
 			b.Size(metric.Settings.Get("limit").MustInt(500))
 			b.AddHighlight()
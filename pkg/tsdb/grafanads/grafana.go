@@ -1,19 +1,24 @@
 package grafanads
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/live"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginuser"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/tsdb/testdatasource"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -31,6 +36,9 @@ const DatasourceID = -1
 // Grafana DS command.
 const DatasourceUID = "grafana"
 
+// maxReadFileSize is the size limit of files read by read queries.
+const maxReadFileSize = 20 * 1024 * 1024
+
 // Make sure Service implements required interfaces.
 // This is important to do since otherwise we will only get a
 // not implemented error response from plugin at runtime.
@@ -50,15 +58,20 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, store store.StorageService) *Service {
-	return newService(search, store)
+func ProvideService(search searchV2.SearchService, store store.StorageService, history managedstream.FrameHistory,
+	accessControl accesscontrol.AccessControl, users pluginuser.Resolver) *Service {
+	return newService(search, store, history, accessControl, users)
 }
 
-func newService(search searchV2.SearchService, store store.StorageService) *Service {
+func newService(search searchV2.SearchService, store store.StorageService, history managedstream.FrameHistory,
+	accessControl accesscontrol.AccessControl, users pluginuser.Resolver) *Service {
 	s := &Service{
-		search: search,
-		store:  store,
-		log:    log.New("grafanads"),
+		search:        search,
+		store:         store,
+		history:       history,
+		accessControl: accessControl,
+		users:         users,
+		log:           log.New("grafanads"),
 	}
 
 	return s
@@ -66,9 +79,12 @@ func newService(search searchV2.SearchService, store store.StorageService) *Serv
 
 // Service exists regardless of user settings
 type Service struct {
-	search searchV2.SearchService
-	store  store.StorageService
-	log    log.Logger
+	search        searchV2.SearchService
+	store         store.StorageService
+	history       managedstream.FrameHistory
+	accessControl accesscontrol.AccessControl
+	users         pluginuser.Resolver
+	log           log.Logger
 }
 
 func DataSourceModel(orgId int64) *datasources.DataSource {
@@ -93,9 +109,11 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		case queryTypeList:
 			response.Responses[q.RefID] = s.doListQuery(ctx, q)
 		case queryTypeRead:
-			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
+			response.Responses[q.RefID] = s.doReadQuery(ctx, req.PluginContext, q)
 		case queryTypeSearch:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeHistory:
+			response.Responses[q.RefID] = s.doHistoryQuery(ctx, req.PluginContext, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -131,7 +149,7 @@ func (s *Service) doListQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
-func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) backend.DataResponse {
+func (s *Service) doReadQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) backend.DataResponse {
 	q := &readQueryModel{}
 	response := backend.DataResponse{}
 	err := json.Unmarshal(query.JSON, &q)
@@ -140,19 +158,56 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 		return response
 	}
 
-	if filepath.Ext(q.Path) != ".csv" {
+	if _, ok := fileReaders[strings.ToLower(filepath.Ext(q.Path))]; !ok {
 		response.Error = fmt.Errorf("unsupported file type")
 		return response
 	}
 
-	path := store.RootPublicStatic + "/" + q.Path
-	file, err := s.store.Read(ctx, nil, path)
+	// Public files can be read by anyone, other storage roots check the access of the user
+	var signedInUser *user.SignedInUser
+	root := q.Root
+	if root == "" {
+		root = store.RootPublicStatic
+	}
+	if root != store.RootPublicStatic {
+		signedInUser, err = s.users.SignedInUser(ctx, pCtx.OrgID, pCtx.User)
+		if err != nil {
+			response.Error = err
+			return response
+		}
+	}
+
+	// Files are read into memory as a whole, so large files are rejected before reading them
+	path := root + "/" + q.Path
+	meta, err := s.store.ReadMetadata(ctx, signedInUser, path)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if meta == nil {
+		response.Error = fmt.Errorf("file not found")
+		return response
+	}
+	if meta.Size > maxReadFileSize {
+		response.Error = fmt.Errorf("file is too large to be read, the maximum size is %d bytes", maxReadFileSize)
+		return response
+	}
+
+	file, err := s.store.Read(ctx, signedInUser, path)
 	if err != nil {
 		response.Error = err
 		return response
 	}
+	if file == nil {
+		response.Error = fmt.Errorf("file not found")
+		return response
+	}
+	if len(file.Contents) > maxReadFileSize {
+		response.Error = fmt.Errorf("file is too large to be read, the maximum size is %d bytes", maxReadFileSize)
+		return response
+	}
 
-	frame, err := testdatasource.LoadCsvContent(bytes.NewReader(file.Contents), filepath.Base(path))
+	frame, err := readFile(file.Contents, path, q, query.TimeRange)
 	if err != nil {
 		response.Error = err
 		return response
@@ -161,6 +216,52 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
+// doHistoryQuery returns frames pushed into a managed stream channel within the query time range,
+// so live panels can start with context.
+func (s *Service) doHistoryQuery(ctx context.Context, pCtx backend.PluginContext, query backend.DataQuery) backend.DataResponse {
+	q := &historyQueryModel{}
+	response := backend.DataResponse{}
+	if err := json.Unmarshal(query.JSON, &q); err != nil {
+		response.Error = err
+		return response
+	}
+
+	ch, err := live.ParseChannel(q.Channel)
+	if err != nil || ch.Scope != live.ScopeStream {
+		response.Error = fmt.Errorf("invalid stream channel")
+		return response
+	}
+	if s.history == nil {
+		response.Error = fmt.Errorf("channel history is disabled")
+		return response
+	}
+
+	signedInUser, err := s.users.SignedInUser(ctx, pCtx.OrgID, pCtx.User)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	ok, err := s.accessControl.Evaluate(ctx, signedInUser, accesscontrol.EvalPermission(accesscontrol.ActionLiveSubscribe, accesscontrol.ScopeLiveChannel(q.Channel)))
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if !ok {
+		response.Error = fmt.Errorf("access denied to channel %s", q.Channel)
+		return response
+	}
+
+	frame, err := managedstream.ReadHistory(ctx, s.history, pCtx.OrgID, q.Channel, query.TimeRange.From)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	if frame != nil {
+		response.Frames = data.Frames{frame}
+	}
+	return response
+}
+
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
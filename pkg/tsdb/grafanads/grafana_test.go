@@ -0,0 +1,134 @@
+package grafanads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/live/managedstream"
+	"github.com/grafana/grafana/pkg/services/store"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeUserResolver struct {
+	permissions map[string][]string
+}
+
+func (f *fakeUserResolver) SignedInUser(_ context.Context, orgID int64, u *backend.User) (*user.SignedInUser, error) {
+	return &user.SignedInUser{
+		UserID:      1,
+		OrgID:       orgID,
+		Login:       u.Login,
+		Permissions: map[int64]map[string][]string{orgID: f.permissions},
+	}, nil
+}
+
+func TestHistoryQuery(t *testing.T) {
+	history := managedstream.NewMemoryFrameHistory(time.Hour, 100)
+	for i := 0; i < 3; i++ {
+		frameJSON, err := data.FrameToJSON(data.NewFrame("cpu",
+			data.NewField("time", nil, []time.Time{time.Now()}),
+			data.NewField("value", nil, []float64{float64(i)}),
+		), data.IncludeAll)
+		require.NoError(t, err)
+		require.NoError(t, history.Add(context.Background(), 1, "stream/telegraf/cpu", time.Now(), frameJSON))
+	}
+
+	query := func(t *testing.T, permissions map[string][]string, channel string) backend.DataResponse {
+		s := newService(nil, nil, history, acimpl.ProvideAccessControl(setting.NewCfg()), &fakeUserResolver{permissions: permissions})
+		q, err := json.Marshal(historyQueryModel{Channel: channel})
+		require.NoError(t, err)
+		rsp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, User: &backend.User{Login: "viewer"}},
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				QueryType: queryTypeHistory,
+				JSON:      q,
+				TimeRange: backend.TimeRange{From: time.Now().Add(-time.Minute), To: time.Now()},
+			}},
+		})
+		require.NoError(t, err)
+		return rsp.Responses["A"]
+	}
+
+	t.Run("should return merged channel history", func(t *testing.T) {
+		rsp := query(t, map[string][]string{accesscontrol.ActionLiveSubscribe: {"live:stream/*"}}, "stream/telegraf/cpu")
+		require.NoError(t, rsp.Error)
+		require.Len(t, rsp.Frames, 1)
+		require.Equal(t, 3, rsp.Frames[0].Rows())
+	})
+
+	t.Run("should check the live subscribe permission", func(t *testing.T) {
+		rsp := query(t, map[string][]string{}, "stream/telegraf/cpu")
+		require.Error(t, rsp.Error)
+	})
+
+	t.Run("should only allow stream channels", func(t *testing.T) {
+		rsp := query(t, map[string][]string{accesscontrol.ActionLiveSubscribe: {"*"}}, "grafana/dashboard/uid/abc")
+		require.Error(t, rsp.Error)
+	})
+}
+
+type fakeStorage struct {
+	store.StorageService
+	size  int64
+	user  *user.SignedInUser
+	reads int
+}
+
+func (f *fakeStorage) ReadMetadata(_ context.Context, u *user.SignedInUser, path string) (*filestorage.FileMetadata, error) {
+	f.user = u
+	return &filestorage.FileMetadata{FullPath: path, Size: f.size}, nil
+}
+
+func (f *fakeStorage) Read(_ context.Context, u *user.SignedInUser, path string) (*filestorage.File, error) {
+	f.reads++
+	contents := []byte("time,value\n2022-01-01T00:00:00Z,1\n")
+	return &filestorage.File{Contents: contents, FileMetadata: filestorage.FileMetadata{FullPath: path, Size: int64(len(contents))}}, nil
+}
+
+func TestReadQuery(t *testing.T) {
+	query := func(t *testing.T, storage *fakeStorage, model readQueryModel) backend.DataResponse {
+		s := newService(nil, storage, nil, acimpl.ProvideAccessControl(setting.NewCfg()), &fakeUserResolver{})
+		q, err := json.Marshal(model)
+		require.NoError(t, err)
+		rsp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{OrgID: 1, User: &backend.User{Login: "viewer"}},
+			Queries:       []backend.DataQuery{{RefID: "A", QueryType: queryTypeRead, JSON: q}},
+		})
+		require.NoError(t, err)
+		return rsp.Responses["A"]
+	}
+
+	t.Run("should read files as the resolved user", func(t *testing.T) {
+		storage := &fakeStorage{size: 34}
+		rsp := query(t, storage, readQueryModel{Root: "content", Path: "data.csv"})
+		require.NoError(t, rsp.Error)
+		require.Len(t, rsp.Frames, 1)
+		require.Equal(t, int64(1), storage.user.UserID)
+		require.Equal(t, "viewer", storage.user.Login)
+	})
+
+	t.Run("should read public files without a user", func(t *testing.T) {
+		storage := &fakeStorage{size: 34}
+		rsp := query(t, storage, readQueryModel{Path: "data.csv"})
+		require.NoError(t, rsp.Error)
+		require.Nil(t, storage.user)
+	})
+
+	t.Run("should reject files larger than the limit before reading them", func(t *testing.T) {
+		storage := &fakeStorage{size: maxReadFileSize + 1}
+		rsp := query(t, storage, readQueryModel{Root: "content", Path: "data.parquet"})
+		require.Error(t, rsp.Error)
+		require.Equal(t, 0, storage.reads)
+	})
+}
@@ -11,9 +11,11 @@ const (
 	queryTypeList = "list"
 
 	// QueryTypeRead will read a file and return it as data frames
-	// currently only .csv files are supported,
-	// other file types will eventually be supported (parquet, etc)
+	// .csv, .arrow, .parquet and .jsonl files are supported
 	queryTypeRead = "read"
+
+	// QueryTypeHistory returns the history of a managed stream channel within the query time range
+	queryTypeHistory = "history"
 )
 
 type listQueryModel struct {
@@ -21,4 +23,15 @@ type listQueryModel struct {
 }
 type readQueryModel struct {
 	Path string `json:"path"`
+	// Root is the storage root of the path, public-static by default
+	Root string `json:"root,omitempty"`
+	// Columns are the columns to return, all columns by default
+	Columns []string `json:"columns,omitempty"`
+	// TimeField is the field used to filter rows by the query time range, rows are not filtered by default
+	TimeField string `json:"timeField,omitempty"`
+}
+
+type historyQueryModel struct {
+	// Channel is a managed stream channel, i.e. stream/telegraf/cpu
+	Channel string `json:"channel"`
 }
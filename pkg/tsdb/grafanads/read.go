@@ -0,0 +1,356 @@
+package grafanads
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/tsdb/testdatasource"
+)
+
+// fileReaders read the contents of a file into a frame, by file extension.
+var fileReaders = map[string]func(contents []byte, name string) (*data.Frame, error){
+	".csv": func(contents []byte, name string) (*data.Frame, error) {
+		return testdatasource.LoadCsvContent(bytes.NewReader(contents), name)
+	},
+	".arrow":   readArrow,
+	".feather": readArrow,
+	".parquet": readParquet,
+	".jsonl":   readJSONLines,
+	".ndjson":  readJSONLines,
+}
+
+// readFile reads a file into a frame, then selects the columns and filters the rows of the query.
+func readFile(contents []byte, path string, q *readQueryModel, timeRange backend.TimeRange) (*data.Frame, error) {
+	reader, ok := fileReaders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported file type")
+	}
+
+	frame, err := reader(contents, filepath.Base(path))
+	if err != nil {
+		return nil, err
+	}
+	if frame.Name == "" {
+		frame.Name = filepath.Base(path)
+	}
+
+	if q.TimeField != "" {
+		frame, err = filterTimeRange(frame, q.TimeField, timeRange)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(q.Columns) > 0 {
+		frame, err = selectColumns(frame, q.Columns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return frame, nil
+}
+
+// selectColumns returns a frame with the given fields of frame, in the given order.
+func selectColumns(frame *data.Frame, columns []string) (*data.Frame, error) {
+	fields := make([]*data.Field, 0, len(columns))
+	for _, name := range columns {
+		field, idx := frame.FieldByName(name)
+		if idx == -1 {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+		fields = append(fields, field)
+	}
+	selected := data.NewFrame(frame.Name, fields...)
+	selected.Meta = frame.Meta
+	return selected, nil
+}
+
+// filterTimeRange returns the rows of frame where the time field is within the time range. Fields which aren't time
+// fields yet are converted: numbers are Unix timestamps in milliseconds, and strings are RFC 3339 timestamps.
+func filterTimeRange(frame *data.Frame, timeField string, timeRange backend.TimeRange) (*data.Frame, error) {
+	field, idx := frame.FieldByName(timeField)
+	if idx == -1 {
+		return nil, fmt.Errorf("time field %q not found", timeField)
+	}
+	times, err := toTimeField(field)
+	if err != nil {
+		return nil, err
+	}
+	frame.Fields[idx] = times
+
+	return frame.FilterRowsByField(idx, func(v interface{}) (bool, error) {
+		t, ok := v.(*time.Time)
+		if !ok || t == nil {
+			return false, nil
+		}
+		return !t.Before(timeRange.From) && !t.After(timeRange.To), nil
+	})
+}
+
+func toTimeField(field *data.Field) (*data.Field, error) {
+	times := data.NewFieldFromFieldType(data.FieldTypeNullableTime, field.Len())
+	times.Name = field.Name
+	times.Labels = field.Labels
+	times.Config = field.Config
+
+	for i := 0; i < field.Len(); i++ {
+		v, ok := field.ConcreteAt(i)
+		if !ok {
+			continue
+		}
+		var t time.Time
+		switch v := v.(type) {
+		case time.Time:
+			t = v
+		case string:
+			parsed, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse time %q of field %q: %w", v, field.Name, err)
+			}
+			t = parsed
+		default:
+			f, err := field.NullableFloatAt(i)
+			if err != nil || f == nil {
+				return nil, fmt.Errorf("field %q can't be converted to time", field.Name)
+			}
+			t = time.UnixMilli(int64(*f)).UTC()
+		}
+		times.Set(i, &t)
+	}
+	return times, nil
+}
+
+func readArrow(contents []byte, _ string) (*data.Frame, error) {
+	return data.UnmarshalArrowFrame(contents)
+}
+
+// readParquet reads a Parquet file with a flat schema. Timestamp and date columns are read as time fields.
+func readParquet(contents []byte, name string) (*data.Frame, error) {
+	mem := memory.NewGoAllocator()
+	table, err := pqarrow.ReadTable(context.Background(), bytes.NewReader(contents), parquet.NewReaderProperties(mem), pqarrow.ArrowReadProperties{}, mem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parquet file: %w", err)
+	}
+	defer table.Release()
+
+	fields := make([]*data.Field, 0, table.NumCols())
+	for i := 0; i < int(table.NumCols()); i++ {
+		column := table.Column(i)
+		fieldType, value, err := arrowColumnType(column.Name(), column.DataType())
+		if err != nil {
+			return nil, err
+		}
+		field := data.NewFieldFromFieldType(fieldType, 0)
+		field.Name = column.Name()
+		for _, chunk := range column.Data().Chunks() {
+			for j := 0; j < chunk.Len(); j++ {
+				if chunk.IsNull(j) {
+					field.Append(nil)
+					continue
+				}
+				field.Append(value(chunk, j))
+			}
+		}
+		fields = append(fields, field)
+	}
+	return data.NewFrame(name, fields...), nil
+}
+
+// arrowColumnType returns the nullable field type of an Arrow column, and a function returning a non null value of
+// a chunk of the column as a pointer of that type.
+func arrowColumnType(name string, dataType arrow.DataType) (data.FieldType, func(arrow.Array, int) interface{}, error) {
+	switch dataType.ID() {
+	case arrow.BOOL:
+		return data.FieldTypeNullableBool, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Boolean).Value(i)
+			return &v
+		}, nil
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32:
+		return data.FieldTypeNullableInt64, func(a arrow.Array, i int) interface{} {
+			var v int64
+			switch a := a.(type) {
+			case *array.Int8:
+				v = int64(a.Value(i))
+			case *array.Int16:
+				v = int64(a.Value(i))
+			case *array.Int32:
+				v = int64(a.Value(i))
+			case *array.Int64:
+				v = a.Value(i)
+			case *array.Uint8:
+				v = int64(a.Value(i))
+			case *array.Uint16:
+				v = int64(a.Value(i))
+			case *array.Uint32:
+				v = int64(a.Value(i))
+			}
+			return &v
+		}, nil
+	case arrow.UINT64:
+		return data.FieldTypeNullableUint64, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Uint64).Value(i)
+			return &v
+		}, nil
+	case arrow.FLOAT32:
+		return data.FieldTypeNullableFloat64, func(a arrow.Array, i int) interface{} {
+			v := float64(a.(*array.Float32).Value(i))
+			return &v
+		}, nil
+	case arrow.FLOAT64:
+		return data.FieldTypeNullableFloat64, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Float64).Value(i)
+			return &v
+		}, nil
+	case arrow.STRING:
+		return data.FieldTypeNullableString, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.String).Value(i)
+			return &v
+		}, nil
+	case arrow.BINARY:
+		return data.FieldTypeNullableString, func(a arrow.Array, i int) interface{} {
+			v := string(a.(*array.Binary).Value(i))
+			return &v
+		}, nil
+	case arrow.TIMESTAMP:
+		unit := dataType.(*arrow.TimestampType).Unit
+		return data.FieldTypeNullableTime, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Timestamp).Value(i).ToTime(unit)
+			return &v
+		}, nil
+	case arrow.DATE32:
+		return data.FieldTypeNullableTime, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Date32).Value(i).ToTime()
+			return &v
+		}, nil
+	case arrow.DATE64:
+		return data.FieldTypeNullableTime, func(a arrow.Array, i int) interface{} {
+			v := a.(*array.Date64).Value(i).ToTime()
+			return &v
+		}, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported type %s of column %q", dataType, name)
+	}
+}
+
+// readJSONLines reads newline delimited JSON objects, where each object is a row. The columns are the keys of the
+// objects in the order they first appear. Columns where all values are numbers or booleans are read as numbers or
+// booleans, other values are read as strings, with objects and arrays encoded as JSON.
+func readJSONLines(contents []byte, name string) (*data.Frame, error) {
+	var columns []string
+	values := map[string][]interface{}{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(contents)+1)
+	rows := 0
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		row := map[string]interface{}{}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("failed to parse line %d: %w", line, err)
+		}
+
+		// Map iteration order is random, so new columns of a line are added by name.
+		var newColumns []string
+		for k := range row {
+			if _, ok := values[k]; !ok {
+				newColumns = append(newColumns, k)
+			}
+		}
+		sort.Strings(newColumns)
+		for _, k := range newColumns {
+			columns = append(columns, k)
+			values[k] = make([]interface{}, rows)
+		}
+		for _, k := range columns {
+			values[k] = append(values[k], row[k])
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	fields := make([]*data.Field, 0, len(columns))
+	for _, k := range columns {
+		fields = append(fields, jsonValuesToField(k, values[k]))
+	}
+	return data.NewFrame(name, fields...), nil
+}
+
+func jsonValuesToField(name string, values []interface{}) *data.Field {
+	numbers, bools := true, true
+	for _, v := range values {
+		switch v.(type) {
+		case nil:
+		case json.Number:
+			bools = false
+		case bool:
+			numbers = false
+		default:
+			numbers, bools = false, false
+		}
+	}
+
+	switch {
+	case numbers:
+		field := data.NewFieldFromFieldType(data.FieldTypeNullableFloat64, len(values))
+		for i, v := range values {
+			if n, ok := v.(json.Number); ok {
+				if f, err := strconv.ParseFloat(n.String(), 64); err == nil {
+					field.Set(i, &f)
+				}
+			}
+		}
+		field.Name = name
+		return field
+	case bools:
+		field := data.NewFieldFromFieldType(data.FieldTypeNullableBool, len(values))
+		for i, v := range values {
+			if b, ok := v.(bool); ok {
+				field.Set(i, &b)
+			}
+		}
+		field.Name = name
+		return field
+	default:
+		field := data.NewFieldFromFieldType(data.FieldTypeNullableString, len(values))
+		for i, v := range values {
+			var s string
+			switch v := v.(type) {
+			case nil:
+				continue
+			case string:
+				s = v
+			case json.Number:
+				s = v.String()
+			default:
+				b, _ := json.Marshal(v)
+				s = string(b)
+			}
+			field.Set(i, &s)
+		}
+		field.Name = name
+		return field
+	}
+}
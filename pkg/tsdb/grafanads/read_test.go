@@ -0,0 +1,126 @@
+package grafanads
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/v8/arrow"
+	"github.com/apache/arrow/go/v8/arrow/array"
+	"github.com/apache/arrow/go/v8/arrow/memory"
+	"github.com/apache/arrow/go/v8/parquet/pqarrow"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+var testTimeRange = backend.TimeRange{
+	From: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	To:   time.Date(2022, 1, 1, 1, 0, 0, 0, time.UTC),
+}
+
+func TestReadFile(t *testing.T) {
+	t.Run("csv with columns and time filter", func(t *testing.T) {
+		csv := "time,host,value\n" +
+			"2021-12-31T23:59:00Z,a,1\n" +
+			"2022-01-01T00:10:00Z,b,2\n" +
+			"2022-01-01T00:20:00Z,c,3\n"
+
+		frame, err := readFile([]byte(csv), "public-static/data.csv", &readQueryModel{TimeField: "time", Columns: []string{"value", "time"}}, testTimeRange)
+		require.NoError(t, err)
+		require.Equal(t, "data.csv", frame.Name)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "value", frame.Fields[0].Name)
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[1].Type())
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		jsonl := `{"ts": 1640995800000, "host": "a", "up": true, "tags": ["x"]}` + "\n\n" +
+			`{"host": "b", "ts": 1640996400000, "latency": 1.5}` + "\n"
+
+		frame, err := readFile([]byte(jsonl), "data.jsonl", &readQueryModel{TimeField: "ts"}, testTimeRange)
+		require.NoError(t, err)
+		require.Equal(t, 2, frame.Rows())
+
+		names := make([]string, 0, len(frame.Fields))
+		for _, f := range frame.Fields {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{"host", "tags", "ts", "up", "latency"}, names)
+		require.Equal(t, data.FieldTypeNullableString, frame.Fields[0].Type())
+		require.Equal(t, `["x"]`, *frame.Fields[1].At(0).(*string))
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[2].Type())
+		require.Equal(t, data.FieldTypeNullableBool, frame.Fields[3].Type())
+		require.Nil(t, frame.Fields[4].At(0))
+		require.Equal(t, 1.5, *frame.Fields[4].At(1).(*float64))
+	})
+
+	t.Run("parquet", func(t *testing.T) {
+		mem := memory.NewGoAllocator()
+		schema := arrow.NewSchema([]arrow.Field{
+			{Name: "time", Type: &arrow.TimestampType{Unit: arrow.Millisecond}},
+			{Name: "host", Type: arrow.BinaryTypes.String},
+			{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			{Name: "count", Type: arrow.PrimitiveTypes.Int32},
+		}, nil)
+		b := array.NewRecordBuilder(mem, schema)
+		defer b.Release()
+		for _, ts := range []time.Time{
+			time.Date(2022, 1, 1, 0, 10, 0, 0, time.UTC),
+			time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2022, 1, 1, 0, 20, 0, 0, time.UTC),
+		} {
+			b.Field(0).(*array.TimestampBuilder).Append(arrow.Timestamp(ts.UnixMilli()))
+		}
+		b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "b", "c"}, nil)
+		b.Field(2).(*array.Float64Builder).AppendValues([]float64{2.5, 0, 3}, []bool{true, false, true})
+		b.Field(3).(*array.Int32Builder).AppendValues([]int32{1, 2, 3}, nil)
+		record := b.NewRecord()
+		defer record.Release()
+		table := array.NewTableFromRecords(schema, []arrow.Record{record})
+		defer table.Release()
+
+		buf := &bytes.Buffer{}
+		require.NoError(t, pqarrow.WriteTable(table, buf, 1024, nil, pqarrow.DefaultWriterProps()))
+
+		frame, err := readFile(buf.Bytes(), "data.parquet", &readQueryModel{}, testTimeRange)
+		require.NoError(t, err)
+		require.Equal(t, 3, frame.Rows())
+		host, _ := frame.FieldByName("host")
+		require.Equal(t, "b", *host.At(1).(*string))
+		v, _ := frame.FieldByName("value")
+		require.Equal(t, 2.5, *v.At(0).(*float64))
+		require.Nil(t, v.At(1))
+		count, _ := frame.FieldByName("count")
+		require.Equal(t, int64(3), *count.At(2).(*int64))
+		ts, _ := frame.FieldByName("time")
+		require.Equal(t, time.Date(2022, 1, 1, 0, 10, 0, 0, time.UTC), ts.At(0).(*time.Time).UTC())
+
+		frame, err = readFile(buf.Bytes(), "data.parquet", &readQueryModel{TimeField: "time", Columns: []string{"host"}}, testTimeRange)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 1)
+		require.Equal(t, 2, frame.Rows())
+	})
+
+	t.Run("arrow", func(t *testing.T) {
+		raw, err := data.NewFrame("metrics", data.NewField("value", nil, []float64{1, 2})).MarshalArrow()
+		require.NoError(t, err)
+
+		frame, err := readFile(raw, "data.arrow", &readQueryModel{}, testTimeRange)
+		require.NoError(t, err)
+		require.Equal(t, "metrics", frame.Name)
+		require.Equal(t, 2, frame.Rows())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := readFile([]byte("a\n1\n"), "data.txt", &readQueryModel{}, testTimeRange)
+		require.EqualError(t, err, "unsupported file type")
+
+		_, err = readFile([]byte("a\n1\n"), "data.csv", &readQueryModel{Columns: []string{"b"}}, testTimeRange)
+		require.EqualError(t, err, `column "b" not found`)
+
+		_, err = readFile([]byte("{\"a\": 1}\n{"), "data.jsonl", &readQueryModel{}, testTimeRange)
+		require.ErrorContains(t, err, "failed to parse line 2")
+	})
+}
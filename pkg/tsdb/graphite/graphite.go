@@ -36,6 +36,12 @@ type Service struct {
 	tracer tracing.Tracer
 }
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+	_ backend.CheckHealthHandler  = (*Service)(nil)
+)
+
 const (
 	TargetFullModelField = "targetFull"
 	TargetModelField     = "target"
@@ -0,0 +1,48 @@
+package graphite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+const healthCheckRefID = "healthcheck"
+
+// CheckHealth renders a constant line for the last hour, which fails unless the URL points to a
+// reachable Graphite API returning valid series.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	now := time.Now()
+	res, err := s.QueryData(ctx, &backend.QueryDataRequest{
+		PluginContext: req.PluginContext,
+		Queries: []backend.DataQuery{
+			{
+				RefID:     healthCheckRefID,
+				JSON:      []byte(`{"target": "constantLine(100)"}`),
+				TimeRange: backend.TimeRange{From: now.Add(-time.Hour), To: now},
+			},
+		},
+	})
+	if err != nil {
+		logger.Warn("Graphite health check failed", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("Graphite health check failed: %s", err.Error()),
+		}, nil
+	}
+
+	if _, ok := res.Responses[healthCheckRefID]; !ok {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: "Graphite health check failed: no series returned",
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
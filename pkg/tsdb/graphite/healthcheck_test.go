@@ -0,0 +1,44 @@
+package graphite
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("should succeed when Graphite renders constant line", func(t *testing.T) {
+		service, received := newGraphiteServer(t, http.StatusOK, `[{"target": "constantLine(100) healthcheck", "datapoints": [[100, 1668422400], [100, 1668426000]]}]`)
+		service.tracer = tracing.InitializeTracerForTest()
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+		require.Equal(t, "/graphite/render", received.path)
+		require.Equal(t, []string{`aliasSub(constantLine(100),"(^.*$)","\1 healthcheck")`}, received.params["target"])
+	})
+
+	t.Run("should fail when Graphite returns an error", func(t *testing.T) {
+		service, _ := newGraphiteServer(t, http.StatusNotFound, "not found")
+		service.tracer = tracing.InitializeTracerForTest()
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, "Graphite health check failed: request failed, status: 404 Not Found", res.Message)
+	})
+
+	t.Run("should fail when response isn't a Graphite response", func(t *testing.T) {
+		service, _ := newGraphiteServer(t, http.StatusOK, "<html>Welcome</html>")
+		service.tracer = tracing.InitializeTracerForTest()
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+	})
+}
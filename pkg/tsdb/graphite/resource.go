@@ -0,0 +1,164 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// resourceParams lists the query parameters forwarded to Graphite for each resource
+var resourceParams = map[string][]string{
+	"metrics/find":             {"query", "from", "until"},
+	"tags/autoComplete/tags":   {"expr", "tagPrefix", "limit", "from", "until"},
+	"tags/autoComplete/values": {"expr", "tag", "valuePrefix", "limit", "from", "until"},
+	"functions":                {},
+}
+
+// Graphite 1.1.7 returns Infinity as default value of some function parameters, which isn't valid JSON.
+// See https://github.com/graphite-project/graphite-web/issues/2609
+var infinityDefault = regexp.MustCompile(`"default": ?Infinity`)
+
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return err
+	}
+
+	resourcePath := strings.Trim(req.Path, "/")
+	allowedParams, ok := resourceParams[resourcePath]
+	if !ok {
+		return sendResourceError(sender, http.StatusNotFound, fmt.Errorf("unknown resource: %s", req.Path))
+	}
+
+	params, err := resourceRequestParams(req)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadRequest, err)
+	}
+	for name := range params {
+		if !contains(allowedParams, name) {
+			delete(params, name)
+		}
+	}
+
+	graphiteReq, err := s.createResourceRequest(ctx, dsInfo, resourcePath, params)
+	if err != nil {
+		return err
+	}
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if err != nil {
+		return sendResourceError(sender, http.StatusBadGateway, err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Resource request failed", "path", resourcePath, "status", res.Status, "body", string(body))
+		return sendResourceError(sender, res.StatusCode, fmt.Errorf("request failed, status: %s", res.Status))
+	}
+
+	if resourcePath == "functions" {
+		body = infinityDefault.ReplaceAll(body, []byte(`"default": 1e9999`))
+	}
+
+	return sender.Send(&backend.CallResourceResponse{
+		Status: http.StatusOK,
+		Headers: map[string][]string{
+			"content-type": {"application/json"},
+		},
+		Body: body,
+	})
+}
+
+// resourceRequestParams returns the parameters of a resource request, which are either passed in the
+// URL or, for POST requests, as form data in the body
+func resourceRequestParams(req *backend.CallResourceRequest) (url.Values, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	params := u.Query()
+
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		form, err := url.ParseQuery(string(req.Body))
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range form {
+			params[name] = append(params[name], values...)
+		}
+	default:
+		return nil, fmt.Errorf("invalid resource method: %s", req.Method)
+	}
+
+	return params, nil
+}
+
+// createResourceRequest creates a request to a Graphite API endpoint. Metric find requests are sent as
+// form data, since queries can be too long for the URL.
+func (s *Service) createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, resourcePath string, params url.Values) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, resourcePath)
+
+	if resourcePath == "metrics/find" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(params.Encode()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	}
+
+	u.RawQuery = params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return req, nil
+}
+
+func sendResourceError(sender backend.CallResourceResponseSender, status int, err error) error {
+	body, marshalErr := json.Marshal(map[string]string{"message": err.Error()})
+	if marshalErr != nil {
+		return marshalErr
+	}
+	return sender.Send(&backend.CallResourceResponse{
+		Status: status,
+		Headers: map[string][]string{
+			"content-type": {"application/json"},
+		},
+		Body: body,
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package graphite
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDSInstanceManager struct {
+	info datasourceInfo
+}
+
+func (f fakeDSInstanceManager) Get(pluginContext backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.info, nil
+}
+
+func (f fakeDSInstanceManager) Do(pluginContext backend.PluginContext, fn instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type graphiteRequest struct {
+	method string
+	path   string
+	params url.Values
+}
+
+func newGraphiteServer(t *testing.T, status int, body string) (*Service, *graphiteRequest) {
+	t.Helper()
+
+	received := &graphiteRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		received.method = r.Method
+		received.path = r.URL.Path
+		received.params = r.Form
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: fakeDSInstanceManager{info: datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL + "/graphite"}},
+	}
+	return service, received
+}
+
+func callResource(t *testing.T, service *Service, req *backend.CallResourceRequest) *backend.CallResourceResponse {
+	t.Helper()
+
+	sender := &fakeSender{}
+	err := service.CallResource(context.Background(), req, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.res)
+	return sender.res
+}
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (f *fakeSender) Send(res *backend.CallResourceResponse) error {
+	f.res = res
+	return nil
+}
+
+func TestCallResource(t *testing.T) {
+	t.Run("should send metric find query as form data", func(t *testing.T) {
+		service, received := newGraphiteServer(t, http.StatusOK, `[{"text": "cpu", "expandable": 1}]`)
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodPost,
+			Path:   "metrics/find",
+			URL:    "metrics/find?from=now-1h&until=now",
+			Body:   []byte("query=servers.*"),
+		})
+
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `[{"text": "cpu", "expandable": 1}]`, string(res.Body))
+		require.Equal(t, http.MethodPost, received.method)
+		require.Equal(t, "/graphite/metrics/find", received.path)
+		require.Equal(t, url.Values{"query": {"servers.*"}, "from": {"now-1h"}, "until": {"now"}}, received.params)
+	})
+
+	t.Run("should forward tag autocomplete parameters", func(t *testing.T) {
+		service, received := newGraphiteServer(t, http.StatusOK, `["server1", "server2"]`)
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "tags/autoComplete/values",
+			URL:    "tags/autoComplete/values?expr=name%3Dcpu&expr=dc%3Deu&tag=server&valuePrefix=serv&limit=10&other=1",
+		})
+
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, http.MethodGet, received.method)
+		require.Equal(t, "/graphite/tags/autoComplete/values", received.path)
+		require.Equal(t, url.Values{
+			"expr":        {"name=cpu", "dc=eu"},
+			"tag":         {"server"},
+			"valuePrefix": {"serv"},
+			"limit":       {"10"},
+		}, received.params)
+	})
+
+	t.Run("should fix infinite defaults in function list", func(t *testing.T) {
+		service, _ := newGraphiteServer(t, http.StatusOK, `{"limit": {"params": [{"name": "n", "default": Infinity}]}}`)
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "functions",
+			URL:    "functions",
+		})
+
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, `{"limit": {"params": [{"name": "n", "default": 1e9999}]}}`, string(res.Body))
+	})
+
+	t.Run("should return error status of Graphite", func(t *testing.T) {
+		service, _ := newGraphiteServer(t, http.StatusUnauthorized, "unauthorized")
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "tags/autoComplete/tags",
+			URL:    "tags/autoComplete/tags",
+		})
+
+		require.Equal(t, http.StatusUnauthorized, res.Status)
+		require.JSONEq(t, `{"message": "request failed, status: 401 Unauthorized"}`, string(res.Body))
+	})
+
+	t.Run("should reject unknown resources", func(t *testing.T) {
+		service, received := newGraphiteServer(t, http.StatusOK, "")
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodGet,
+			Path:   "render",
+			URL:    "render?target=servers.*",
+		})
+
+		require.Equal(t, http.StatusNotFound, res.Status)
+		require.Empty(t, received.path)
+	})
+
+	t.Run("should reject unsupported methods", func(t *testing.T) {
+		service, received := newGraphiteServer(t, http.StatusOK, "")
+
+		res := callResource(t, service, &backend.CallResourceRequest{
+			Method: http.MethodDelete,
+			Path:   "metrics/find",
+			URL:    "metrics/find",
+		})
+
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Empty(t, received.path)
+	})
+}
@@ -3,14 +3,17 @@ package loki
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
@@ -51,6 +54,11 @@ type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
 
+	// range queries longer than QueryChunkInterval are split into chunks, of which
+	// QueryChunkConcurrency are run at the same time
+	QueryChunkInterval    time.Duration
+	QueryChunkConcurrency int
+
 	// open streams
 	streams   map[string]data.FrameJSONCache
 	streamsMu sync.RWMutex
@@ -68,6 +76,11 @@ type QueryJSONModel struct {
 	VolumeQuery  bool   `json:"volumeQuery"`
 }
 
+type datasourceJSONData struct {
+	QueryChunkInterval    string `json:"queryChunkInterval"`
+	QueryChunkConcurrency int    `json:"queryChunkConcurrency"`
+}
+
 func parseQueryModel(raw json.RawMessage) (*QueryJSONModel, error) {
 	model := &QueryJSONModel{}
 	err := json.Unmarshal(raw, model)
@@ -86,10 +99,27 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		jsonData := datasourceJSONData{}
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jsonData); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
+		var chunkInterval time.Duration
+		if jsonData.QueryChunkInterval != "" {
+			chunkInterval, err = gtime.ParseDuration(jsonData.QueryChunkInterval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query chunk interval: %w", err)
+			}
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
-			streams:    make(map[string]data.FrameJSONCache),
+			HTTPClient:            client,
+			URL:                   settings.URL,
+			QueryChunkInterval:    chunkInterval,
+			QueryChunkConcurrency: jsonData.QueryChunkConcurrency,
+			streams:               make(map[string]data.FrameJSONCache),
 		}
 		return model, nil
 	}
@@ -166,14 +196,16 @@ func queryData(ctx context.Context, req *backend.QueryDataRequest, dsInfo *datas
 		logger := logger.FromContext(ctx) // get logger with trace-id and other contextual info
 		logger.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", query.Expr)
 
-		frames, err := runQuery(ctx, api, query)
+		frames, err := runSplitQuery(ctx, api, query, dsInfo.QueryChunkInterval, dsInfo.QueryChunkConcurrency)
 
 		span.End()
 		queryRes := backend.DataResponse{}
 
+		var partialErr *partialResultError
 		if err != nil {
 			queryRes.Error = err
-		} else {
+		}
+		if err == nil || errors.As(err, &partialErr) {
 			queryRes.Frames = frames
 		}
 
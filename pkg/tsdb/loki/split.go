@@ -0,0 +1,288 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const defaultChunkConcurrency = 4
+
+// partialResultError is returned when some chunks of a split query failed. The frames of the other chunks are
+// still returned, marked with a notice.
+type partialResultError struct {
+	failed int
+	total  int
+	err    error
+}
+
+func (e *partialResultError) Error() string {
+	return fmt.Sprintf("partial result: %d of %d time ranges failed: %v", e.failed, e.total, e.err)
+}
+
+func (e *partialResultError) Unwrap() error {
+	return e.err
+}
+
+// splitQuery splits a range query into consecutive chunks covering at most chunkSize each. Chunk boundaries are
+// aligned to the step, and every chunk but the last ends right before the next one starts, so no sample or line is
+// returned twice. Chunks are ordered in the direction of the query, so backward queries start with the newest chunk.
+func splitQuery(query *lokiQuery, chunkSize time.Duration) []*lokiQuery {
+	if query.QueryType != QueryTypeRange || chunkSize <= 0 || query.End.Sub(query.Start) <= chunkSize {
+		return []*lokiQuery{query}
+	}
+
+	if query.Step > 0 && chunkSize%query.Step != 0 {
+		chunkSize = (chunkSize/query.Step + 1) * query.Step
+	}
+
+	var chunks []*lokiQuery
+	for start := query.Start; ; {
+		end := start.Add(chunkSize)
+		chunk := *query
+		chunk.Start = start
+		if !end.Before(query.End) {
+			chunk.End = query.End
+			chunks = append(chunks, &chunk)
+			break
+		}
+		chunk.End = end.Add(-time.Nanosecond)
+		chunks = append(chunks, &chunk)
+		start = end
+	}
+
+	if query.Direction == DirectionBackward {
+		for i, j := 0, len(chunks)-1; i < j; i, j = i+1, j-1 {
+			chunks[i], chunks[j] = chunks[j], chunks[i]
+		}
+	}
+	return chunks
+}
+
+type chunkResult struct {
+	frames  data.Frames
+	err     error
+	done    bool
+	skipped bool
+}
+
+// runSplitQuery runs a range query in chunks of the configured size with bounded concurrency and merges the frames
+// of all chunks. Once the chunks at the start of a logs query return the line limit, the remaining chunks are skipped.
+func runSplitQuery(ctx context.Context, api *LokiAPI, query *lokiQuery, chunkSize time.Duration, concurrency int) (data.Frames, error) {
+	chunks := splitQuery(query, chunkSize)
+	if len(chunks) == 1 {
+		return runQuery(ctx, api, query)
+	}
+
+	if concurrency < 1 {
+		concurrency = defaultChunkConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make([]chunkResult, len(chunks))
+		next    = make(chan int)
+	)
+
+	for w := 0; w < concurrency && w < len(chunks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				mu.Lock()
+				skip := lineLimitReached(results[:i], query.MaxLines)
+				if skip {
+					results[i] = chunkResult{done: true, skipped: true}
+				}
+				mu.Unlock()
+				if skip {
+					continue
+				}
+
+				frames, err := runQuery(ctx, api, chunks[i])
+
+				mu.Lock()
+				results[i] = chunkResult{frames: frames, err: err, done: true}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range chunks {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var (
+		failed   int
+		firstErr error
+		ordered  = make([]data.Frames, 0, len(chunks))
+	)
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		if !r.skipped {
+			ordered = append(ordered, r.frames)
+		}
+	}
+
+	if failed == len(chunks) {
+		return data.Frames{}, firstErr
+	}
+
+	frames := mergeChunkFrames(ordered, query)
+	if failed > 0 {
+		partialErr := &partialResultError{failed: failed, total: len(chunks), err: firstErr}
+		for _, frame := range frames {
+			frame.AppendNotices(data.Notice{
+				Severity: data.NoticeSeverityWarning,
+				Text:     partialErr.Error(),
+			})
+		}
+		return frames, partialErr
+	}
+	return frames, nil
+}
+
+// lineLimitReached returns true if the completed chunks at the start of the results already contain the line limit.
+func lineLimitReached(results []chunkResult, maxLines int) bool {
+	if maxLines <= 0 {
+		return false
+	}
+
+	lines := 0
+	for _, r := range results {
+		if !r.done || r.err != nil {
+			return false
+		}
+		for _, frame := range r.frames {
+			if !isLogsFrame(frame) {
+				return false
+			}
+			lines += frame.Rows()
+		}
+		if lines >= maxLines {
+			return true
+		}
+	}
+	return false
+}
+
+func isLogsFrame(frame *data.Frame) bool {
+	return len(frame.Fields) > 1 && frame.Fields[1].Type() != data.FieldTypeFloat64
+}
+
+// mergeChunkFrames merges the frames of the chunks, which are in the order of the query direction. Log lines are
+// concatenated up to the line limit, metric series are concatenated in time order.
+func mergeChunkFrames(chunkFrames []data.Frames, query *lokiQuery) data.Frames {
+	chronological := chunkFrames
+	if query.Direction == DirectionBackward {
+		chronological = make([]data.Frames, len(chunkFrames))
+		for i, frames := range chunkFrames {
+			chronological[len(chunkFrames)-1-i] = frames
+		}
+	}
+
+	var (
+		merged  data.Frames
+		byKey   = map[string]*data.Frame{}
+		statsOf = map[*data.Frame][][]data.QueryStat{}
+	)
+
+	add := func(frame *data.Frame, key string, maxRows int) {
+		existing, ok := byKey[key]
+		if !ok {
+			// EmptyCopy doesn't copy the frame meta and field configs set by adjustFrame
+			existing = frame.EmptyCopy()
+			existing.Meta = &data.FrameMeta{}
+			if frame.Meta != nil {
+				meta := *frame.Meta
+				existing.Meta = &meta
+			}
+			for i, field := range existing.Fields {
+				field.Config = frame.Fields[i].Config
+			}
+			byKey[key] = existing
+			merged = append(merged, existing)
+		}
+		if frame.Meta != nil {
+			statsOf[existing] = append(statsOf[existing], frame.Meta.Stats)
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			if maxRows > 0 && existing.Rows() >= maxRows {
+				return
+			}
+			existing.AppendRow(frame.RowCopy(i)...)
+		}
+	}
+
+	for _, frames := range chunkFrames {
+		for _, frame := range frames {
+			if isLogsFrame(frame) {
+				add(frame, "logs", query.MaxLines)
+			}
+		}
+	}
+	for _, frames := range chronological {
+		for _, frame := range frames {
+			if !isLogsFrame(frame) {
+				add(frame, seriesKey(frame), 0)
+			}
+		}
+	}
+
+	for frame, stats := range statsOf {
+		frame.Meta.Stats = mergeStats(stats)
+	}
+	return merged
+}
+
+// seriesKey identifies a metric series by the frame name and the labels of its fields.
+func seriesKey(frame *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Name)
+	for _, field := range frame.Fields {
+		sb.WriteString("\n")
+		sb.WriteString(field.Labels.String())
+	}
+	return sb.String()
+}
+
+// mergeStats combines the query stats of chunks. Totals are summed, per second rates are averaged.
+func mergeStats(chunkStats [][]data.QueryStat) []data.QueryStat {
+	var (
+		merged []data.QueryStat
+		index  = map[string]int{}
+		counts = map[string]int{}
+	)
+	for _, stats := range chunkStats {
+		for _, stat := range stats {
+			name := stat.FieldConfig.DisplayName
+			counts[name]++
+			i, ok := index[name]
+			if !ok {
+				index[name] = len(merged)
+				merged = append(merged, stat)
+				continue
+			}
+			merged[i].Value += stat.Value
+		}
+	}
+	for i, stat := range merged {
+		if strings.HasSuffix(stat.FieldConfig.DisplayName, "per second") {
+			merged[i].Value = stat.Value / float64(counts[stat.FieldConfig.DisplayName])
+		}
+	}
+	return merged
+}
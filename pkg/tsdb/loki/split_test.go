@@ -0,0 +1,193 @@
+package loki
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestSplitQuery(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("range queries are split into chunks in the query direction", func(t *testing.T) {
+		query := &lokiQuery{QueryType: QueryTypeRange, Direction: DirectionBackward, Step: time.Minute, Start: start, End: start.Add(60 * time.Hour)}
+		chunks := splitQuery(query, 24*time.Hour)
+		require.Len(t, chunks, 3)
+		require.Equal(t, start.Add(48*time.Hour), chunks[0].Start)
+		require.Equal(t, start.Add(60*time.Hour), chunks[0].End)
+		require.Equal(t, start.Add(24*time.Hour), chunks[1].Start)
+		require.Equal(t, start.Add(48*time.Hour-time.Nanosecond), chunks[1].End)
+		require.Equal(t, start, chunks[2].Start)
+		require.Equal(t, start.Add(24*time.Hour-time.Nanosecond), chunks[2].End)
+
+		query.Direction = DirectionForward
+		chunks = splitQuery(query, 24*time.Hour)
+		require.Len(t, chunks, 3)
+		require.Equal(t, start, chunks[0].Start)
+		require.Equal(t, start.Add(60*time.Hour), chunks[2].End)
+	})
+
+	t.Run("chunks are aligned to the step", func(t *testing.T) {
+		query := &lokiQuery{QueryType: QueryTypeRange, Direction: DirectionForward, Step: time.Minute, Start: start, End: start.Add(5 * time.Minute)}
+		chunks := splitQuery(query, 90*time.Second)
+		require.Len(t, chunks, 3)
+		require.Equal(t, start.Add(2*time.Minute), chunks[1].Start)
+		require.Equal(t, start.Add(4*time.Minute), chunks[2].Start)
+	})
+
+	t.Run("instant and short queries are not split", func(t *testing.T) {
+		query := &lokiQuery{QueryType: QueryTypeInstant, Start: start, End: start.Add(48 * time.Hour)}
+		require.Equal(t, []*lokiQuery{query}, splitQuery(query, 24*time.Hour))
+
+		query = &lokiQuery{QueryType: QueryTypeRange, Start: start, End: start.Add(24 * time.Hour)}
+		require.Equal(t, []*lokiQuery{query}, splitQuery(query, 24*time.Hour))
+		require.Equal(t, []*lokiQuery{query}, splitQuery(query, 0))
+	})
+}
+
+func TestRunSplitQuery(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("metric series of all chunks are merged in time order", func(t *testing.T) {
+		rt := &chunkRoundTripper{}
+		query := &lokiQuery{Expr: "rate({a=\"b\"}[1m])", QueryType: QueryTypeRange, Direction: DirectionBackward, Step: time.Hour, Start: start, End: start.Add(72 * time.Hour)}
+
+		frames, err := runSplitQuery(context.Background(), rt.api(), query, 24*time.Hour, 2)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, 3, frames[0].Rows())
+		require.Equal(t, start, frames[0].Fields[0].At(0))
+		require.Equal(t, start.Add(24*time.Hour), frames[0].Fields[0].At(1))
+		require.Equal(t, start.Add(48*time.Hour), frames[0].Fields[0].At(2))
+		require.Equal(t, `{a="b"}`, frames[0].Fields[1].Config.DisplayNameFromDS)
+		require.Equal(t, "Summary: total lines processed", frames[0].Meta.Stats[3].DisplayName)
+		require.Equal(t, float64(3), frames[0].Meta.Stats[3].Value)
+		require.Equal(t, 3, rt.requests)
+		require.LessOrEqual(t, rt.maxInFlight, 2)
+	})
+
+	t.Run("logs chunks after reaching the line limit are skipped", func(t *testing.T) {
+		rt := &chunkRoundTripper{logs: true}
+		query := &lokiQuery{Expr: "{a=\"b\"}", QueryType: QueryTypeRange, Direction: DirectionBackward, MaxLines: 2, Step: time.Hour, Start: start, End: start.Add(96 * time.Hour)}
+
+		frames, err := runSplitQuery(context.Background(), rt.api(), query, 24*time.Hour, 1)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, 2, frames[0].Rows())
+		require.Equal(t, "line "+strconv.FormatInt(start.Add(72*time.Hour).UnixNano(), 10), frames[0].Fields[2].At(0))
+		require.Equal(t, "line "+strconv.FormatInt(start.Add(48*time.Hour).UnixNano(), 10), frames[0].Fields[2].At(1))
+		require.Equal(t, 2, rt.requests)
+	})
+
+	t.Run("logs are limited to the line limit", func(t *testing.T) {
+		rt := &chunkRoundTripper{logs: true, linesPerChunk: 2}
+		query := &lokiQuery{Expr: "{a=\"b\"}", QueryType: QueryTypeRange, Direction: DirectionForward, MaxLines: 3, Step: time.Hour, Start: start, End: start.Add(72 * time.Hour)}
+
+		frames, err := runSplitQuery(context.Background(), rt.api(), query, 24*time.Hour, 3)
+		require.NoError(t, err)
+		require.Len(t, frames, 1)
+		require.Equal(t, 3, frames[0].Rows())
+	})
+
+	t.Run("failed chunks mark the result as partial", func(t *testing.T) {
+		rt := &chunkRoundTripper{failStart: start.Add(24 * time.Hour)}
+		query := &lokiQuery{Expr: "rate({a=\"b\"}[1m])", QueryType: QueryTypeRange, Direction: DirectionForward, Step: time.Hour, Start: start, End: start.Add(72 * time.Hour)}
+
+		frames, err := runSplitQuery(context.Background(), rt.api(), query, 24*time.Hour, 2)
+		var partialErr *partialResultError
+		require.True(t, errors.As(err, &partialErr))
+		require.Equal(t, "partial result: 1 of 3 time ranges failed: chunk failed", err.Error())
+		require.Len(t, frames, 1)
+		require.Equal(t, 2, frames[0].Rows())
+		require.Len(t, frames[0].Meta.Notices, 1)
+		require.Equal(t, data.NoticeSeverityWarning, frames[0].Meta.Notices[0].Severity)
+	})
+
+	t.Run("query fails when all chunks fail", func(t *testing.T) {
+		rt := &chunkRoundTripper{failAll: true}
+		query := &lokiQuery{Expr: "rate({a=\"b\"}[1m])", QueryType: QueryTypeRange, Direction: DirectionForward, Step: time.Hour, Start: start, End: start.Add(48 * time.Hour)}
+
+		frames, err := runSplitQuery(context.Background(), rt.api(), query, 24*time.Hour, 2)
+		require.EqualError(t, err, "chunk failed")
+		require.Empty(t, frames)
+	})
+}
+
+// chunkRoundTripper answers Loki range queries with one sample or log line at the start of the queried range
+type chunkRoundTripper struct {
+	logs          bool
+	linesPerChunk int
+	failStart     time.Time
+	failAll       bool
+
+	mu          sync.Mutex
+	inFlight    int
+	requests    int
+	maxInFlight int
+}
+
+func (rt *chunkRoundTripper) api() *LokiAPI {
+	return newLokiAPI(&http.Client{Transport: rt}, "http://localhost:3100", log.New("test"))
+}
+
+func (rt *chunkRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests++
+	rt.inFlight++
+	if rt.inFlight > rt.maxInFlight {
+		rt.maxInFlight = rt.inFlight
+	}
+	rt.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	defer func() {
+		rt.mu.Lock()
+		rt.inFlight--
+		rt.mu.Unlock()
+	}()
+
+	startNs, err := strconv.ParseInt(req.URL.Query().Get("start"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if rt.failAll || startNs == rt.failStart.UnixNano() {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"message":"chunk failed"}`))),
+		}, nil
+	}
+
+	var body string
+	if rt.logs {
+		lines := rt.linesPerChunk
+		if lines == 0 {
+			lines = 1
+		}
+		values := ""
+		for i := 0; i < lines; i++ {
+			if i > 0 {
+				values += ","
+			}
+			ts := strconv.FormatInt(startNs+int64(i), 10)
+			values += fmt.Sprintf(`["%s", "line %s"]`, ts, ts)
+		}
+		body = fmt.Sprintf(`{"status":"success","data":{"resultType":"streams","result":[{"stream":{"a":"b"},"values":[%s]}]}}`, values)
+	} else {
+		body = fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"a":"b"},"values":[[%d,"1"]]}],"stats":{"summary":{"totalLinesProcessed":1}}}}`, startNs/int64(time.Second))
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
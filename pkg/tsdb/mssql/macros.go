@@ -14,6 +14,9 @@ import (
 const rsIdentifier = `([_a-zA-Z0-9]+)`
 const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
 
+// localTimeFormat formats wall clock times for comparison with columns without time zone.
+const localTimeFormat = "2006-01-02T15:04:05"
+
 type msSQLMacroEngine struct {
 	*sqleng.SQLMacroEngineBase
 }
@@ -61,9 +64,20 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 		return fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s) AS time", args[0]), nil
 	case "__timeFilter":
+		args, tz := sqleng.TimezoneArg(args, 1)
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
+		if tz != "" {
+			loc, err := sqleng.ParseTimezone(tz)
+			if err != nil {
+				return "", err
+			}
+			if loc != time.UTC {
+				// column holds wall clock times of the given timezone
+				return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.In(loc).Format(localTimeFormat), timeRange.To.In(loc).Format(localTimeFormat)), nil
+			}
+		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339), timeRange.To.UTC().Format(time.RFC3339)), nil
 	case "__timeFrom":
@@ -71,6 +85,7 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 	case "__timeTo":
 		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339)), nil
 	case "__timeGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
 		}
@@ -78,13 +93,22 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
-		return fmt.Sprintf("FLOOR(DATEDIFF(second, '1970-01-01', %s)/%.0f)*%.0f", args[0], interval.Seconds(), interval.Seconds()), nil
+		return sqleng.TimeGroupExpression(fmt.Sprintf("DATEDIFF(second, '1970-01-01', %s)", args[0]), interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("FLOOR(%s/%.0f)*%.0f", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__timeGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
 		if err == nil {
@@ -106,6 +130,7 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 	case "__unixEpochNanoTo":
 		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
 	case "__unixEpochGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
@@ -113,13 +138,22 @@ func (m *msSQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
-		return fmt.Sprintf("FLOOR(%s/%v)*%v", args[0], interval.Seconds(), interval.Seconds()), nil
+		return sqleng.TimeGroupExpression(args[0], interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("FLOOR(%s/%v)*%v", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__unixEpochGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
 		if err == nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
@@ -15,6 +16,9 @@ import (
 const rsIdentifier = `([_a-zA-Z0-9]+)`
 const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
 
+// localTimeFormat formats wall clock times for comparison with columns without time zone.
+const localTimeFormat = "2006-01-02 15:04:05"
+
 var restrictedRegExp = regexp.MustCompile(`(?im)([\s]*show[\s]+grants|[\s,]session_user\([^\)]*\)|[\s,]current_user(\([^\)]*\))?|[\s,]system_user\([^\)]*\)|[\s,]user\([^\)]*\))([\s,;]|$)`)
 
 type mySQLMacroEngine struct {
@@ -65,9 +69,20 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		}
 		return fmt.Sprintf("UNIX_TIMESTAMP(%s) as time_sec", args[0]), nil
 	case "__timeFilter":
+		args, tz := sqleng.TimezoneArg(args, 1)
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
+		if tz != "" {
+			loc, err := sqleng.ParseTimezone(tz)
+			if err != nil {
+				return "", err
+			}
+			if loc != time.UTC {
+				// column holds wall clock times of the given timezone
+				return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.In(loc).Format(localTimeFormat), timeRange.To.In(loc).Format(localTimeFormat)), nil
+			}
+		}
 		if timeRange.From.UTC().Unix() < 0 {
 			return fmt.Sprintf("%s BETWEEN DATE_ADD(FROM_UNIXTIME(0), INTERVAL %d SECOND) AND FROM_UNIXTIME(%d)", args[0], timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
 		}
@@ -77,6 +92,7 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 	case "__timeTo":
 		return fmt.Sprintf("FROM_UNIXTIME(%d)", timeRange.To.UTC().Unix()), nil
 	case "__timeGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval", name)
 		}
@@ -84,13 +100,22 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
-		return fmt.Sprintf("UNIX_TIMESTAMP(%s) DIV %.0f * %.0f", args[0], interval.Seconds(), interval.Seconds()), nil
+		return sqleng.TimeGroupExpression(fmt.Sprintf("UNIX_TIMESTAMP(%s)", args[0]), interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("%s DIV %.0f * %.0f", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__timeGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
 		if err == nil {
@@ -112,6 +137,7 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 	case "__unixEpochNanoTo":
 		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
 	case "__unixEpochGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
@@ -119,13 +145,22 @@ func (m *mySQLMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *bac
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
-		return fmt.Sprintf("%s DIV %v * %v", args[0], interval.Seconds(), interval.Seconds()), nil
+		return sqleng.TimeGroupExpression(args[0], interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("%s DIV %v * %v", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__unixEpochGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
 		if err == nil {
@@ -0,0 +1,34 @@
+package opentsdb
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// CheckHealth asks OpenTSDB for metric suggestions, which only requires read access to the metric names.
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []string
+	err = s.getJSON(ctx, dsInfo, "api/suggest", url.Values{"type": {"metrics"}, "q": {"cpu"}, "max": {"1"}}, &suggestions)
+	if err != nil {
+		logger.Warn("OpenTSDB health check failed", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: fmt.Sprintf("OpenTSDB health check failed: %s", err.Error()),
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Data source is working",
+	}, nil
+}
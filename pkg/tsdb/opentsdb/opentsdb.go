@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +30,12 @@ type Service struct {
 	im instancemgmt.InstanceManager
 }
 
+var (
+	_ backend.QueryDataHandler    = (*Service)(nil)
+	_ backend.CallResourceHandler = (*Service)(nil)
+	_ backend.CheckHealthHandler  = (*Service)(nil)
+)
+
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
 	return &Service{
 		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
@@ -36,12 +43,27 @@ func ProvideService(httpClientProvider httpclient.Provider) *Service {
 }
 
 type datasourceInfo struct {
-	HTTPClient *http.Client
-	URL        string
+	HTTPClient  *http.Client
+	URL         string
+	Version     int
+	Resolution  int
+	LookupLimit int
 }
 
 type DsAccess string
 
+type jsonData struct {
+	TsdbVersion    int `json:"tsdbVersion"`
+	TsdbResolution int `json:"tsdbResolution"`
+	LookupLimit    int `json:"lookupLimit"`
+}
+
+const (
+	// tsdbResolutionMilliseconds is the resolution setting of OpenTSDB instances storing timestamps in milliseconds
+	tsdbResolutionMilliseconds = 2
+	defaultLookupLimit         = 1000
+)
+
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
 	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 		opts, err := settings.HTTPClientOptions()
@@ -54,28 +76,74 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		jsonData := jsonData{}
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jsonData); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+		if jsonData.TsdbVersion == 0 {
+			jsonData.TsdbVersion = 1
+		}
+		if jsonData.LookupLimit == 0 {
+			jsonData.LookupLimit = defaultLookupLimit
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:  client,
+			URL:         settings.URL,
+			Version:     jsonData.TsdbVersion,
+			Resolution:  jsonData.TsdbResolution,
+			LookupLimit: jsonData.LookupLimit,
 		}
 
 		return model, nil
 	}
 }
 
+// queryTarget associates a sub-query of the OpenTSDB request with the Grafana query it was built from
+type queryTarget struct {
+	RefID string
+	// Metric of the sub-query, used to match results if OpenTSDB doesn't return the sub-query index
+	Metric string
+	// Annotation targets return the annotations of the metric instead of its data points
+	IsAnnotation bool
+	IsGlobal     bool
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	var tsdbQuery OpenTsdbQuery
 
 	logger := logger.FromContext(ctx)
 
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+
 	q := req.Queries[0]
 
 	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
 	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+	tsdbQuery.MsResolution = dsInfo.Resolution == tsdbResolutionMilliseconds
+	tsdbQuery.GlobalAnnotations = true
+	tsdbQuery.ShowQuery = true
 
+	targets := make([]queryTarget, 0, len(req.Queries))
 	for _, query := range req.Queries {
-		metric := s.buildMetric(query)
+		metric, target, err := s.buildSubQuery(query)
+		if err != nil {
+			return &backend.QueryDataResponse{}, err
+		}
+		if metric == nil {
+			continue
+		}
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return backend.NewQueryDataResponse(), nil
 	}
 
 	// TODO: Don't use global variable
@@ -83,11 +151,6 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(req.PluginContext)
-	if err != nil {
-		return nil, err
-	}
-
 	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
@@ -98,7 +161,7 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		return &backend.QueryDataResponse{}, err
 	}
 
-	result, err := s.parseResponse(logger, res)
+	result, err := s.parseResponse(logger, res, targets, tsdbQuery.MsResolution)
 	if err != nil {
 		return &backend.QueryDataResponse{}, err
 	}
@@ -106,6 +169,38 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 	return result, nil
 }
 
+// buildSubQuery returns the OpenTSDB sub-query for a Grafana query, or nil if the query is hidden or
+// has no metric.
+func (s *Service) buildSubQuery(query backend.DataQuery) (map[string]interface{}, queryTarget, error) {
+	target := queryTarget{RefID: query.RefID}
+
+	model, err := simplejson.NewJson(query.JSON)
+	if err != nil {
+		return nil, target, err
+	}
+	if model.Get("hide").MustBool() {
+		return nil, target, nil
+	}
+
+	// Annotation queries use the target field for the metric annotations are read from
+	if model.Get("fromAnnotations").MustBool() {
+		target.Metric = model.Get("target").MustString()
+		target.IsAnnotation = true
+		target.IsGlobal = model.Get("isGlobal").MustBool()
+		if target.Metric == "" {
+			return nil, target, nil
+		}
+		return map[string]interface{}{"aggregator": "sum", "metric": target.Metric}, target, nil
+	}
+
+	metric := s.buildMetric(query)
+	if metric == nil || metric["metric"] == "" {
+		return nil, target, nil
+	}
+	target.Metric = metric["metric"].(string)
+	return metric, target, nil
+}
+
 func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
 	u, err := url.Parse(dsInfo.URL)
 	if err != nil {
@@ -129,7 +224,7 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 	return req, nil
 }
 
-func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend.QueryDataResponse, error) {
+func (s *Service) parseResponse(logger log.Logger, res *http.Response, targets []queryTarget, msResolution bool) (*backend.QueryDataResponse, error) {
 	resp := backend.NewQueryDataResponse()
 
 	body, err := io.ReadAll(res.Body)
@@ -154,32 +249,113 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend
 		return nil, err
 	}
 
-	frames := data.Frames{}
+	for _, target := range targets {
+		resp.Responses[target.RefID] = backend.DataResponse{Frames: data.Frames{}}
+	}
+
+	annotated := make(map[int]bool)
 	for _, val := range responseData {
-		timeVector := make([]time.Time, 0, len(val.DataPoints))
-		values := make([]float64, 0, len(val.DataPoints))
-		name := val.Metric
-		tags := val.Tags
-
-		for timeString, value := range val.DataPoints {
-			timestamp, err := strconv.ParseInt(timeString, 10, 64)
-			if err != nil {
-				logger.Info("Failed to unmarshal opentsdb timestamp", "timestamp", timeString)
-				return nil, err
+		index := findTarget(val, targets)
+		if index < 0 {
+			logger.Warn("Unable to find query associated with OpenTSDB result", "metric", val.Metric)
+			continue
+		}
+		target := targets[index]
+		result := resp.Responses[target.RefID]
+
+		if target.IsAnnotation {
+			// Global annotations are returned with every series, so they're only read from the first one
+			if annotated[index] {
+				continue
 			}
-			timeVector = append(timeVector, time.Unix(timestamp, 0).UTC())
-			values = append(values, value)
+			annotated[index] = true
+
+			annotations := val.Annotations
+			if target.IsGlobal {
+				annotations = val.GlobalAnnotations
+			}
+			result.Frames = append(result.Frames, annotationFrame(annotations))
+			resp.Responses[target.RefID] = result
+			continue
+		}
+
+		frame, err := timeSeriesFrame(val, msResolution)
+		if err != nil {
+			logger.Info("Failed to unmarshal opentsdb timestamp", "error", err)
+			return nil, err
 		}
-		frames = append(frames, data.NewFrame(name,
-			data.NewField("time", nil, timeVector),
-			data.NewField("value", tags, values)))
+		result.Frames = append(result.Frames, frame)
+		resp.Responses[target.RefID] = result
 	}
-	result := resp.Responses["A"]
-	result.Frames = frames
-	resp.Responses["A"] = result
+
 	return resp, nil
 }
 
+// findTarget returns the index of the target a result belongs to. OpenTSDB returns the index of the
+// sub-query along with the result if it is asked to show the query, older versions only the metric.
+func findTarget(val OpenTsdbResponse, targets []queryTarget) int {
+	if val.Query != nil && val.Query.Index != nil {
+		if *val.Query.Index >= 0 && *val.Query.Index < len(targets) {
+			return *val.Query.Index
+		}
+		return -1
+	}
+
+	for i, target := range targets {
+		if target.Metric == val.Metric {
+			return i
+		}
+	}
+	return -1
+}
+
+func timeSeriesFrame(val OpenTsdbResponse, msResolution bool) (*data.Frame, error) {
+	timestamps := make([]int64, 0, len(val.DataPoints))
+	values := make(map[int64]float64, len(val.DataPoints))
+	for timeString, value := range val.DataPoints {
+		timestamp, err := strconv.ParseInt(timeString, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q: %w", timeString, err)
+		}
+		timestamps = append(timestamps, timestamp)
+		values[timestamp] = value
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+	timeVector := make([]time.Time, 0, len(timestamps))
+	valueVector := make([]float64, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		if msResolution {
+			timeVector = append(timeVector, time.UnixMilli(timestamp).UTC())
+		} else {
+			timeVector = append(timeVector, time.Unix(timestamp, 0).UTC())
+		}
+		valueVector = append(valueVector, values[timestamp])
+	}
+
+	return data.NewFrame(val.Metric,
+		data.NewField("time", nil, timeVector),
+		data.NewField("value", val.Tags, valueVector)), nil
+}
+
+// annotationFrame converts OpenTSDB annotations, whose times are in seconds, to an annotation frame
+func annotationFrame(annotations []OpenTsdbAnnotation) *data.Frame {
+	frame := data.NewFrame("annotations",
+		data.NewField("time", nil, []time.Time{}),
+		data.NewField("timeEnd", nil, []time.Time{}),
+		data.NewField("text", nil, []string{}),
+		data.NewField("tsuid", nil, []string{}),
+	)
+	for _, a := range annotations {
+		endTime := a.EndTime
+		if endTime == 0 {
+			endTime = a.StartTime
+		}
+		frame.AppendRow(time.Unix(a.StartTime, 0).UTC(), time.Unix(endTime, 0).UTC(), a.Description, a.TSUID)
+	}
+	return frame
+}
+
 func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 	metric := make(map[string]interface{})
 
@@ -230,21 +406,44 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		metric["rateOptions"] = rateOptions
 	}
 
-	// Setting tags
-	tags, tagsCheck := model.CheckGet("tags")
-	if tagsCheck && len(tags.MustMap()) > 0 {
-		metric["tags"] = tags.MustMap()
+	// Setting filters, which replace tags since OpenTSDB 2.2
+	filters := buildFilters(model.Get("filters"))
+	if len(filters) > 0 {
+		metric["filters"] = filters
+	} else {
+		tags, tagsCheck := model.CheckGet("tags")
+		if tagsCheck && len(tags.MustMap()) > 0 {
+			metric["tags"] = tags.MustMap()
+		}
 	}
 
-	// Setting filters
-	filters, filtersCheck := model.CheckGet("filters")
-	if filtersCheck && len(filters.MustArray()) > 0 {
-		metric["filters"] = filters.MustArray()
+	if model.Get("explicitTags").MustBool() {
+		metric["explicitTags"] = true
 	}
 
 	return metric
 }
 
+// buildFilters returns the filters of a query in the filter syntax of OpenTSDB 2.2, e.g.
+// {"type": "wildcard", "tagk": "host", "filter": "web*", "groupBy": true}
+func buildFilters(model *simplejson.Json) []map[string]interface{} {
+	filters := make([]map[string]interface{}, 0)
+	for i := range model.MustArray() {
+		f := model.GetIndex(i)
+		tagk := f.Get("tagk").MustString()
+		if tagk == "" {
+			continue
+		}
+		filters = append(filters, map[string]interface{}{
+			"type":    f.Get("type").MustString("literal_or"),
+			"tagk":    tagk,
+			"filter":  f.Get("filter").MustString(),
+			"groupBy": f.Get("groupBy").MustBool(),
+		})
+	}
+	return filters
+}
+
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, error) {
 	i, err := s.im.Get(pluginCtx)
 	if err != nil {
@@ -33,7 +33,7 @@ func TestOpenTsdbExecutor(t *testing.T) {
 	t.Run("Parse response should handle invalid JSON", func(t *testing.T) {
 		response := `{ invalid }`
 
-		result, err := service.parseResponse(logger, &http.Response{Body: io.NopCloser(strings.NewReader(response))})
+		result, err := service.parseResponse(logger, &http.Response{Body: io.NopCloser(strings.NewReader(response))}, []queryTarget{{RefID: "A", Metric: "test"}}, false)
 		require.Nil(t, result)
 		require.Error(t, err)
 	})
@@ -63,7 +63,7 @@ func TestOpenTsdbExecutor(t *testing.T) {
 
 		resp := http.Response{Body: io.NopCloser(strings.NewReader(response))}
 		resp.StatusCode = 200
-		result, err := service.parseResponse(logger, &resp)
+		result, err := service.parseResponse(logger, &resp, []queryTarget{{RefID: "A", Metric: "test"}}, false)
 		require.NoError(t, err)
 
 		frame := result.Responses["A"]
@@ -241,4 +241,122 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(45), metricRateOptions["counterMax"])
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
+
+	t.Run("Parse response should assign results to queries by sub-query index", func(t *testing.T) {
+		response := `
+		[
+			{
+				"metric": "cpu",
+				"dps": {"1405544146000": 2.0, "1405544145000": 1.0},
+				"tags": {"host": "a"},
+				"query": {"index": 1}
+			},
+			{
+				"metric": "cpu",
+				"dps": {"1405544146000": 3.0},
+				"tags": {"host": "b"},
+				"query": {"index": 0}
+			}
+		]`
+
+		resp := http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(response))}
+		result, err := service.parseResponse(logger, &resp, []queryTarget{{RefID: "A", Metric: "cpu"}, {RefID: "B", Metric: "cpu"}}, true)
+		require.NoError(t, err)
+
+		require.Len(t, result.Responses["A"].Frames, 1)
+		require.Equal(t, map[string]string{"host": "b"}, map[string]string(result.Responses["A"].Frames[0].Fields[1].Labels))
+
+		frames := result.Responses["B"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, time.Date(2014, 7, 16, 20, 55, 45, 0, time.UTC), frames[0].Fields[0].At(0))
+		require.Equal(t, time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC), frames[0].Fields[0].At(1))
+		require.Equal(t, 1.0, frames[0].Fields[1].At(0))
+	})
+
+	t.Run("Parse response should return annotations", func(t *testing.T) {
+		response := `
+		[
+			{
+				"metric": "deploys",
+				"dps": {},
+				"tags": {},
+				"annotations": [{"tsuid": "000001000001000001", "description": "deploy v1", "startTime": 1405544146}],
+				"globalAnnotations": [{"description": "outage", "startTime": 1405544100, "endTime": 1405544200}]
+			}
+		]`
+
+		targets := []queryTarget{{RefID: "A", Metric: "deploys", IsAnnotation: true}}
+		resp := http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(response))}
+		result, err := service.parseResponse(logger, &resp, targets, false)
+		require.NoError(t, err)
+
+		expected := data.NewFrame("annotations",
+			data.NewField("time", nil, []time.Time{time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC)}),
+			data.NewField("timeEnd", nil, []time.Time{time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC)}),
+			data.NewField("text", nil, []string{"deploy v1"}),
+			data.NewField("tsuid", nil, []string{"000001000001000001"}),
+		)
+		if diff := cmp.Diff(expected, result.Responses["A"].Frames[0], data.FrameTestCompareOptions()...); diff != "" {
+			t.Errorf("Result mismatch (-want +got):\n%s", diff)
+		}
+
+		targets[0].IsGlobal = true
+		resp = http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(response))}
+		result, err = service.parseResponse(logger, &resp, targets, false)
+		require.NoError(t, err)
+
+		frame := result.Responses["A"].Frames[0]
+		require.Equal(t, 1, frame.Rows())
+		require.Equal(t, "outage", frame.Fields[2].At(0))
+		require.Equal(t, time.Date(2014, 7, 16, 20, 56, 40, 0, time.UTC), frame.Fields[1].At(0))
+	})
+
+	t.Run("Build sub-query for annotation query", func(t *testing.T) {
+		metric, target, err := service.buildSubQuery(backend.DataQuery{
+			RefID: "Anno",
+			JSON:  []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`),
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"aggregator": "sum", "metric": "deploys"}, metric)
+		require.Equal(t, queryTarget{RefID: "Anno", Metric: "deploys", IsAnnotation: true, IsGlobal: true}, target)
+	})
+
+	t.Run("Build sub-query should skip hidden queries and queries without metric", func(t *testing.T) {
+		metric, _, err := service.buildSubQuery(backend.DataQuery{JSON: []byte(`{"metric": "cpu", "hide": true}`)})
+		require.NoError(t, err)
+		require.Nil(t, metric)
+
+		metric, _, err = service.buildSubQuery(backend.DataQuery{JSON: []byte(`{"aggregator": "sum"}`)})
+		require.NoError(t, err)
+		require.Nil(t, metric)
+	})
+
+	t.Run("Build metric with filters", func(t *testing.T) {
+		query := backend.DataQuery{
+			JSON: []byte(`
+					{
+						"metric": "cpu.average.percent",
+						"aggregator": "avg",
+						"disableDownsampling": true,
+						"explicitTags": true,
+						"tags": {
+							"env": "prod"
+						},
+						"filters": [
+							{"type": "wildcard", "tagk": "host", "filter": "web*", "groupBy": true},
+							{"tagk": "env", "filter": "prod"}
+						]
+					}`,
+			),
+		}
+
+		metric := service.buildMetric(query)
+
+		require.Nil(t, metric["tags"])
+		require.True(t, metric["explicitTags"].(bool))
+		require.Equal(t, []map[string]interface{}{
+			{"type": "wildcard", "tagk": "host", "filter": "web*", "groupBy": true},
+			{"type": "literal_or", "tagk": "env", "filter": "prod", "groupBy": false},
+		}, metric["filters"])
+	})
 }
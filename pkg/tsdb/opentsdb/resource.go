@@ -0,0 +1,227 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// CallResource serves metric, tag key and tag value lookups for the query editor and template variables
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/suggest", s.handleSuggest)
+	mux.HandleFunc("/lookup/tag-keys", s.handleTagKeys)
+	mux.HandleFunc("/lookup/tag-values", s.handleTagValues)
+	mux.HandleFunc("/aggregators", s.handleProxy("api/aggregators"))
+	mux.HandleFunc("/filters", s.handleProxy("api/config/filters"))
+	return httpadapter.New(mux).CallResource(ctx, req, sender)
+}
+
+// handleSuggest returns metric names, tag keys or tag values starting with q, depending on type
+func (s *Service) handleSuggest(rw http.ResponseWriter, req *http.Request) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		writeResourceError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	suggestType := req.URL.Query().Get("type")
+	if suggestType != "metrics" && suggestType != "tagk" && suggestType != "tagv" {
+		writeResourceError(rw, http.StatusBadRequest, fmt.Errorf("invalid suggest type: %q", suggestType))
+		return
+	}
+
+	params := url.Values{
+		"type": {suggestType},
+		"q":    {req.URL.Query().Get("q")},
+		"max":  {strconv.Itoa(dsInfo.LookupLimit)},
+	}
+	suggestions := []string{}
+	if err := s.getJSON(req.Context(), dsInfo, "api/suggest", params, &suggestions); err != nil {
+		writeResourceError(rw, http.StatusBadGateway, err)
+		return
+	}
+	writeResourceJSON(rw, suggestions)
+}
+
+// handleTagKeys returns the tag keys of the time series of a metric
+func (s *Service) handleTagKeys(rw http.ResponseWriter, req *http.Request) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		writeResourceError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	metric := req.URL.Query().Get("metric")
+	if metric == "" {
+		writeResourceJSON(rw, []string{})
+		return
+	}
+
+	results, err := s.lookup(req.Context(), dsInfo, metric, dsInfo.LookupLimit)
+	if err != nil {
+		writeResourceError(rw, http.StatusBadGateway, err)
+		return
+	}
+
+	keys := []string{}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		for key := range r.Tags {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	writeResourceJSON(rw, keys)
+}
+
+// handleTagValues returns the values of the first of the comma separated tag keys, for time series of
+// a metric matching the remaining tag conditions, e.g. keys=host,env=prod
+func (s *Service) handleTagValues(rw http.ResponseWriter, req *http.Request) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		writeResourceError(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	metric := req.URL.Query().Get("metric")
+	keys := req.URL.Query().Get("keys")
+	if metric == "" || keys == "" {
+		writeResourceJSON(rw, []string{})
+		return
+	}
+
+	conditions := strings.Split(keys, ",")
+	for i := range conditions {
+		conditions[i] = strings.TrimSpace(conditions[i])
+	}
+	key := conditions[0]
+	conditions[0] = key + "=*"
+
+	results, err := s.lookup(req.Context(), dsInfo, metric+"{"+strings.Join(conditions, ",")+"}", dsInfo.LookupLimit)
+	if err != nil {
+		writeResourceError(rw, http.StatusBadGateway, err)
+		return
+	}
+
+	values := []string{}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if value, ok := r.Tags[key]; ok && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	writeResourceJSON(rw, values)
+}
+
+// handleProxy returns a handler forwarding the request to a parameterless OpenTSDB endpoint
+func (s *Service) handleProxy(endpoint string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+		if err != nil {
+			writeResourceError(rw, http.StatusInternalServerError, err)
+			return
+		}
+
+		var result interface{}
+		if err := s.getJSON(req.Context(), dsInfo, endpoint, nil, &result); err != nil {
+			writeResourceError(rw, http.StatusBadGateway, err)
+			return
+		}
+		writeResourceJSON(rw, result)
+	}
+}
+
+type lookupResult struct {
+	Tags map[string]string `json:"tags"`
+}
+
+func (s *Service) lookup(ctx context.Context, dsInfo *datasourceInfo, m string, limit int) ([]lookupResult, error) {
+	var response struct {
+		Results []lookupResult `json:"results"`
+	}
+	params := url.Values{"m": {m}, "limit": {strconv.Itoa(limit)}}
+	if err := s.getJSON(ctx, dsInfo, "api/search/lookup", params, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// getJSON sends a GET request to an OpenTSDB endpoint and decodes the JSON response into v
+func (s *Service) getJSON(ctx context.Context, dsInfo *datasourceInfo, endpoint string, params url.Values, v interface{}) error {
+	logger := logger.FromContext(ctx)
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = path.Join(u.Path, endpoint)
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "endpoint", endpoint, "status", res.Status, "body", string(body))
+		return fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func writeResourceJSON(rw http.ResponseWriter, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeResourceError(rw, http.StatusInternalServerError, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := rw.Write(body); err != nil {
+		logger.Warn("Failed to write resource response", "err", err)
+	}
+}
+
+func writeResourceError(rw http.ResponseWriter, status int, err error) {
+	body, marshalErr := json.Marshal(map[string]string{"message": err.Error()})
+	if marshalErr != nil {
+		body = []byte(`{"message": "internal error"}`)
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	if _, err := rw.Write(body); err != nil {
+		logger.Warn("Failed to write resource response", "err", err)
+	}
+}
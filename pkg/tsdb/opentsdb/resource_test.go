@@ -0,0 +1,155 @@
+package opentsdb
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInstanceManager struct {
+	info *datasourceInfo
+}
+
+func (f fakeInstanceManager) Get(pluginContext backend.PluginContext) (instancemgmt.Instance, error) {
+	return f.info, nil
+}
+
+func (f fakeInstanceManager) Do(pluginContext backend.PluginContext, fn instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+type fakeSender struct {
+	res *backend.CallResourceResponse
+}
+
+func (f *fakeSender) Send(res *backend.CallResourceResponse) error {
+	f.res = res
+	return nil
+}
+
+// newOpenTsdbServer returns a service for an OpenTSDB server responding with the given bodies by path
+func newOpenTsdbServer(t *testing.T, responses map[string]string) (*Service, *[]*url.URL) {
+	t.Helper()
+
+	var requests []*url.URL
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	service := &Service{
+		im: fakeInstanceManager{info: &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL, LookupLimit: 50}},
+	}
+	return service, &requests
+}
+
+func callResource(t *testing.T, service *Service, resourceURL string) *backend.CallResourceResponse {
+	t.Helper()
+
+	u, err := url.Parse(resourceURL)
+	require.NoError(t, err)
+
+	sender := &fakeSender{}
+	err = service.CallResource(context.Background(), &backend.CallResourceRequest{
+		Method: http.MethodGet,
+		Path:   u.Path,
+		URL:    resourceURL,
+	}, sender)
+	require.NoError(t, err)
+	require.NotNil(t, sender.res)
+	return sender.res
+}
+
+func TestCallResource(t *testing.T) {
+	lookup := `{"results": [
+		{"metric": "cpu", "tags": {"host": "web01", "env": "prod"}},
+		{"metric": "cpu", "tags": {"host": "web02", "env": "prod"}},
+		{"metric": "cpu", "tags": {"host": "web01", "env": "prod", "core": "0"}}
+	]}`
+
+	t.Run("should suggest metrics", func(t *testing.T) {
+		service, requests := newOpenTsdbServer(t, map[string]string{"/api/suggest": `["cpu.idle", "cpu.user"]`})
+
+		res := callResource(t, service, "suggest?type=metrics&q=cpu")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.JSONEq(t, `["cpu.idle", "cpu.user"]`, string(res.Body))
+		require.Equal(t, url.Values{"type": {"metrics"}, "q": {"cpu"}, "max": {"50"}}, (*requests)[0].Query())
+	})
+
+	t.Run("should reject invalid suggest type", func(t *testing.T) {
+		service, requests := newOpenTsdbServer(t, nil)
+
+		res := callResource(t, service, "suggest?type=other&q=cpu")
+		require.Equal(t, http.StatusBadRequest, res.Status)
+		require.Empty(t, *requests)
+	})
+
+	t.Run("should look up tag keys of metric", func(t *testing.T) {
+		service, requests := newOpenTsdbServer(t, map[string]string{"/api/search/lookup": lookup})
+
+		res := callResource(t, service, "lookup/tag-keys?metric=cpu")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.ElementsMatch(t, []string{"host", "env", "core"}, unmarshalStrings(t, res.Body))
+		require.Equal(t, "cpu", (*requests)[0].Query().Get("m"))
+		require.Equal(t, "50", (*requests)[0].Query().Get("limit"))
+	})
+
+	t.Run("should look up tag values of metric", func(t *testing.T) {
+		service, requests := newOpenTsdbServer(t, map[string]string{"/api/search/lookup": lookup})
+
+		res := callResource(t, service, "lookup/tag-values?metric=cpu&keys=host,%20env=prod")
+		require.Equal(t, http.StatusOK, res.Status)
+		require.Equal(t, []string{"web01", "web02"}, unmarshalStrings(t, res.Body))
+		require.Equal(t, "cpu{host=*,env=prod}", (*requests)[0].Query().Get("m"))
+		require.Equal(t, "50", (*requests)[0].Query().Get("limit"))
+	})
+
+	t.Run("should return error of OpenTSDB", func(t *testing.T) {
+		service, _ := newOpenTsdbServer(t, nil)
+
+		res := callResource(t, service, "aggregators")
+		require.Equal(t, http.StatusBadGateway, res.Status)
+		require.JSONEq(t, `{"message": "request failed, status: 404 Not Found"}`, string(res.Body))
+	})
+}
+
+func TestCheckHealth(t *testing.T) {
+	t.Run("should succeed when metrics can be suggested", func(t *testing.T) {
+		service, _ := newOpenTsdbServer(t, map[string]string{"/api/suggest": `[]`})
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("should fail when OpenTSDB is not reachable", func(t *testing.T) {
+		service, _ := newOpenTsdbServer(t, nil)
+
+		res, err := service.CheckHealth(context.Background(), &backend.CheckHealthRequest{})
+		require.NoError(t, err)
+		require.Equal(t, backend.HealthStatusError, res.Status)
+		require.Equal(t, "OpenTSDB health check failed: request failed, status: 404 Not Found", res.Message)
+	})
+}
+
+func unmarshalStrings(t *testing.T, body []byte) []string {
+	t.Helper()
+
+	var values []string
+	require.NoError(t, json.Unmarshal(body, &values))
+	return values
+}
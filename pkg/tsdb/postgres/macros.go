@@ -15,6 +15,9 @@ import (
 const rsIdentifier = `([_a-zA-Z0-9]+)`
 const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
 
+// localTimeFormat formats wall clock times for comparison with timestamp without time zone columns.
+const localTimeFormat = "2006-01-02T15:04:05.999999999"
+
 type postgresMacroEngine struct {
 	*sqleng.SQLMacroEngineBase
 	timescaledb bool
@@ -80,9 +83,20 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		}
 		return fmt.Sprintf("extract(epoch from %s) as \"time\"", args[0]), nil
 	case "__timeFilter":
+		args, tz := sqleng.TimezoneArg(args, 1)
 		if len(args) == 0 {
 			return "", fmt.Errorf("missing time column argument for macro %v", name)
 		}
+		if tz != "" {
+			loc, err := sqleng.ParseTimezone(tz)
+			if err != nil {
+				return "", err
+			}
+			if loc != time.UTC {
+				// column holds wall clock times of the given timezone
+				return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.In(loc).Format(localTimeFormat), timeRange.To.In(loc).Format(localTimeFormat)), nil
+			}
+		}
 
 		return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.UTC().Format(time.RFC3339Nano), timeRange.To.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeFrom":
@@ -90,6 +104,7 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 	case "__timeTo":
 		return fmt.Sprintf("'%s'", timeRange.To.UTC().Format(time.RFC3339Nano)), nil
 	case "__timeGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
@@ -97,22 +112,27 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
 
-		if m.timescaledb {
+		if m.timescaledb && loc == time.UTC {
 			return fmt.Sprintf("time_bucket('%.3fs',%s)", interval.Seconds(), args[0]), nil
 		}
 
-		return fmt.Sprintf(
-			"floor(extract(epoch from %s)/%v)*%v", args[0],
-			interval.Seconds(),
-			interval.Seconds(),
-		), nil
+		return sqleng.TimeGroupExpression(fmt.Sprintf("extract(epoch from %s)", args[0]), interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("floor(%s/%v)*%v", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__timeGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
 		if err == nil {
@@ -134,6 +154,7 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 	case "__unixEpochNanoTo":
 		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
 	case "__unixEpochGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
 		if len(args) < 2 {
 			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
 		}
@@ -141,13 +162,22 @@ func (m *postgresMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *
 		if err != nil {
 			return "", fmt.Errorf("error parsing interval %v", args[1])
 		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
 		if len(args) == 3 {
 			err := sqleng.SetupFillmode(query, interval, args[2])
 			if err != nil {
 				return "", err
 			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
 		}
-		return fmt.Sprintf("floor((%s)/%v)*%v", args[0], interval.Seconds(), interval.Seconds()), nil
+		return sqleng.TimeGroupExpression(args[0], interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("floor((%s)/%v)*%v", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
 	case "__unixEpochGroupAlias":
 		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
 		if err == nil {
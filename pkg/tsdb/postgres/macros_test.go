@@ -247,3 +247,50 @@ func TestMacroEngineConcurrency(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestMacroEngineTimezone(t *testing.T) {
+	engine := newPostgresMacroEngine(false)
+	from := time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)
+	timeRange := backend.TimeRange{From: from, To: to}
+
+	t.Run("interpolate __timeGroup function with timezone argument", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte("{}")}
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1d','Asia/Kolkata')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY (floor((extract(epoch from time_column) + 19800)/86400)*86400 - 19800)", sql)
+	})
+
+	t.Run("interpolate __timeGroup function with dashboard timezone across DST transition", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte(`{"timezone": "Europe/Berlin"}`)}
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'1d', NULL)")
+		require.NoError(t, err)
+
+		bucket := "(floor((extract(epoch from time_column) + CASE WHEN extract(epoch from time_column) < 1679792400 THEN 3600 ELSE 7200 END)/86400)*86400)"
+		require.Equal(t, "GROUP BY ("+bucket+" - CASE WHEN "+bucket+" < 1679799600 THEN 3600 ELSE 7200 END)", sql)
+		require.JSONEq(t, `{"timezone": "Europe/Berlin", "fill": true, "fillInterval": 86400, "fillMode": "null", "fillTimezone": "Europe/Berlin"}`, string(query.JSON))
+	})
+
+	t.Run("interpolate __timeGroup function with UTC timezone argument", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte(`{"timezone": "Europe/Berlin"}`)}
+		sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m','UTC')")
+		require.NoError(t, err)
+
+		require.Equal(t, "GROUP BY floor(extract(epoch from time_column)/300)*300", sql)
+	})
+
+	t.Run("interpolate __timeGroup function with invalid timezone", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte("{}")}
+		_, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m','Mars/Olympus_Mons')")
+		require.Error(t, err)
+	})
+
+	t.Run("interpolate __timeFilter function with timezone argument", func(t *testing.T) {
+		query := &backend.DataQuery{JSON: []byte(`{"timezone": "Europe/Berlin"}`)}
+		sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column, 'America/New_York')")
+		require.NoError(t, err)
+
+		require.Equal(t, "WHERE time_column BETWEEN '2023-03-19T20:00:00' AND '2023-03-29T20:00:00'", sql)
+	})
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/httpclient"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/setting"
@@ -35,15 +37,15 @@ type instance struct {
 	versionCache *cache.Cache
 }
 
-func ProvideService(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer) *Service {
+func ProvideService(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer, sqlStore db.DB) *Service {
 	plog.Debug("initializing")
 	return &Service{
-		im:       datasource.NewInstanceManager(newInstanceSettings(httpClientProvider, cfg, features, tracer)),
+		im:       datasource.NewInstanceManager(newInstanceSettings(httpClientProvider, cfg, features, tracer, newCacheStorage(cfg, sqlStore))),
 		features: features,
 	}
 }
 
-func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer) datasource.InstanceFactoryFunc {
+func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cfg, features featuremgmt.FeatureToggles, tracer tracing.Tracer, cacheStorage remotecache.CacheStorage) datasource.InstanceFactoryFunc {
 	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
 		// Creates a http roundTripper.
 		opts, err := client.CreateTransportOptions(settings, cfg, plog)
@@ -56,7 +58,7 @@ func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cf
 		}
 
 		// New version using custom client and better response parsing
-		qd, err := querydata.New(httpClient, features, tracer, settings, plog)
+		qd, err := querydata.New(httpClient, features, tracer, settings, plog, newResultCache(cfg, settings, cacheStorage))
 		if err != nil {
 			return nil, err
 		}
@@ -75,6 +77,32 @@ func newInstanceSettings(httpClientProvider httpclient.Provider, cfg *setting.Cf
 	}
 }
 
+// newCacheStorage returns the remote cache storage for query results, or nil if results are cached in memory.
+func newCacheStorage(cfg *setting.Cfg, sqlStore db.DB) remotecache.CacheStorage {
+	if !cfg.PrometheusQueryCache.Enabled || cfg.PrometheusQueryCache.Backend != "remote" {
+		return nil
+	}
+	storage, err := remotecache.NewUnencryptedStorage(cfg, sqlStore)
+	if err != nil {
+		plog.Error("Failed to create remote cache for query results, caching them in memory instead", "err", err)
+		return nil
+	}
+	return storage
+}
+
+// newResultCache returns the cache for range query results of a data source, or nil if caching is disabled.
+// Memory caches belong to the data source instance, so they are dropped when the data source is updated.
+func newResultCache(cfg *setting.Cfg, settings backend.DataSourceInstanceSettings, storage remotecache.CacheStorage) *querydata.ResultCache {
+	cacheCfg := cfg.PrometheusQueryCache
+	if !cacheCfg.Enabled {
+		return nil
+	}
+	if storage == nil {
+		storage = querydata.NewMemoryStorage(cacheCfg.TTL)
+	}
+	return querydata.NewResultCache(storage, settings, cacheCfg.MaxStaleness, cacheCfg.TTL)
+}
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	if len(req.Queries) == 0 {
 		return &backend.QueryDataResponse{}, fmt.Errorf("query contains no queries")
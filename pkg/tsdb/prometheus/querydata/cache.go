@@ -0,0 +1,291 @@
+package querydata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/patrickmn/go-cache"
+
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/util/proxyutil"
+)
+
+// ResultCache stores range query results, so that refreshing a dashboard only queries Prometheus for the part of
+// the time range that isn't cached yet. Cached data is aligned to the query step, which doesn't change between
+// refreshes of a dashboard with a relative time range of constant width.
+type ResultCache struct {
+	storage      remotecache.CacheStorage
+	keyPrefix    string
+	maxStaleness time.Duration
+	ttl          time.Duration
+}
+
+// NewResultCache creates a cache for the results of a single data source. Data within maxStaleness of the end of
+// a cached result is always queried again, since Prometheus may still ingest samples for it. Entries include the
+// time the data source was last updated in their key, so results of outdated settings are never read.
+func NewResultCache(storage remotecache.CacheStorage, settings backend.DataSourceInstanceSettings, maxStaleness, ttl time.Duration) *ResultCache {
+	return &ResultCache{
+		storage:      storage,
+		keyPrefix:    fmt.Sprintf("prometheus-query-result:%d:%d", settings.ID, settings.Updated.UnixNano()),
+		maxStaleness: maxStaleness,
+		ttl:          ttl,
+	}
+}
+
+// NewMemoryStorage returns a cache storage that keeps items in memory, for data sources which don't share their
+// cache with other Grafana instances.
+func NewMemoryStorage(ttl time.Duration) remotecache.CacheStorage {
+	return &memoryStorage{cache: cache.New(ttl, 2*ttl)}
+}
+
+type memoryStorage struct {
+	cache *cache.Cache
+}
+
+func (m *memoryStorage) Get(_ context.Context, key string) (interface{}, error) {
+	v, ok := m.cache.Get(key)
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return v, nil
+}
+
+func (m *memoryStorage) Set(_ context.Context, key string, value interface{}, expire time.Duration) error {
+	m.cache.Set(key, value, expire)
+	return nil
+}
+
+func (m *memoryStorage) Delete(_ context.Context, key string) error {
+	m.cache.Delete(key)
+	return nil
+}
+
+// cacheEntry is the cached result of a range query. Frames are stored in their arrow encoding, so entries can be
+// put in any remote cache and don't share memory with responses returned to callers.
+type cacheEntry struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Frames [][]byte  `json:"frames"`
+}
+
+// identityHeaders are the forwarded headers which identify the user to Prometheus, i.e. with OAuth pass-through,
+// forwarded cookies or the user header. Prometheus, or a proxy in front of it, may return different results per
+// user, so results are only shared between requests forwarding the same identity.
+var identityHeaders = []string{"Authorization", "X-ID-Token", "Cookie", proxyutil.UserHeaderName}
+
+// forwardedIdentity returns the values of the identity headers of a request. Middlewares set them both as plain
+// and as forwarded HTTP headers.
+func forwardedIdentity(req *backend.QueryDataRequest) string {
+	var sb strings.Builder
+	for _, name := range identityHeaders {
+		value := req.GetHTTPHeader(name)
+		if value == "" {
+			value = req.Headers[name]
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", name, value)
+	}
+	return sb.String()
+}
+
+func (c *ResultCache) key(q *models.Query, identity string) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n%d\n%s\n%d\n%s", q.Expr, q.Step, q.LegendFormat, q.UtcOffsetSec, identity)
+	return fmt.Sprintf("%s:%x", c.keyPrefix, h.Sum(nil))
+}
+
+func (c *ResultCache) get(ctx context.Context, key string) (*cacheEntry, data.Frames, error) {
+	v, err := c.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected cached value of type %T", v)
+	}
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(b, entry); err != nil {
+		return nil, nil, err
+	}
+	frames, err := data.UnmarshalArrowFrames(entry.Frames)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cacheable(frames) {
+		return nil, nil, fmt.Errorf("cached frames have no time field")
+	}
+	for _, frame := range frames {
+		toUTC(frame.Fields[0])
+	}
+	return entry, frames, nil
+}
+
+func (c *ResultCache) set(ctx context.Context, key string, start, end time.Time, frames data.Frames) error {
+	encoded, err := frames.MarshalArrow()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(&cacheEntry{Start: start, End: end, Frames: encoded})
+	if err != nil {
+		return err
+	}
+	return c.storage.Set(ctx, key, b, c.ttl)
+}
+
+// cachedRangeQuery runs a range query, reusing the cached result of a previous query with the same expression and
+// step, forwarding the same identity. Only the end of the time range which is missing from the cache, or is too recent to be final, is queried.
+func (s *QueryData) cachedRangeQuery(ctx context.Context, c *client.Client, q *models.Query, identity string) (*backend.DataResponse, error) {
+	logger := s.log.FromContext(ctx)
+	tr := q.TimeRange()
+	key := s.cache.key(q, identity)
+
+	fetchStart := tr.Start
+	entry, cached, err := s.cache.get(ctx, key)
+	if err != nil {
+		logger.Warn("Failed to read cached query result", "query", q.Expr, "err", err)
+	}
+	if entry != nil && !entry.Start.After(tr.Start) && !entry.End.Before(tr.Start) {
+		fetchStart = models.AlignTimeRange(entry.End.Add(-s.cache.maxStaleness), q.Step, q.UtcOffsetSec)
+		if fetchStart.Before(tr.Start) {
+			fetchStart = tr.Start
+		}
+	} else {
+		cached = nil
+	}
+
+	if fetchStart.After(tr.End) {
+		frames, err := mergeFrames(cached, nil, tr.Start, tr.End.Add(time.Nanosecond))
+		if err != nil {
+			return nil, err
+		}
+		return &backend.DataResponse{Frames: frames}, nil
+	}
+
+	fetchQuery := *q
+	fetchQuery.Start = fetchStart
+	if !fetchStart.Equal(tr.Start) {
+		logger.Debug("Querying range missing from cache", "query", q.Expr, "start", fetchStart, "end", tr.End)
+	}
+
+	res, err := c.QueryRange(ctx, &fetchQuery)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.parseResponse(ctx, &fetchQuery, res)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != nil || !cacheable(r.Frames) {
+		if cached != nil {
+			// The partial response can't be merged with the cache, so query the whole range instead
+			return s.uncachedRangeQuery(ctx, c, q)
+		}
+		return r, nil
+	}
+
+	frames, err := mergeFrames(cached, r.Frames, tr.Start, fetchStart)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.set(ctx, key, tr.Start, tr.End, frames); err != nil {
+		logger.Warn("Failed to cache query result", "query", q.Expr, "err", err)
+	}
+
+	r.Frames = frames
+	return r, nil
+}
+
+// cacheable returns true if all frames start with a time field, which is needed to merge them by time.
+func cacheable(frames data.Frames) bool {
+	for _, frame := range frames {
+		if len(frame.Fields) == 0 || frame.Fields[0].Type() != data.FieldTypeTime {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeFrames combines the rows of cached frames before fetchStart, starting at start, with the rows of the
+// fetched frames of the same series. Series which are only cached are kept, as they may have ended.
+func mergeFrames(cached, fetched data.Frames, start, fetchStart time.Time) (data.Frames, error) {
+	byKey := make(map[string]*data.Frame, len(cached))
+	keys := make([]string, 0, len(cached))
+	for _, frame := range cached {
+		trimmed, err := frame.FilterRowsByField(0, func(v interface{}) (bool, error) {
+			t := v.(time.Time)
+			return !t.Before(start) && t.Before(fetchStart), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		copyMetadata(trimmed, frame)
+		key := seriesKey(frame)
+		byKey[key] = trimmed
+		keys = append(keys, key)
+	}
+
+	merged := make(data.Frames, 0, len(fetched)+len(cached))
+	for _, frame := range fetched {
+		key := seriesKey(frame)
+		old, ok := byKey[key]
+		if !ok {
+			merged = append(merged, frame)
+			continue
+		}
+		delete(byKey, key)
+
+		combined := frame.EmptyCopy()
+		copyMetadata(combined, frame)
+		for i := 0; i < old.Rows(); i++ {
+			combined.AppendRow(old.RowCopy(i)...)
+		}
+		for i := 0; i < frame.Rows(); i++ {
+			combined.AppendRow(frame.RowCopy(i)...)
+		}
+		merged = append(merged, combined)
+	}
+
+	for _, key := range keys {
+		if old, ok := byKey[key]; ok && old.Rows() > 0 {
+			merged = append(merged, old)
+		}
+	}
+	return merged, nil
+}
+
+// copyMetadata sets the frame meta and field configs of src, which aren't copied by data.Frame.EmptyCopy, on dst.
+func copyMetadata(dst, src *data.Frame) {
+	dst.Meta = src.Meta
+	for i, field := range dst.Fields {
+		field.Config = src.Fields[i].Config
+	}
+}
+
+// seriesKey identifies the series of a frame by its name and the names, types and labels of its fields.
+func seriesKey(frame *data.Frame) string {
+	var sb strings.Builder
+	sb.WriteString(frame.Name)
+	for _, field := range frame.Fields {
+		fmt.Fprintf(&sb, "\n%s/%s/%s", field.Name, field.Type(), field.Labels.String())
+	}
+	return sb.String()
+}
+
+// toUTC converts the times read from arrow, which are in the local time zone, to UTC like in parsed responses.
+func toUTC(field *data.Field) {
+	for i := 0; i < field.Len(); i++ {
+		field.Set(i, field.At(i).(time.Time).UTC())
+	}
+}
@@ -0,0 +1,202 @@
+package querydata_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	sdkhttpclient "github.com/grafana/grafana-plugin-sdk-go/backend/httpclient"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	p "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/client"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/models"
+	"github.com/grafana/grafana/pkg/tsdb/prometheus/querydata"
+)
+
+func TestResultCache(t *testing.T) {
+	storage := querydata.NewMemoryStorage(time.Hour)
+
+	t.Run("queries only the missing and stale end of the range", func(t *testing.T) {
+		tctx := setupCached(t, storage, time.Unix(1, 0))
+
+		res := executeRange(t, tctx, 0, 600, matrix(0, 600, 1))
+		require.Equal(t, "0", tctx.httpProvider.req.URL.Query().Get("start"))
+		require.Len(t, res, 1)
+		require.Equal(t, 11, res[0].Rows())
+
+		res = executeRange(t, tctx, 120, 720, matrix(480, 720, 2))
+		require.Equal(t, "480", tctx.httpProvider.req.URL.Query().Get("start"))
+		require.Equal(t, "720", tctx.httpProvider.req.URL.Query().Get("end"))
+		require.Len(t, res, 1)
+		require.Equal(t, 11, res[0].Rows())
+		require.Equal(t, time.Unix(120, 0).UTC(), res[0].Fields[0].At(0))
+		require.Equal(t, 1.0, res[0].Fields[1].At(0))
+		require.Equal(t, time.Unix(420, 0).UTC(), res[0].Fields[0].At(5))
+		require.Equal(t, 1.0, res[0].Fields[1].At(5))
+		require.Equal(t, time.Unix(480, 0).UTC(), res[0].Fields[0].At(6))
+		require.Equal(t, 2.0, res[0].Fields[1].At(6))
+		require.Equal(t, time.Unix(720, 0).UTC(), res[0].Fields[0].At(10))
+		require.Equal(t, "legend Application", res[0].Name)
+		require.Equal(t, "legend Application", res[0].Fields[1].Config.DisplayNameFromDS)
+	})
+
+	t.Run("returns cached data without querying if it can't be stale", func(t *testing.T) {
+		tctx := setupCached(t, storage, time.Unix(1, 0))
+
+		res := executeRange(t, tctx, 120, 300, nil)
+		require.Nil(t, tctx.httpProvider.req)
+		require.Len(t, res, 1)
+		require.Equal(t, 4, res[0].Rows())
+		require.Equal(t, time.Unix(120, 0).UTC(), res[0].Fields[0].At(0))
+		require.Equal(t, time.Unix(300, 0).UTC(), res[0].Fields[0].At(3))
+		require.Equal(t, "legend Application", res[0].Fields[1].Config.DisplayNameFromDS)
+		require.Equal(t, float64(60000), res[0].Fields[0].Config.Interval)
+		require.Equal(t, "Expr: up\nStep: 1m0s", res[0].Meta.ExecutedQueryString)
+	})
+
+	t.Run("queries the whole range after the data source is updated", func(t *testing.T) {
+		tctx := setupCached(t, storage, time.Unix(2, 0))
+
+		res := executeRange(t, tctx, 120, 720, matrix(120, 720, 3))
+		require.Equal(t, "120", tctx.httpProvider.req.URL.Query().Get("start"))
+		require.Len(t, res, 1)
+		require.Equal(t, 11, res[0].Rows())
+		require.Equal(t, 3.0, res[0].Fields[1].At(0))
+	})
+
+	t.Run("keeps cached series missing from the queried range", func(t *testing.T) {
+		tctx := setupCached(t, storage, time.Unix(2, 0))
+
+		res := executeRange(t, tctx, 240, 840, queryResult{Type: p.ValMatrix, Result: p.Matrix{}})
+		require.Equal(t, "600", tctx.httpProvider.req.URL.Query().Get("start"))
+		require.Len(t, res, 1)
+		require.Equal(t, 6, res[0].Rows())
+		require.Equal(t, time.Unix(540, 0).UTC(), res[0].Fields[0].At(5))
+	})
+
+	t.Run("doesn't share results between forwarded identities", func(t *testing.T) {
+		tctx := setupCached(t, querydata.NewMemoryStorage(time.Hour), time.Unix(1, 0))
+		alice := map[string]string{"Authorization": "Bearer alice"}
+		bob := map[string]string{"http_Authorization": "Bearer bob"}
+
+		executeRangeWithHeaders(t, tctx, alice, 0, 600, matrix(0, 600, 1))
+
+		res := executeRangeWithHeaders(t, tctx, bob, 120, 300, matrix(120, 300, 2))
+		require.NotNil(t, tctx.httpProvider.req)
+		require.Equal(t, "120", tctx.httpProvider.req.URL.Query().Get("start"))
+		require.Equal(t, 2.0, res[0].Fields[1].At(0))
+
+		res = executeRangeWithHeaders(t, tctx, alice, 120, 300, nil)
+		require.Nil(t, tctx.httpProvider.req)
+		require.Equal(t, 1.0, res[0].Fields[1].At(0))
+	})
+}
+
+func setupCached(t *testing.T, storage remotecache.CacheStorage, updated time.Time) *testContext {
+	t.Helper()
+
+	httpProvider := &fakeHttpClientProvider{
+		opts: sdkhttpclient.Options{
+			Timeouts: &sdkhttpclient.DefaultTimeoutOptions,
+		},
+	}
+	settings := backend.DataSourceInstanceSettings{
+		ID:       1,
+		URL:      "http://localhost:9090",
+		JSONData: json.RawMessage(`{"timeInterval": "60s"}`),
+		Updated:  updated,
+	}
+	opts, err := client.CreateTransportOptions(settings, &setting.Cfg{}, &logtest.Fake{})
+	require.NoError(t, err)
+	httpClient, err := httpProvider.New(*opts)
+	require.NoError(t, err)
+
+	features := &fakeFeatureToggles{flags: map[string]bool{}}
+	cache := querydata.NewResultCache(storage, settings, 2*time.Minute, time.Hour)
+	queryData, err := querydata.New(httpClient, features, tracing.InitializeTracerForTest(), settings, &logtest.Fake{}, cache)
+	require.NoError(t, err)
+
+	return &testContext{
+		httpProvider: httpProvider,
+		queryData:    queryData,
+	}
+}
+
+// executeRange runs a range query from and to the given unix seconds. A nil result means Prometheus must not be
+// queried.
+func executeRange(t *testing.T, tctx *testContext, from, to int64, qr interface{}) data.Frames {
+	t.Helper()
+	return executeRangeWithHeaders(t, tctx, nil, from, to, qr)
+}
+
+// executeRangeWithHeaders runs a range query like executeRange, with headers forwarded by Grafana.
+func executeRangeWithHeaders(t *testing.T, tctx *testContext, headers map[string]string, from, to int64, qr interface{}) data.Frames {
+	t.Helper()
+
+	tctx.httpProvider.req = nil
+	tctx.httpProvider.res = nil
+	if qr != nil {
+		b, err := json.Marshal(&apiResponse{Status: "success", Data: mustMarshal(t, qr)})
+		require.NoError(t, err)
+		tctx.httpProvider.res = &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewReader(b)),
+		}
+	}
+
+	qm := models.QueryModel{
+		Expr:         "up",
+		LegendFormat: "legend {{app}}",
+		RangeQuery:   true,
+	}
+	query := backend.DataQuery{
+		RefID: "A",
+		TimeRange: backend.TimeRange{
+			From: time.Unix(from, 0).UTC(),
+			To:   time.Unix(to, 0).UTC(),
+		},
+		JSON: mustMarshal(t, qm),
+	}
+	res, err := tctx.queryData.Execute(context.Background(), &backend.QueryDataRequest{
+		Headers: headers,
+		Queries: []backend.DataQuery{query},
+	})
+	require.NoError(t, err)
+	require.NoError(t, res.Responses["A"].Error)
+	return res.Responses["A"].Frames
+}
+
+// matrix returns a series with a sample of the given value every minute between from and to.
+func matrix(from, to int64, value float64) queryResult {
+	var values []p.SamplePair
+	for ts := from; ts <= to; ts += 60 {
+		values = append(values, p.SamplePair{Value: p.SampleValue(value), Timestamp: p.Time(ts * 1000)})
+	}
+	return queryResult{
+		Type: p.ValMatrix,
+		Result: p.Matrix{
+			&p.SampleStream{
+				Metric: p.Metric{"app": "Application"},
+				Values: values,
+			},
+		},
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) json.RawMessage {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return b
+}
@@ -44,6 +44,7 @@ type QueryData struct {
 	TimeInterval                      string
 	enableWideSeries                  bool
 	disablePrometheusExemplarSampling bool
+	cache                             *ResultCache
 }
 
 func New(
@@ -52,6 +53,7 @@ func New(
 	tracer tracing.Tracer,
 	settings backend.DataSourceInstanceSettings,
 	plog log.Logger,
+	cache *ResultCache,
 ) (*QueryData, error) {
 	jsonData, err := utils.GetJsonData(settings)
 	if err != nil {
@@ -76,6 +78,7 @@ func New(
 		URL:                               settings.URL,
 		enableWideSeries:                  features.IsEnabled(featuremgmt.FlagPrometheusWideSeries),
 		disablePrometheusExemplarSampling: features.IsEnabled(featuremgmt.FlagDisablePrometheusExemplarSampling),
+		cache:                             cache,
 	}, nil
 }
 
@@ -84,13 +87,14 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 	result := backend.QueryDataResponse{
 		Responses: backend.Responses{},
 	}
+	identity := forwardedIdentity(req)
 
 	for _, q := range req.Queries {
 		query, err := models.Parse(q, s.TimeInterval, s.intervalCalculator, fromAlert)
 		if err != nil {
 			return &result, err
 		}
-		r, err := s.fetch(ctx, s.client, query)
+		r, err := s.fetch(ctx, s.client, query, identity)
 		if err != nil {
 			return &result, err
 		}
@@ -104,7 +108,7 @@ func (s *QueryData) Execute(ctx context.Context, req *backend.QueryDataRequest)
 	return &result, nil
 }
 
-func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.Query) (*backend.DataResponse, error) {
+func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.Query, identity string) (*backend.DataResponse, error) {
 	traceCtx, end := s.trace(ctx, q)
 	defer end()
 
@@ -126,7 +130,7 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	}
 
 	if q.RangeQuery {
-		res, err := s.rangeQuery(traceCtx, client, q)
+		res, err := s.rangeQuery(traceCtx, client, q, identity)
 		if err != nil {
 			return nil, err
 		}
@@ -155,7 +159,15 @@ func (s *QueryData) fetch(ctx context.Context, client *client.Client, q *models.
 	return response, nil
 }
 
-func (s *QueryData) rangeQuery(ctx context.Context, c *client.Client, q *models.Query) (*backend.DataResponse, error) {
+func (s *QueryData) rangeQuery(ctx context.Context, c *client.Client, q *models.Query, identity string) (*backend.DataResponse, error) {
+	// Wide frames combine all series, so they can't be merged per series with cached results
+	if s.cache != nil && !s.enableWideSeries {
+		return s.cachedRangeQuery(ctx, c, q, identity)
+	}
+	return s.uncachedRangeQuery(ctx, c, q)
+}
+
+func (s *QueryData) uncachedRangeQuery(ctx context.Context, c *client.Client, q *models.Query) (*backend.DataResponse, error) {
 	res, err := c.QueryRange(ctx, q)
 	if err != nil {
 		return nil, err
@@ -432,7 +432,7 @@ func setup(wideFrames bool) (*testContext, error) {
 		return nil, err
 	}
 
-	queryData, _ := querydata.New(httpClient, features, tracer, settings, &logtest.Fake{})
+	queryData, _ := querydata.New(httpClient, features, tracer, settings, &logtest.Fake{}, nil)
 
 	return &testContext{
 		httpProvider: httpProvider,
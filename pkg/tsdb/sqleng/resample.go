@@ -90,10 +90,8 @@ func resample(f *data.Frame, qm dataQueryModel) (*data.Frame, error) {
 	lastSeenRowIdx := -1
 	timeField := f.Fields[tsSchema.TimeIndex]
 
-	startUnixTime := qm.TimeRange.From.Unix() / int64(qm.Interval.Seconds()) * int64(qm.Interval.Seconds())
-	startTime := time.Unix(startUnixTime, 0)
-
-	for currentTime := startTime; !currentTime.After(qm.TimeRange.To); currentTime = currentTime.Add(qm.Interval) {
+	times := bucketTimes(qm)
+	for i, currentTime := range times {
 		initialRowIdx := 0
 		if lastSeenRowIdx > 0 {
 			initialRowIdx = lastSeenRowIdx + 1
@@ -115,6 +113,9 @@ func resample(f *data.Frame, qm dataQueryModel) (*data.Frame, error) {
 
 			// take the last element of the period current - interval <-> current, use it as value for current data point value
 			previousTime := currentTime.Add(-qm.Interval)
+			if i > 0 {
+				previousTime = times[i-1]
+			}
 			if t.(time.Time).After(previousTime) {
 				if !t.(time.Time).After(currentTime) {
 					intermediateRows = append(intermediateRows, initialRowIdx)
@@ -136,3 +137,42 @@ func resample(f *data.Frame, qm dataQueryModel) (*data.Frame, error) {
 
 	return resampledFrame, nil
 }
+
+// bucketTimes returns the start times of all buckets in the time range of the query. Buckets
+// in a location other than UTC are aligned to its wall clock, so that they match the buckets
+// returned by the timezone-aware time group macros, e.g. daily buckets start at local midnight
+// and last 23 or 25 hours on days with a DST transition.
+func bucketTimes(qm dataQueryModel) []time.Time {
+	var times []time.Time
+
+	if qm.Location == nil || qm.Location == time.UTC {
+		startUnixTime := qm.TimeRange.From.Unix() / int64(qm.Interval.Seconds()) * int64(qm.Interval.Seconds())
+		startTime := time.Unix(startUnixTime, 0)
+
+		for currentTime := startTime; !currentTime.After(qm.TimeRange.To); currentTime = currentTime.Add(qm.Interval) {
+			times = append(times, currentTime)
+		}
+		return times
+	}
+
+	interval := int64(qm.Interval.Seconds())
+	if interval <= 0 {
+		return times
+	}
+	zone := newZoneOffsets(qm.Location, qm.TimeRange.From.Add(-qm.Interval), qm.TimeRange.To.Add(qm.Interval))
+	wall := zone.wallTime(qm.TimeRange.From.Unix())
+	wall -= ((wall % interval) + interval) % interval
+
+	for ; ; wall += interval {
+		currentTime := time.Unix(zone.unixTime(wall), 0)
+		if currentTime.After(qm.TimeRange.To) {
+			break
+		}
+		// wall clock times skipped by a DST transition map to the same bucket
+		if len(times) > 0 && !currentTime.After(times[len(times)-1]) {
+			continue
+		}
+		times = append(times, currentTime)
+	}
+	return times
+}
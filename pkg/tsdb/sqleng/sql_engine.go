@@ -41,6 +41,13 @@ type SqlQueryResultTransformer interface {
 	GetConverterList() []sqlutil.StringConverter
 }
 
+// SqlQueryResultConverter can optionally be implemented by SqlQueryResultTransformer to scan
+// query results with converters instead of string converters. It's useful for databases
+// with dynamic column types where column type is only known from the returned values.
+type SqlQueryResultConverter interface {
+	GetConverters() []sqlutil.Converter
+}
+
 var sqlIntervalCalculator = intervalv2.NewCalculator()
 
 // NewXormEngine is an xorm.Engine factory, that can be stubbed by tests.
@@ -105,6 +112,8 @@ type QueryJson struct {
 	FillMode     string  `json:"fillMode"`
 	FillValue    float64 `json:"fillValue"`
 	Format       string  `json:"format"`
+	Timezone     string  `json:"timezone"`
+	FillTimezone string  `json:"fillTimezone"`
 }
 
 func (e *DataSourceHandler) TransformQueryError(logger log.Logger, err error) error {
@@ -287,12 +296,20 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 	}
 
 	// Convert row.Rows to dataframe
-	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(e.queryResultTransformer.GetConverterList()...)
+	if resultConverter, ok := e.queryResultTransformer.(SqlQueryResultConverter); ok {
+		converters = resultConverter.GetConverters()
+	}
+	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, converters...)
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
 	}
+	// Dynamic converters don't report errors returned while stepping through the rows.
+	if err := rows.Err(); err != nil {
+		errAppendDebug("db query error", e.TransformQueryError(logger, err), interpolatedQuery)
+		return
+	}
 
 	if frame.Meta == nil {
 		frame.Meta = &data.FrameMeta{}
@@ -434,6 +451,10 @@ func (e *DataSourceHandler) newProcessCfg(query backend.DataQuery, queryContext
 			qm.FillMissing.Value = queryJson.FillValue
 		default:
 		}
+		qm.Location, err = ParseTimezone(queryJson.FillTimezone)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	qm.TimeRange.From = query.TimeRange.From.UTC()
@@ -496,6 +517,7 @@ type dataQueryModel struct {
 	TimeRange         backend.TimeRange
 	FillMissing       *data.FillMissing // property not set until after Interpolate()
 	Interval          time.Duration
+	Location          *time.Location // location of time buckets, property not set until after Interpolate()
 	columnNames       []string
 	columnTypes       []*sql.ColumnType
 	timeIndex         int
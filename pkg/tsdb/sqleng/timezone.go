@@ -0,0 +1,168 @@
+package sqleng
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+)
+
+// ParseTimezone returns the location for an IANA timezone name. Empty names and the
+// dashboard values "utc" and "browser", which can't be resolved on the server, map to UTC.
+// "Local" is rejected, as it would make buckets depend on the timezone of the Grafana server.
+func ParseTimezone(name string) (*time.Location, error) {
+	name = strings.Trim(strings.TrimSpace(name), `'"`)
+	switch strings.ToLower(name) {
+	case "", "utc", "browser":
+		return time.UTC, nil
+	case "local":
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q", name)
+	}
+	return loc, nil
+}
+
+// TimezoneArg splits an optional timezone argument off macro arguments. The timezone is the
+// last argument after the first minArgs arguments and has to be quoted, which tells it apart
+// from unquoted fill values such as NULL, previous or numbers.
+func TimezoneArg(args []string, minArgs int) ([]string, string) {
+	if len(args) <= minArgs {
+		return args, ""
+	}
+	last := args[len(args)-1]
+	if len(last) < 2 || !(last[0] == '\'' || last[0] == '"') || last[len(last)-1] != last[0] {
+		return args, ""
+	}
+	return args[:len(args)-1], last
+}
+
+// MacroTimezone returns the location used by time bucketing macros. An explicit timezone
+// argument takes precedence over the dashboard timezone passed in the query.
+func MacroTimezone(query *backend.DataQuery, name string) (*time.Location, error) {
+	if name != "" {
+		return ParseTimezone(name)
+	}
+
+	queryJson := QueryJson{}
+	if len(query.JSON) > 0 {
+		if err := json.Unmarshal(query.JSON, &queryJson); err != nil {
+			return nil, err
+		}
+	}
+	return ParseTimezone(queryJson.Timezone)
+}
+
+// SetupFillTimezone stores the location of time buckets in the query, so that missing
+// points are filled in at the same bucket boundaries as returned by the query.
+func SetupFillTimezone(query *backend.DataQuery, loc *time.Location) error {
+	if loc == time.UTC {
+		return nil
+	}
+
+	rawQueryProp := make(map[string]interface{})
+	queryBytes, err := query.JSON.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(queryBytes, &rawQueryProp)
+	if err != nil {
+		return err
+	}
+	rawQueryProp["fillTimezone"] = loc.String()
+	query.JSON, err = json.Marshal(rawQueryProp)
+	return err
+}
+
+// TimeGroupExpression returns an expression grouping the unix timestamp expression epoch into
+// buckets aligned to the wall clock of loc. group returns the database specific expression
+// rounding its argument down to a multiple of interval. Offsets of loc are resolved for the
+// time range of the query, which keeps the expression DST-correct without relying on time
+// zone support of the database.
+func TimeGroupExpression(epoch string, interval time.Duration, loc *time.Location, timeRange backend.TimeRange, group func(expr string) string) string {
+	if loc == time.UTC {
+		return group(epoch)
+	}
+
+	zone := newZoneOffsets(loc, timeRange.From.Add(-interval), timeRange.To.Add(interval))
+	if len(zone.transitions) == 0 {
+		if zone.offsets[0] == 0 {
+			return group(epoch)
+		}
+		return addOffset(group(addOffset(epoch, zone.offsets[0])), -zone.offsets[0])
+	}
+
+	bucket := "(" + group("("+epoch+" + "+zone.caseExpression(epoch, false)+")") + ")"
+	return "(" + bucket + " - " + zone.caseExpression(bucket, true) + ")"
+}
+
+func addOffset(expr string, offset int64) string {
+	if offset < 0 {
+		return fmt.Sprintf("(%s - %d)", expr, -offset)
+	}
+	return fmt.Sprintf("(%s + %d)", expr, offset)
+}
+
+// zoneOffsets holds the UTC offsets of a location within a time range. offsets[i] applies
+// before transitions[i], the last offset applies after the last transition.
+type zoneOffsets struct {
+	transitions []int64
+	offsets     []int64
+}
+
+func newZoneOffsets(loc *time.Location, from, to time.Time) zoneOffsets {
+	t := from.In(loc)
+	_, offset := t.Zone()
+	z := zoneOffsets{offsets: []int64{int64(offset)}}
+	for {
+		_, end := t.ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			break
+		}
+		t = end
+		_, offset = t.Zone()
+		z.transitions = append(z.transitions, t.Unix())
+		z.offsets = append(z.offsets, int64(offset))
+	}
+	return z
+}
+
+// wallTime returns the wall clock of a unix timestamp as seconds since epoch.
+func (z zoneOffsets) wallTime(unix int64) int64 {
+	for i, transition := range z.transitions {
+		if unix < transition {
+			return unix + z.offsets[i]
+		}
+	}
+	return unix + z.offsets[len(z.offsets)-1]
+}
+
+// unixTime returns the unix timestamp of a wall clock. Wall clock times skipped by a
+// transition use the offset before it, repeated ones the offset after it.
+func (z zoneOffsets) unixTime(wall int64) int64 {
+	for i, transition := range z.transitions {
+		if wall < transition+z.offsets[i+1] {
+			return wall - z.offsets[i]
+		}
+	}
+	return wall - z.offsets[len(z.offsets)-1]
+}
+
+// caseExpression returns a SQL CASE expression returning the offset for expr, which is
+// either a unix timestamp or, if wall is true, a wall clock time. Mirrors wallTime and unixTime.
+func (z zoneOffsets) caseExpression(expr string, wall bool) string {
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	for i, transition := range z.transitions {
+		if wall {
+			transition += z.offsets[i+1]
+		}
+		fmt.Fprintf(&sb, " WHEN %s < %d THEN %d", expr, transition, z.offsets[i])
+	}
+	fmt.Fprintf(&sb, " ELSE %d END", z.offsets[len(z.offsets)-1])
+	return sb.String()
+}
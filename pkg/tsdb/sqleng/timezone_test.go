@@ -0,0 +1,246 @@
+package sqleng
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+	"github.com/xorcare/pointer"
+)
+
+func TestParseTimezone(t *testing.T) {
+	for _, name := range []string{"", "utc", "UTC", "browser", "'UTC'"} {
+		loc, err := ParseTimezone(name)
+		require.NoError(t, err)
+		require.Equal(t, time.UTC, loc, name)
+	}
+
+	loc, err := ParseTimezone("'Europe/Berlin'")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", loc.String())
+
+	_, err = ParseTimezone("Mars/Olympus_Mons")
+	require.Error(t, err)
+
+	// the timezone of the Grafana server is not an IANA timezone
+	for _, name := range []string{"Local", "'local'"} {
+		_, err = ParseTimezone(name)
+		require.Error(t, err, name)
+	}
+}
+
+func TestTimezoneArg(t *testing.T) {
+	tests := []struct {
+		args     []string
+		minArgs  int
+		expArgs  []string
+		expZone  string
+		scenario string
+	}{
+		{[]string{"time", "'5m'"}, 2, []string{"time", "'5m'"}, "", "no timezone"},
+		{[]string{"time", "'5m'", "NULL"}, 2, []string{"time", "'5m'", "NULL"}, "", "fill value only"},
+		{[]string{"time", "'5m'", "'Europe/Berlin'"}, 2, []string{"time", "'5m'"}, "'Europe/Berlin'", "timezone"},
+		{[]string{"time", "'5m'", "0", `"Europe/Berlin"`}, 2, []string{"time", "'5m'", "0"}, `"Europe/Berlin"`, "fill value and timezone"},
+		{[]string{"time", "'Europe/Berlin'"}, 1, []string{"time"}, "'Europe/Berlin'", "time filter timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			args, zone := TimezoneArg(tt.args, tt.minArgs)
+			require.Equal(t, tt.expArgs, args)
+			require.Equal(t, tt.expZone, zone)
+		})
+	}
+}
+
+func TestMacroTimezone(t *testing.T) {
+	query := &backend.DataQuery{JSON: []byte(`{"timezone": "America/New_York"}`)}
+
+	loc, err := MacroTimezone(query, "")
+	require.NoError(t, err)
+	require.Equal(t, "America/New_York", loc.String())
+
+	loc, err = MacroTimezone(query, "'Europe/Berlin'")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Berlin", loc.String())
+
+	loc, err = MacroTimezone(&backend.DataQuery{JSON: []byte(`{"timezone": "browser"}`)}, "")
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, loc)
+}
+
+func TestZoneOffsets(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	from := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	zone := newZoneOffsets(berlin, from, to)
+
+	springForward := time.Date(2023, 3, 26, 1, 0, 0, 0, time.UTC).Unix()
+	fallBack := time.Date(2023, 10, 29, 1, 0, 0, 0, time.UTC).Unix()
+	require.Equal(t, []int64{springForward, fallBack}, zone.transitions)
+	require.Equal(t, []int64{3600, 7200, 3600}, zone.offsets)
+
+	t.Run("wall and unix times match time package", func(t *testing.T) {
+		for ts := from; ts.Before(to); ts = ts.Add(30 * time.Minute) {
+			local := ts.In(berlin)
+			wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, time.UTC).Unix()
+			require.Equal(t, wall, zone.wallTime(ts.Unix()), ts.String())
+			require.Equal(t, time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, berlin).Unix(), zone.unixTime(wall), ts.String())
+		}
+	})
+
+	t.Run("skipped wall clock time uses offset before transition", func(t *testing.T) {
+		wall := time.Date(2023, 3, 26, 2, 30, 0, 0, time.UTC).Unix()
+		require.Equal(t, time.Date(2023, 3, 26, 1, 30, 0, 0, time.UTC).Unix(), zone.unixTime(wall))
+	})
+
+	t.Run("repeated wall clock time uses offset after transition", func(t *testing.T) {
+		wall := time.Date(2023, 10, 29, 2, 30, 0, 0, time.UTC).Unix()
+		require.Equal(t, time.Date(2023, 10, 29, 1, 30, 0, 0, time.UTC).Unix(), zone.unixTime(wall))
+	})
+
+	t.Run("fixed zone has no transitions", func(t *testing.T) {
+		zone := newZoneOffsets(time.FixedZone("UTC+5:30", 19800), from, to)
+		require.Empty(t, zone.transitions)
+		require.Equal(t, []int64{19800}, zone.offsets)
+	})
+}
+
+func TestTimeGroupExpression(t *testing.T) {
+	group := func(interval time.Duration) func(string) string {
+		return func(epoch string) string {
+			return fmt.Sprintf("floor(%s/%v)*%v", epoch, interval.Seconds(), interval.Seconds())
+		}
+	}
+
+	t.Run("UTC keeps plain expression", func(t *testing.T) {
+		timeRange := backend.TimeRange{From: time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)}
+		sql := TimeGroupExpression("epoch", time.Hour, time.UTC, timeRange, group(time.Hour))
+		require.Equal(t, "floor(epoch/3600)*3600", sql)
+	})
+
+	t.Run("constant offset is added and removed", func(t *testing.T) {
+		kolkata, err := time.LoadLocation("Asia/Kolkata")
+		require.NoError(t, err)
+		timeRange := backend.TimeRange{From: time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)}
+
+		sql := TimeGroupExpression("epoch", 24*time.Hour, kolkata, timeRange, group(24*time.Hour))
+		require.Equal(t, "(floor((epoch + 19800)/86400)*86400 - 19800)", sql)
+
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		sql = TimeGroupExpression("epoch", 24*time.Hour, newYork, timeRange, group(24*time.Hour))
+		require.Equal(t, "(floor((epoch - 14400)/86400)*86400 + 14400)", sql)
+	})
+
+	t.Run("DST transition is resolved from time range", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		timeRange := backend.TimeRange{From: time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC), To: time.Date(2023, 3, 30, 0, 0, 0, 0, time.UTC)}
+
+		sql := TimeGroupExpression("epoch", 24*time.Hour, berlin, timeRange, group(24*time.Hour))
+		bucket := "(floor((epoch + CASE WHEN epoch < 1679792400 THEN 3600 ELSE 7200 END)/86400)*86400)"
+		require.Equal(t, "("+bucket+" - CASE WHEN "+bucket+" < 1679799600 THEN 3600 ELSE 7200 END)", sql)
+	})
+}
+
+func TestBucketTimes(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("daily buckets start at local midnight across DST transitions", func(t *testing.T) {
+		times := bucketTimes(dataQueryModel{
+			TimeRange: backend.TimeRange{
+				From: time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC),
+				To:   time.Date(2023, 3, 28, 0, 0, 0, 0, time.UTC),
+			},
+			Interval: 24 * time.Hour,
+			Location: berlin,
+		})
+
+		require.Len(t, times, 4)
+		for i, day := range []int{25, 26, 27, 28} {
+			require.True(t, time.Date(2023, 3, day, 0, 0, 0, 0, berlin).Equal(times[i]), times[i].String())
+		}
+		require.Equal(t, 23*time.Hour, times[2].Sub(times[1]))
+
+		times = bucketTimes(dataQueryModel{
+			TimeRange: backend.TimeRange{
+				From: time.Date(2023, 10, 28, 12, 0, 0, 0, time.UTC),
+				To:   time.Date(2023, 10, 30, 0, 0, 0, 0, time.UTC),
+			},
+			Interval: 24 * time.Hour,
+			Location: berlin,
+		})
+
+		require.Len(t, times, 3)
+		require.Equal(t, 25*time.Hour, times[2].Sub(times[1]))
+	})
+
+	t.Run("hourly buckets skip non-existent wall clock hour", func(t *testing.T) {
+		times := bucketTimes(dataQueryModel{
+			TimeRange: backend.TimeRange{
+				From: time.Date(2023, 3, 26, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2023, 3, 26, 3, 0, 0, 0, time.UTC),
+			},
+			Interval: time.Hour,
+			Location: berlin,
+		})
+
+		expected := []time.Time{
+			time.Date(2023, 3, 26, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 3, 26, 1, 0, 0, 0, time.UTC),
+			time.Date(2023, 3, 26, 2, 0, 0, 0, time.UTC),
+			time.Date(2023, 3, 26, 3, 0, 0, 0, time.UTC),
+		}
+		require.Len(t, times, len(expected))
+		for i := range expected {
+			require.True(t, expected[i].Equal(times[i]), times[i].String())
+		}
+	})
+}
+
+func TestResampleTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	input := data.NewFrame("wide_test",
+		data.NewField("Time", nil, []time.Time{
+			time.Date(2023, 3, 25, 23, 0, 0, 0, time.UTC),
+		}),
+		data.NewField("Values", nil, []*float64{
+			pointer.Float64(1),
+		}))
+
+	frame, err := resample(input, dataQueryModel{
+		FillMissing: &data.FillMissing{Mode: data.FillModeNull},
+		TimeRange: backend.TimeRange{
+			From: time.Date(2023, 3, 24, 23, 0, 0, 0, time.UTC),
+			To:   time.Date(2023, 3, 27, 12, 0, 0, 0, time.UTC),
+		},
+		Interval: 24 * time.Hour,
+		Location: berlin,
+	})
+	require.NoError(t, err)
+
+	output := data.NewFrame("wide_test",
+		data.NewField("Time", nil, []time.Time{
+			time.Date(2023, 3, 24, 23, 0, 0, 0, time.UTC),
+			time.Date(2023, 3, 25, 23, 0, 0, 0, time.UTC),
+			time.Date(2023, 3, 26, 22, 0, 0, 0, time.UTC),
+		}),
+		data.NewField("Values", nil, []*float64{
+			nil,
+			pointer.Float64(1),
+			nil,
+		}))
+	if diff := cmp.Diff(output, frame, data.FrameTestCompareOptions()...); diff != "" {
+		t.Errorf("Result mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"xorm.io/core"
+)
+
+// driverName is the database/sql driver used for data source connections. It's the
+// sqlite3 driver with every connection restricted to the opened database file.
+const driverName = "sqlite3_datasource"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{ConnectHook: restrictConnection})
+	core.RegisterDriver(driverName, core.QueryDriver("sqlite3"))
+}
+
+// readOnlyPragmas are the schema pragmas which can be used, mostly through table-valued
+// functions like pragma_table_info, to explore the database from the query editor.
+var readOnlyPragmas = map[string]bool{
+	"table_info":       true,
+	"table_xinfo":      true,
+	"table_list":       true,
+	"index_list":       true,
+	"index_info":       true,
+	"index_xinfo":      true,
+	"foreign_key_list": true,
+}
+
+// restrictConnection prevents queries from reaching files other than the data source
+// database file. Attaching databases is disabled on the connection level, which also
+// covers VACUUM INTO, and the authorizer denies ATTACH, DETACH and all but the schema
+// pragmas however the statements are written.
+func restrictConnection(conn *sqlite3.SQLiteConn) error {
+	conn.SetLimit(sqlite3.SQLITE_LIMIT_ATTACHED, 0)
+	conn.RegisterAuthorizer(authorize)
+	return nil
+}
+
+func authorize(action int, arg1, _, _ string) int {
+	switch action {
+	case sqlite3.SQLITE_ATTACH, sqlite3.SQLITE_DETACH:
+		return sqlite3.SQLITE_DENY
+	case sqlite3.SQLITE_PRAGMA:
+		if !readOnlyPragmas[strings.ToLower(arg1)] {
+			return sqlite3.SQLITE_DENY
+		}
+	}
+	return sqlite3.SQLITE_OK
+}
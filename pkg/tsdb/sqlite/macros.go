@@ -0,0 +1,167 @@
+package sqlite
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+const rsIdentifier = `([_a-zA-Z0-9]+)`
+const sExpr = `\$` + rsIdentifier + `\(([^\)]*)\)`
+
+// localTimeFormat formats wall clock times for comparison with ISO8601 text columns.
+const localTimeFormat = "2006-01-02 15:04:05"
+
+type sqliteMacroEngine struct {
+	*sqleng.SQLMacroEngineBase
+	logger log.Logger
+}
+
+func newSqliteMacroEngine(logger log.Logger) sqleng.SQLMacroEngine {
+	return &sqliteMacroEngine{SQLMacroEngineBase: sqleng.NewSQLMacroEngineBase(), logger: logger}
+}
+
+func (m *sqliteMacroEngine) Interpolate(query *backend.DataQuery, timeRange backend.TimeRange, sql string) (string, error) {
+	// TODO: Handle error
+	rExp, _ := regexp.Compile(sExpr)
+	var macroError error
+
+	sql = m.ReplaceAllStringSubmatchFunc(rExp, sql, func(groups []string) string {
+		args := strings.Split(groups[2], ",")
+		for i, arg := range args {
+			args[i] = strings.Trim(arg, " ")
+		}
+		res, err := m.evaluateMacro(timeRange, query, groups[1], args)
+		if err != nil && macroError == nil {
+			macroError = err
+			return "macro_error()"
+		}
+		return res
+	})
+
+	if macroError != nil {
+		return "", macroError
+	}
+
+	return sql, nil
+}
+
+// unixEpoch returns an expression converting a SQLite time value (ISO8601 text
+// or DATETIME column) to unix timestamp in seconds.
+func unixEpoch(column string) string {
+	return fmt.Sprintf("CAST(strftime('%%s', %s) AS INTEGER)", column)
+}
+
+func (m *sqliteMacroEngine) evaluateMacro(timeRange backend.TimeRange, query *backend.DataQuery, name string, args []string) (string, error) {
+	switch name {
+	case "__timeEpoch", "__time":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s AS \"time\"", unixEpoch(args[0])), nil
+	case "__timeFilter":
+		args, tz := sqleng.TimezoneArg(args, 1)
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		if tz != "" {
+			loc, err := sqleng.ParseTimezone(tz)
+			if err != nil {
+				return "", err
+			}
+			if loc != time.UTC {
+				// column holds wall clock times of the given timezone
+				return fmt.Sprintf("%s BETWEEN '%s' AND '%s'", args[0], timeRange.From.In(loc).Format(localTimeFormat), timeRange.To.In(loc).Format(localTimeFormat)), nil
+			}
+		}
+		return fmt.Sprintf("%s BETWEEN %d AND %d", unixEpoch(args[0]), timeRange.From.UTC().Unix(), timeRange.To.UTC().Unix()), nil
+	case "__timeFrom":
+		return fmt.Sprintf("datetime(%d, 'unixepoch')", timeRange.From.UTC().Unix()), nil
+	case "__timeTo":
+		return fmt.Sprintf("datetime(%d, 'unixepoch')", timeRange.To.UTC().Unix()), nil
+	case "__timeGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'"`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
+		}
+		return sqleng.TimeGroupExpression(unixEpoch(args[0]), interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("%s / %.0f * %.0f", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
+	case "__timeGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__timeGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	case "__unixEpochFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().Unix(), args[0], timeRange.To.UTC().Unix()), nil
+	case "__unixEpochNanoFilter":
+		if len(args) == 0 {
+			return "", fmt.Errorf("missing time column argument for macro %v", name)
+		}
+		return fmt.Sprintf("%s >= %d AND %s <= %d", args[0], timeRange.From.UTC().UnixNano(), args[0], timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochNanoFrom":
+		return fmt.Sprintf("%d", timeRange.From.UTC().UnixNano()), nil
+	case "__unixEpochNanoTo":
+		return fmt.Sprintf("%d", timeRange.To.UTC().UnixNano()), nil
+	case "__unixEpochGroup":
+		args, tz := sqleng.TimezoneArg(args, 2)
+		if len(args) < 2 {
+			return "", fmt.Errorf("macro %v needs time column and interval and optional fill value", name)
+		}
+		interval, err := gtime.ParseInterval(strings.Trim(args[1], `'`))
+		if err != nil {
+			return "", fmt.Errorf("error parsing interval %v", args[1])
+		}
+		loc, err := sqleng.MacroTimezone(query, tz)
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 3 {
+			err := sqleng.SetupFillmode(query, interval, args[2])
+			if err != nil {
+				return "", err
+			}
+			if err := sqleng.SetupFillTimezone(query, loc); err != nil {
+				return "", err
+			}
+		}
+		return sqleng.TimeGroupExpression(fmt.Sprintf("CAST(%s AS INTEGER)", args[0]), interval, loc, timeRange, func(epoch string) string {
+			return fmt.Sprintf("%s / %.0f * %.0f", epoch, interval.Seconds(), interval.Seconds())
+		}), nil
+	case "__unixEpochGroupAlias":
+		tg, err := m.evaluateMacro(timeRange, query, "__unixEpochGroup", args)
+		if err == nil {
+			return tg + " AS \"time\"", nil
+		}
+		return "", err
+	default:
+		return "", fmt.Errorf("unknown macro %v", name)
+	}
+}
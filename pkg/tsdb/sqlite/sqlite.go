@@ -0,0 +1,195 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+var logger = log.New("tsdb.sqlite")
+
+var (
+	errPathRequired   = errors.New("database file path is required")
+	errPathNotAllowed = errors.New("database file path is not in allowed paths, see sqlite_allowed_paths setting")
+)
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg)),
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
+	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    0,
+			MaxIdleConns:    2,
+			ConnMaxLifetime: 14400,
+		}
+
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+
+		database := jsonData.Database
+		if database == "" {
+			database = settings.Database
+		}
+
+		path, err := resolveDatabasePath(database, cfg.SQLiteDataSourceAllowedPaths)
+		if err != nil {
+			return nil, err
+		}
+
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                jsonData,
+			URL:                     settings.URL,
+			Database:                path,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		// Database files are always opened in read-only mode, query_only additionally
+		// prevents changes to the database from within queries. The driver restricts
+		// connections to this file, see restrictConnection.
+		cnnstr := fmt.Sprintf("file:%s?mode=ro&_query_only=true&_busy_timeout=5000", (&url.URL{Path: path}).EscapedPath())
+
+		if cfg.Env == setting.Dev {
+			logger.Debug("GetEngine", "connection", cnnstr)
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:        driverName,
+			ConnectionString:  cnnstr,
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"TEXT", "VARCHAR", "CHAR", "CLOB"},
+			RowLimit:          cfg.DataProxyRowLimit,
+		}
+
+		queryResultTransformer := sqliteQueryResultTransformer{}
+
+		return sqleng.NewQueryDataHandler(config, &queryResultTransformer, newSqliteMacroEngine(logger), logger)
+	}
+}
+
+// resolveDatabasePath checks that path points to an existing database file located
+// in one of allowed paths. Symbolic links are resolved before the check, so a link
+// in an allowed directory can't be used to read files outside of it.
+func resolveDatabasePath(path string, allowedPaths []string) (string, error) {
+	if path == "" {
+		return "", errPathRequired
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("database file path must be absolute: %s", path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("database file is not accessible: %w", err)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("database file is not accessible: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("database file path is a directory: %s", path)
+	}
+	for _, allowedPath := range allowedPaths {
+		allowed, err := filepath.EvalSymlinks(filepath.Clean(allowedPath))
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(allowed, resolved)
+		if err != nil {
+			continue
+		}
+		if rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))) {
+			return resolved, nil
+		}
+	}
+	return "", errPathNotAllowed
+}
+
+func (s *Service) getDataSourceHandler(pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
+	i, err := s.im.Get(pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	instance := i.(*sqleng.DataSourceHandler)
+	return instance, nil
+}
+
+// CheckHealth pings the SQLite database file
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := s.getDataSourceHandler(req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: err.Error()}, nil
+	}
+
+	err = dsHandler.Ping()
+	if err != nil {
+		return &backend.CheckHealthResult{Status: backend.HealthStatusError, Message: dsHandler.TransformQueryError(logger, err).Error()}, nil
+	}
+	return &backend.CheckHealthResult{Status: backend.HealthStatusOk, Message: "Database Connection OK"}, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := s.getDataSourceHandler(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+type sqliteQueryResultTransformer struct{}
+
+func (t *sqliteQueryResultTransformer) TransformQueryError(logger log.Logger, err error) error {
+	var driverErr sqlite3.Error
+	if errors.As(err, &driverErr) {
+		// Syntax errors and missing tables or columns are reported as a generic SQL error,
+		// anything else (I/O errors, corrupted files) is logged instead of returned.
+		if driverErr.Code != sqlite3.ErrError {
+			logger.Error("Query error", "error", err)
+			return errQueryFailed
+		}
+	}
+
+	return err
+}
+
+var errQueryFailed = errors.New("query failed - please inspect Grafana server log for details")
+
+func (t *sqliteQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
+
+// GetConverters returns a dynamic converter since SQLite columns have no strict types and
+// expression columns, i.e. aggregations or macros, have no declared type at all. Field
+// types are detected from returned values instead.
+func (t *sqliteQueryResultTransformer) GetConverters() []sqlutil.Converter {
+	return []sqlutil.Converter{
+		{Name: "sqlite dynamic converter", Dynamic: true},
+	}
+}
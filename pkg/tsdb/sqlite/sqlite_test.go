@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+func createTestDatabase(t *testing.T, dir string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, "metrics.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, db.Close()) }()
+
+	_, err = db.Exec(`CREATE TABLE metric (time DATETIME, host TEXT, value REAL)`)
+	require.NoError(t, err)
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		ts := from.Add(time.Duration(i) * time.Minute).Format("2006-01-02 15:04:05")
+		_, err = db.Exec(`INSERT INTO metric (time, host, value) VALUES (?, ?, ?)`, ts, "server1", float64(i))
+		require.NoError(t, err)
+	}
+
+	return path
+}
+
+func TestResolveDatabasePath(t *testing.T) {
+	allowed := t.TempDir()
+	other := t.TempDir()
+	path := createTestDatabase(t, allowed)
+	outside := createTestDatabase(t, other)
+
+	t.Run("should accept file in allowed path", func(t *testing.T) {
+		resolved, err := resolveDatabasePath(path, []string{allowed})
+		require.NoError(t, err)
+		expected, err := filepath.EvalSymlinks(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, resolved)
+	})
+
+	t.Run("should reject empty path", func(t *testing.T) {
+		_, err := resolveDatabasePath("", []string{allowed})
+		require.ErrorIs(t, err, errPathRequired)
+	})
+
+	t.Run("should reject relative path", func(t *testing.T) {
+		_, err := resolveDatabasePath("metrics.db", []string{allowed})
+		require.Error(t, err)
+	})
+
+	t.Run("should reject directory", func(t *testing.T) {
+		_, err := resolveDatabasePath(allowed, []string{allowed})
+		require.Error(t, err)
+	})
+
+	t.Run("should reject file outside of allowed paths", func(t *testing.T) {
+		_, err := resolveDatabasePath(outside, []string{allowed})
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = resolveDatabasePath(path, nil)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should reject path escaping allowed path", func(t *testing.T) {
+		_, err := resolveDatabasePath(filepath.Join(allowed, "..", filepath.Base(other), "metrics.db"), []string{allowed})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should reject symbolic link to file outside of allowed paths", func(t *testing.T) {
+		link := filepath.Join(allowed, "link.db")
+		require.NoError(t, os.Symlink(outside, link))
+
+		_, err := resolveDatabasePath(link, []string{allowed})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	path := createTestDatabase(t, dir)
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000000
+	cfg.SQLiteDataSourceAllowedPaths = []string{dir}
+
+	instance, err := newInstanceSettings(cfg)(backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"database": "` + path + `"}`),
+	})
+	require.NoError(t, err)
+	handler := instance.(*sqleng.DataSourceHandler)
+	t.Cleanup(handler.Dispose)
+
+	from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+	timeRange := backend.TimeRange{From: from, To: from.Add(10 * time.Minute)}
+
+	query := func(t *testing.T, rawSQL string, format string) backend.DataResponse {
+		t.Helper()
+
+		resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{
+				{
+					JSON:      []byte(`{"rawSql": "` + rawSQL + `", "format": "` + format + `"}`),
+					RefID:     "A",
+					TimeRange: timeRange,
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("should return table", func(t *testing.T) {
+		res := query(t, "SELECT host, value FROM metric ORDER BY value", "table")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 10, frame.Rows())
+		require.Equal(t, "host", frame.Fields[0].Name)
+		require.Equal(t, "value", frame.Fields[1].Name)
+		require.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[1].Type())
+	})
+
+	t.Run("should return time series grouped by interval", func(t *testing.T) {
+		res := query(t, "SELECT $__timeGroupAlias(time, '5m'), sum(value) AS value FROM metric WHERE $__timeFilter(time) GROUP BY 1 ORDER BY 1", "time_series")
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, data.FieldTypeNullableTime, frame.Fields[0].Type())
+		require.Equal(t, from, frame.Fields[0].At(0).(*time.Time).UTC())
+		require.Equal(t, 10.0, *frame.Fields[1].At(0).(*float64))
+		require.Equal(t, 35.0, *frame.Fields[1].At(1).(*float64))
+	})
+
+	t.Run("should reject statements accessing other database files", func(t *testing.T) {
+		other := createTestDatabase(t, t.TempDir())
+
+		for _, sql := range []string{
+			"ATTACH DATABASE '" + other + "' AS other",
+			"/**/ATTACH DATABASE '" + other + "' AS other",
+			"SELECT 1; ATTACH '" + other + "' AS other",
+			"DETACH DATABASE main",
+			"VACUUM INTO '" + filepath.Join(t.TempDir(), "copy.db") + "'",
+			"PRAGMA journal_mode = WAL",
+			"/* comment */ PRAGMA main.journal_mode",
+		} {
+			res := query(t, sql, "table")
+			require.Error(t, res.Error, sql)
+		}
+
+		// the attach must not have succeeded on any pooled connection
+		res := query(t, "SELECT count(*) AS total FROM other.metric", "table")
+		require.Error(t, res.Error)
+	})
+
+	t.Run("should allow table-valued pragma functions", func(t *testing.T) {
+		res := query(t, "SELECT name FROM pragma_table_info('metric')", "table")
+		require.NoError(t, res.Error)
+		require.Equal(t, 3, res.Frames[0].Rows())
+	})
+
+	t.Run("should not allow changes to database file", func(t *testing.T) {
+		res := query(t, "DELETE FROM metric", "table")
+		require.Error(t, res.Error)
+
+		res = query(t, "SELECT count(*) AS total FROM metric", "table")
+		require.NoError(t, res.Error)
+		require.Equal(t, 10.0, *res.Frames[0].Fields[0].At(0).(*float64))
+	})
+
+	t.Run("should fail creating instance for database outside of allowed paths", func(t *testing.T) {
+		_, err := newInstanceSettings(cfg)(backend.DataSourceInstanceSettings{
+			JSONData: []byte(`{"database": "` + createTestDatabase(t, t.TempDir()) + `"}`),
+		})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+}
+
+func TestSQLiteTimezone(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dst.db")
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`CREATE TABLE metric (time DATETIME, value REAL)`)
+	require.NoError(t, err)
+
+	// hourly values from midnight 2023-03-25 until midnight 2023-03-28 in Europe/Berlin,
+	// which switches to daylight saving time on 2023-03-26
+	from := time.Date(2023, 3, 24, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 3, 27, 22, 0, 0, 0, time.UTC)
+	for ts := from; ts.Before(to); ts = ts.Add(time.Hour) {
+		_, err = db.Exec(`INSERT INTO metric (time, value) VALUES (?, 1)`, ts.Format("2006-01-02 15:04:05"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000000
+	cfg.SQLiteDataSourceAllowedPaths = []string{dir}
+
+	instance, err := newInstanceSettings(cfg)(backend.DataSourceInstanceSettings{
+		JSONData: []byte(`{"database": "` + path + `"}`),
+	})
+	require.NoError(t, err)
+	handler := instance.(*sqleng.DataSourceHandler)
+	t.Cleanup(handler.Dispose)
+
+	resp, err := handler.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{
+			{
+				JSON:      []byte(`{"rawSql": "SELECT $__timeGroupAlias(time, '1d', 0), sum(value) AS value FROM metric WHERE $__timeFilter(time) GROUP BY 1 ORDER BY 1", "format": "time_series", "timezone": "Europe/Berlin"}`),
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: to.Add(-time.Second)},
+			},
+		},
+	})
+	require.NoError(t, err)
+	res := resp.Responses["A"]
+	require.NoError(t, res.Error)
+	require.Len(t, res.Frames, 1)
+
+	frame := res.Frames[0]
+	require.Equal(t, 3, frame.Rows())
+	expected := []struct {
+		time  time.Time
+		value float64
+	}{
+		{time.Date(2023, 3, 24, 23, 0, 0, 0, time.UTC), 24},
+		{time.Date(2023, 3, 25, 23, 0, 0, 0, time.UTC), 23},
+		{time.Date(2023, 3, 26, 22, 0, 0, 0, time.UTC), 24},
+	}
+	for i, e := range expected {
+		require.Equal(t, e.time, frame.Fields[0].At(i).(*time.Time).UTC())
+		require.Equal(t, e.value, *frame.Fields[1].At(i).(*float64))
+	}
+}
@@ -0,0 +1,141 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+const defaultSearchLimit = 20
+
+type searchResponse struct {
+	Traces []traceSearchMetadata `json:"traces"`
+}
+
+type traceSearchMetadata struct {
+	TraceID           string `json:"traceID"`
+	RootServiceName   string `json:"rootServiceName"`
+	RootTraceName     string `json:"rootTraceName"`
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	DurationMs        *int64 `json:"durationMs"`
+}
+
+// querySearch runs a TraceQL query and returns the matching traces as a table, most recent first.
+func (s *Service) querySearch(ctx context.Context, dsInfo *datasourceInfo, query backend.DataQuery, model *QueryModel) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+
+	limit := model.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	params := url.Values{}
+	params.Set("q", model.TraceID)
+	params.Set("limit", strconv.FormatInt(limit, 10))
+	params.Set("start", strconv.FormatInt(query.TimeRange.From.Unix(), 10))
+	params.Set("end", strconv.FormatInt(query.TimeRange.To.Unix(), 10))
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, dsInfo.URL+"/api/search?"+params.Encode(), nil)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+	request.Header.Set("Accept", "application/json")
+	s.tlog.FromContext(ctx).Debug("Tempo search request", "url", request.URL.String())
+
+	resp, err := dsInfo.HTTPClient.Do(request)
+	if err != nil {
+		queryRes.Error = fmt.Errorf("failed get to tempo: %w", err)
+		return queryRes
+	}
+
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			s.tlog.FromContext(ctx).Warn("failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		queryRes.Error = fmt.Errorf("failed to search traces with query: %s Status: %s Body: %s", model.TraceID, resp.Status, string(body))
+		return queryRes
+	}
+
+	res := searchResponse{}
+	if err := json.Unmarshal(body, &res); err != nil {
+		queryRes.Error = fmt.Errorf("failed to parse tempo search response: %w", err)
+		return queryRes
+	}
+
+	frame := searchToFrame(res.Traces)
+	frame.Meta.ExecutedQueryString = model.TraceID
+	queryRes.Frames = data.Frames{frame}
+	return queryRes
+}
+
+// searchToFrame converts the traces of a search response to a table with the same columns as in the query editor.
+func searchToFrame(traces []traceSearchMetadata) *data.Frame {
+	type row struct {
+		trace traceSearchMetadata
+		start *time.Time
+	}
+
+	rows := make([]row, 0, len(traces))
+	for _, trace := range traces {
+		r := row{trace: trace}
+		if ns, err := strconv.ParseInt(trace.StartTimeUnixNano, 10, 64); err == nil {
+			t := time.Unix(0, ns).UTC()
+			r.start = &t
+		}
+		rows = append(rows, r)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].start == nil || rows[j].start == nil {
+			return rows[j].start == nil && rows[i].start != nil
+		}
+		return rows[i].start.After(*rows[j].start)
+	})
+
+	traceIDs := make([]string, 0, len(rows))
+	names := make([]string, 0, len(rows))
+	starts := make([]*time.Time, 0, len(rows))
+	durations := make([]*float64, 0, len(rows))
+	for _, r := range rows {
+		traceIDs = append(traceIDs, r.trace.TraceID)
+		names = append(names, strings.TrimSpace(r.trace.RootServiceName+" "+r.trace.RootTraceName))
+		starts = append(starts, r.start)
+		var duration *float64
+		if r.trace.DurationMs != nil {
+			d := float64(*r.trace.DurationMs)
+			duration = &d
+		}
+		durations = append(durations, duration)
+	}
+
+	traceIDField := data.NewField("traceID", nil, traceIDs)
+	traceIDField.Config = &data.FieldConfig{DisplayNameFromDS: "Trace ID"}
+	nameField := data.NewField("traceName", nil, names)
+	nameField.Config = &data.FieldConfig{DisplayNameFromDS: "Name"}
+	startField := data.NewField("startTime", nil, starts)
+	startField.Config = &data.FieldConfig{DisplayNameFromDS: "Start time"}
+	durationField := data.NewField("traceDuration", nil, durations)
+	durationField.Config = &data.FieldConfig{DisplayNameFromDS: "Duration", Unit: "ms"}
+
+	frame := data.NewFrame("Traces", traceIDField, nameField, startField, durationField)
+	frame.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeTable}
+	return frame
+}
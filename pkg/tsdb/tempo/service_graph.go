@@ -0,0 +1,243 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// The service graph metrics generated by Tempo, which are queried from the span metrics data source.
+const (
+	secondsMetric = "traces_service_graph_request_server_seconds_sum"
+	totalsMetric  = "traces_service_graph_request_total"
+	failedMetric  = "traces_service_graph_request_failed_total"
+)
+
+// DataSourceQuerier queries another data source of the organization of a plugin context. Service graph queries use
+// it to query the span metrics Prometheus data source.
+type DataSourceQuerier interface {
+	QueryDataSource(ctx context.Context, pCtx backend.PluginContext, datasourceUID string, timeRange backend.TimeRange, queries []*simplejson.Json) (*backend.QueryDataResponse, error)
+}
+
+type serviceGraphStats struct {
+	total   float64
+	seconds float64
+	failed  float64
+}
+
+type serviceGraphEdge struct {
+	serviceGraphStats
+	source string
+	target string
+}
+
+// queryServiceMap builds the service graph from the request rates between services over the query time range.
+func (s *Service) queryServiceMap(ctx context.Context, pCtx backend.PluginContext, dsInfo *datasourceInfo, query backend.DataQuery, model *QueryModel) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+	if dsInfo.ServiceMapDatasourceUID == "" {
+		queryRes.Error = errors.New("no service graph data source is configured")
+		return queryRes
+	}
+
+	selector, err := serviceMapSelector(model.ServiceMapQuery)
+	if err != nil {
+		queryRes.Error = err
+		return queryRes
+	}
+
+	metrics := []string{totalsMetric, secondsMetric, failedMetric}
+	queries := make([]*simplejson.Json, 0, len(metrics))
+	for _, metric := range metrics {
+		queries = append(queries, simplejson.NewFromAny(map[string]interface{}{
+			"refId":   metric,
+			"expr":    fmt.Sprintf("rate(%s%s[$__range])", metric, selector),
+			"instant": true,
+			"range":   false,
+		}))
+	}
+
+	res, err := s.querier.QueryDataSource(ctx, pCtx, dsInfo.ServiceMapDatasourceUID, query.TimeRange, queries)
+	if err != nil {
+		queryRes.Error = fmt.Errorf("failed to query service graph metrics: %w", err)
+		return queryRes
+	}
+
+	graph := newServiceGraph()
+	for _, metric := range metrics {
+		metricRes := res.Responses[metric]
+		if metricRes.Error != nil {
+			queryRes.Error = fmt.Errorf("failed to query service graph metric %s: %w", metric, metricRes.Error)
+			return queryRes
+		}
+		for _, frame := range metricRes.Frames {
+			graph.collect(frame, metric)
+		}
+	}
+
+	queryRes.Frames = graph.frames()
+	return queryRes
+}
+
+// serviceMapSelector returns the label matchers of a service map query, e.g. {client="app"}, which filter the
+// service graph metrics. The matchers are parsed and formatted again, so the query can't add anything else to
+// the PromQL expressions of the metrics.
+func serviceMapSelector(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || query == "{}" {
+		return "", nil
+	}
+	if !strings.HasPrefix(query, "{") || !strings.HasSuffix(query, "}") {
+		return "", fmt.Errorf("invalid service map query %q: expected label matchers, e.g. {client=\"app\"}", query)
+	}
+
+	matchers, err := parser.ParseMetricSelector(query)
+	if err != nil {
+		return "", fmt.Errorf("invalid service map query %q: %w", query, err)
+	}
+	formatted := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m.Name == labels.MetricName {
+			return "", fmt.Errorf("invalid service map query %q: metric names can't be matched", query)
+		}
+		formatted = append(formatted, m.String())
+	}
+	return "{" + strings.Join(formatted, ",") + "}", nil
+}
+
+// serviceGraph collects the metrics of the edges between a client and a server. Metrics are attributed to the server
+// node, so the stats of a node are about the requests it handled.
+type serviceGraph struct {
+	nodes     map[string]*serviceGraphStats
+	nodeOrder []string
+	edges     map[string]*serviceGraphEdge
+	edgeOrder []string
+}
+
+func newServiceGraph() *serviceGraph {
+	return &serviceGraph{
+		nodes: map[string]*serviceGraphStats{},
+		edges: map[string]*serviceGraphEdge{},
+	}
+}
+
+func (g *serviceGraph) node(id string) *serviceGraphStats {
+	node, ok := g.nodes[id]
+	if !ok {
+		node = &serviceGraphStats{}
+		g.nodes[id] = node
+		g.nodeOrder = append(g.nodeOrder, id)
+	}
+	return node
+}
+
+func (g *serviceGraph) collect(frame *data.Frame, metric string) {
+	for _, field := range frame.Fields {
+		if field.Type() != data.FieldTypeFloat64 && field.Type() != data.FieldTypeNullableFloat64 {
+			continue
+		}
+		if field.Len() == 0 {
+			continue
+		}
+		value, ok := field.ConcreteAt(field.Len() - 1)
+		if !ok {
+			continue
+		}
+		v := value.(float64)
+		if math.IsNaN(v) {
+			continue
+		}
+
+		client, server := field.Labels["client"], field.Labels["server"]
+		edgeID := client + "_" + server
+		edge, ok := g.edges[edgeID]
+		if !ok {
+			edge = &serviceGraphEdge{source: client, target: server}
+			g.edges[edgeID] = edge
+			g.edgeOrder = append(g.edgeOrder, edgeID)
+		}
+		serverNode := g.node(server)
+		g.node(client)
+
+		switch metric {
+		case totalsMetric:
+			edge.total += v
+			serverNode.total += v
+		case secondsMetric:
+			edge.seconds += v
+			serverNode.seconds += v
+		case failedMetric:
+			edge.failed += v
+			serverNode.failed += v
+		}
+	}
+}
+
+// frames returns the nodes and edges frames of the node graph. The metrics are per second rates, so the request rate
+// is the total, and the average response time is the ratio of the seconds and the total.
+func (g *serviceGraph) frames() data.Frames {
+	nodes := data.NewFrame("Nodes",
+		data.NewField("id", nil, []string{}),
+		data.NewField("title", nil, []string{}).SetConfig(&data.FieldConfig{DisplayName: "Service name"}),
+		data.NewField("mainStat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondaryStat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+		data.NewField("arc__success", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Success",
+			Color:       map[string]interface{}{"mode": "fixed", "fixedColor": "green"},
+		}),
+		data.NewField("arc__failed", nil, []float64{}).SetConfig(&data.FieldConfig{
+			DisplayName: "Failed",
+			Color:       map[string]interface{}{"mode": "fixed", "fixedColor": "red"},
+		}),
+	)
+	nodes.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	edges := data.NewFrame("Edges",
+		data.NewField("id", nil, []string{}),
+		data.NewField("source", nil, []string{}),
+		data.NewField("target", nil, []string{}),
+		data.NewField("mainStat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Average response time", Unit: "ms/r"}),
+		data.NewField("secondaryStat", nil, []float64{}).SetConfig(&data.FieldConfig{DisplayName: "Requests per second", Unit: "r/sec"}),
+	)
+	edges.Meta = &data.FrameMeta{PreferredVisualization: data.VisTypeNodeGraph}
+
+	for _, id := range g.nodeOrder {
+		node := g.nodes[id]
+		success, failed := 1.0, 0.0
+		if node.total > 0 {
+			failed = math.Min(node.failed, node.total) / node.total
+			success = 1 - failed
+		}
+		nodes.AppendRow(id, id, node.responseTime(), node.requestRate(), success, failed)
+	}
+	for _, id := range g.edgeOrder {
+		edge := g.edges[id]
+		edges.AppendRow(id, edge.source, edge.target, edge.responseTime(), edge.requestRate())
+	}
+	return data.Frames{nodes, edges}
+}
+
+// responseTime returns the average response time in milliseconds, or NaN for nodes which didn't handle requests,
+// which the node graph doesn't show.
+func (s *serviceGraphStats) responseTime() float64 {
+	if s.total == 0 {
+		return math.NaN()
+	}
+	return s.seconds / s.total * 1000
+}
+
+// requestRate returns the requests per second rounded to two decimals.
+func (s *serviceGraphStats) requestRate() float64 {
+	if s.total == 0 {
+		return math.NaN()
+	}
+	return math.Round(s.total*100) / 100
+}
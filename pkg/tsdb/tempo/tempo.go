@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
@@ -18,24 +20,45 @@ import (
 )
 
 type Service struct {
-	im   instancemgmt.InstanceManager
-	tlog log.Logger
+	im      instancemgmt.InstanceManager
+	tlog    log.Logger
+	querier DataSourceQuerier
 }
 
-func ProvideService(httpClientProvider httpclient.Provider) *Service {
+func ProvideService(httpClientProvider httpclient.Provider, querier DataSourceQuerier) *Service {
 	return &Service{
-		tlog: log.New("tsdb.tempo"),
-		im:   datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		tlog:    log.New("tsdb.tempo"),
+		im:      datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
+		querier: querier,
 	}
 }
 
 type datasourceInfo struct {
 	HTTPClient *http.Client
 	URL        string
+	// ServiceMapDatasourceUID is the uid of the Prometheus data source with the service graph span metrics
+	ServiceMapDatasourceUID string
 }
 
+const (
+	queryTypeTraceID    = "traceId"
+	queryTypeTraceQL    = "traceql"
+	queryTypeServiceMap = "serviceMap"
+)
+
 type QueryModel struct {
-	TraceID string `json:"query"`
+	// QueryType defaults to a trace ID query
+	QueryType string `json:"queryType"`
+	// TraceID is the trace ID, or the TraceQL query of traceql queries
+	TraceID         string `json:"query"`
+	Limit           int64  `json:"limit"`
+	ServiceMapQuery string `json:"serviceMapQuery"`
+}
+
+type datasourceJSONData struct {
+	ServiceMap struct {
+		DatasourceUID string `json:"datasourceUid"`
+	} `json:"serviceMap"`
 }
 
 func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.InstanceFactoryFunc {
@@ -50,38 +73,75 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		jsonData := datasourceJSONData{}
+		if len(settings.JSONData) > 0 {
+			if err := json.Unmarshal(settings.JSONData, &jsonData); err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:              client,
+			URL:                     settings.URL,
+			ServiceMapDatasourceUID: jsonData.ServiceMap.DatasourceUID,
 		}
 		return model, nil
 	}
 }
 
+// hexOnly matches trace IDs. Like in the query editor, traceql queries consisting of hex characters only are
+// trace IDs.
+var hexOnly = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
-	queryRes := backend.DataResponse{}
-	refID := req.Queries[0].RefID
-
-	model := &QueryModel{}
-	err := json.Unmarshal(req.Queries[0].JSON, model)
-	if err != nil {
-		return result, err
-	}
 
 	dsInfo, err := s.getDSInfo(req.PluginContext)
 	if err != nil {
 		return nil, err
 	}
 
-	request, err := s.createRequest(ctx, dsInfo, model.TraceID, req.Queries[0].TimeRange.From.Unix(), req.Queries[0].TimeRange.To.Unix())
+	for _, query := range req.Queries {
+		model := &QueryModel{}
+		if err := json.Unmarshal(query.JSON, model); err != nil {
+			return result, err
+		}
+
+		var queryRes backend.DataResponse
+		switch {
+		case model.QueryType == "" || model.QueryType == queryTypeTraceID:
+			queryRes = s.queryTrace(ctx, dsInfo, query, model.TraceID)
+		case model.QueryType == queryTypeTraceQL && hexOnly.MatchString(strings.TrimSpace(model.TraceID)):
+			queryRes = s.queryTrace(ctx, dsInfo, query, strings.TrimSpace(model.TraceID))
+		case model.QueryType == queryTypeTraceQL:
+			queryRes = s.querySearch(ctx, dsInfo, query, model)
+		case model.QueryType == queryTypeServiceMap:
+			queryRes = s.queryServiceMap(ctx, req.PluginContext, dsInfo, query, model)
+		default:
+			queryRes.Error = fmt.Errorf("unsupported query type: %s", model.QueryType)
+		}
+
+		for _, frame := range queryRes.Frames {
+			frame.RefID = query.RefID
+		}
+		result.Responses[query.RefID] = queryRes
+	}
+	return result, nil
+}
+
+func (s *Service) queryTrace(ctx context.Context, dsInfo *datasourceInfo, query backend.DataQuery, traceID string) backend.DataResponse {
+	queryRes := backend.DataResponse{}
+
+	request, err := s.createRequest(ctx, dsInfo, traceID, query.TimeRange.From.Unix(), query.TimeRange.To.Unix())
 	if err != nil {
-		return result, err
+		queryRes.Error = err
+		return queryRes
 	}
 
 	resp, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return result, fmt.Errorf("failed get to tempo: %w", err)
+		queryRes.Error = fmt.Errorf("failed get to tempo: %w", err)
+		return queryRes
 	}
 
 	defer func() {
@@ -92,30 +152,28 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		queryRes.Error = err
+		return queryRes
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", model.TraceID, resp.Status, string(body))
-		result.Responses[refID] = queryRes
-		return result, nil
+		queryRes.Error = fmt.Errorf("failed to get trace with id: %s Status: %s Body: %s", traceID, resp.Status, string(body))
+		return queryRes
 	}
 
 	otTrace, err := otlp.NewProtobufTracesUnmarshaler().UnmarshalTraces(body)
-
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+		queryRes.Error = fmt.Errorf("failed to convert tempo response to Otlp: %w", err)
+		return queryRes
 	}
 
 	frame, err := TraceToFrame(otTrace)
 	if err != nil {
-		return &backend.QueryDataResponse{}, fmt.Errorf("failed to transform trace %v to data frame: %w", model.TraceID, err)
+		queryRes.Error = fmt.Errorf("failed to transform trace %v to data frame: %w", traceID, err)
+		return queryRes
 	}
-	frame.RefID = refID
-	frames := []*data.Frame{frame}
-	queryRes.Frames = frames
-	result.Responses[refID] = queryRes
-	return result, nil
+	queryRes.Frames = data.Frames{frame}
+	return queryRes
 }
 
 func (s *Service) createRequest(ctx context.Context, dsInfo *datasourceInfo, traceID string, start int64, end int64) (*http.Request, error) {
@@ -2,8 +2,18 @@ package tempo
 
 import (
 	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,3 +35,177 @@ func TestTempo(t *testing.T) {
 		assert.Equal(t, "/api/traces/traceID?start=1&end=2", req.URL.String())
 	})
 }
+
+func TestTempoQueryTypes(t *testing.T) {
+	timeRange := backend.TimeRange{From: time.Unix(1000, 0), To: time.Unix(2000, 0)}
+
+	t.Run("traceql queries return the traces found as a table", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/search", r.URL.Path)
+			require.Equal(t, `{ .http.status_code = 500 }`, r.URL.Query().Get("q"))
+			require.Equal(t, "20", r.URL.Query().Get("limit"))
+			require.Equal(t, "1000", r.URL.Query().Get("start"))
+			require.Equal(t, "2000", r.URL.Query().Get("end"))
+			_, _ = w.Write([]byte(`{"traces":[
+				{"traceID":"a1","rootServiceName":"app","rootTraceName":"GET /","startTimeUnixNano":"1500000000000","durationMs":10},
+				{"traceID":"b2","rootServiceName":"db","startTimeUnixNano":"1600000000000"}
+			]}`))
+		}))
+		defer srv.Close()
+
+		res := queryTempo(t, &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil, timeRange, `{"queryType":"traceql","query":"{ .http.status_code = 500 }"}`)
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+		frame := res.Frames[0]
+		require.Equal(t, "A", frame.RefID)
+		require.Equal(t, data.VisTypeTable, string(frame.Meta.PreferredVisualization))
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, "b2", frame.Fields[0].At(0))
+		require.Equal(t, "db", frame.Fields[1].At(0))
+		require.Nil(t, frame.Fields[3].At(0))
+		require.Equal(t, "a1", frame.Fields[0].At(1))
+		require.Equal(t, "app GET /", frame.Fields[1].At(1))
+		require.Equal(t, time.Unix(1500, 0).UTC(), *frame.Fields[2].At(1).(*time.Time))
+		require.Equal(t, 10.0, *frame.Fields[3].At(1).(*float64))
+	})
+
+	t.Run("traceql queries of a trace ID get the trace", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/traces/abc123", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		res := queryTempo(t, &datasourceInfo{HTTPClient: srv.Client(), URL: srv.URL}, nil, timeRange, `{"queryType":"traceql","query":" abc123 "}`)
+		require.ErrorContains(t, res.Error, "failed to get trace with id: abc123")
+	})
+
+	t.Run("service map queries build the node graph from the span metrics", func(t *testing.T) {
+		querier := &fakeQuerier{values: map[string]map[string]float64{
+			totalsMetric:  {"app_db": 2, "user_app": 4},
+			secondsMetric: {"app_db": 0.1, "user_app": 0.4},
+			failedMetric:  {"user_app": 1},
+		}}
+		dsInfo := &datasourceInfo{ServiceMapDatasourceUID: "prom"}
+
+		res := queryTempo(t, dsInfo, querier, timeRange, `{"queryType":"serviceMap","serviceMapQuery":"{client=\"app\"}"}`)
+		require.NoError(t, res.Error)
+		require.Equal(t, "prom", querier.uid)
+		require.Equal(t, timeRange, querier.timeRange)
+		require.Equal(t, `rate(traces_service_graph_request_total{client="app"}[$__range])`, querier.exprs[0])
+
+		require.Len(t, res.Frames, 2)
+		nodes, edges := res.Frames[0], res.Frames[1]
+		require.Equal(t, "Nodes", nodes.Name)
+		require.Equal(t, data.VisTypeNodeGraph, string(nodes.Meta.PreferredVisualization))
+		require.Equal(t, 3, nodes.Rows())
+		require.Equal(t, []interface{}{"db", "db", 50.0, 2.0, 1.0, 0.0}, nodes.RowCopy(0))
+		require.Equal(t, "app", nodes.Fields[0].At(1))
+		require.Equal(t, 100.0, nodes.Fields[2].At(1))
+		require.Equal(t, 0.75, nodes.Fields[4].At(1))
+		require.Equal(t, 0.25, nodes.Fields[5].At(1))
+		require.Equal(t, "user", nodes.Fields[0].At(2))
+		require.True(t, math.IsNaN(nodes.Fields[2].At(2).(float64)))
+
+		require.Equal(t, 2, edges.Rows())
+		require.Equal(t, []interface{}{"app_db", "app", "db", 50.0, 2.0}, edges.RowCopy(0))
+		require.Equal(t, []interface{}{"user_app", "user", "app", 100.0, 4.0}, edges.RowCopy(1))
+	})
+
+	t.Run("service map queries fail without a span metrics data source", func(t *testing.T) {
+		res := queryTempo(t, &datasourceInfo{}, &fakeQuerier{}, timeRange, `{"queryType":"serviceMap"}`)
+		require.EqualError(t, res.Error, "no service graph data source is configured")
+	})
+
+	t.Run("service map queries only accept label matchers", func(t *testing.T) {
+		dsInfo := &datasourceInfo{ServiceMapDatasourceUID: "prom"}
+
+		for _, serviceMapQuery := range []string{
+			`client=\"app\"`,
+			`{client=\"app\"}[1h])) or vector(1) or (rate(up`,
+			`{client=\"app\"} or up{job=\"a\"}`,
+			`{__name__=\"up\"}`,
+		} {
+			querier := &fakeQuerier{}
+			res := queryTempo(t, dsInfo, querier, timeRange, `{"queryType":"serviceMap","serviceMapQuery":"`+serviceMapQuery+`"}`)
+			require.Error(t, res.Error, serviceMapQuery)
+			require.Empty(t, querier.exprs)
+		}
+
+		querier := &fakeQuerier{}
+		res := queryTempo(t, dsInfo, querier, timeRange, `{"queryType":"serviceMap","serviceMapQuery":" {client=~\"app|db\", server!=\"\"} "}`)
+		require.NoError(t, res.Error)
+		require.Equal(t, `rate(traces_service_graph_request_total{client=~"app|db",server!=""}[$__range])`, querier.exprs[0])
+	})
+
+	t.Run("unsupported query types fail", func(t *testing.T) {
+		res := queryTempo(t, &datasourceInfo{}, nil, timeRange, `{"queryType":"upload"}`)
+		require.EqualError(t, res.Error, "unsupported query type: upload")
+	})
+}
+
+func queryTempo(t *testing.T, dsInfo *datasourceInfo, querier DataSourceQuerier, timeRange backend.TimeRange, model string) backend.DataResponse {
+	t.Helper()
+
+	if querier == nil {
+		querier = &fakeQuerier{}
+	}
+	service := &Service{
+		tlog:    log.New("tempo-test"),
+		im:      fakeInstanceManager{dsInfo: dsInfo},
+		querier: querier,
+	}
+	res, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+		Queries: []backend.DataQuery{{RefID: "A", TimeRange: timeRange, JSON: []byte(model)}},
+	})
+	require.NoError(t, err)
+	return res.Responses["A"]
+}
+
+type fakeInstanceManager struct {
+	dsInfo *datasourceInfo
+}
+
+func (m fakeInstanceManager) Get(_ backend.PluginContext) (instancemgmt.Instance, error) {
+	return m.dsInfo, nil
+}
+
+func (m fakeInstanceManager) Do(_ backend.PluginContext, _ instancemgmt.InstanceCallbackFunc) error {
+	return nil
+}
+
+// fakeQuerier returns a series per edge with the given value for each metric, keyed by client and server.
+type fakeQuerier struct {
+	values map[string]map[string]float64
+
+	uid       string
+	timeRange backend.TimeRange
+	exprs     []string
+}
+
+func (q *fakeQuerier) QueryDataSource(_ context.Context, _ backend.PluginContext, uid string, timeRange backend.TimeRange, queries []*simplejson.Json) (*backend.QueryDataResponse, error) {
+	q.uid = uid
+	q.timeRange = timeRange
+	res := backend.NewQueryDataResponse()
+	for _, query := range queries {
+		q.exprs = append(q.exprs, query.Get("expr").MustString())
+		refID := query.Get("refId").MustString()
+
+		edges := make([]string, 0, len(q.values[refID]))
+		for edge := range q.values[refID] {
+			edges = append(edges, edge)
+		}
+		sort.Strings(edges)
+
+		var frames data.Frames
+		for _, edge := range edges {
+			clientServer := strings.Split(edge, "_")
+			frames = append(frames, data.NewFrame("",
+				data.NewField("Time", nil, []time.Time{timeRange.To}),
+				data.NewField("Value", data.Labels{"client": clientServer[0], "server": clientServer[1]}, []float64{q.values[refID][edge]}),
+			))
+		}
+		res.Responses[refID] = backend.DataResponse{Frames: frames}
+	}
+	return res, nil
+}
@@ -0,0 +1,283 @@
+package testdatasource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const (
+	maxAlertingScriptPoints  = 10000
+	maxHighCardinalitySeries = 100000
+	maxHighCardinalityValues = 1000000
+)
+
+type alertingScriptQueryWrapper struct {
+	AlertingScript alertingScriptQuery `json:"alertingScript"`
+}
+
+// alertingScriptQuery describes a series which changes its value in windows that repeat over time, for example
+// "above 80 for 5m every hour". Windows are aligned to the Unix epoch in UTC, so a query returns the same values for
+// the same time whenever it's evaluated.
+type alertingScriptQuery struct {
+	// TimeStep is the time between points in seconds, 60 by default.
+	TimeStep int64 `json:"timeStep"`
+	// Value is the value of the series outside of windows.
+	Value float64 `json:"value"`
+	// Noise is the maximum deviation added to the values, derived from the seed and the time of the point.
+	Noise float64 `json:"noise"`
+	Seed  int64   `json:"seed"`
+	// Windows are applied in order, so later windows take precedence over earlier ones when they overlap.
+	Windows []alertingScriptWindow `json:"windows"`
+}
+
+type alertingScriptWindow struct {
+	// Every is how often the window repeats, for example 1h or 24h.
+	Every string `json:"every"`
+	// Offset is the start of the window within each period, for example 2h for a daily window starting at 02:00 UTC.
+	Offset string `json:"offset"`
+	// Duration is how long the window lasts, for example 5m.
+	Duration string `json:"duration"`
+	// Value is the value of the series within the window.
+	Value *float64 `json:"value"`
+	// NoData drops the points within the window.
+	NoData bool `json:"noData"`
+
+	every, offset, duration time.Duration
+}
+
+// contains returns true if t is within the window.
+func (w alertingScriptWindow) contains(t time.Time) bool {
+	elapsed := (time.Duration(t.UnixNano()) - w.offset) % w.every
+	if elapsed < 0 {
+		elapsed += w.every
+	}
+	return elapsed < w.duration
+}
+
+func (s *Service) handleAlertingScriptScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		wrapper := &alertingScriptQueryWrapper{}
+		if err := json.Unmarshal(q.JSON, wrapper); err != nil {
+			return nil, fmt.Errorf("failed to parse query json: %v", err)
+		}
+		model, err := simplejson.NewJson(q.JSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query json: %v", err)
+		}
+
+		respD := resp.Responses[q.RefID]
+		frame, err := alertingScript(q, model, wrapper.AlertingScript)
+		if err != nil {
+			respD.Error = err
+		} else {
+			respD.Frames = append(respD.Frames, frame)
+		}
+		resp.Responses[q.RefID] = respD
+	}
+
+	return resp, nil
+}
+
+func alertingScript(query backend.DataQuery, model *simplejson.Json, script alertingScriptQuery) (*data.Frame, error) {
+	if script.TimeStep <= 0 {
+		script.TimeStep = 60
+	}
+	for i := range script.Windows {
+		w := &script.Windows[i]
+		var err error
+		if w.every, err = parseScriptDuration("every", w.Every); err != nil {
+			return nil, err
+		}
+		if w.duration, err = parseScriptDuration("duration", w.Duration); err != nil {
+			return nil, err
+		}
+		if w.Offset != "" {
+			if w.offset, err = time.ParseDuration(w.Offset); err != nil {
+				return nil, fmt.Errorf("failed to parse window offset '%s': %v", w.Offset, err)
+			}
+		}
+		if w.Value == nil && !w.NoData {
+			return nil, fmt.Errorf("window %d must have a value or no data", i+1)
+		}
+	}
+
+	timeStep := time.Duration(script.TimeStep) * time.Second
+	t := query.TimeRange.From.Truncate(timeStep)
+	count := pointCount(t, query.TimeRange.To, timeStep)
+	if count > maxAlertingScriptPoints {
+		return nil, fmt.Errorf("too many points, increase the time step or reduce the time range to return at most %d points", maxAlertingScriptPoints)
+	}
+
+	timeVec := make([]time.Time, 0, count)
+	floatVec := make([]float64, 0, count)
+	for ; !t.After(query.TimeRange.To); t = t.Add(timeStep) {
+		value, ok := script.Value, true
+		for _, w := range script.Windows {
+			if !w.contains(t) {
+				continue
+			}
+			if w.NoData {
+				ok = false
+			} else {
+				value, ok = *w.Value, true
+			}
+		}
+		if ok {
+			timeVec = append(timeVec, t)
+			floatVec = append(floatVec, value+script.Noise*seededNoise(script.Seed, 0, t))
+		}
+	}
+
+	frame := newSeriesForQuery(query, model, 0)
+	frame.Fields = data.Fields{
+		data.NewField(data.TimeSeriesTimeFieldName, nil, timeVec),
+		data.NewField(data.TimeSeriesValueFieldName, parseLabels(model), floatVec),
+	}
+	return frame, nil
+}
+
+func parseScriptDuration(name, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse window %s '%s': %v", name, value, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("window %s must be positive", name)
+	}
+	return d, nil
+}
+
+type highCardinalityQueryWrapper struct {
+	HighCardinality highCardinalityQuery `json:"highCardinality"`
+}
+
+// highCardinalityQuery describes many series with generated label values, to load test alert rules.
+type highCardinalityQuery struct {
+	SeriesCount int `json:"seriesCount"`
+	// Labels are the labels of the series with the number of distinct values of each label. Series get the
+	// combinations of the label values in order, so labels with a cardinality lower than the series count repeat.
+	Labels []highCardinalityLabel `json:"labels"`
+	// TimeStep is the time between points in seconds, 60 by default.
+	TimeStep int64 `json:"timeStep"`
+	// Min and Max are the range of the values, which are derived from the seed, the series and the time of a point.
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Seed int64   `json:"seed"`
+}
+
+type highCardinalityLabel struct {
+	Name        string `json:"name"`
+	Cardinality int    `json:"cardinality"`
+}
+
+func (s *Service) handleHighCardinalityScenario(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+
+	for _, q := range req.Queries {
+		wrapper := &highCardinalityQueryWrapper{}
+		if err := json.Unmarshal(q.JSON, wrapper); err != nil {
+			return nil, fmt.Errorf("failed to parse query json: %v", err)
+		}
+
+		respD := resp.Responses[q.RefID]
+		frames, err := highCardinalitySeries(q, wrapper.HighCardinality)
+		if err != nil {
+			respD.Error = err
+		} else {
+			respD.Frames = append(respD.Frames, frames...)
+		}
+		resp.Responses[q.RefID] = respD
+	}
+
+	return resp, nil
+}
+
+func highCardinalitySeries(query backend.DataQuery, hc highCardinalityQuery) (data.Frames, error) {
+	if hc.SeriesCount <= 0 {
+		hc.SeriesCount = 100
+	}
+	if hc.SeriesCount > maxHighCardinalitySeries {
+		return nil, fmt.Errorf("series count must be at most %d", maxHighCardinalitySeries)
+	}
+	if len(hc.Labels) == 0 {
+		hc.Labels = []highCardinalityLabel{{Name: "instance", Cardinality: hc.SeriesCount}}
+	}
+	for _, l := range hc.Labels {
+		if l.Name == "" || l.Cardinality <= 0 {
+			return nil, fmt.Errorf("labels must have a name and a positive cardinality")
+		}
+	}
+	if hc.TimeStep <= 0 {
+		hc.TimeStep = 60
+	}
+	if hc.Max <= hc.Min {
+		hc.Min, hc.Max = 0, 100
+	}
+
+	timeStep := time.Duration(hc.TimeStep) * time.Second
+	start := query.TimeRange.From.Truncate(timeStep)
+	count := pointCount(start, query.TimeRange.To, timeStep)
+	if query.MaxDataPoints > 0 && count > query.MaxDataPoints {
+		// keep the latest points
+		start = start.Add(time.Duration(count-query.MaxDataPoints) * timeStep)
+		count = query.MaxDataPoints
+	}
+	if count > maxHighCardinalityValues/int64(hc.SeriesCount) {
+		return nil, fmt.Errorf("too many values, reduce the series count or the time range to return at most %d values", maxHighCardinalityValues)
+	}
+
+	times := make([]time.Time, count)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * timeStep)
+	}
+
+	frames := make(data.Frames, 0, hc.SeriesCount)
+	for i := 0; i < hc.SeriesCount; i++ {
+		labels := make(data.Labels, len(hc.Labels))
+		n := i
+		for _, l := range hc.Labels {
+			labels[l.Name] = l.Name + "-" + strconv.Itoa(n%l.Cardinality)
+			n /= l.Cardinality
+		}
+
+		values := make([]float64, len(times))
+		for j, t := range times {
+			values[j] = hc.Min + (hc.Max-hc.Min)*(seededNoise(hc.Seed, i, t)+1)/2
+		}
+		frames = append(frames, data.NewFrame("",
+			data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+			data.NewField(data.TimeSeriesValueFieldName, labels, values),
+		))
+	}
+	return frames, nil
+}
+
+// pointCount returns the number of points between from and to, both included, without building them.
+func pointCount(from, to time.Time, step time.Duration) int64 {
+	if from.After(to) {
+		return 0
+	}
+	return int64(to.Sub(from)/step) + 1
+}
+
+// seededNoise returns a value between -1 and 1 which only depends on the seed, the series and the time, so that
+// alert rules evaluating the same time get the same value.
+func seededNoise(seed int64, series int, t time.Time) float64 {
+	x := uint64(seed) ^ uint64(series)*0x9e3779b97f4a7c15 ^ uint64(t.UnixNano())
+	// splitmix64
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x)/math.MaxUint64*2 - 1
+}
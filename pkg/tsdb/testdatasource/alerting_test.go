@@ -0,0 +1,167 @@
+package testdatasource
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertingScriptScenario(t *testing.T) {
+	s := &Service{}
+	from := time.Date(2022, 1, 1, 1, 50, 0, 0, time.UTC)
+	query := func(model string) backend.DataResponse {
+		t.Helper()
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: from.Add(20 * time.Minute)},
+				JSON:      []byte(model),
+			}},
+		}
+		resp, err := s.handleAlertingScriptScenario(context.Background(), req)
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("windows change the value and drop points", func(t *testing.T) {
+		res := query(`{"labels": "job=foo", "alertingScript": {"value": 10, "windows": [
+			{"every": "1h", "duration": "5m", "value": 90},
+			{"every": "24h", "offset": "2h", "duration": "2m", "noData": true}
+		]}}`)
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 1)
+
+		frame := res.Frames[0]
+		require.Equal(t, "A-series", frame.Name)
+		require.Equal(t, data.Labels{"job": "foo"}, frame.Fields[1].Labels)
+
+		values := map[time.Time]float64{}
+		for i := 0; i < frame.Rows(); i++ {
+			values[frame.Fields[0].At(i).(time.Time)] = frame.Fields[1].At(i).(float64)
+		}
+		require.Len(t, values, 19)
+		require.Equal(t, 10.0, values[from])
+		require.NotContains(t, values, from.Add(10*time.Minute))
+		require.NotContains(t, values, from.Add(11*time.Minute))
+		require.Equal(t, 90.0, values[from.Add(12*time.Minute)])
+		require.Equal(t, 90.0, values[from.Add(14*time.Minute)])
+		require.Equal(t, 10.0, values[from.Add(15*time.Minute)])
+	})
+
+	t.Run("noise depends on the seed and time only", func(t *testing.T) {
+		model := `{"alertingScript": {"value": 50, "noise": 5, "seed": 42}}`
+		first, second := query(model), query(model)
+		require.Equal(t, first.Frames[0].Fields[1], second.Frames[0].Fields[1])
+
+		for i := 0; i < first.Frames[0].Rows(); i++ {
+			require.InDelta(t, 50, first.Frames[0].Fields[1].At(i).(float64), 5)
+		}
+		require.NotEqual(t, first.Frames[0].Fields[1], query(`{"alertingScript": {"value": 50, "noise": 5, "seed": 43}}`).Frames[0].Fields[1])
+	})
+
+	t.Run("invalid windows return an error", func(t *testing.T) {
+		res := query(`{"alertingScript": {"windows": [{"every": "1h", "duration": "5m"}]}}`)
+		require.EqualError(t, res.Error, "window 1 must have a value or no data")
+
+		res = query(`{"alertingScript": {"windows": [{"every": "0s", "duration": "5m", "value": 1}]}}`)
+		require.EqualError(t, res.Error, "window every must be positive")
+	})
+
+	t.Run("too many points return an error", func(t *testing.T) {
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: from.Add(24 * time.Hour)},
+				JSON:      []byte(`{"alertingScript": {"timeStep": 1}}`),
+			}},
+		}
+		resp, err := s.handleAlertingScriptScenario(context.Background(), req)
+		require.NoError(t, err)
+		require.EqualError(t, resp.Responses["A"].Error, "too many points, increase the time step or reduce the time range to return at most 10000 points")
+	})
+}
+
+func TestHighCardinalityScenario(t *testing.T) {
+	s := &Service{}
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := func(model string) backend.DataResponse {
+		t.Helper()
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: from, To: from.Add(time.Hour)},
+				JSON:      []byte(model),
+			}},
+		}
+		resp, err := s.handleHighCardinalityScenario(context.Background(), req)
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("series get the combinations of the label values", func(t *testing.T) {
+		res := query(`{"highCardinality": {"seriesCount": 6, "min": 10, "max": 20, "labels": [
+			{"name": "instance", "cardinality": 3},
+			{"name": "job", "cardinality": 2}
+		]}}`)
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 6)
+
+		seen := map[string]bool{}
+		for _, frame := range res.Frames {
+			require.Equal(t, 61, frame.Rows())
+			seen[frame.Fields[1].Labels.String()] = true
+			for i := 0; i < frame.Rows(); i++ {
+				v := frame.Fields[1].At(i).(float64)
+				require.True(t, v >= 10 && v <= 20)
+			}
+		}
+		require.Len(t, seen, 6)
+		require.Equal(t, data.Labels{"instance": "instance-2", "job": "job-1"}, res.Frames[5].Fields[1].Labels)
+	})
+
+	t.Run("default labels", func(t *testing.T) {
+		res := query(`{"highCardinality": {}}`)
+		require.NoError(t, res.Error)
+		require.Len(t, res.Frames, 100)
+		require.Equal(t, data.Labels{"instance": "instance-99"}, res.Frames[99].Fields[1].Labels)
+	})
+
+	t.Run("too many values return an error", func(t *testing.T) {
+		res := query(`{"highCardinality": {"seriesCount": 100000, "timeStep": 1}}`)
+		require.Error(t, res.Error)
+
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				TimeRange: backend.TimeRange{From: time.Unix(0, 0), To: from},
+				JSON:      []byte(`{"highCardinality": {"seriesCount": 1, "timeStep": 1}}`),
+			}},
+		}
+		resp, err := s.handleHighCardinalityScenario(context.Background(), req)
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+	})
+
+	t.Run("max data points keep the latest points", func(t *testing.T) {
+		req := &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:         "A",
+				TimeRange:     backend.TimeRange{From: time.Unix(0, 0), To: from},
+				MaxDataPoints: 10,
+				JSON:          []byte(`{"highCardinality": {"seriesCount": 1, "timeStep": 1}}`),
+			}},
+		}
+		resp, err := s.handleHighCardinalityScenario(context.Background(), req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Responses["A"].Error)
+
+		frame := resp.Responses["A"].Frames[0]
+		require.Equal(t, 10, frame.Rows())
+		require.Equal(t, from.Add(-9*time.Second), frame.Fields[0].At(0).(time.Time).UTC())
+		require.Equal(t, from, frame.Fields[0].At(9).(time.Time).UTC())
+	})
+}
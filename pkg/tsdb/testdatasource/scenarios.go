@@ -44,6 +44,8 @@ const (
 	csvFileQueryType                  queryType = "csv_file"
 	csvContentQueryType               queryType = "csv_content"
 	traceType                         queryType = "trace"
+	alertingScriptQueryType           queryType = "alerting_script"
+	highCardinalityQueryType          queryType = "high_cardinality"
 )
 
 type queryType string
@@ -224,6 +226,21 @@ Timestamps will line up evenly on timeStepSeconds (For example, 60 seconds means
 		Name: "Trace",
 	})
 
+	s.registerScenario(&Scenario{
+		ID:      string(alertingScriptQueryType),
+		Name:    "Alerting Script",
+		handler: s.handleAlertingScriptScenario,
+		Description: `Alerting Script returns a series which changes its value in windows that repeat over time, such as above 80 for 5m every hour.
+Windows are aligned to the epoch in UTC and the noise depends on the seed and the time of each point, so alert rules get the same values whenever they are evaluated.`,
+	})
+
+	s.registerScenario(&Scenario{
+		ID:          string(highCardinalityQueryType),
+		Name:        "High Cardinality Series",
+		handler:     s.handleHighCardinalityScenario,
+		Description: "High Cardinality Series returns many series with generated label values, to load test alert rules.",
+	})
+
 	s.queryMux.HandleFunc("", s.handleFallbackScenario)
 }
 
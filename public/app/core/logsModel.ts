@@ -415,7 +415,7 @@ export function logSeriesToLogsModel(logSeries: DataFrame[], queries: DataQuery[
 
       const hasUnescapedContent = !!message.match(/\\n|\\t|\\r/);
 
-      const searchWords = series.meta && series.meta.searchWords ? series.meta.searchWords : [];
+      const searchWords = series.meta?.searchWords ?? series.meta?.custom?.searchWords ?? [];
       const entry = hasAnsi ? ansicolor.strip(message) : message;
 
       const labels = getLabelsForFrameRow(info, j);
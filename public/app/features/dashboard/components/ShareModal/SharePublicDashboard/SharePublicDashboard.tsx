@@ -29,7 +29,7 @@ import { AcknowledgeCheckboxes } from 'app/features/dashboard/components/ShareMo
 import { Configuration } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/Configuration';
 import { Description } from 'app/features/dashboard/components/ShareModal/SharePublicDashboard/Description';
 import {
-  dashboardHasTemplateVariables,
+  getUnsupportedTemplateVariables,
   generatePublicDashboardUrl,
   getUnsupportedDashboardDatasources,
   publicDashboardPersisted,
@@ -63,7 +63,7 @@ export const SharePublicDashboard = (props: Props) => {
   const { showModal, hideModal } = useContext(ModalsContext);
   const isDesktop = useIsDesktop();
 
-  const dashboardVariables = props.dashboard.getVariables();
+  const unsupportedTemplateVariables = getUnsupportedTemplateVariables(props.dashboard.getVariables());
   const selectors = e2eSelectors.pages.ShareDashboardModal.PublicDashboard;
   const { hasPublicDashboard } = props.dashboard.meta;
 
@@ -200,13 +200,14 @@ export const SharePublicDashboard = (props: Props) => {
             </div>
           </Alert>
         ) : null}
-        {dashboardHasTemplateVariables(dashboardVariables) && !publicDashboardPersisted(publicDashboard) ? (
+        {unsupportedTemplateVariables.length > 0 && !publicDashboardPersisted(publicDashboard) ? (
           <Alert
             severity="warning"
             title="dashboard cannot be public"
             data-testid={selectors.TemplateVariablesWarningAlert}
           >
-            This dashboard cannot be made public because it has template variables
+            This dashboard cannot be made public because it has template variables that public dashboards don&apos;t
+            support: {unsupportedTemplateVariables.join(', ')}
           </Alert>
         ) : (
           <form onSubmit={handleSubmit(onSavePublicConfig)}>
@@ -251,9 +252,9 @@ export const SharePublicDashboard = (props: Props) => {
                   severity="warning"
                 />
               ) : (
-                dashboardHasTemplateVariables(dashboardVariables) && (
+                unsupportedTemplateVariables.length > 0 && (
                   <Alert
-                    title="This public dashboard may not work since it uses template variables"
+                    title="This public dashboard may not work since it uses unsupported template variables"
                     severity="warning"
                   />
                 )
@@ -5,22 +5,36 @@ import { VariableModel } from 'app/features/variables/types';
 
 import {
   PublicDashboard,
-  dashboardHasTemplateVariables,
+  getUnsupportedTemplateVariables,
   publicDashboardPersisted,
   generatePublicDashboardUrl,
   getUnsupportedDashboardDatasources,
 } from './SharePublicDashboardUtils';
 
-describe('dashboardHasTemplateVariables', () => {
-  it('false', () => {
+describe('getUnsupportedTemplateVariables', () => {
+  it('returns nothing when there are no variables', () => {
     let variables: VariableModel[] = [];
-    expect(dashboardHasTemplateVariables(variables)).toBe(false);
+    expect(getUnsupportedTemplateVariables(variables)).toEqual([]);
   });
 
-  it('true', () => {
+  it('returns variables with unsupported types', () => {
+    //@ts-ignore
+    let variables: VariableModel[] = [
+      { name: 'env', type: 'custom' },
+      { name: 'ds', type: 'datasource' },
+      { name: 'filters', type: 'adhoc' },
+    ];
+    expect(getUnsupportedTemplateVariables(variables)).toEqual(['ds', 'filters']);
+  });
+
+  it('returns query variables without saved options or data source query', () => {
     //@ts-ignore
-    let variables: VariableModel[] = ['a'];
-    expect(dashboardHasTemplateVariables(variables)).toBe(true);
+    let variables: VariableModel[] = [
+      { name: 'host', type: 'query', refresh: 0, query: 'label_values(host)' },
+      { name: 'pod', type: 'query', refresh: 1, query: { refId: 'A', rawSql: 'SELECT pod FROM pods' } },
+      { name: 'job', type: 'query', refresh: 1, query: 'label_values(job)' },
+    ];
+    expect(getUnsupportedTemplateVariables(variables)).toEqual(['job']);
   });
 });
 
@@ -1,5 +1,5 @@
 import { getConfig } from 'app/core/config';
-import { VariableModel } from 'app/features/variables/types';
+import { QueryVariableModel, VariableModel, VariableRefresh } from 'app/features/variables/types';
 import { DashboardDataDTO, DashboardMeta } from 'app/types/dashboard';
 
 import { PanelModel } from '../../../state';
@@ -14,6 +14,9 @@ export interface PublicDashboard {
   dashboardUid: string;
   timeSettings?: object;
   timeSelectionEnabled: boolean;
+  share?: 'public' | 'email';
+  expiresAt?: string | null;
+  rateLimit?: number;
 }
 
 export interface DashboardResponse {
@@ -21,9 +24,28 @@ export interface DashboardResponse {
   meta: DashboardMeta;
 }
 
+// Template variables with options that can be resolved by the server, so public dashboard viewers can only select
+// values the dashboard allows.
+const supportedTemplateVariableTypes = ['constant', 'custom', 'interval', 'query'];
+
 // Instance methods
-export const dashboardHasTemplateVariables = (variables: VariableModel[]): boolean => {
-  return variables.length > 0;
+/**
+ * Get the names of the template variables that public dashboards don't support. Query variables are supported when
+ * their options are saved with the dashboard or when their query is a data source query.
+ */
+export const getUnsupportedTemplateVariables = (variables: VariableModel[]): string[] => {
+  return variables
+    .filter((variable) => {
+      if (!supportedTemplateVariableTypes.includes(variable.type)) {
+        return true;
+      }
+      if (variable.type === 'query') {
+        const { refresh, query } = variable as QueryVariableModel;
+        return refresh !== VariableRefresh.never && typeof query !== 'object';
+      }
+      return false;
+    })
+    .map((variable) => variable.name);
 };
 
 export const publicDashboardPersisted = (publicDashboard?: PublicDashboard): boolean => {
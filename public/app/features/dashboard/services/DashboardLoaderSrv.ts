@@ -51,7 +51,13 @@ export class DashboardLoaderSrv {
         .then((result: any) => {
           return result;
         })
-        .catch(() => {
+        .catch((err) => {
+          if (err?.status === 401) {
+            return this._dashboardLoadFailed(
+              'This dashboard is only available to invited viewers. Open the link sent to your email to view it',
+              true
+            );
+          }
           return this._dashboardLoadFailed('Public Dashboard Not found', true);
         });
     } else {
@@ -23,6 +23,12 @@ const backendSrv = {
 jest.mock('@grafana/runtime', () => ({
   ...jest.requireActual('@grafana/runtime'),
   getBackendSrv: () => backendSrv,
+  getTemplateSrv: () => ({
+    getVariables: () => [
+      { name: 'env', type: 'custom', current: { value: 'prod' } },
+      { name: 'host', type: 'query', current: { value: ['a', 'b'] } },
+    ],
+  }),
   getDataSourceSrv: () => {
     return {
       getInstanceSettings: (ref?: DataSourceRef) => ({ type: ref?.type ?? '?', uid: ref?.uid ?? '?' }),
@@ -132,6 +138,7 @@ describe('PublicDashboardDatasource', () => {
     expect(mock.lastCall[0].url).toEqual(
       `/api/public/dashboards/${publicDashboardAccessToken}/panels/${panelId}/query`
     );
+    expect(mock.lastCall[0].data.variables).toEqual({ env: ['prod'], host: ['a', 'b'] });
   });
 
   test('returns public datasource uid when datasource passed in is null', () => {
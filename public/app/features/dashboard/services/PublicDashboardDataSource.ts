@@ -11,7 +11,7 @@ import {
   DataSourceRef,
   toDataFrame,
 } from '@grafana/data';
-import { BackendDataSourceResponse, getBackendSrv, toDataQueryResponse } from '@grafana/runtime';
+import { BackendDataSourceResponse, getBackendSrv, getTemplateSrv, toDataQueryResponse } from '@grafana/runtime';
 
 import { GrafanaQueryType } from '../../../plugins/datasource/grafana/types';
 import { MIXED_DATASOURCE_NAME } from '../../../plugins/datasource/mixed/MixedDataSource';
@@ -76,6 +76,22 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
     return interval ?? DEFAULT_INTERVAL;
   }
 
+  /**
+   * Get the selected values of the dashboard template variables. The server only uses values the dashboard allows.
+   */
+  private static getVariableValues(): Record<string, string[]> {
+    const values: Record<string, string[]> = {};
+
+    for (const variable of getTemplateSrv().getVariables()) {
+      const value = 'current' in variable ? variable.current?.value : undefined;
+      if (value !== undefined) {
+        values[variable.name] = Array.isArray(value) ? value : [value];
+      }
+    }
+
+    return values;
+  }
+
   /**
    * Ideally final -- any other implementation may not work as expected
    */
@@ -111,6 +127,7 @@ export class PublicDashboardDataSource extends DataSourceApi<DataQuery, DataSour
         intervalMs,
         maxDataPoints,
         timeRange: { from: fromRange.valueOf().toString(), to: toRange.valueOf().toString() },
+        variables: PublicDashboardDataSource.getVariableValues(),
       };
 
       return getBackendSrv()
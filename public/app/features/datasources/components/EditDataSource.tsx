@@ -36,6 +36,7 @@ import { DataSourcePluginSettings } from './DataSourcePluginSettings';
 import { DataSourcePluginState } from './DataSourcePluginState';
 import { DataSourceReadOnlyMessage } from './DataSourceReadOnlyMessage';
 import { DataSourceTestingStatus } from './DataSourceTestingStatus';
+import { QueryCachingSettings } from './QueryCachingSettings';
 
 export type Props = {
   // The ID of the data source
@@ -172,6 +173,8 @@ export function EditDataSourceView({
         </DataSourcePluginContextProvider>
       )}
 
+      {dataSourceMeta.backend && <QueryCachingSettings dataSource={dataSource} onOptionsChange={onOptionsChange} />}
+
       <DataSourceTestingStatus testingStatus={testingStatus} />
 
       <ButtonRow
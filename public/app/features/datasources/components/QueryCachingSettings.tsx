@@ -0,0 +1,54 @@
+import React from 'react';
+
+import { DataSourceSettings } from '@grafana/data';
+import { InlineField, InlineSwitch, Input } from '@grafana/ui';
+
+export interface Props {
+  dataSource: DataSourceSettings;
+  onOptionsChange: (dataSource: DataSourceSettings) => void;
+}
+
+export function QueryCachingSettings({ dataSource, onOptionsChange }: Props) {
+  const { queryCachingEnabled, queryCachingTTL } = dataSource.jsonData as {
+    queryCachingEnabled?: boolean;
+    queryCachingTTL?: string;
+  };
+
+  const onJsonDataChange = (changes: Record<string, unknown>) => {
+    onOptionsChange({ ...dataSource, jsonData: { ...dataSource.jsonData, ...changes } });
+  };
+
+  return (
+    <div className="gf-form-group">
+      <h3 className="page-heading">Query caching</h3>
+      <InlineField
+        label="Cache query results"
+        labelWidth={24}
+        tooltip="Identical queries are served from the remote cache instead of querying the data source again."
+      >
+        <InlineSwitch
+          id="query-caching-enabled"
+          value={queryCachingEnabled ?? false}
+          onChange={(event: React.FormEvent<HTMLInputElement>) =>
+            onJsonDataChange({ queryCachingEnabled: event.currentTarget.checked })
+          }
+        />
+      </InlineField>
+      {queryCachingEnabled && (
+        <InlineField
+          label="Cache TTL"
+          labelWidth={24}
+          tooltip="How long query results are cached, for example 5m. Leave empty to use the server default."
+        >
+          <Input
+            id="query-caching-ttl"
+            width={16}
+            placeholder="1m"
+            value={queryCachingTTL ?? ''}
+            onChange={(event) => onJsonDataChange({ queryCachingTTL: event.currentTarget.value })}
+          />
+        </InlineField>
+      )}
+    </div>
+  );
+}
@@ -82,6 +82,11 @@ export async function loadDashboards(items: PlaylistItem[]): Promise<PlaylistIte
       case 'dashboard_by_tag':
         query.tags = [item.value];
         break;
+
+      case 'dashboard_by_search':
+        // saved search query, e.g. a folder or a sorted tag query
+        Object.assign(query, JSON.parse(item.value), { kind: ['dashboard'] });
+        break;
     }
     targets.push({
       refId: `${idx++}`,
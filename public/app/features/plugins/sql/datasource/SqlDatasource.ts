@@ -1,13 +1,16 @@
-import { lastValueFrom } from 'rxjs';
+import { lastValueFrom, Observable } from 'rxjs';
 import { map } from 'rxjs/operators';
 
 import {
   DataFrame,
   DataFrameView,
   DataQuery,
+  DataQueryRequest,
+  DataQueryResponse,
   DataSourceInstanceSettings,
   DataSourceRef,
   MetricFindValue,
+  getTimeZoneInfo,
   ScopedVars,
   TimeRange,
 } from '@grafana/data';
@@ -99,6 +102,12 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
     return expandedQueries;
   }
 
+  query(request: DataQueryRequest<SQLQuery>): Observable<DataQueryResponse> {
+    // Time group macros bucket by the wall clock of the dashboard timezone
+    const timezone = getTimeZoneInfo(request.timezone, request.range.to.valueOf())?.ianaName;
+    return super.query({ ...request, targets: request.targets.map((target) => ({ ...target, timezone })) });
+  }
+
   filterQuery(query: SQLQuery): boolean {
     return !query.hide;
   }
@@ -112,6 +121,7 @@ export abstract class SqlDatasource extends DataSourceWithBackend<SQLQuery, SQLO
       datasource: this.getRef(),
       rawSql: this.templateSrv.replace(target.rawSql, scopedVars, this.interpolateVariable),
       format: target.format,
+      timezone: target.timezone,
     };
   }
 
@@ -58,6 +58,7 @@ export interface SQLQuery extends DataQuery {
   sql?: SQLExpression;
   editorMode?: EditorMode;
   rawQuery?: boolean;
+  timezone?: string;
 }
 
 export interface NameValue {
@@ -90,6 +90,15 @@ export class BlugeSearcher implements GrafanaSearcher {
         opts.push({ value: `-${sf.name}`, label: `${sf.display} (recent)` });
         opts.push({ value: `${sf.name}`, label: `${sf.display} (oldest)` });
       }
+    } else {
+      for (const sf of usageSortFields) {
+        opts.push({ value: `-${sf.name}`, label: `${sf.display} (most)` });
+        opts.push({ value: `${sf.name}`, label: `${sf.display} (least)` });
+      }
+      for (const sf of usageSortTimeFields) {
+        opts.push({ value: `-${sf.name}`, label: `${sf.display} (recent)` });
+        opts.push({ value: `${sf.name}`, label: `${sf.display} (oldest)` });
+      }
     }
 
     return Promise.resolve(opts);
@@ -234,14 +243,22 @@ const sortTimeFields = [
   { name: 'updated_at', display: 'Updated time' },
 ];
 
+// Sort field values for dashboards, based on the dashboard usage recorded by the server
+const usageSortFields = [
+  { name: 'usage_views_last_30_days', display: 'Views 30 days' },
+  { name: 'usage_queries_last_30_days', display: 'Queries 30 days' },
+];
+
+const usageSortTimeFields = [{ name: 'usage_last_viewed', display: 'Last viewed' }];
+
 /** Given the internal field name, this gives a reasonable display name for the table colum header */
 function getSortFieldDisplayName(name: string) {
-  for (const sf of sortFields) {
+  for (const sf of [...sortFields, ...usageSortFields]) {
     if (sf.name === name) {
       return sf.display;
     }
   }
-  for (const sf of sortTimeFields) {
+  for (const sf of [...sortTimeFields, ...usageSortTimeFields]) {
     if (sf.name === name) {
       return sf.display;
     }
@@ -19,6 +19,7 @@ export interface SearchQuery {
   uid?: string[];
   facet?: FacetField[];
   explain?: boolean;
+  fullText?: boolean; // also match descriptions, query expressions and transformations
   withAllowedActions?: boolean;
   accessInfo?: boolean;
   hasPreview?: string; // theme
@@ -37,6 +38,9 @@ export interface DashboardQueryResult {
   location: string; // url that can be split
   ds_uid: string[];
 
+  // matched snippets by field, only set for full-text queries
+  highlight?: Record<string, string>;
+
   // debugging fields
   score: number;
   explain: {};
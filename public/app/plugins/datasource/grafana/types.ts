@@ -16,14 +16,18 @@ export enum GrafanaQueryType {
   List = 'list',
   Read = 'read',
   Search = 'search',
+  History = 'history',
 }
 
 export interface GrafanaQuery extends DataQuery {
   queryType: GrafanaQueryType; // RandomWalk by default
-  channel?: string;
+  channel?: string; // for live measurements and history
   filter?: LiveDataFilter;
   buffer?: number;
   path?: string; // for list and read
+  root?: string; // storage root for read, public-static by default
+  columns?: string[]; // columns returned by read, all by default
+  timeField?: string; // field read filters by the time range
   search?: SearchQuery;
   snapshot?: DataFrameJSON[];
 }
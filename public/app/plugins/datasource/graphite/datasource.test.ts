@@ -645,6 +645,47 @@ describe('graphiteDatasource', () => {
     });
   });
 
+  describe('when accessed through the server', () => {
+    let requestOptions: any;
+
+    beforeEach(() => {
+      const instanceSettings = {
+        url: '/api/datasources/proxy/1',
+        uid: 'graphite-uid',
+        access: 'proxy',
+        name: 'graphiteProd',
+        jsonData: {},
+      };
+      ctx.ds = new GraphiteDatasource(instanceSettings, ctx.templateSrv);
+      fetchMock.mockImplementation((options: any) => {
+        requestOptions = options;
+        return of(createFetchResponse({ status: 'OK', message: 'Data source is working' }));
+      });
+    });
+
+    it('should request metric find from backend resources', () => {
+      ctx.ds.metricFindQuery('apps.*');
+
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/metrics/find');
+      expect(requestOptions.method).toBe('POST');
+      expect(requestOptions.data).toBe('query=apps.*');
+    });
+
+    it('should request tag autocomplete from backend resources', () => {
+      ctx.ds.getTagsAutoComplete(['server=backend_01'], 'serv');
+
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/resources/tags/autoComplete/tags');
+      expect(requestOptions.params).toEqual({ expr: ['server=backend_01'], tagPrefix: 'serv' });
+    });
+
+    it('should test data source with backend health check', async () => {
+      const result = await ctx.ds.testDatasource();
+
+      expect(requestOptions.url).toBe('/api/datasources/uid/graphite-uid/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+  });
+
   describe('exporting to abstract query', () => {
     async function assertQueryExport(target: string, labelMatchers: AbstractLabelMatcher[]): Promise<void> {
       let abstractQueries = await ctx.ds.exportToAbstractQueries([
@@ -82,6 +82,7 @@ export class GraphiteDatasource
   funcDefsPromise: Promise<any> | null = null;
   _seriesRefLetters: string;
   requestCounter = 100;
+  access: string;
   private readonly metricMappings: GraphiteLokiMapping[];
 
   constructor(instanceSettings: any, private readonly templateSrv: TemplateSrv = getTemplateSrv()) {
@@ -98,6 +99,7 @@ export class GraphiteDatasource
     this.cacheTimeout = instanceSettings.cacheTimeout;
     this.rollupIndicatorEnabled = instanceSettings.jsonData.rollupIndicatorEnabled;
     this.withCredentials = instanceSettings.withCredentials;
+    this.access = instanceSettings.access;
     this.funcDefs = null;
     this.funcDefsPromise = null;
     this._seriesRefLetters = 'ABCDEFGHIJKLMNOPQRSTUVWXYZ';
@@ -612,7 +614,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data, (metric) => {
             return {
@@ -748,7 +750,7 @@ export class GraphiteDatasource
       httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
       httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return lastValueFrom(this.doGraphiteResourceRequest(httpOptions).pipe(mapToTags()));
   }
 
   getTagValuesAutoComplete(expressions: any[], tag: any, valuePrefix: any, optionalOptions: any) {
@@ -775,7 +777,7 @@ export class GraphiteDatasource
       httpOptions.params.from = this.translateTime(options.range.from, false, options.timezone);
       httpOptions.params.until = this.translateTime(options.range.to, true, options.timezone);
     }
-    return lastValueFrom(this.doGraphiteRequest(httpOptions).pipe(mapToTags()));
+    return lastValueFrom(this.doGraphiteResourceRequest(httpOptions).pipe(mapToTags()));
   }
 
   getVersion(optionalOptions: any) {
@@ -835,7 +837,7 @@ export class GraphiteDatasource
     };
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doGraphiteResourceRequest(httpOptions).pipe(
         map((results: any) => {
           // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
           // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
@@ -856,6 +858,21 @@ export class GraphiteDatasource
   }
 
   testDatasource() {
+    if (this.usesBackendResources()) {
+      return lastValueFrom(
+        getBackendSrv().fetch<{ message: string }>({
+          method: 'GET',
+          url: `/api/datasources/uid/${this.uid}/health`,
+          showErrorAlert: false,
+        })
+      ).then(
+        (res) => ({ status: 'success', message: res.data.message }),
+        (err) => {
+          throw new Error(err.data?.message ?? 'Data source health check failed');
+        }
+      );
+    }
+
     const query: DataQueryRequest<GraphiteQuery> = {
       app: 'graphite',
       interval: '10ms',
@@ -906,6 +923,38 @@ export class GraphiteDatasource
       );
   }
 
+  /**
+   * Metric find, tag autocomplete and function list lookups are served by the backend when the data
+   * source is accessed through the server, so they are subject to the same permissions as queries.
+   */
+  doGraphiteResourceRequest(options: {
+    method?: string;
+    url: any;
+    requestId?: any;
+    withCredentials?: any;
+    headers?: any;
+    inspect?: any;
+  }) {
+    if (!this.usesBackendResources()) {
+      return this.doGraphiteRequest(options);
+    }
+
+    options.url = `/api/datasources/uid/${this.uid}/resources${options.url}`;
+    options.inspect = { type: 'graphite' };
+
+    return getBackendSrv()
+      .fetch(options)
+      .pipe(
+        catchError((err: any) => {
+          return throwError(reduceError(err));
+        })
+      );
+  }
+
+  private usesBackendResources() {
+    return this.access === 'proxy' && !!this.uid;
+  }
+
   buildGraphiteParams(options: any, scopedVars?: ScopedVars): string[] {
     const graphiteOptions = ['from', 'until', 'rawData', 'format', 'maxDataPoints', 'cacheTimeout'];
     const cleanOptions = [],
@@ -7,6 +7,7 @@ import { LokiOptions } from '../types';
 
 import { DerivedFields } from './DerivedFields';
 import { MaxLinesField } from './MaxLinesField';
+import { QuerySplittingFields } from './QuerySplittingFields';
 
 export type Props = DataSourcePluginOptionsEditorProps<LokiOptions>;
 
@@ -24,6 +25,8 @@ const makeJsonUpdater =
 
 const setMaxLines = makeJsonUpdater('maxLines');
 const setDerivedFields = makeJsonUpdater('derivedFields');
+const setQueryChunkInterval = makeJsonUpdater('queryChunkInterval');
+const setQueryChunkConcurrency = makeJsonUpdater('queryChunkConcurrency');
 
 export const ConfigEditor = (props: Props) => {
   const { options, onOptionsChange } = props;
@@ -48,6 +51,14 @@ export const ConfigEditor = (props: Props) => {
             />
           </div>
         </div>
+        <div className="gf-form-inline">
+          <QuerySplittingFields
+            chunkInterval={options.jsonData.queryChunkInterval || ''}
+            concurrency={options.jsonData.queryChunkConcurrency?.toString() || ''}
+            onChunkIntervalChange={(value) => onOptionsChange(setQueryChunkInterval(options, value))}
+            onConcurrencyChange={(value) => onOptionsChange(setQueryChunkConcurrency(options, value))}
+          />
+        </div>
       </div>
 
       <DerivedFields
@@ -0,0 +1,63 @@
+import React from 'react';
+
+import { LegacyForms } from '@grafana/ui';
+const { FormField } = LegacyForms;
+
+type Props = {
+  chunkInterval: string;
+  concurrency: string;
+  onChunkIntervalChange: (value: string) => void;
+  onConcurrencyChange: (value: number | undefined) => void;
+};
+
+export const QuerySplittingFields = (props: Props) => {
+  const { chunkInterval, concurrency, onChunkIntervalChange, onConcurrencyChange } = props;
+  return (
+    <>
+      <div className="gf-form">
+        <FormField
+          label="Query chunk interval"
+          labelWidth={11}
+          inputWidth={20}
+          inputEl={
+            <input
+              type="text"
+              className="gf-form-input width-8 gf-form-input--has-help-icon"
+              value={chunkInterval}
+              onChange={(event) => onChunkIntervalChange(event.currentTarget.value)}
+              spellCheck={false}
+              placeholder="1d"
+            />
+          }
+          tooltip={
+            <>
+              Range queries over a longer time range are split into queries of this interval, which are run separately
+              and merged. Splitting long queries prevents them from timing out. Leave empty to not split queries.
+            </>
+          }
+        />
+      </div>
+      <div className="gf-form">
+        <FormField
+          label="Query chunk concurrency"
+          labelWidth={11}
+          inputWidth={20}
+          inputEl={
+            <input
+              type="number"
+              className="gf-form-input width-8 gf-form-input--has-help-icon"
+              value={concurrency}
+              onChange={(event) => {
+                const value = parseInt(event.currentTarget.value, 10);
+                onConcurrencyChange(isNaN(value) ? undefined : value);
+              }}
+              spellCheck={false}
+              placeholder="4"
+            />
+          }
+          tooltip={<>Maximum number of chunks of a split query that are run at the same time (default: 4).</>}
+        />
+      </div>
+    </>
+  );
+};
@@ -42,6 +42,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   tsdbResolution: any;
   lookupLimit: any;
   tagKeys: any;
+  access: any;
 
   aggregatorsPromise: any;
   filterTypesPromise: any;
@@ -53,6 +54,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     this.name = instanceSettings.name;
     this.withCredentials = instanceSettings.withCredentials;
     this.basicAuth = instanceSettings.basicAuth;
+    this.access = instanceSettings.access;
     instanceSettings.jsonData = instanceSettings.jsonData || {};
     this.tsdbVersion = instanceSettings.jsonData.tsdbVersion || 1;
     this.tsdbResolution = instanceSettings.jsonData.tsdbResolution || 1;
@@ -257,6 +259,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _performSuggestQuery(query: string, type: string): Observable<any> {
+    if (this._usesBackendResources()) {
+      return this._getResource('/suggest', { type, q: query }).pipe(map((result: any) => result.data));
+    }
+
     return this._get('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
       map((result: any) => {
         return result.data;
@@ -269,6 +275,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
+    if (this._usesBackendResources()) {
+      return this._getResource('/lookup/tag-values', { metric, keys }).pipe(map((result: any) => result.data));
+    }
+
     const keysArray = keys.split(',').map((key: any) => {
       return key.trim();
     });
@@ -300,6 +310,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
+    if (this._usesBackendResources()) {
+      return this._getResource('/lookup/tag-keys', { metric }).pipe(map((result: any) => result.data));
+    }
+
     return this._get('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
       map((result: any) => {
         result = result.data.results;
@@ -331,6 +345,22 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     return getBackendSrv().fetch(options);
   }
 
+  /**
+   * Lookups are served by the backend when the data source is accessed through the server, so they
+   * are subject to the same permissions as queries.
+   */
+  _usesBackendResources() {
+    return this.access === 'proxy' && !!this.uid;
+  }
+
+  _getResource(path: string, params?: Record<string, string>): Observable<FetchResponse> {
+    return getBackendSrv().fetch({
+      method: 'GET',
+      url: `/api/datasources/uid/${this.uid}/resources${path}`,
+      params: params,
+    });
+  }
+
   _addCredentialOptions(options: any) {
     if (this.basicAuth || this.withCredentials) {
       options.withCredentials = true;
@@ -395,6 +425,21 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   testDatasource() {
+    if (this._usesBackendResources()) {
+      return lastValueFrom(
+        getBackendSrv().fetch<{ message: string }>({
+          method: 'GET',
+          url: `/api/datasources/uid/${this.uid}/health`,
+          showErrorAlert: false,
+        })
+      ).then(
+        (res) => ({ status: 'success', message: res.data.message }),
+        (err) => {
+          throw new Error(err.data?.message ?? 'Data source health check failed');
+        }
+      );
+    }
+
     return lastValueFrom(
       this._performSuggestQuery('cpu', 'metrics').pipe(
         map(() => {
@@ -410,7 +455,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     }
 
     this.aggregatorsPromise = lastValueFrom(
-      this._get('/api/aggregators').pipe(
+      (this._usesBackendResources() ? this._getResource('/aggregators') : this._get('/api/aggregators')).pipe(
         map((result: any) => {
           if (result.data && isArray(result.data)) {
             return result.data.sort();
@@ -428,7 +473,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     }
 
     this.filterTypesPromise = lastValueFrom(
-      this._get('/api/config/filters').pipe(
+      (this._usesBackendResources() ? this._getResource('/filters') : this._get('/api/config/filters')).pipe(
         map((result: any) => {
           if (result.data) {
             return Object.keys(result.data).sort();
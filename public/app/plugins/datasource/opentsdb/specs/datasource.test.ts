@@ -23,12 +23,12 @@ const metricFindQueryData = [
 ];
 
 describe('opentsdb', () => {
-  function getTestcontext({ data = metricFindQueryData }: { data?: any } = {}) {
+  function getTestcontext({ data = metricFindQueryData, settings = {} }: { data?: any; settings?: any } = {}) {
     jest.clearAllMocks();
     const fetchMock = jest.spyOn(backendSrv, 'fetch');
     fetchMock.mockImplementation(() => of(createFetchResponse(data)));
 
-    const instanceSettings = { url: '', jsonData: { tsdbVersion: 1 } };
+    const instanceSettings = { url: '', jsonData: { tsdbVersion: 1 }, ...settings };
     const replace = jest.fn((value) => value);
     const templateSrv: any = {
       replace,
@@ -121,6 +121,39 @@ describe('opentsdb', () => {
     });
   });
 
+  describe('When accessed through the server', () => {
+    const settings = { uid: 'opentsdb-uid', access: 'proxy' };
+
+    it('metrics() should request backend suggest resource', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: ['cpu'], settings });
+
+      const results = await ds.metricFindQuery('metrics(pew)');
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/suggest');
+      expect(fetchMock.mock.calls[0][0].params).toEqual({ type: 'metrics', q: 'pew' });
+      expect(results).toEqual([{ text: 'cpu' }]);
+    });
+
+    it('tag_values() should request backend tag values resource', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: ['web01'], settings });
+
+      const results = await ds.metricFindQuery('tag_values(cpu, hostname, env=prod)');
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/resources/lookup/tag-values');
+      expect(fetchMock.mock.calls[0][0].params).toEqual({ metric: 'cpu', keys: 'hostname, env=prod' });
+      expect(results).toEqual([{ text: 'web01' }]);
+    });
+
+    it('should test data source with backend health check', async () => {
+      const { ds, fetchMock } = getTestcontext({ data: { message: 'Data source is working' }, settings });
+
+      const result = await ds.testDatasource();
+
+      expect(fetchMock.mock.calls[0][0].url).toBe('/api/datasources/uid/opentsdb-uid/health');
+      expect(result).toEqual({ status: 'success', message: 'Data source is working' });
+    });
+  });
+
   describe('When interpolating variables', () => {
     it('should return an empty array if no queries are provided', () => {
       const { ds } = getTestcontext();
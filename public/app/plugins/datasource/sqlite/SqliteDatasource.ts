@@ -0,0 +1,83 @@
+import { DataSourceInstanceSettings, ScopedVars, TimeRange } from '@grafana/data';
+import { CompletionItemKind, LanguageDefinition, TableIdentifier } from '@grafana/experimental';
+import { TemplateSrv } from '@grafana/runtime';
+import { SqlDatasource } from 'app/features/plugins/sql/datasource/SqlDatasource';
+import { DB, SQLQuery } from 'app/features/plugins/sql/types';
+import { formatSQL } from 'app/features/plugins/sql/utils/formatSQL';
+
+import SQLiteQueryModel from './SqliteQueryModel';
+import { mapFieldsToTypes } from './fields';
+import { getSqlCompletionProvider } from './sqlCompletionProvider';
+import { buildColumnQuery, buildTableQuery } from './sqliteMetaQuery';
+import { SQLiteOptions } from './types';
+
+// SQLite database file has a single schema available to queries.
+const mainDataset = 'main';
+
+export class SqliteDatasource extends SqlDatasource {
+  sqlLanguageDefinition: LanguageDefinition | undefined;
+
+  constructor(instanceSettings: DataSourceInstanceSettings<SQLiteOptions>) {
+    super(instanceSettings);
+  }
+
+  getQueryModel(target?: Partial<SQLQuery>, templateSrv?: TemplateSrv, scopedVars?: ScopedVars): SQLiteQueryModel {
+    return new SQLiteQueryModel(target!, templateSrv, scopedVars);
+  }
+
+  getSqlLanguageDefinition(db: DB): LanguageDefinition {
+    if (this.sqlLanguageDefinition !== undefined) {
+      return this.sqlLanguageDefinition;
+    }
+
+    const args = {
+      getMeta: { current: (identifier?: TableIdentifier) => this.fetchMeta(identifier) },
+    };
+    this.sqlLanguageDefinition = {
+      id: 'sql',
+      completionProvider: getSqlCompletionProvider(args),
+      formatter: formatSQL,
+    };
+    return this.sqlLanguageDefinition;
+  }
+
+  async fetchTables(): Promise<string[]> {
+    const tables = await this.runSql<string[]>(buildTableQuery(), { refId: 'tables' });
+    return tables.map((t) => t[0]);
+  }
+
+  async fetchFields(query: Partial<SQLQuery>) {
+    if (!query.table) {
+      return [];
+    }
+    const queryString = buildColumnQuery(this.getQueryModel(query), query.table);
+    const frame = await this.runSql<string[]>(queryString, { refId: 'fields' });
+    const fields = frame.map((f) => ({ name: f[0], text: f[0], value: f[0], type: f[1], label: f[0] }));
+    return mapFieldsToTypes(fields);
+  }
+
+  async fetchMeta(identifier?: TableIdentifier) {
+    if (!identifier?.table) {
+      const tables = await this.fetchTables();
+      return tables.map((t) => ({ name: t, completion: t, kind: CompletionItemKind.Class }));
+    }
+    const fields = await this.fetchFields({ dataset: mainDataset, table: identifier.table });
+    return fields.map((t) => ({ name: t.value, completion: t.value, kind: CompletionItemKind.Field }));
+  }
+
+  getDB(): DB {
+    if (this.db !== undefined) {
+      return this.db;
+    }
+    return {
+      datasets: () => Promise.resolve([mainDataset]),
+      tables: () => this.fetchTables(),
+      fields: (query: SQLQuery) => this.fetchFields(query),
+      validateQuery: (query: SQLQuery, range?: TimeRange) =>
+        Promise.resolve({ query, error: '', isError: false, isValid: true }),
+      dsID: () => this.id,
+      functions: () => ['AVG', 'COUNT', 'MAX', 'MIN', 'SUM', 'TOTAL'],
+      getEditorLanguageDefinition: () => this.getSqlLanguageDefinition(this.db),
+    };
+  }
+}
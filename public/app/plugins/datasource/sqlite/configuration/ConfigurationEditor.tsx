@@ -0,0 +1,81 @@
+import React from 'react';
+
+import {
+  DataSourcePluginOptionsEditorProps,
+  onUpdateDatasourceJsonDataOption,
+  updateDatasourcePluginJsonDataOption,
+} from '@grafana/data';
+import { Alert, FieldSet, InlineField, Input, Link } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+
+import { SQLiteOptions } from '../types';
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options } = props;
+  const jsonData = options.jsonData;
+
+  const mediumWidth = 20;
+  const shortWidth = 15;
+  const longWidth = 40;
+
+  return (
+    <>
+      <FieldSet label="SQLite Database" width={400}>
+        <InlineField
+          tooltip={
+            <span>
+              Absolute path of the database file. The file must be located in one of the paths allowed by the
+              <code>sqlite_allowed_paths</code> setting and is always opened in read-only mode.
+            </span>
+          }
+          labelWidth={shortWidth}
+          label="Path"
+        >
+          <Input
+            width={longWidth}
+            name="database"
+            value={jsonData.database || ''}
+            placeholder="/var/lib/grafana/sqlite/data.db"
+            onChange={onUpdateDatasourceJsonDataOption(props, 'database')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <ConnectionLimits
+        labelWidth={shortWidth}
+        jsonData={jsonData}
+        onPropertyChanged={(property, value) => {
+          updateDatasourcePluginJsonDataOption(props, property, value);
+        }}
+      ></ConnectionLimits>
+
+      <FieldSet label="SQLite details">
+        <InlineField
+          tooltip={
+            <span>
+              A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example
+              <code>1m</code> if your data is written every minute.
+            </span>
+          }
+          labelWidth={mediumWidth}
+          label="Min time interval"
+        >
+          <Input
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <Alert title="Read-only access" severity="info">
+        Database files are opened in read-only mode and <code>ATTACH</code>, <code>DETACH</code>, <code>VACUUM</code> and
+        <code>PRAGMA</code> statements are rejected. Check out the{' '}
+        <Link rel="noreferrer" target="_blank" href="http://docs.grafana.org/features/datasources/sqlite/">
+          SQLite Data Source Docs
+        </Link>{' '}
+        for more information.
+      </Alert>
+    </>
+  );
+};
@@ -0,0 +1,43 @@
+import { RAQBFieldTypes, SQLSelectableValue } from 'app/features/plugins/sql/types';
+
+// mapFieldsToTypes maps declared SQLite column types to query builder types
+// following SQLite type affinity rules.
+export function mapFieldsToTypes(columns: SQLSelectableValue[]) {
+  const fields: SQLSelectableValue[] = [];
+  for (const col of columns) {
+    const declaredType = col.type?.toUpperCase() ?? '';
+    let type: RAQBFieldTypes = 'text';
+    if (declaredType === 'DATE') {
+      type = 'date';
+    } else if (declaredType === 'DATETIME' || declaredType === 'TIMESTAMP') {
+      type = 'datetime';
+    } else if (declaredType === 'BOOLEAN' || declaredType === 'BOOL') {
+      type = 'boolean';
+    } else if (
+      declaredType.includes('INT') ||
+      declaredType.includes('REAL') ||
+      declaredType.includes('FLOA') ||
+      declaredType.includes('DOUB') ||
+      declaredType.includes('NUMERIC') ||
+      declaredType.includes('DECIMAL')
+    ) {
+      type = 'number';
+    }
+    fields.push({ ...col, raqbFieldType: type, icon: mapColumnTypeToIcon(type) });
+  }
+  return fields;
+}
+
+function mapColumnTypeToIcon(type: RAQBFieldTypes) {
+  switch (type) {
+    case 'date':
+    case 'datetime':
+      return 'clock-nine';
+    case 'boolean':
+      return 'toggle-off';
+    case 'number':
+      return 'calculator-alt';
+    default:
+      return 'text';
+  }
+}
@@ -0,0 +1,11 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SqlQueryEditor } from 'app/features/plugins/sql/components/QueryEditor';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { SqliteDatasource } from './SqliteDatasource';
+import { ConfigurationEditor } from './configuration/ConfigurationEditor';
+import { SQLiteOptions } from './types';
+
+export const plugin = new DataSourcePlugin<SqliteDatasource, SQLQuery, SQLiteOptions>(SqliteDatasource)
+  .setQueryEditor(SqlQueryEditor)
+  .setConfigEditor(ConfigurationEditor);
@@ -0,0 +1,10 @@
+import SQLiteQueryModel from './SqliteQueryModel';
+
+export function buildTableQuery() {
+  return `SELECT name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`;
+}
+
+export function buildColumnQuery(queryModel: SQLiteQueryModel, table: string) {
+  const tableName = queryModel.quoteLiteral(queryModel.unquoteIdentifier(table));
+  return `SELECT name, type FROM pragma_table_info(${tableName}) ORDER BY name`;
+}
@@ -6,22 +6,30 @@ import { selectors as editorSelectors } from '@grafana/e2e-selectors';
 import { InlineField, InlineFieldRow, InlineSwitch, Input, Select, TextArea } from '@grafana/ui';
 
 import { RandomWalkEditor, StreamingClientEditor } from './components';
+import { AlertingScriptEditor } from './components/AlertingScriptEditor';
 import { CSVContentEditor } from './components/CSVContentEditor';
 import { CSVFileEditor } from './components/CSVFileEditor';
 import { CSVWavesEditor } from './components/CSVWaveEditor';
 import ErrorEditor from './components/ErrorEditor';
 import { GrafanaLiveEditor } from './components/GrafanaLiveEditor';
+import { HighCardinalityEditor } from './components/HighCardinalityEditor';
 import { NodeGraphEditor } from './components/NodeGraphEditor';
 import { PredictablePulseEditor } from './components/PredictablePulseEditor';
 import { RawFrameEditor } from './components/RawFrameEditor';
 import { SimulationQueryEditor } from './components/SimulationQueryEditor';
 import { USAQueryEditor, usaQueryModes } from './components/USAQueryEditor';
-import { defaultCSVWaveQuery, defaultPulseQuery, defaultQuery } from './constants';
+import {
+  defaultAlertingScriptQuery,
+  defaultCSVWaveQuery,
+  defaultHighCardinalityQuery,
+  defaultPulseQuery,
+  defaultQuery,
+} from './constants';
 import { TestDataDataSource } from './datasource';
 import { defaultStreamQuery } from './runStreams';
 import { CSVWave, NodesQuery, TestDataQuery, USAQuery } from './types';
 
-const showLabelsFor = ['random_walk', 'predictable_pulse'];
+const showLabelsFor = ['random_walk', 'predictable_pulse', 'alerting_script'];
 const endpoints = [
   { value: 'datasources', label: 'Data Sources' },
   { value: 'search', label: 'Search' },
@@ -118,6 +126,12 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
         update.usa = {
           mode: usaQueryModes[0].value,
         };
+        break;
+      case 'alerting_script':
+        update.alertingScript = defaultAlertingScriptQuery;
+        break;
+      case 'high_cardinality':
+        update.highCardinality = defaultHighCardinalityQuery;
     }
 
     onUpdate(update);
@@ -298,6 +312,18 @@ export const QueryEditor = ({ query, datasource, onChange, onRunQuery }: Props)
         <PredictablePulseEditor onChange={onPulseWaveChange} query={query} ds={datasource} />
       )}
       {scenarioId === 'predictable_csv_wave' && <CSVWavesEditor onChange={onCSVWaveChange} waves={query.csvWave} />}
+      {scenarioId === 'alerting_script' && (
+        <AlertingScriptEditor
+          onChange={(alertingScript) => onUpdate({ ...query, alertingScript })}
+          query={query.alertingScript ?? {}}
+        />
+      )}
+      {scenarioId === 'high_cardinality' && (
+        <HighCardinalityEditor
+          onChange={(highCardinality) => onUpdate({ ...query, highCardinality })}
+          query={query.highCardinality ?? {}}
+        />
+      )}
       {scenarioId === 'node_graph' && (
         <NodeGraphEditor onChange={(val: NodesQuery) => onChange({ ...query, nodes: val })} query={query} />
       )}
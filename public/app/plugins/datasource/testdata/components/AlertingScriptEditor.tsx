@@ -0,0 +1,66 @@
+import React, { ChangeEvent, useState } from 'react';
+
+import { InlineField, InlineFieldRow, Input, TextArea } from '@grafana/ui';
+
+import { AlertingScriptQuery, AlertingScriptWindow } from '../types';
+
+interface Props {
+  onChange: (value: AlertingScriptQuery) => void;
+  query: AlertingScriptQuery;
+}
+
+const fields = [
+  { label: 'Step', id: 'timeStep', placeholder: '60', tooltip: 'The number of seconds between datapoints.' },
+  { label: 'Value', id: 'value', placeholder: '0', tooltip: 'The value outside of the windows.' },
+  { label: 'Noise', id: 'noise', placeholder: '0', tooltip: 'The maximum deviation added to the values.' },
+  { label: 'Seed', id: 'seed', placeholder: '0', tooltip: 'Queries with the same seed return the same noise.' },
+];
+
+export const AlertingScriptEditor = ({ onChange, query }: Props) => {
+  const [windows, setWindows] = useState(JSON.stringify(query.windows ?? [], null, 2));
+  const [invalid, setInvalid] = useState(false);
+
+  const onInputChange = (e: ChangeEvent<HTMLInputElement>) => {
+    const { name, value } = e.target;
+    onChange({ ...query, [name]: Number(value) });
+  };
+
+  const onWindowsBlur = () => {
+    try {
+      const parsed: AlertingScriptWindow[] = JSON.parse(windows);
+      setInvalid(false);
+      onChange({ ...query, windows: parsed });
+    } catch {
+      setInvalid(true);
+    }
+  };
+
+  return (
+    <>
+      <InlineFieldRow>
+        {fields.map(({ label, id, placeholder, tooltip }) => (
+          <InlineField label={label} labelWidth={14} key={id} tooltip={tooltip}>
+            <Input
+              width={16}
+              type="number"
+              name={id}
+              value={query[id as keyof AlertingScriptQuery] as number | undefined}
+              placeholder={placeholder}
+              onChange={onInputChange}
+            />
+          </InlineField>
+        ))}
+      </InlineFieldRow>
+      <InlineField
+        label="Windows"
+        labelWidth={14}
+        grow
+        invalid={invalid}
+        error="Windows must be a JSON array"
+        tooltip='Windows repeat every period from 00:00 UTC, for example {"every": "24h", "offset": "2h", "duration": "5m", "noData": true}.'
+      >
+        <TextArea rows={6} value={windows} onChange={(e) => setWindows(e.currentTarget.value)} onBlur={onWindowsBlur} />
+      </InlineField>
+    </>
+  );
+};
@@ -0,0 +1,61 @@
+import React, { ChangeEvent } from 'react';
+
+import { InlineField, InlineFieldRow, Input } from '@grafana/ui';
+
+import { HighCardinalityQuery } from '../types';
+
+interface Props {
+  onChange: (value: HighCardinalityQuery) => void;
+  query: HighCardinalityQuery;
+}
+
+const fields = [
+  { label: 'Series', id: 'seriesCount', placeholder: '100', tooltip: 'The number of series.' },
+  { label: 'Step', id: 'timeStep', placeholder: '60', tooltip: 'The number of seconds between datapoints.' },
+  { label: 'Min', id: 'min', placeholder: '0', tooltip: 'The minimum value.' },
+  { label: 'Max', id: 'max', placeholder: '100', tooltip: 'The maximum value.' },
+  { label: 'Seed', id: 'seed', placeholder: '0', tooltip: 'Queries with the same seed return the same values.' },
+];
+
+// Labels are edited as name=cardinality pairs, for example instance=100, job=5
+const formatLabels = (labels: HighCardinalityQuery['labels']) =>
+  (labels ?? []).map((l) => `${l.name}=${l.cardinality}`).join(', ');
+
+const parseLabels = (value: string) =>
+  value
+    .split(',')
+    .map((pair) => pair.split('='))
+    .filter(([name]) => name.trim() !== '')
+    .map(([name, cardinality]) => ({ name: name.trim(), cardinality: Number(cardinality) }));
+
+export const HighCardinalityEditor = ({ onChange, query }: Props) => {
+  const onInputChange = (e: ChangeEvent<HTMLInputElement>) => {
+    const { name, value } = e.target;
+    onChange({ ...query, [name]: Number(value) });
+  };
+
+  return (
+    <InlineFieldRow>
+      {fields.map(({ label, id, placeholder, tooltip }) => (
+        <InlineField label={label} labelWidth={14} key={id} tooltip={tooltip}>
+          <Input
+            width={16}
+            type="number"
+            name={id}
+            value={query[id as keyof HighCardinalityQuery] as number | undefined}
+            placeholder={placeholder}
+            onChange={onInputChange}
+          />
+        </InlineField>
+      ))}
+      <InlineField label="Labels" labelWidth={14} tooltip="Label names with the number of distinct values of each.">
+        <Input
+          width={32}
+          defaultValue={formatLabels(query.labels)}
+          placeholder="instance=100, job=5"
+          onBlur={(e) => onChange({ ...query, labels: parseLabels(e.currentTarget.value) })}
+        />
+      </InlineField>
+    </InlineFieldRow>
+  );
+};
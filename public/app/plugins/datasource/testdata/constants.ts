@@ -1,4 +1,4 @@
-import { CSVWave, TestDataQuery } from './types';
+import { AlertingScriptQuery, CSVWave, HighCardinalityQuery, TestDataQuery } from './types';
 
 export const defaultPulseQuery: any = {
   timeStep: 60,
@@ -15,6 +15,18 @@ export const defaultCSVWaveQuery: CSVWave[] = [
   },
 ];
 
+export const defaultAlertingScriptQuery: AlertingScriptQuery = {
+  timeStep: 60,
+  value: 10,
+  noise: 1,
+  windows: [{ every: '1h', duration: '5m', value: 90 }],
+};
+
+export const defaultHighCardinalityQuery: HighCardinalityQuery = {
+  seriesCount: 100,
+  labels: [{ name: 'instance', cardinality: 100 }],
+};
+
 export const defaultQuery: TestDataQuery = {
   scenarioId: 'random_walk',
   refId: '',
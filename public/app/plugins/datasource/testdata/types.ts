@@ -28,6 +28,8 @@ export interface TestDataQuery extends DataQuery {
   usa?: USAQuery;
   errorType?: 'server_panic' | 'frontend_exception' | 'frontend_observable';
   spanCount?: number;
+  alertingScript?: AlertingScriptQuery;
+  highCardinality?: HighCardinalityQuery;
 }
 
 export interface NodesQuery {
@@ -75,3 +77,28 @@ export interface USAQuery {
   fields?: string[]; // foo, bar, baz
   states?: string[];
 }
+
+export interface AlertingScriptQuery {
+  timeStep?: number;
+  value?: number;
+  noise?: number;
+  seed?: number;
+  windows?: AlertingScriptWindow[];
+}
+
+export interface AlertingScriptWindow {
+  every: string; // 1h
+  offset?: string; // 2h, from 00:00 UTC
+  duration: string; // 5m
+  value?: number;
+  noData?: boolean;
+}
+
+export interface HighCardinalityQuery {
+  seriesCount?: number;
+  labels?: Array<{ name: string; cardinality: number }>;
+  timeStep?: number;
+  min?: number;
+  max?: number;
+  seed?: number;
+}